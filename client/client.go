@@ -0,0 +1,337 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gojektech/proctor-engine/jobs/execution"
+	"github.com/gojektech/proctor-engine/jobs/idempotency"
+	"github.com/gojektech/proctor-engine/jobs/metadata"
+	"github.com/gojektech/proctor-engine/jobs/queue"
+	"github.com/gojektech/proctor-engine/jobs/secrets"
+	"github.com/gojektech/proctor-engine/ratelimit"
+	"github.com/gojektech/proctor-engine/utility"
+)
+
+const AccessTokenHeaderKey = "Access-Token"
+
+const (
+	apiPrefix          = "/api/v1"
+	defaultMaxAttempts = 3
+	defaultRetryDelay  = 500 * time.Millisecond
+)
+
+type Config struct {
+	URL         string
+	EmailID     string
+	AccessToken string
+	MaxAttempts int
+	RetryDelay  time.Duration
+	HTTPClient  *http.Client
+}
+
+type Execution struct {
+	Name              string          `json:"name,omitempty"`
+	ApprovalRequestID string          `json:"approval_request_id,omitempty"`
+	Queue             *queue.Position `json:"queue,omitempty"`
+}
+
+type Client interface {
+	ExecuteJob(context.Context, execution.Job) (Execution, error)
+	StreamLogs(context.Context, string) (io.ReadCloser, error)
+	ListMetadata(context.Context) ([]metadata.Metadata, error)
+	DescribeJob(context.Context, string) (*metadata.Metadata, error)
+	SubmitMetadata(context.Context, []metadata.Metadata) error
+	SubmitSecrets(context.Context, secrets.Secret) error
+	ExecutionStatus(context.Context, string) (*execution.Execution, error)
+	CancelExecution(context.Context, string) (*execution.Execution, error)
+}
+
+type client struct {
+	baseURL     string
+	emailID     string
+	accessToken string
+	maxAttempts int
+	retryDelay  time.Duration
+	httpClient  *http.Client
+}
+
+func NewClient(config Config) Client {
+	maxAttempts := config.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMaxAttempts
+	}
+	retryDelay := config.RetryDelay
+	if retryDelay <= 0 {
+		retryDelay = defaultRetryDelay
+	}
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &client{
+		baseURL:     strings.TrimRight(config.URL, "/"),
+		emailID:     config.EmailID,
+		accessToken: config.AccessToken,
+		maxAttempts: maxAttempts,
+		retryDelay:  retryDelay,
+		httpClient:  httpClient,
+	}
+}
+
+type Error struct {
+	StatusCode int
+	Code       string
+	Message    string
+	Details    []utility.FieldError
+	RequestID  string
+	RetryAfter time.Duration
+}
+
+func (err *Error) Error() string {
+	if err.Code == "" {
+		return fmt.Sprintf("proctor: unexpected response status %d", err.StatusCode)
+	}
+	return fmt.Sprintf("proctor: %s (%d): %s", err.Code, err.StatusCode, err.Message)
+}
+
+func (client *client) ExecuteJob(ctx context.Context, job execution.Job) (Execution, error) {
+	var result Execution
+
+	body, contentType, err := executionBody(job)
+	if err != nil {
+		return result, err
+	}
+	idempotencyKey, err := utility.RandomID()
+	if err != nil {
+		return result, err
+	}
+	header := http.Header{}
+	header.Set("Content-Type", contentType)
+	header.Set(idempotency.HeaderKey, idempotencyKey)
+
+	resp, err := client.do(ctx, "POST", "/jobs/"+url.PathEscape(job.Name)+"/executions", header, body)
+	if err != nil {
+		return result, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusAccepted {
+		var accepted struct {
+			RequestID string `json:"request_id"`
+			queue.Position
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&accepted); err != nil {
+			return result, err
+		}
+		if accepted.RequestID != "" {
+			result.ApprovalRequestID = accepted.RequestID
+		} else {
+			result.Queue = &accepted.Position
+		}
+		return result, nil
+	}
+
+	var created struct {
+		Name string `json:"name"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&created)
+	result.Name = created.Name
+	return result, err
+}
+
+func executionBody(job execution.Job) ([]byte, string, error) {
+	jobInJSON, err := json.Marshal(job)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(job.Files) == 0 {
+		return jobInJSON, "application/json", nil
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	if err := writer.WriteField(execution.JobFormField, string(jobInJSON)); err != nil {
+		return nil, "", err
+	}
+	for name, content := range job.Files {
+		part, err := writer.CreateFormFile(name, name)
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err := part.Write(content); err != nil {
+			return nil, "", err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+	return body.Bytes(), writer.FormDataContentType(), nil
+}
+
+func (client *client) ListMetadata(ctx context.Context) ([]metadata.Metadata, error) {
+	var jobMetadata []metadata.Metadata
+	err := client.fetch(ctx, "GET", "/jobs", &jobMetadata)
+	return jobMetadata, err
+}
+
+func (client *client) DescribeJob(ctx context.Context, jobName string) (*metadata.Metadata, error) {
+	var jobMetadata metadata.Metadata
+	if err := client.fetch(ctx, "GET", "/jobs/"+url.PathEscape(jobName), &jobMetadata); err != nil {
+		return nil, err
+	}
+	return &jobMetadata, nil
+}
+
+func (client *client) ExecutionStatus(ctx context.Context, executionName string) (*execution.Execution, error) {
+	var jobExecution execution.Execution
+	if err := client.fetch(ctx, "GET", "/executions/"+url.PathEscape(executionName), &jobExecution); err != nil {
+		return nil, err
+	}
+	return &jobExecution, nil
+}
+
+func (client *client) CancelExecution(ctx context.Context, executionName string) (*execution.Execution, error) {
+	var jobExecution execution.Execution
+	if err := client.fetch(ctx, "POST", "/executions/"+url.PathEscape(executionName)+"/cancel", &jobExecution); err != nil {
+		return nil, err
+	}
+	return &jobExecution, nil
+}
+
+func (client *client) SubmitMetadata(ctx context.Context, jobMetadata []metadata.Metadata) error {
+	return client.submit(ctx, "POST", "/jobs", jobMetadata)
+}
+
+func (client *client) SubmitSecrets(ctx context.Context, secret secrets.Secret) error {
+	return client.submit(ctx, "PUT", "/jobs/"+url.PathEscape(secret.JobName)+"/secrets", secret)
+}
+
+func (client *client) submit(ctx context.Context, method, path string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+
+	resp, err := client.do(ctx, method, path, header, body)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (client *client) fetch(ctx context.Context, method, path string, value interface{}) error {
+	resp, err := client.do(ctx, method, path, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return json.NewDecoder(resp.Body).Decode(value)
+}
+
+func (client *client) authenticate(header http.Header) {
+	if client.emailID != "" {
+		header.Set(utility.UserEmailHeaderKey, client.emailID)
+	}
+	if client.accessToken != "" {
+		header.Set(AccessTokenHeaderKey, client.accessToken)
+	}
+}
+
+func (client *client) do(ctx context.Context, method, path string, header http.Header, body []byte) (*http.Response, error) {
+	var lastErr error
+	for attempt := 1; attempt <= client.maxAttempts; attempt++ {
+		if attempt > 1 {
+			if err := client.wait(ctx, attempt, lastErr); err != nil {
+				return nil, err
+			}
+		}
+
+		req, err := http.NewRequest(method, client.baseURL+apiPrefix+path, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		for key, values := range header {
+			req.Header[key] = values
+		}
+		client.authenticate(req.Header)
+
+		resp, err := client.httpClient.Do(req.WithContext(ctx))
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			lastErr = err
+			continue
+		}
+
+		if resp.StatusCode < http.StatusBadRequest {
+			return resp, nil
+		}
+
+		lastErr = responseError(resp)
+		if !retryable(resp.StatusCode) {
+			return nil, lastErr
+		}
+	}
+	return nil, lastErr
+}
+
+func (client *client) wait(ctx context.Context, attempt int, lastErr error) error {
+	delay := client.retryDelay * time.Duration(1<<uint(attempt-2))
+	if err, ok := lastErr.(*Error); ok && err.RetryAfter > delay {
+		delay = err.RetryAfter
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func retryable(statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func responseError(resp *http.Response) *Error {
+	defer resp.Body.Close()
+
+	apiError := &Error{StatusCode: resp.StatusCode}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err == nil {
+		var errorResponse utility.ErrorResponse
+		if json.Unmarshal(body, &errorResponse) == nil {
+			apiError.Code = errorResponse.Error.Code
+			apiError.Message = errorResponse.Error.Message
+			apiError.Details = errorResponse.Error.Details
+			apiError.RequestID = errorResponse.Error.RequestID
+		}
+	}
+
+	if seconds, err := strconv.Atoi(resp.Header.Get(ratelimit.RetryAfterHeaderKey)); err == nil && seconds > 0 {
+		apiError.RetryAfter = time.Duration(seconds) * time.Second
+	}
+	return apiError
+}
@@ -0,0 +1,56 @@
+package client
+
+import (
+	"context"
+	"io"
+
+	"github.com/gojektech/proctor-engine/jobs/execution"
+	"github.com/gojektech/proctor-engine/jobs/metadata"
+	"github.com/gojektech/proctor-engine/jobs/secrets"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockClient struct {
+	mock.Mock
+}
+
+func (m *MockClient) ExecuteJob(ctx context.Context, job execution.Job) (Execution, error) {
+	args := m.Called(ctx, job)
+	return args.Get(0).(Execution), args.Error(1)
+}
+
+func (m *MockClient) StreamLogs(ctx context.Context, executionName string) (io.ReadCloser, error) {
+	args := m.Called(ctx, executionName)
+	return args.Get(0).(io.ReadCloser), args.Error(1)
+}
+
+func (m *MockClient) ListMetadata(ctx context.Context) ([]metadata.Metadata, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]metadata.Metadata), args.Error(1)
+}
+
+func (m *MockClient) DescribeJob(ctx context.Context, jobName string) (*metadata.Metadata, error) {
+	args := m.Called(ctx, jobName)
+	return args.Get(0).(*metadata.Metadata), args.Error(1)
+}
+
+func (m *MockClient) SubmitMetadata(ctx context.Context, jobMetadata []metadata.Metadata) error {
+	args := m.Called(ctx, jobMetadata)
+	return args.Error(0)
+}
+
+func (m *MockClient) SubmitSecrets(ctx context.Context, secret secrets.Secret) error {
+	args := m.Called(ctx, secret)
+	return args.Error(0)
+}
+
+func (m *MockClient) ExecutionStatus(ctx context.Context, executionName string) (*execution.Execution, error) {
+	args := m.Called(ctx, executionName)
+	return args.Get(0).(*execution.Execution), args.Error(1)
+}
+
+func (m *MockClient) CancelExecution(ctx context.Context, executionName string) (*execution.Execution, error) {
+	args := m.Called(ctx, executionName)
+	return args.Get(0).(*execution.Execution), args.Error(1)
+}
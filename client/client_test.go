@@ -0,0 +1,293 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gojektech/proctor-engine/health"
+	"github.com/gojektech/proctor-engine/jobs/approval"
+	"github.com/gojektech/proctor-engine/jobs/execution"
+	"github.com/gojektech/proctor-engine/jobs/idempotency"
+	"github.com/gojektech/proctor-engine/jobs/logs"
+	"github.com/gojektech/proctor-engine/jobs/metadata"
+	"github.com/gojektech/proctor-engine/jobs/policy"
+	"github.com/gojektech/proctor-engine/jobs/queue"
+	"github.com/gojektech/proctor-engine/jobs/secrets"
+	"github.com/gojektech/proctor-engine/jobs/webhook"
+	"github.com/gojektech/proctor-engine/jobs/workflow"
+	"github.com/gojektech/proctor-engine/kubernetes"
+	"github.com/gojektech/proctor-engine/ratelimit"
+	"github.com/gojektech/proctor-engine/registry"
+	"github.com/gojektech/proctor-engine/server"
+	"github.com/gojektech/proctor-engine/utility"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type ClientTestSuite struct {
+	suite.Suite
+	mockKubeClient     *kubernetes.MockClient
+	mockMetadataStore  *metadata.MockStore
+	mockSecretsStore   *secrets.MockStore
+	mockExecutionStore *execution.MockStore
+	mockIdempotency    *idempotency.MockStore
+	mockTracker        *execution.MockTracker
+	requestHeaders     []http.Header
+	server             *httptest.Server
+	testClient         Client
+}
+
+func (suite *ClientTestSuite) SetupTest() {
+	suite.mockKubeClient = &kubernetes.MockClient{}
+	suite.mockMetadataStore = &metadata.MockStore{}
+	suite.mockSecretsStore = &secrets.MockStore{}
+	suite.mockExecutionStore = &execution.MockStore{}
+	suite.mockIdempotency = &idempotency.MockStore{}
+	suite.mockIdempotency.On("Reserve", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+	suite.mockIdempotency.On("Complete", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	suite.mockIdempotency.On("Release", mock.Anything, mock.Anything).Return(nil)
+	suite.mockTracker = &execution.MockTracker{}
+	suite.mockTracker.On("Track", mock.Anything, mock.Anything)
+
+	mockPolicyEngine := &policy.MockEngine{}
+	mockPolicyEngine.On("Evaluate", mock.Anything, mock.Anything).Return([]policy.Denial(nil), nil)
+	mockPolicyEngine.On("Admit", mock.Anything, mock.Anything).Return([]policy.Denial(nil), nil)
+	mockPolicyEngine.On("Release", mock.Anything, mock.Anything).Return()
+
+	executioner := execution.NewExecutioner(suite.mockKubeClient, suite.mockMetadataStore, suite.mockSecretsStore, &approval.MockStore{}, suite.mockExecutionStore, suite.mockIdempotency, &queue.MockStore{}, suite.mockTracker, mockPolicyEngine, &ratelimit.MockLimiter{})
+	router := server.NewRouter(server.Handlers{
+		Health:      health.NewHealthHandler(map[string]health.Check{}, time.Second),
+		Executioner: executioner,
+		Logger:      logs.NewLogger(suite.mockKubeClient, suite.mockExecutionStore),
+		Metadata:    metadata.NewMetadataHandler(suite.mockMetadataStore, &registry.MockResolver{}),
+		Secrets:     secrets.NewSecretsHandler(suite.mockSecretsStore),
+		Policy:      policy.NewPolicyHandler(&policy.MockStore{}, mockPolicyEngine),
+		Approval:    approval.NewApprovalHandler(&approval.MockStore{}, executioner),
+		Workflow:    workflow.NewWorkflowHandler(&workflow.MockStore{}, suite.mockMetadataStore, &workflow.MockRunner{}),
+		Webhook:     webhook.NewWebhookHandler(&webhook.MockStore{}, &webhook.MockNotifier{}),
+	})
+
+	suite.requestHeaders = nil
+	suite.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		suite.requestHeaders = append(suite.requestHeaders, req.Header)
+		router.ServeHTTP(w, req)
+	}))
+	suite.testClient = NewClient(Config{
+		URL:         suite.server.URL,
+		EmailID:     "foo@bar.com",
+		AccessToken: "access-token",
+		RetryDelay:  time.Millisecond,
+	})
+}
+
+func (suite *ClientTestSuite) TearDownTest() {
+	suite.server.Close()
+}
+
+func (suite *ClientTestSuite) TestExecuteJob() {
+	t := suite.T()
+
+	jobMetadata := metadata.Metadata{Name: "vacuum", ImageName: "ops-toolbox"}
+	suite.mockMetadataStore.On("GetJobMetadata", "vacuum").Return(&jobMetadata, nil).Once()
+	suite.mockSecretsStore.On("GetJobSecrets", "vacuum").Return(map[string]string{"TOKEN": "secret"}, nil).Once()
+	spec := kubernetes.ExecutionSpec{ImageName: "ops-toolbox", EnvVars: map[string]string{"TABLE": "users", "TOKEN": "secret"}}
+	suite.mockKubeClient.On("ExecuteJob", mock.Anything, spec).Return("proctor-vacuum-1", nil).Once()
+
+	result, err := suite.testClient.ExecuteJob(context.Background(), execution.Job{Name: "vacuum", Args: map[string]string{"TABLE": "users"}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, Execution{Name: "proctor-vacuum-1"}, result)
+	suite.mockKubeClient.AssertExpectations(t)
+
+	assert.Len(t, suite.requestHeaders, 1)
+	assert.Equal(t, "foo@bar.com", suite.requestHeaders[0].Get(utility.UserEmailHeaderKey))
+	assert.Equal(t, "access-token", suite.requestHeaders[0].Get(AccessTokenHeaderKey))
+	assert.NotEmpty(t, suite.requestHeaders[0].Get(idempotency.HeaderKey))
+}
+
+func (suite *ClientTestSuite) TestExecuteJobRetriesWhenStoreIsUnavailable() {
+	t := suite.T()
+
+	jobMetadata := metadata.Metadata{Name: "vacuum", ImageName: "ops-toolbox"}
+	suite.mockMetadataStore.On("GetJobMetadata", "vacuum").Return((*metadata.Metadata)(nil), errors.New("redis down")).Once()
+	suite.mockMetadataStore.On("GetJobMetadata", "vacuum").Return(&jobMetadata, nil).Once()
+	suite.mockSecretsStore.On("GetJobSecrets", "vacuum").Return(map[string]string{}, nil).Once()
+	suite.mockKubeClient.On("ExecuteJob", mock.Anything, mock.Anything).Return("proctor-vacuum-1", nil).Once()
+
+	result, err := suite.testClient.ExecuteJob(context.Background(), execution.Job{Name: "vacuum"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "proctor-vacuum-1", result.Name)
+	assert.Len(t, suite.requestHeaders, 2)
+	assert.Equal(t, suite.requestHeaders[0].Get(idempotency.HeaderKey), suite.requestHeaders[1].Get(idempotency.HeaderKey))
+	suite.mockMetadataStore.AssertExpectations(t)
+}
+
+func (suite *ClientTestSuite) TestExecuteJobForUnknownJob() {
+	t := suite.T()
+
+	suite.mockMetadataStore.On("GetJobMetadata", "vacuum").Return((*metadata.Metadata)(nil), metadata.ErrJobNotFound).Once()
+
+	_, err := suite.testClient.ExecuteJob(context.Background(), execution.Job{Name: "vacuum"})
+
+	apiError, ok := err.(*Error)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusNotFound, apiError.StatusCode)
+	assert.Equal(t, utility.ErrCodeJobNotFound, apiError.Code)
+	assert.Len(t, suite.requestHeaders, 1)
+}
+
+func (suite *ClientTestSuite) TestExecuteJobGivesUpAfterMaxAttempts() {
+	t := suite.T()
+
+	suite.mockMetadataStore.On("GetJobMetadata", "vacuum").Return((*metadata.Metadata)(nil), errors.New("redis down"))
+
+	_, err := suite.testClient.ExecuteJob(context.Background(), execution.Job{Name: "vacuum"})
+
+	apiError, ok := err.(*Error)
+	assert.True(t, ok)
+	assert.Equal(t, utility.ErrCodeStoreUnavailable, apiError.Code)
+	assert.Len(t, suite.requestHeaders, defaultMaxAttempts)
+}
+
+func (suite *ClientTestSuite) TestExecuteJobWithCancelledContext() {
+	t := suite.T()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := suite.testClient.ExecuteJob(ctx, execution.Job{Name: "vacuum"})
+
+	assert.Equal(t, context.Canceled, err)
+	suite.mockMetadataStore.AssertNotCalled(t, "GetJobMetadata", mock.Anything)
+}
+
+func (suite *ClientTestSuite) TestListMetadata() {
+	t := suite.T()
+
+	jobMetadata := []metadata.Metadata{{Name: "vacuum", ImageName: "ops-toolbox"}}
+	suite.mockMetadataStore.On("GetAllJobsMetadata").Return(jobMetadata, nil).Once()
+
+	result, err := suite.testClient.ListMetadata(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, jobMetadata, result)
+}
+
+func (suite *ClientTestSuite) TestDescribeJob() {
+	t := suite.T()
+
+	jobMetadata := metadata.Metadata{Name: "vacuum", ImageName: "ops-toolbox"}
+	suite.mockMetadataStore.On("GetJobMetadata", "vacuum").Return(&jobMetadata, nil).Once()
+
+	result, err := suite.testClient.DescribeJob(context.Background(), "vacuum")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &jobMetadata, result)
+}
+
+func (suite *ClientTestSuite) TestSubmitMetadata() {
+	t := suite.T()
+
+	jobMetadata := metadata.Metadata{Name: "vacuum", ImageName: "ops-toolbox"}
+	suite.mockMetadataStore.On("CreateOrUpdateJobMetadata", jobMetadata).Return(nil).Once()
+
+	err := suite.testClient.SubmitMetadata(context.Background(), []metadata.Metadata{jobMetadata})
+
+	assert.NoError(t, err)
+	suite.mockMetadataStore.AssertExpectations(t)
+}
+
+func (suite *ClientTestSuite) TestSubmitMetadataWithValidationErrors() {
+	t := suite.T()
+
+	err := suite.testClient.SubmitMetadata(context.Background(), []metadata.Metadata{{Name: "vacuum"}})
+
+	apiError, ok := err.(*Error)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusUnprocessableEntity, apiError.StatusCode)
+	assert.Equal(t, utility.ErrCodeValidationFailed, apiError.Code)
+	assert.NotEmpty(t, apiError.Details)
+	suite.mockMetadataStore.AssertNotCalled(t, "CreateOrUpdateJobMetadata", mock.Anything)
+}
+
+func (suite *ClientTestSuite) TestSubmitSecrets() {
+	t := suite.T()
+
+	secret := secrets.Secret{JobName: "vacuum", Secrets: map[string]string{"TOKEN": "secret"}}
+	suite.mockSecretsStore.On("CreateOrUpdateJobSecret", secret).Return(nil).Once()
+
+	err := suite.testClient.SubmitSecrets(context.Background(), secret)
+
+	assert.NoError(t, err)
+	suite.mockSecretsStore.AssertExpectations(t)
+}
+
+func (suite *ClientTestSuite) TestExecutionStatus() {
+	t := suite.T()
+
+	suite.mockExecutionStore.On("GetExecution", "proctor-vacuum-1").Return(&execution.Execution{Name: "proctor-vacuum-1", JobName: "vacuum", Status: execution.StatusRunning}, nil).Once()
+
+	result, err := suite.testClient.ExecutionStatus(context.Background(), "proctor-vacuum-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "vacuum", result.JobName)
+	assert.Equal(t, execution.StatusRunning, result.Status)
+}
+
+func (suite *ClientTestSuite) TestCancelExecution() {
+	t := suite.T()
+
+	suite.mockExecutionStore.On("GetExecution", "proctor-vacuum-1").Return(&execution.Execution{Name: "proctor-vacuum-1", Status: execution.StatusRunning}, nil).Once()
+	suite.mockKubeClient.On("DeleteJob", kubernetes.Target{}, "proctor-vacuum-1").Return(nil).Once()
+	suite.mockExecutionStore.On("SaveExecution", mock.Anything).Return(nil).Once()
+
+	result, err := suite.testClient.CancelExecution(context.Background(), "proctor-vacuum-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, execution.StatusCancelled, result.Status)
+	suite.mockKubeClient.AssertExpectations(t)
+}
+
+func (suite *ClientTestSuite) TestCancelFinishedExecution() {
+	t := suite.T()
+
+	finishedAt := time.Now()
+	suite.mockExecutionStore.On("GetExecution", "proctor-vacuum-1").Return(&execution.Execution{Name: "proctor-vacuum-1", Status: execution.StatusSucceeded, FinishedAt: &finishedAt}, nil).Once()
+
+	_, err := suite.testClient.CancelExecution(context.Background(), "proctor-vacuum-1")
+
+	apiError, ok := err.(*Error)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusConflict, apiError.StatusCode)
+	assert.Equal(t, utility.ErrCodeExecutionFinished, apiError.Code)
+	suite.mockKubeClient.AssertNotCalled(t, "DeleteJob", mock.Anything, mock.Anything)
+}
+
+func (suite *ClientTestSuite) TestStreamLogs() {
+	t := suite.T()
+
+	buffer := utility.NewBuffer()
+	buffer.Write([]byte("first line\nsecond line\n"))
+	suite.mockExecutionStore.On("GetExecution", "proctor-vacuum-1").Return((*execution.Execution)(nil), execution.ErrExecutionNotFound).Once()
+	suite.mockKubeClient.On("StreamJobLogs", mock.Anything, kubernetes.Target{}, "proctor-vacuum-1").Return(buffer, nil).Once()
+
+	stream, err := suite.testClient.StreamLogs(context.Background(), "proctor-vacuum-1")
+	assert.NoError(t, err)
+	defer stream.Close()
+
+	output, err := ioutil.ReadAll(stream)
+	assert.NoError(t, err)
+	assert.Equal(t, "first line\nsecond line\n", string(output))
+	assert.Equal(t, "foo@bar.com", suite.requestHeaders[0].Get(utility.UserEmailHeaderKey))
+}
+
+func TestClientTestSuite(t *testing.T) {
+	suite.Run(t, new(ClientTestSuite))
+}
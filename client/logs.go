@@ -0,0 +1,75 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+type logStream struct {
+	conn   *websocket.Conn
+	reader *io.PipeReader
+	done   chan struct{}
+	once   sync.Once
+}
+
+func (client *client) StreamLogs(ctx context.Context, executionName string) (io.ReadCloser, error) {
+	header := http.Header{}
+	client.authenticate(header)
+
+	streamURL := "ws" + strings.TrimPrefix(client.baseURL, "http") + apiPrefix + "/executions/" + url.PathEscape(executionName) + "/logs"
+	conn, resp, err := websocket.DefaultDialer.Dial(streamURL, header)
+	if err != nil {
+		if resp != nil && resp.Body != nil {
+			return nil, responseError(resp)
+		}
+		return nil, err
+	}
+
+	reader, writer := io.Pipe()
+	stream := &logStream{conn: conn, reader: reader, done: make(chan struct{})}
+	go stream.copy(writer)
+	go stream.closeOnCancel(ctx)
+	return stream, nil
+}
+
+func (stream *logStream) copy(writer *io.PipeWriter) {
+	for {
+		_, message, err := stream.conn.ReadMessage()
+		if websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+			writer.Close()
+			return
+		}
+		if err != nil {
+			writer.CloseWithError(err)
+			return
+		}
+		if _, err := writer.Write(append(message, '\n')); err != nil {
+			return
+		}
+	}
+}
+
+func (stream *logStream) closeOnCancel(ctx context.Context) {
+	select {
+	case <-ctx.Done():
+		stream.reader.CloseWithError(ctx.Err())
+		stream.conn.Close()
+	case <-stream.done:
+	}
+}
+
+func (stream *logStream) Read(p []byte) (int, error) {
+	return stream.reader.Read(p)
+}
+
+func (stream *logStream) Close() error {
+	stream.once.Do(func() { close(stream.done) })
+	stream.reader.Close()
+	return stream.conn.Close()
+}
@@ -0,0 +1,263 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gojektech/proctor-engine/client"
+	"github.com/gojektech/proctor-engine/jobs/execution"
+	"github.com/gojektech/proctor-engine/jobs/metadata"
+
+	"github.com/urfave/cli"
+)
+
+var newClient = client.NewClient
+
+var clientFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:   "profile, p",
+		Usage:  "engine profile to use from the profiles file",
+		EnvVar: "PROCTOR_PROFILE",
+	},
+	cli.StringFlag{
+		Name:  "output, o",
+		Usage: "output format: table, json or yaml",
+		Value: formatTable,
+	},
+}
+
+type commandFunc func(ctx context.Context, c *cli.Context, proctor client.Client, format string) error
+
+func Commands() []cli.Command {
+	return []cli.Command{
+		{
+			Name:   "list",
+			Usage:  "list jobs",
+			Flags:  clientFlags,
+			Action: action(0, list),
+		},
+		{
+			Name:      "describe",
+			Usage:     "describe a job",
+			ArgsUsage: "<job>",
+			Flags:     clientFlags,
+			Action:    action(1, describe),
+		},
+		{
+			Name:      "execute",
+			Usage:     "execute a job",
+			ArgsUsage: "<job> [KEY=VALUE...]",
+			Flags: append([]cli.Flag{
+				cli.BoolFlag{Name: "follow, f", Usage: "stream logs of the execution until it finishes"},
+			}, clientFlags...),
+			Action: action(1, execute),
+		},
+		{
+			Name:      "logs",
+			Usage:     "stream logs of an execution",
+			ArgsUsage: "<execution>",
+			Flags:     clientFlags,
+			Action:    action(1, streamLogs),
+		},
+		{
+			Name:      "status",
+			Usage:     "show the status of an execution",
+			ArgsUsage: "<execution>",
+			Flags:     clientFlags,
+			Action:    action(1, status),
+		},
+		{
+			Name:      "cancel",
+			Usage:     "cancel a running execution",
+			ArgsUsage: "<execution>",
+			Flags:     clientFlags,
+			Action:    action(1, cancelExecution),
+		},
+	}
+}
+
+func action(minArgs int, run commandFunc) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		if c.NArg() < minArgs {
+			return cli.NewExitError(fmt.Sprintf("usage: %s %s %s", c.App.Name, c.Command.Name, c.Command.ArgsUsage), 1)
+		}
+		format := c.String("output")
+		if err := validFormat(format); err != nil {
+			return cli.NewExitError(err.Error(), 1)
+		}
+
+		profiles, err := LoadProfiles(profilesPath())
+		if err != nil {
+			return cli.NewExitError(err.Error(), 1)
+		}
+		profile, err := profiles.Profile(c.String("profile"))
+		if err != nil {
+			return cli.NewExitError(err.Error(), 1)
+		}
+		proctor := newClient(client.Config{URL: profile.URL, EmailID: profile.EmailID, AccessToken: profile.AccessToken})
+
+		ctx, cancel := interruptible()
+		defer cancel()
+		if err := run(ctx, c, proctor, format); err != nil {
+			return cli.NewExitError(err.Error(), 1)
+		}
+		return nil
+	}
+}
+
+func interruptible() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	go func() {
+		select {
+		case <-interrupts:
+			cancel()
+		case <-ctx.Done():
+		}
+		signal.Stop(interrupts)
+	}()
+	return ctx, cancel
+}
+
+func list(ctx context.Context, c *cli.Context, proctor client.Client, format string) error {
+	jobMetadata, err := proctor.ListMetadata(ctx)
+	if err != nil {
+		return err
+	}
+	sort.Slice(jobMetadata, func(i, j int) bool { return jobMetadata[i].Name < jobMetadata[j].Name })
+
+	t := table{headers: []string{"NAME", "IMAGE", "DESCRIPTION"}}
+	for _, job := range jobMetadata {
+		t.add(job.Name, job.ImageName, job.Description)
+	}
+	return render(c.App.Writer, format, jobMetadata, t)
+}
+
+func describe(ctx context.Context, c *cli.Context, proctor client.Client, format string) error {
+	job, err := proctor.DescribeJob(ctx, c.Args().First())
+	if err != nil {
+		return err
+	}
+
+	t := table{}
+	t.add("NAME", job.Name)
+	t.add("DESCRIPTION", job.Description)
+	t.add("IMAGE", imageOf(job))
+	t.add("TARGET", targetOf(job.Cluster, job.Namespace))
+	for _, arg := range job.EnvVars.Args {
+		t.add("ARG "+arg.Name, arg.Description)
+	}
+	for _, secret := range job.EnvVars.Secrets {
+		t.add("SECRET "+secret.Name, secret.Description)
+	}
+	if job.RequiredApprovals > 0 {
+		t.add("REQUIRED APPROVALS", strconv.Itoa(job.RequiredApprovals))
+	}
+	if job.MaxConcurrent > 0 {
+		t.add("MAX CONCURRENT", strconv.Itoa(job.MaxConcurrent))
+	}
+	return render(c.App.Writer, format, job, t)
+}
+
+func execute(ctx context.Context, c *cli.Context, proctor client.Client, format string) error {
+	job := execution.Job{Name: c.Args().First(), Args: map[string]string{}}
+	for _, arg := range c.Args().Tail() {
+		parts := strings.SplitN(arg, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return fmt.Errorf("argument %s must be of the form KEY=VALUE", arg)
+		}
+		job.Args[parts[0]] = parts[1]
+	}
+
+	result, err := proctor.ExecuteJob(ctx, job)
+	if err != nil {
+		return err
+	}
+
+	t := table{}
+	switch {
+	case result.ApprovalRequestID != "":
+		t.add("APPROVAL REQUEST", result.ApprovalRequestID)
+	case result.Queue != nil:
+		t.add("QUEUE ID", result.Queue.ID)
+		t.add("POSITION", strconv.Itoa(result.Queue.Position))
+		t.add("ETA", (time.Duration(result.Queue.ETASeconds) * time.Second).String())
+	default:
+		t.add("EXECUTION", result.Name)
+	}
+	if err := render(c.App.Writer, format, result, t); err != nil {
+		return err
+	}
+
+	if !c.Bool("follow") || result.Name == "" {
+		return nil
+	}
+	return follow(ctx, c.App.Writer, proctor, result.Name)
+}
+
+func streamLogs(ctx context.Context, c *cli.Context, proctor client.Client, format string) error {
+	return follow(ctx, c.App.Writer, proctor, c.Args().First())
+}
+
+func follow(ctx context.Context, w io.Writer, proctor client.Client, executionName string) error {
+	logs, err := proctor.StreamLogs(ctx, executionName)
+	if err != nil {
+		return err
+	}
+	defer logs.Close()
+
+	_, err = io.Copy(w, logs)
+	return err
+}
+
+func status(ctx context.Context, c *cli.Context, proctor client.Client, format string) error {
+	jobExecution, err := proctor.ExecutionStatus(ctx, c.Args().First())
+	if err != nil {
+		return err
+	}
+	return render(c.App.Writer, format, jobExecution, executionTable(jobExecution))
+}
+
+func cancelExecution(ctx context.Context, c *cli.Context, proctor client.Client, format string) error {
+	jobExecution, err := proctor.CancelExecution(ctx, c.Args().First())
+	if err != nil {
+		return err
+	}
+	return render(c.App.Writer, format, jobExecution, executionTable(jobExecution))
+}
+
+func executionTable(jobExecution *execution.Execution) table {
+	exitCode, finishedAt := "", ""
+	if jobExecution.ExitCode != nil {
+		exitCode = strconv.Itoa(int(*jobExecution.ExitCode))
+	}
+	if jobExecution.FinishedAt != nil {
+		finishedAt = jobExecution.FinishedAt.Format(time.RFC3339)
+	}
+
+	t := table{headers: []string{"NAME", "JOB", "STATUS", "EXIT CODE", "STARTED", "FINISHED"}}
+	t.add(jobExecution.Name, jobExecution.JobName, jobExecution.Status, exitCode, jobExecution.StartedAt.Format(time.RFC3339), finishedAt)
+	return t
+}
+
+func imageOf(job *metadata.Metadata) string {
+	if job.ImageDigest == "" {
+		return job.ImageName
+	}
+	return job.ImageName + "@" + job.ImageDigest
+}
+
+func targetOf(cluster, namespace string) string {
+	if cluster == "" && namespace == "" {
+		return "default"
+	}
+	return strings.Trim(cluster+"/"+namespace, "/")
+}
@@ -0,0 +1,201 @@
+package command
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gojektech/proctor-engine/client"
+	"github.com/gojektech/proctor-engine/jobs/execution"
+	"github.com/gojektech/proctor-engine/jobs/metadata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/urfave/cli"
+)
+
+type CommandsTestSuite struct {
+	suite.Suite
+	mockClient   *client.MockClient
+	clientConfig client.Config
+	profilesPath string
+	output       *bytes.Buffer
+}
+
+func (suite *CommandsTestSuite) SetupTest() {
+	suite.profilesPath = writeProfiles(suite.T(), `
+current_profile: staging
+profiles:
+  staging:
+    url: https://proctor.staging.example.com
+    email_id: foo@bar.com
+  production:
+    url: https://proctor.example.com
+`)
+	os.Setenv(ProfilesPathEnv, suite.profilesPath)
+
+	suite.mockClient = &client.MockClient{}
+	newClient = func(config client.Config) client.Client {
+		suite.clientConfig = config
+		return suite.mockClient
+	}
+
+	suite.output = &bytes.Buffer{}
+	cli.OsExiter = func(int) {}
+	cli.ErrWriter = ioutil.Discard
+}
+
+func (suite *CommandsTestSuite) TearDownTest() {
+	os.RemoveAll(filepath.Dir(suite.profilesPath))
+	os.Unsetenv(ProfilesPathEnv)
+	newClient = client.NewClient
+}
+
+func (suite *CommandsTestSuite) run(args ...string) error {
+	app := cli.NewApp()
+	app.Name = "proctor"
+	app.Writer = suite.output
+	app.Commands = Commands()
+	return app.Run(append([]string{"proctor"}, args...))
+}
+
+func (suite *CommandsTestSuite) TestList() {
+	t := suite.T()
+
+	jobMetadata := []metadata.Metadata{
+		{Name: "vacuum", ImageName: "ops-toolbox", Description: "vacuum tables"},
+		{Name: "backup", ImageName: "ops-backup", Description: "back up tables"},
+	}
+	suite.mockClient.On("ListMetadata", mock.Anything).Return(jobMetadata, nil).Once()
+
+	err := suite.run("list")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "https://proctor.staging.example.com", suite.clientConfig.URL)
+	assert.Equal(t, "foo@bar.com", suite.clientConfig.EmailID)
+	assert.Equal(t, ""+
+		"NAME    IMAGE        DESCRIPTION\n"+
+		"backup  ops-backup   back up tables\n"+
+		"vacuum  ops-toolbox  vacuum tables\n", suite.output.String())
+}
+
+func (suite *CommandsTestSuite) TestListWithProfileAndJSONOutput() {
+	t := suite.T()
+
+	suite.mockClient.On("ListMetadata", mock.Anything).Return([]metadata.Metadata{{Name: "vacuum", ImageName: "ops-toolbox"}}, nil).Once()
+
+	err := suite.run("list", "--profile", "production", "-o", "json")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "https://proctor.example.com", suite.clientConfig.URL)
+	assert.Contains(t, suite.output.String(), `"image_name": "ops-toolbox"`)
+}
+
+func (suite *CommandsTestSuite) TestListWithUnknownOutputFormat() {
+	t := suite.T()
+
+	err := suite.run("list", "-o", "xml")
+
+	assert.EqualError(t, err, "output must be one of table, json or yaml")
+	suite.mockClient.AssertNotCalled(t, "ListMetadata", mock.Anything)
+}
+
+func (suite *CommandsTestSuite) TestDescribeWithYAMLOutput() {
+	t := suite.T()
+
+	job := &metadata.Metadata{Name: "vacuum", ImageName: "ops-toolbox", RequiredApprovals: 1}
+	suite.mockClient.On("DescribeJob", mock.Anything, "vacuum").Return(job, nil).Once()
+
+	err := suite.run("describe", "-o", "yaml", "vacuum")
+
+	assert.NoError(t, err)
+	assert.Contains(t, suite.output.String(), "image_name: ops-toolbox\n")
+	assert.Contains(t, suite.output.String(), "required_approvals: 1\n")
+}
+
+func (suite *CommandsTestSuite) TestDescribeWithoutJobName() {
+	t := suite.T()
+
+	err := suite.run("describe")
+
+	assert.EqualError(t, err, "usage: proctor describe <job>")
+}
+
+func (suite *CommandsTestSuite) TestExecuteWithFollow() {
+	t := suite.T()
+
+	job := execution.Job{Name: "vacuum", Args: map[string]string{"TABLE": "users", "WHERE": "id=1"}}
+	suite.mockClient.On("ExecuteJob", mock.Anything, job).Return(client.Execution{Name: "proctor-vacuum-1"}, nil).Once()
+	suite.mockClient.On("StreamLogs", mock.Anything, "proctor-vacuum-1").Return(ioutil.NopCloser(strings.NewReader("vacuumed users\n")), nil).Once()
+
+	err := suite.run("execute", "--follow", "vacuum", "TABLE=users", "WHERE=id=1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "EXECUTION  proctor-vacuum-1\nvacuumed users\n", suite.output.String())
+}
+
+func (suite *CommandsTestSuite) TestExecuteAwaitingApprovalDoesNotFollow() {
+	t := suite.T()
+
+	suite.mockClient.On("ExecuteJob", mock.Anything, mock.Anything).Return(client.Execution{ApprovalRequestID: "request-1"}, nil).Once()
+
+	err := suite.run("execute", "--follow", "vacuum")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "APPROVAL REQUEST  request-1\n", suite.output.String())
+	suite.mockClient.AssertNotCalled(t, "StreamLogs", mock.Anything, mock.Anything)
+}
+
+func (suite *CommandsTestSuite) TestExecuteWithMalformedArgument() {
+	t := suite.T()
+
+	err := suite.run("execute", "vacuum", "TABLE")
+
+	assert.EqualError(t, err, "argument TABLE must be of the form KEY=VALUE")
+	suite.mockClient.AssertNotCalled(t, "ExecuteJob", mock.Anything, mock.Anything)
+}
+
+func (suite *CommandsTestSuite) TestLogs() {
+	t := suite.T()
+
+	suite.mockClient.On("StreamLogs", mock.Anything, "proctor-vacuum-1").Return(ioutil.NopCloser(strings.NewReader("first\nsecond\n")), nil).Once()
+
+	err := suite.run("logs", "proctor-vacuum-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "first\nsecond\n", suite.output.String())
+}
+
+func (suite *CommandsTestSuite) TestStatus() {
+	t := suite.T()
+
+	startedAt := time.Date(2018, 10, 1, 10, 0, 0, 0, time.UTC)
+	jobExecution := &execution.Execution{Name: "proctor-vacuum-1", JobName: "vacuum", Status: execution.StatusRunning, StartedAt: startedAt}
+	suite.mockClient.On("ExecutionStatus", mock.Anything, "proctor-vacuum-1").Return(jobExecution, nil).Once()
+
+	err := suite.run("status", "proctor-vacuum-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, ""+
+		"NAME              JOB     STATUS   EXIT CODE  STARTED               FINISHED\n"+
+		"proctor-vacuum-1  vacuum  RUNNING             2018-10-01T10:00:00Z  \n", suite.output.String())
+}
+
+func (suite *CommandsTestSuite) TestCancelFailure() {
+	t := suite.T()
+
+	suite.mockClient.On("CancelExecution", mock.Anything, "proctor-vacuum-1").Return((*execution.Execution)(nil), errors.New("proctor: execution_finished (409): execution proctor-vacuum-1 has already finished")).Once()
+
+	err := suite.run("cancel", "proctor-vacuum-1")
+
+	assert.EqualError(t, err, "proctor: execution_finished (409): execution proctor-vacuum-1 has already finished")
+}
+
+func TestCommandsTestSuite(t *testing.T) {
+	suite.Run(t, new(CommandsTestSuite))
+}
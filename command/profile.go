@@ -0,0 +1,78 @@
+package command
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+)
+
+const (
+	ProfilesPathEnv = "PROCTOR_PROFILES"
+	defaultProfile  = "default"
+)
+
+type Profile struct {
+	URL         string `json:"url"`
+	EmailID     string `json:"email_id,omitempty"`
+	AccessToken string `json:"access_token,omitempty"`
+}
+
+type Profiles struct {
+	Current  string             `json:"current_profile,omitempty"`
+	Profiles map[string]Profile `json:"profiles"`
+}
+
+func profilesPath() string {
+	if path := os.Getenv(ProfilesPathEnv); path != "" {
+		return path
+	}
+	return filepath.Join(os.Getenv("HOME"), ".proctor", "profiles.yaml")
+}
+
+func LoadProfiles(path string) (Profiles, error) {
+	var profiles Profiles
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return profiles, nil
+	}
+	if err != nil {
+		return profiles, err
+	}
+
+	if err := yaml.Unmarshal(content, &profiles); err != nil {
+		return profiles, fmt.Errorf("invalid profiles file %s: %s", path, err)
+	}
+	return profiles, nil
+}
+
+func (profiles Profiles) Profile(name string) (Profile, error) {
+	if name == "" {
+		name = profiles.Current
+	}
+	if name == "" {
+		name = defaultProfile
+	}
+
+	profile, ok := profiles.Profiles[name]
+	if !ok {
+		return profile, fmt.Errorf("profile %s not found, configured profiles: [%s]", name, strings.Join(profiles.names(), ", "))
+	}
+	if profile.URL == "" {
+		return profile, fmt.Errorf("profile %s has no url", name)
+	}
+	return profile, nil
+}
+
+func (profiles Profiles) names() []string {
+	var names []string
+	for name := range profiles.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
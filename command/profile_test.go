@@ -0,0 +1,73 @@
+package command
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeProfiles(t *testing.T, content string) string {
+	dir, err := ioutil.TempDir("", "proctor-profiles")
+	assert.NoError(t, err)
+	path := filepath.Join(dir, "profiles.yaml")
+	assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestLoadProfiles(t *testing.T) {
+	path := writeProfiles(t, `
+current_profile: staging
+profiles:
+  staging:
+    url: https://proctor.staging.example.com
+    email_id: foo@bar.com
+    access_token: token
+  production:
+    url: https://proctor.example.com
+`)
+	defer os.RemoveAll(filepath.Dir(path))
+
+	profiles, err := LoadProfiles(path)
+	assert.NoError(t, err)
+
+	profile, err := profiles.Profile("")
+	assert.NoError(t, err)
+	assert.Equal(t, Profile{URL: "https://proctor.staging.example.com", EmailID: "foo@bar.com", AccessToken: "token"}, profile)
+
+	profile, err = profiles.Profile("production")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://proctor.example.com", profile.URL)
+}
+
+func TestLoadProfilesWithoutFile(t *testing.T) {
+	profiles, err := LoadProfiles(filepath.Join(os.TempDir(), "proctor-profiles-missing.yaml"))
+	assert.NoError(t, err)
+
+	_, err = profiles.Profile("")
+	assert.EqualError(t, err, "profile default not found, configured profiles: []")
+}
+
+func TestLoadProfilesWithMalformedFile(t *testing.T) {
+	path := writeProfiles(t, "profiles: [")
+	defer os.RemoveAll(filepath.Dir(path))
+
+	_, err := LoadProfiles(path)
+	assert.Error(t, err)
+}
+
+func TestProfileWithoutURL(t *testing.T) {
+	profiles := Profiles{Profiles: map[string]Profile{"default": {EmailID: "foo@bar.com"}}}
+
+	_, err := profiles.Profile("")
+	assert.EqualError(t, err, "profile default has no url")
+}
+
+func TestUnknownProfile(t *testing.T) {
+	profiles := Profiles{Profiles: map[string]Profile{"staging": {URL: "a"}, "production": {URL: "b"}}}
+
+	_, err := profiles.Profile("dev")
+	assert.EqualError(t, err, "profile dev not found, configured profiles: [production, staging]")
+}
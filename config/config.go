@@ -1,10 +1,138 @@
 package config
 
-import "github.com/spf13/viper"
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const FileEnv = "PROCTOR_CONFIG_FILE"
+
+var fileErr error
 
 func init() {
+	setup()
+}
+
+func setup() {
 	viper.AutomaticEnv()
 	viper.SetEnvPrefix("PROCTOR")
+	setDefaults()
+
+	fileErr = nil
+	if path := os.Getenv(FileEnv); path != "" {
+		fileErr = Load(path)
+	}
+}
+
+func setDefaults() {
+	viper.SetDefault("LOG_LEVEL", "info")
+	viper.SetDefault("APP_PORT", "5000")
+	viper.SetDefault("DEFAULT_NAMESPACE", "default")
+	viper.SetDefault("REDIS_ADDRESS", "localhost:6379")
+	viper.SetDefault("REDIS_MAX_ACTIVE_CONNECTIONS", 10)
+	viper.SetDefault("LOGS_STREAM_READ_BUFFER_SIZE", 140)
+	viper.SetDefault("LOGS_STREAM_WRITE_BUFFER_SIZE", 4096)
+	viper.SetDefault("KUBE_POD_LIST_WAIT_TIME", "5s")
+	viper.SetDefault("KUBE_JOB_ACTIVE_DEADLINE_SECONDS", 3600)
+	viper.SetDefault("EXECUTION_REQUEST_EXPIRY_SECONDS", 86400)
+	viper.SetDefault("READINESS_CHECK_CACHE_SECONDS", 5)
+	viper.SetDefault("WEBHOOKS_ENABLED", true)
+	viper.SetDefault("WEBHOOK_MAX_ATTEMPTS", 5)
+	viper.SetDefault("WEBHOOK_RETRY_INITIAL_DELAY_MILLISECONDS", 1000)
+	viper.SetDefault("WEBHOOK_DEAD_LETTER_EXPIRY_SECONDS", 604800)
+	viper.SetDefault("IDEMPOTENCY_KEY_EXPIRY_SECONDS", 86400)
+	viper.SetDefault("QUEUE_DISPATCH_INTERVAL_SECONDS", 5)
+	viper.SetDefault("RUNNING_SLOT_LEASE_SECONDS", 300)
+	viper.SetDefault("WORKFLOW_RUN_LEASE_SECONDS", 60)
+	viper.SetDefault("FILE_INPUT_MAX_BYTES", 1048576)
+	viper.SetDefault("REQUEST_BODY_MAX_BYTES", 1048576)
+	viper.SetDefault("POLICY_TIMEZONE", "UTC")
+}
+
+// Load reads settings from a YAML, TOML or JSON file, picked by extension.
+// PROCTOR_* environment variables still take precedence over the file.
+func Load(path string) error {
+	viper.SetConfigFile(path)
+	if err := viper.ReadInConfig(); err != nil {
+		return fmt.Errorf("reading config file %s: %s", path, err)
+	}
+	return nil
+}
+
+// Durations accept either a plain integer in the unit named by the setting
+// (e.g. 300 for *_SECONDS) or a Go duration string such as "5m".
+func parseDuration(key string, unit time.Duration) (time.Duration, error) {
+	raw := strings.TrimSpace(viper.GetString(key))
+	if raw == "" {
+		return 0, nil
+	}
+	if count, err := strconv.Atoi(raw); err == nil {
+		return time.Duration(count) * unit, nil
+	}
+	return time.ParseDuration(raw)
+}
+
+func duration(key string, unit time.Duration) time.Duration {
+	value, _ := parseDuration(key, unit)
+	return value
+}
+
+// Settings holding JSON documents can be written as nested YAML or TOML in
+// a config file; they are handed to callers as JSON either way.
+func structured(key string) (string, error) {
+	value := viper.Get(key)
+	if value == nil {
+		return "", nil
+	}
+	if raw, ok := value.(string); ok {
+		return raw, nil
+	}
+	content, err := json.Marshal(jsonCompatible(value))
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+func jsonCompatible(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[interface{}]interface{}:
+		converted := map[string]interface{}{}
+		for k, v := range value {
+			converted[fmt.Sprint(k)] = jsonCompatible(v)
+		}
+		return converted
+	case map[string]interface{}:
+		converted := map[string]interface{}{}
+		for k, v := range value {
+			converted[k] = jsonCompatible(v)
+		}
+		return converted
+	case []interface{}:
+		converted := make([]interface{}, len(value))
+		for i, v := range value {
+			converted[i] = jsonCompatible(v)
+		}
+		return converted
+	case []map[string]interface{}:
+		converted := make([]interface{}, len(value))
+		for i, v := range value {
+			converted[i] = jsonCompatible(v)
+		}
+		return converted
+	}
+	return value
+}
+
+func structuredString(key string) string {
+	value, _ := structured(key)
+	return value
 }
 
 func Environment() string {
@@ -43,11 +171,179 @@ func LogsStreamWriteBufferSize() int {
 	return viper.GetInt("LOGS_STREAM_WRITE_BUFFER_SIZE")
 }
 
-func KubePodsListWaitTime() int {
-	return viper.GetInt("KUBE_POD_LIST_WAIT_TIME")
+func KubePodsListWaitTime() time.Duration {
+	return duration("KUBE_POD_LIST_WAIT_TIME", time.Second)
 }
 
 func KubeJobActiveDeadlineSeconds() *int64 {
 	tmp := viper.GetInt64("KUBE_JOB_ACTIVE_DEADLINE_SECONDS")
 	return &tmp
 }
+
+func ExecutionRequestExpiry() time.Duration {
+	return duration("EXECUTION_REQUEST_EXPIRY_SECONDS", time.Second)
+}
+
+func ReadinessCheckCache() time.Duration {
+	return duration("READINESS_CHECK_CACHE_SECONDS", time.Second)
+}
+
+func PublicURL() string {
+	return viper.GetString("PUBLIC_URL")
+}
+
+func WebhooksEnabled() bool {
+	return viper.GetBool("WEBHOOKS_ENABLED")
+}
+
+func WebhookSigningSecret() string {
+	return viper.GetString("WEBHOOK_SIGNING_SECRET")
+}
+
+func WebhookAllowedHosts() string {
+	return viper.GetString("WEBHOOK_ALLOWED_HOSTS")
+}
+
+func WebhookMaxAttempts() int {
+	return viper.GetInt("WEBHOOK_MAX_ATTEMPTS")
+}
+
+func WebhookRetryInitialDelay() time.Duration {
+	return duration("WEBHOOK_RETRY_INITIAL_DELAY_MILLISECONDS", time.Millisecond)
+}
+
+func WebhookDeadLetterExpiry() time.Duration {
+	return duration("WEBHOOK_DEAD_LETTER_EXPIRY_SECONDS", time.Second)
+}
+
+func ExecutionRecordExpiry() time.Duration {
+	return duration("EXECUTION_RECORD_EXPIRY_SECONDS", time.Second)
+}
+
+func ReaperInterval() time.Duration {
+	return duration("REAPER_INTERVAL_SECONDS", time.Second)
+}
+
+func SucceededJobRetention() time.Duration {
+	return duration("SUCCEEDED_JOB_RETENTION_SECONDS", time.Second)
+}
+
+func FailedJobRetention() time.Duration {
+	return duration("FAILED_JOB_RETENTION_SECONDS", time.Second)
+}
+
+func IdempotencyKeyExpiry() time.Duration {
+	return duration("IDEMPOTENCY_KEY_EXPIRY_SECONDS", time.Second)
+}
+
+func MaxConcurrentExecutions() int {
+	return viper.GetInt("MAX_CONCURRENT_EXECUTIONS")
+}
+
+func QueueDispatchInterval() time.Duration {
+	return duration("QUEUE_DISPATCH_INTERVAL_SECONDS", time.Second)
+}
+
+func RunningSlotLease() time.Duration {
+	return duration("RUNNING_SLOT_LEASE_SECONDS", time.Second)
+}
+
+func WorkflowRunLease() time.Duration {
+	return duration("WORKFLOW_RUN_LEASE_SECONDS", time.Second)
+}
+
+func QueueEncryptionKey() string {
+	return viper.GetString("QUEUE_ENCRYPTION_KEY")
+}
+
+func JobNodeSelector() string {
+	return viper.GetString("JOB_NODE_SELECTOR")
+}
+
+func JobTolerations() string {
+	return structuredString("JOB_TOLERATIONS")
+}
+
+func JobAffinity() string {
+	return structuredString("JOB_AFFINITY")
+}
+
+func JobServiceAccountName() string {
+	return viper.GetString("JOB_SERVICE_ACCOUNT_NAME")
+}
+
+func JobImagePullSecrets() string {
+	return viper.GetString("JOB_IMAGE_PULL_SECRETS")
+}
+
+func JobPriorityClassName() string {
+	return viper.GetString("JOB_PRIORITY_CLASS_NAME")
+}
+
+func JobRunAsNonRoot() bool {
+	return viper.GetBool("JOB_RUN_AS_NON_ROOT")
+}
+
+func JobReadOnlyRootFilesystem() bool {
+	return viper.GetBool("JOB_READ_ONLY_ROOT_FILESYSTEM")
+}
+
+func FileInputMaxBytes() int64 {
+	return viper.GetInt64("FILE_INPUT_MAX_BYTES")
+}
+
+func RequestBodyMaxBytes() int64 {
+	return viper.GetInt64("REQUEST_BODY_MAX_BYTES")
+}
+
+func KubeClusters() string {
+	return structuredString("KUBE_CLUSTERS")
+}
+
+func KubeAllowedNamespaces() string {
+	return viper.GetString("KUBE_ALLOWED_NAMESPACES")
+}
+
+func ImageAllowedPatterns() string {
+	return viper.GetString("IMAGE_ALLOWED_PATTERNS")
+}
+
+func ImageRequireDigest() bool {
+	return viper.GetBool("IMAGE_REQUIRE_DIGEST")
+}
+
+func ImageResolveDigests() bool {
+	return viper.GetBool("IMAGE_RESOLVE_DIGESTS")
+}
+
+func ImageInsecureRegistries() string {
+	return viper.GetString("IMAGE_INSECURE_REGISTRIES")
+}
+
+func PolicyRules() string {
+	return structuredString("POLICY_RULES")
+}
+
+func PolicyTimezone() string {
+	return viper.GetString("POLICY_TIMEZONE")
+}
+
+func PolicyAdminToken() string {
+	return viper.GetString("POLICY_ADMIN_TOKEN")
+}
+
+func RateLimitRequestsPerSecond() float64 {
+	return viper.GetFloat64("RATE_LIMIT_REQUESTS_PER_SECOND")
+}
+
+func RateLimitBurst() int {
+	return viper.GetInt("RATE_LIMIT_BURST")
+}
+
+func RateLimitAddressRequestsPerSecond() float64 {
+	return viper.GetFloat64("RATE_LIMIT_ADDRESS_REQUESTS_PER_SECOND")
+}
+
+func RateLimitAddressBurst() int {
+	return viper.GetInt("RATE_LIMIT_ADDRESS_BURST")
+}
@@ -1,8 +1,11 @@
 package config
 
 import (
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
@@ -80,6 +83,14 @@ func TestLogsStreamWriteBufferSize(t *testing.T) {
 	assert.Equal(t, 4096, LogsStreamWriteBufferSize())
 }
 
+func TestKubePodsListWaitTime(t *testing.T) {
+	os.Setenv("PROCTOR_KUBE_POD_LIST_WAIT_TIME", "2m")
+
+	viper.AutomaticEnv()
+
+	assert.Equal(t, 2*time.Minute, KubePodsListWaitTime())
+}
+
 func TestKubeJobActiveDeadlineSeconds(t *testing.T) {
 	os.Setenv("PROCTOR_KUBE_JOB_ACTIVE_DEADLINE_SECONDS", "900")
 
@@ -88,3 +99,395 @@ func TestKubeJobActiveDeadlineSeconds(t *testing.T) {
 	expectedValue := int64(900)
 	assert.Equal(t, &expectedValue, KubeJobActiveDeadlineSeconds())
 }
+
+func TestExecutionRequestExpiry(t *testing.T) {
+	os.Setenv("PROCTOR_EXECUTION_REQUEST_EXPIRY_SECONDS", "3600")
+
+	viper.AutomaticEnv()
+
+	assert.Equal(t, time.Hour, ExecutionRequestExpiry())
+}
+
+func TestReadinessCheckCache(t *testing.T) {
+	os.Setenv("PROCTOR_READINESS_CHECK_CACHE_SECONDS", "5")
+
+	viper.AutomaticEnv()
+
+	assert.Equal(t, 5*time.Second, ReadinessCheckCache())
+}
+
+func TestPublicURL(t *testing.T) {
+	os.Setenv("PROCTOR_PUBLIC_URL", "https://proctor.example.com")
+
+	viper.AutomaticEnv()
+
+	assert.Equal(t, "https://proctor.example.com", PublicURL())
+}
+
+func TestWebhooksEnabled(t *testing.T) {
+	os.Setenv("PROCTOR_WEBHOOKS_ENABLED", "false")
+	defer os.Unsetenv("PROCTOR_WEBHOOKS_ENABLED")
+
+	viper.AutomaticEnv()
+
+	assert.False(t, WebhooksEnabled())
+}
+
+func TestWebhookSigningSecret(t *testing.T) {
+	os.Setenv("PROCTOR_WEBHOOK_SIGNING_SECRET", "s3cr3t")
+
+	viper.AutomaticEnv()
+
+	assert.Equal(t, "s3cr3t", WebhookSigningSecret())
+}
+
+func TestWebhookAllowedHosts(t *testing.T) {
+	os.Setenv("PROCTOR_WEBHOOK_ALLOWED_HOSTS", "hooks.internal,10.0.0.5")
+
+	viper.AutomaticEnv()
+
+	assert.Equal(t, "hooks.internal,10.0.0.5", WebhookAllowedHosts())
+}
+
+func TestWebhookMaxAttempts(t *testing.T) {
+	os.Setenv("PROCTOR_WEBHOOK_MAX_ATTEMPTS", "5")
+
+	viper.AutomaticEnv()
+
+	assert.Equal(t, 5, WebhookMaxAttempts())
+}
+
+func TestWebhookDeadLetterExpiry(t *testing.T) {
+	os.Setenv("PROCTOR_WEBHOOK_DEAD_LETTER_EXPIRY_SECONDS", "86400")
+
+	viper.AutomaticEnv()
+
+	assert.Equal(t, 24*time.Hour, WebhookDeadLetterExpiry())
+}
+
+func TestWebhookRetryInitialDelay(t *testing.T) {
+	os.Setenv("PROCTOR_WEBHOOK_RETRY_INITIAL_DELAY_MILLISECONDS", "500")
+
+	viper.AutomaticEnv()
+
+	assert.Equal(t, 500*time.Millisecond, WebhookRetryInitialDelay())
+}
+
+func TestExecutionRecordExpiry(t *testing.T) {
+	os.Setenv("PROCTOR_EXECUTION_RECORD_EXPIRY_SECONDS", "86400")
+
+	viper.AutomaticEnv()
+
+	assert.Equal(t, 24*time.Hour, ExecutionRecordExpiry())
+}
+
+func TestReaperInterval(t *testing.T) {
+	os.Setenv("PROCTOR_REAPER_INTERVAL_SECONDS", "300")
+
+	viper.AutomaticEnv()
+
+	assert.Equal(t, 5*time.Minute, ReaperInterval())
+}
+
+func TestSucceededJobRetention(t *testing.T) {
+	os.Setenv("PROCTOR_SUCCEEDED_JOB_RETENTION_SECONDS", "3600")
+
+	viper.AutomaticEnv()
+
+	assert.Equal(t, time.Hour, SucceededJobRetention())
+}
+
+func TestFailedJobRetention(t *testing.T) {
+	os.Setenv("PROCTOR_FAILED_JOB_RETENTION_SECONDS", "86400")
+
+	viper.AutomaticEnv()
+
+	assert.Equal(t, 24*time.Hour, FailedJobRetention())
+}
+
+func TestIdempotencyKeyExpiry(t *testing.T) {
+	os.Setenv("PROCTOR_IDEMPOTENCY_KEY_EXPIRY_SECONDS", "86400")
+
+	viper.AutomaticEnv()
+
+	assert.Equal(t, 24*time.Hour, IdempotencyKeyExpiry())
+}
+
+func TestMaxConcurrentExecutions(t *testing.T) {
+	os.Setenv("PROCTOR_MAX_CONCURRENT_EXECUTIONS", "20")
+
+	viper.AutomaticEnv()
+
+	assert.Equal(t, 20, MaxConcurrentExecutions())
+}
+
+func TestQueueDispatchInterval(t *testing.T) {
+	os.Setenv("PROCTOR_QUEUE_DISPATCH_INTERVAL_SECONDS", "5")
+
+	viper.AutomaticEnv()
+
+	assert.Equal(t, 5*time.Second, QueueDispatchInterval())
+}
+
+func TestRunningSlotLease(t *testing.T) {
+	os.Setenv("PROCTOR_RUNNING_SLOT_LEASE_SECONDS", "300")
+
+	viper.AutomaticEnv()
+
+	assert.Equal(t, 5*time.Minute, RunningSlotLease())
+}
+
+func TestWorkflowRunLease(t *testing.T) {
+	os.Setenv("PROCTOR_WORKFLOW_RUN_LEASE_SECONDS", "60")
+
+	viper.AutomaticEnv()
+
+	assert.Equal(t, time.Minute, WorkflowRunLease())
+}
+
+func TestQueueEncryptionKey(t *testing.T) {
+	os.Setenv("PROCTOR_QUEUE_ENCRYPTION_KEY", "queue-key")
+
+	viper.AutomaticEnv()
+
+	assert.Equal(t, "queue-key", QueueEncryptionKey())
+}
+
+func TestJobNodeSelector(t *testing.T) {
+	os.Setenv("PROCTOR_JOB_NODE_SELECTOR", "pool=batch,zone=a")
+
+	viper.AutomaticEnv()
+
+	assert.Equal(t, "pool=batch,zone=a", JobNodeSelector())
+}
+
+func TestJobTolerations(t *testing.T) {
+	os.Setenv("PROCTOR_JOB_TOLERATIONS", `[{"key":"dedicated","operator":"Equal","value":"batch","effect":"NoSchedule"}]`)
+
+	viper.AutomaticEnv()
+
+	assert.Equal(t, `[{"key":"dedicated","operator":"Equal","value":"batch","effect":"NoSchedule"}]`, JobTolerations())
+}
+
+func TestJobAffinity(t *testing.T) {
+	os.Setenv("PROCTOR_JOB_AFFINITY", `{"nodeAffinity":{}}`)
+
+	viper.AutomaticEnv()
+
+	assert.Equal(t, `{"nodeAffinity":{}}`, JobAffinity())
+}
+
+func TestJobServiceAccountName(t *testing.T) {
+	os.Setenv("PROCTOR_JOB_SERVICE_ACCOUNT_NAME", "proctor-jobs")
+
+	viper.AutomaticEnv()
+
+	assert.Equal(t, "proctor-jobs", JobServiceAccountName())
+}
+
+func TestJobImagePullSecrets(t *testing.T) {
+	os.Setenv("PROCTOR_JOB_IMAGE_PULL_SECRETS", "registry-a,registry-b")
+
+	viper.AutomaticEnv()
+
+	assert.Equal(t, "registry-a,registry-b", JobImagePullSecrets())
+}
+
+func TestJobPriorityClassName(t *testing.T) {
+	os.Setenv("PROCTOR_JOB_PRIORITY_CLASS_NAME", "batch-low")
+
+	viper.AutomaticEnv()
+
+	assert.Equal(t, "batch-low", JobPriorityClassName())
+}
+
+func TestJobRunAsNonRoot(t *testing.T) {
+	os.Setenv("PROCTOR_JOB_RUN_AS_NON_ROOT", "true")
+
+	viper.AutomaticEnv()
+
+	assert.True(t, JobRunAsNonRoot())
+}
+
+func TestJobReadOnlyRootFilesystem(t *testing.T) {
+	os.Setenv("PROCTOR_JOB_READ_ONLY_ROOT_FILESYSTEM", "true")
+
+	viper.AutomaticEnv()
+
+	assert.True(t, JobReadOnlyRootFilesystem())
+}
+
+func TestFileInputMaxBytes(t *testing.T) {
+	os.Setenv("PROCTOR_FILE_INPUT_MAX_BYTES", "1048576")
+
+	viper.AutomaticEnv()
+
+	assert.Equal(t, int64(1048576), FileInputMaxBytes())
+}
+
+func TestRequestBodyMaxBytes(t *testing.T) {
+	os.Setenv("PROCTOR_REQUEST_BODY_MAX_BYTES", "65536")
+
+	viper.AutomaticEnv()
+
+	assert.Equal(t, int64(65536), RequestBodyMaxBytes())
+}
+
+func TestKubeClusters(t *testing.T) {
+	os.Setenv("PROCTOR_KUBE_CLUSTERS", `[{"name":"staging","kubeconfig":"/etc/proctor/staging.kubeconfig"}]`)
+
+	viper.AutomaticEnv()
+
+	assert.Equal(t, `[{"name":"staging","kubeconfig":"/etc/proctor/staging.kubeconfig"}]`, KubeClusters())
+}
+
+func TestKubeAllowedNamespaces(t *testing.T) {
+	os.Setenv("PROCTOR_KUBE_ALLOWED_NAMESPACES", "batch,reports")
+
+	viper.AutomaticEnv()
+
+	assert.Equal(t, "batch,reports", KubeAllowedNamespaces())
+}
+
+func TestImageAllowedPatterns(t *testing.T) {
+	os.Setenv("PROCTOR_IMAGE_ALLOWED_PATTERNS", "gcr.io/ops/*,docker.io/gojek/**")
+
+	viper.AutomaticEnv()
+
+	assert.Equal(t, "gcr.io/ops/*,docker.io/gojek/**", ImageAllowedPatterns())
+}
+
+func TestImageRequireDigest(t *testing.T) {
+	os.Setenv("PROCTOR_IMAGE_REQUIRE_DIGEST", "true")
+
+	viper.AutomaticEnv()
+
+	assert.True(t, ImageRequireDigest())
+}
+
+func TestImageResolveDigests(t *testing.T) {
+	os.Setenv("PROCTOR_IMAGE_RESOLVE_DIGESTS", "true")
+
+	viper.AutomaticEnv()
+
+	assert.True(t, ImageResolveDigests())
+}
+
+func TestImageInsecureRegistries(t *testing.T) {
+	os.Setenv("PROCTOR_IMAGE_INSECURE_REGISTRIES", "localhost:5000")
+
+	viper.AutomaticEnv()
+
+	assert.Equal(t, "localhost:5000", ImageInsecureRegistries())
+}
+
+func TestPolicyRules(t *testing.T) {
+	os.Setenv("PROCTOR_POLICY_RULES", `[{"name":"quiet-hours","deny":"time.hour >= 22","reason":"no executions after 22:00"}]`)
+
+	viper.AutomaticEnv()
+
+	assert.Equal(t, `[{"name":"quiet-hours","deny":"time.hour >= 22","reason":"no executions after 22:00"}]`, PolicyRules())
+}
+
+func TestPolicyTimezone(t *testing.T) {
+	os.Setenv("PROCTOR_POLICY_TIMEZONE", "Asia/Jakarta")
+
+	viper.AutomaticEnv()
+
+	assert.Equal(t, "Asia/Jakarta", PolicyTimezone())
+}
+
+func TestPolicyAdminToken(t *testing.T) {
+	os.Setenv("PROCTOR_POLICY_ADMIN_TOKEN", "policy-admin-token")
+
+	viper.AutomaticEnv()
+
+	assert.Equal(t, "policy-admin-token", PolicyAdminToken())
+}
+
+func TestRateLimitRequestsPerSecond(t *testing.T) {
+	os.Setenv("PROCTOR_RATE_LIMIT_REQUESTS_PER_SECOND", "0.5")
+
+	viper.AutomaticEnv()
+
+	assert.Equal(t, 0.5, RateLimitRequestsPerSecond())
+}
+
+func TestRateLimitBurst(t *testing.T) {
+	os.Setenv("PROCTOR_RATE_LIMIT_BURST", "20")
+
+	viper.AutomaticEnv()
+
+	assert.Equal(t, 20, RateLimitBurst())
+}
+
+func TestRateLimitAddressRequestsPerSecond(t *testing.T) {
+	os.Setenv("PROCTOR_RATE_LIMIT_ADDRESS_REQUESTS_PER_SECOND", "50")
+
+	viper.AutomaticEnv()
+
+	assert.Equal(t, 50.0, RateLimitAddressRequestsPerSecond())
+}
+
+func TestRateLimitAddressBurst(t *testing.T) {
+	os.Setenv("PROCTOR_RATE_LIMIT_ADDRESS_BURST", "100")
+
+	viper.AutomaticEnv()
+
+	assert.Equal(t, 100, RateLimitAddressBurst())
+}
+
+func TestDefaults(t *testing.T) {
+	os.Unsetenv("PROCTOR_REDIS_MAX_ACTIVE_CONNECTIONS")
+	os.Unsetenv("PROCTOR_WEBHOOK_RETRY_INITIAL_DELAY_MILLISECONDS")
+	os.Unsetenv("PROCTOR_RUNNING_SLOT_LEASE_SECONDS")
+
+	viper.AutomaticEnv()
+
+	assert.Equal(t, 10, RedisMaxActiveConnections())
+	assert.Equal(t, time.Second, WebhookRetryInitialDelay())
+	assert.Equal(t, 5*time.Minute, RunningSlotLease())
+	assert.Equal(t, "1000", viper.GetString("WEBHOOK_RETRY_INITIAL_DELAY_MILLISECONDS"))
+}
+
+func TestLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "proctor-config")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "proctor.yaml")
+	assert.NoError(t, ioutil.WriteFile(path, []byte(`
+redis_address: redis.internal:6379
+webhook_max_attempts: 7
+reaper_interval_seconds: 10m
+kube_clusters:
+  - name: staging
+    kubeconfig: /etc/proctor/staging.kubeconfig
+`), 0600))
+	os.Unsetenv("PROCTOR_REDIS_ADDRESS")
+	os.Unsetenv("PROCTOR_REAPER_INTERVAL_SECONDS")
+	os.Unsetenv("PROCTOR_KUBE_CLUSTERS")
+	os.Setenv("PROCTOR_WEBHOOK_MAX_ATTEMPTS", "9")
+	defer func() {
+		viper.Reset()
+		setup()
+	}()
+
+	assert.NoError(t, Load(path))
+
+	assert.Equal(t, "redis.internal:6379", RedisAddress())
+	assert.Equal(t, 9, WebhookMaxAttempts())
+	assert.Equal(t, 10*time.Minute, ReaperInterval())
+	assert.Equal(t, `[{"kubeconfig":"/etc/proctor/staging.kubeconfig","name":"staging"}]`, KubeClusters())
+}
+
+func TestLoadWithMissingFile(t *testing.T) {
+	defer func() {
+		viper.Reset()
+		setup()
+	}()
+
+	err := Load(filepath.Join(os.TempDir(), "proctor-config-missing.yaml"))
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,157 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+type Problems []error
+
+func (problems Problems) Error() string {
+	messages := make([]string, len(problems))
+	for i, problem := range problems {
+		messages[i] = "  " + problem.Error()
+	}
+	return fmt.Sprintf("invalid configuration:\n%s", strings.Join(messages, "\n"))
+}
+
+var positiveIntegers = []string{
+	"REDIS_MAX_ACTIVE_CONNECTIONS",
+	"LOGS_STREAM_READ_BUFFER_SIZE",
+	"LOGS_STREAM_WRITE_BUFFER_SIZE",
+	"KUBE_JOB_ACTIVE_DEADLINE_SECONDS",
+	"WEBHOOK_MAX_ATTEMPTS",
+	"REQUEST_BODY_MAX_BYTES",
+}
+
+var nonNegativeIntegers = []string{
+	"MAX_CONCURRENT_EXECUTIONS",
+	"RATE_LIMIT_BURST",
+	"RATE_LIMIT_ADDRESS_BURST",
+	"FILE_INPUT_MAX_BYTES",
+}
+
+var nonNegativeNumbers = []string{
+	"RATE_LIMIT_REQUESTS_PER_SECOND",
+	"RATE_LIMIT_ADDRESS_REQUESTS_PER_SECOND",
+}
+
+var booleans = []string{
+	"JOB_RUN_AS_NON_ROOT",
+	"JOB_READ_ONLY_ROOT_FILESYSTEM",
+	"IMAGE_REQUIRE_DIGEST",
+	"IMAGE_RESOLVE_DIGESTS",
+	"WEBHOOKS_ENABLED",
+}
+
+type durationSetting struct {
+	key      string
+	unit     time.Duration
+	positive bool
+}
+
+var durations = []durationSetting{
+	{"KUBE_POD_LIST_WAIT_TIME", time.Second, true},
+	{"EXECUTION_REQUEST_EXPIRY_SECONDS", time.Second, true},
+	{"READINESS_CHECK_CACHE_SECONDS", time.Second, true},
+	{"WEBHOOK_RETRY_INITIAL_DELAY_MILLISECONDS", time.Millisecond, true},
+	{"WEBHOOK_DEAD_LETTER_EXPIRY_SECONDS", time.Second, true},
+	{"IDEMPOTENCY_KEY_EXPIRY_SECONDS", time.Second, true},
+	{"QUEUE_DISPATCH_INTERVAL_SECONDS", time.Second, true},
+	{"RUNNING_SLOT_LEASE_SECONDS", time.Second, true},
+	{"WORKFLOW_RUN_LEASE_SECONDS", time.Second, true},
+	{"EXECUTION_RECORD_EXPIRY_SECONDS", time.Second, false},
+	{"REAPER_INTERVAL_SECONDS", time.Second, false},
+	{"SUCCEEDED_JOB_RETENTION_SECONDS", time.Second, false},
+	{"FAILED_JOB_RETENTION_SECONDS", time.Second, false},
+}
+
+var structuredSettings = []string{
+	"JOB_TOLERATIONS",
+	"JOB_AFFINITY",
+	"KUBE_CLUSTERS",
+	"POLICY_RULES",
+}
+
+// Validate checks every setting this package owns and returns all problems
+// found rather than stopping at the first one.
+func Validate() Problems {
+	var problems Problems
+	invalid := func(key, format string, args ...interface{}) {
+		problems = append(problems, fmt.Errorf("PROCTOR_%s %s", key, fmt.Sprintf(format, args...)))
+	}
+
+	if fileErr != nil {
+		problems = append(problems, fileErr)
+	}
+
+	if _, err := logrus.ParseLevel(LogLevel()); err != nil {
+		invalid("LOG_LEVEL", "must be one of debug, info, warn, error, fatal or panic, got %q", LogLevel())
+	}
+	if port, err := strconv.Atoi(AppPort()); err != nil || port < 1 || port > 65535 {
+		invalid("APP_PORT", "must be a port number, got %q", AppPort())
+	}
+	if RedisAddress() == "" {
+		invalid("REDIS_ADDRESS", "is required")
+	}
+	if WebhooksEnabled() && WebhookSigningSecret() == "" {
+		invalid("WEBHOOK_SIGNING_SECRET", "is required to sign webhook deliveries")
+	}
+
+	for _, key := range positiveIntegers {
+		if value, err := strconv.Atoi(viper.GetString(key)); err != nil || value <= 0 {
+			invalid(key, "must be a positive integer, got %q", viper.GetString(key))
+		}
+	}
+	for _, key := range nonNegativeIntegers {
+		raw := viper.GetString(key)
+		if raw == "" {
+			continue
+		}
+		if value, err := strconv.Atoi(raw); err != nil || value < 0 {
+			invalid(key, "must be a non-negative integer, got %q", raw)
+		}
+	}
+	for _, key := range nonNegativeNumbers {
+		if raw := viper.GetString(key); raw != "" {
+			if value, err := strconv.ParseFloat(raw, 64); err != nil || value < 0 {
+				invalid(key, "must be a non-negative number, got %q", raw)
+			}
+		}
+	}
+	for _, key := range booleans {
+		if raw := viper.GetString(key); raw != "" {
+			if _, err := strconv.ParseBool(raw); err != nil {
+				invalid(key, "must be true or false, got %q", raw)
+			}
+		}
+	}
+
+	for _, setting := range durations {
+		value, err := parseDuration(setting.key, setting.unit)
+		switch {
+		case err != nil:
+			invalid(setting.key, "must be an integer or a duration such as 5m, got %q", viper.GetString(setting.key))
+		case setting.positive && value <= 0:
+			invalid(setting.key, "must be positive, got %q", viper.GetString(setting.key))
+		case value < 0:
+			invalid(setting.key, "must not be negative, got %q", viper.GetString(setting.key))
+		}
+	}
+
+	if _, err := time.LoadLocation(PolicyTimezone()); err != nil {
+		invalid("POLICY_TIMEZONE", "must be an IANA time zone, got %q", PolicyTimezone())
+	}
+	for _, key := range structuredSettings {
+		if _, err := structured(key); err != nil {
+			invalid(key, "cannot be converted to JSON: %s", err)
+		}
+	}
+
+	return problems
+}
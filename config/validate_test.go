@@ -0,0 +1,83 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func messages(problems Problems) []string {
+	var messages []string
+	for _, problem := range problems {
+		messages = append(messages, problem.Error())
+	}
+	return messages
+}
+
+func TestValidateReportsAllProblems(t *testing.T) {
+	os.Setenv("PROCTOR_LOG_LEVEL", "verbose")
+	os.Setenv("PROCTOR_REDIS_MAX_ACTIVE_CONNECTIONS", "0")
+	os.Setenv("PROCTOR_KUBE_POD_LIST_WAIT_TIME", "soon")
+	os.Setenv("PROCTOR_REAPER_INTERVAL_SECONDS", "-5m")
+	os.Setenv("PROCTOR_POLICY_TIMEZONE", "Mars/Olympus_Mons")
+	os.Setenv("PROCTOR_JOB_RUN_AS_NON_ROOT", "sometimes")
+	defer func() {
+		os.Setenv("PROCTOR_LOG_LEVEL", "info")
+		os.Unsetenv("PROCTOR_REDIS_MAX_ACTIVE_CONNECTIONS")
+		os.Unsetenv("PROCTOR_KUBE_POD_LIST_WAIT_TIME")
+		os.Unsetenv("PROCTOR_REAPER_INTERVAL_SECONDS")
+		os.Unsetenv("PROCTOR_POLICY_TIMEZONE")
+		os.Unsetenv("PROCTOR_JOB_RUN_AS_NON_ROOT")
+	}()
+
+	viper.AutomaticEnv()
+
+	problems := messages(Validate())
+
+	assert.Contains(t, problems, `PROCTOR_LOG_LEVEL must be one of debug, info, warn, error, fatal or panic, got "verbose"`)
+	assert.Contains(t, problems, `PROCTOR_REDIS_MAX_ACTIVE_CONNECTIONS must be a positive integer, got "0"`)
+	assert.Contains(t, problems, `PROCTOR_KUBE_POD_LIST_WAIT_TIME must be an integer or a duration such as 5m, got "soon"`)
+	assert.Contains(t, problems, `PROCTOR_REAPER_INTERVAL_SECONDS must not be negative, got "-5m"`)
+	assert.Contains(t, problems, `PROCTOR_POLICY_TIMEZONE must be an IANA time zone, got "Mars/Olympus_Mons"`)
+	assert.Contains(t, problems, `PROCTOR_JOB_RUN_AS_NON_ROOT must be true or false, got "sometimes"`)
+}
+
+func TestValidateWithDefaults(t *testing.T) {
+	os.Setenv("PROCTOR_LOG_LEVEL", "info")
+	os.Setenv("PROCTOR_WEBHOOK_SIGNING_SECRET", "s3cr3t")
+
+	viper.AutomaticEnv()
+
+	assert.Empty(t, Validate())
+}
+
+func TestValidateRequiresWebhookSigningSecret(t *testing.T) {
+	os.Unsetenv("PROCTOR_WEBHOOK_SIGNING_SECRET")
+	defer os.Setenv("PROCTOR_WEBHOOK_SIGNING_SECRET", "s3cr3t")
+
+	viper.AutomaticEnv()
+
+	assert.Contains(t, messages(Validate()), "PROCTOR_WEBHOOK_SIGNING_SECRET is required to sign webhook deliveries")
+}
+
+func TestValidateSkipsWebhookSigningSecretWhenWebhooksDisabled(t *testing.T) {
+	os.Unsetenv("PROCTOR_WEBHOOK_SIGNING_SECRET")
+	os.Setenv("PROCTOR_WEBHOOKS_ENABLED", "false")
+	defer func() {
+		os.Setenv("PROCTOR_WEBHOOK_SIGNING_SECRET", "s3cr3t")
+		os.Unsetenv("PROCTOR_WEBHOOKS_ENABLED")
+	}()
+
+	viper.AutomaticEnv()
+
+	assert.NotContains(t, messages(Validate()), "PROCTOR_WEBHOOK_SIGNING_SECRET is required to sign webhook deliveries")
+}
+
+func TestProblemsError(t *testing.T) {
+	problems := Problems{errors.New("first problem"), errors.New("second problem")}
+
+	assert.Equal(t, "invalid configuration:\n  first problem\n  second problem", problems.Error())
+}
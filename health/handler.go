@@ -0,0 +1,145 @@
+package health
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gojektech/proctor-engine/logger"
+	"github.com/gojektech/proctor-engine/utility"
+)
+
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+const checkTimeout = 5 * time.Second
+
+var errCheckTimedOut = errors.New("check timed out")
+
+type Check func() error
+
+type CheckResult struct {
+	Status    string `json:"status"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+type Report struct {
+	Status    string                 `json:"status"`
+	Checks    map[string]CheckResult `json:"checks"`
+	CheckedAt time.Time              `json:"checked_at"`
+}
+
+type healthHandler struct {
+	checks        map[string]Check
+	cacheDuration time.Duration
+	mutex         sync.Mutex
+	cachedReport  *Report
+}
+
+type HealthHandler interface {
+	HandleLiveness() http.HandlerFunc
+	HandleReadiness() http.HandlerFunc
+}
+
+func NewHealthHandler(checks map[string]Check, cacheDuration time.Duration) HealthHandler {
+	return &healthHandler{
+		checks:        checks,
+		cacheDuration: cacheDuration,
+	}
+}
+
+func (healthHandler *healthHandler) HandleLiveness() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("{ \"status\":\"ok\" }"))
+	}
+}
+
+func (healthHandler *healthHandler) HandleReadiness() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		log := logger.FromContext(req.Context())
+		report := healthHandler.report()
+
+		reportInJSON, err := json.Marshal(report)
+		if err != nil {
+			log.Error("Error marshalling readiness report in json", err.Error())
+
+			utility.WriteError(w, req, http.StatusInternalServerError, utility.ErrCodeInternal, utility.ServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if report.Status != StatusOK {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		w.Write(reportInJSON)
+	}
+}
+
+func (healthHandler *healthHandler) report() *Report {
+	healthHandler.mutex.Lock()
+	defer healthHandler.mutex.Unlock()
+
+	if healthHandler.cachedReport != nil && time.Since(healthHandler.cachedReport.CheckedAt) < healthHandler.cacheDuration {
+		return healthHandler.cachedReport
+	}
+
+	report := &Report{
+		Status:    StatusOK,
+		Checks:    make(map[string]CheckResult),
+		CheckedAt: time.Now(),
+	}
+
+	var resultsMutex sync.Mutex
+	var waitGroup sync.WaitGroup
+	for name, check := range healthHandler.checks {
+		waitGroup.Add(1)
+		go func(name string, check Check) {
+			defer waitGroup.Done()
+			result := run(check)
+
+			resultsMutex.Lock()
+			defer resultsMutex.Unlock()
+			report.Checks[name] = result
+			if result.Status != StatusOK {
+				logger.Error("Readiness check failed", name, result.Error)
+				report.Status = StatusFail
+			}
+		}(name, check)
+	}
+	waitGroup.Wait()
+
+	healthHandler.cachedReport = report
+	return report
+}
+
+func run(check Check) CheckResult {
+	start := time.Now()
+
+	checkErr := make(chan error, 1)
+	go func() {
+		checkErr <- check()
+	}()
+
+	var err error
+	select {
+	case err = <-checkErr:
+	case <-time.After(checkTimeout):
+		err = errCheckTimedOut
+	}
+
+	result := CheckResult{
+		Status:    StatusOK,
+		LatencyMs: int64(time.Since(start) / time.Millisecond),
+	}
+	if err != nil {
+		result.Status = StatusFail
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,111 @@
+package health
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type HealthHandlerTestSuite struct {
+	suite.Suite
+	redisCheckCalls int
+	redisCheckErr   error
+	checks          map[string]Check
+}
+
+func (s *HealthHandlerTestSuite) SetupTest() {
+	s.redisCheckCalls = 0
+	s.redisCheckErr = nil
+	s.checks = map[string]Check{
+		"redis": func() error {
+			s.redisCheckCalls++
+			return s.redisCheckErr
+		},
+		"kubernetes": func() error {
+			return nil
+		},
+	}
+}
+
+func readinessReport(t *testing.T, healthHandler HealthHandler) (int, Report) {
+	req := httptest.NewRequest("GET", "/readyz", nil)
+	responseRecorder := httptest.NewRecorder()
+
+	healthHandler.HandleReadiness()(responseRecorder, req)
+
+	var report Report
+	err := json.Unmarshal(responseRecorder.Body.Bytes(), &report)
+	assert.NoError(t, err)
+
+	return responseRecorder.Code, report
+}
+
+func (s *HealthHandlerTestSuite) TestLiveness() {
+	t := s.T()
+
+	req := httptest.NewRequest("GET", "/healthz", nil)
+	responseRecorder := httptest.NewRecorder()
+
+	NewHealthHandler(s.checks, time.Minute).HandleLiveness()(responseRecorder, req)
+
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+	assert.Equal(t, "{ \"status\":\"ok\" }", responseRecorder.Body.String())
+	assert.Equal(t, 0, s.redisCheckCalls)
+}
+
+func (s *HealthHandlerTestSuite) TestReadinessWhenAllChecksPass() {
+	t := s.T()
+
+	code, report := readinessReport(t, NewHealthHandler(s.checks, time.Minute))
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, StatusOK, report.Status)
+	assert.Equal(t, StatusOK, report.Checks["redis"].Status)
+	assert.Equal(t, StatusOK, report.Checks["kubernetes"].Status)
+}
+
+func (s *HealthHandlerTestSuite) TestReadinessWhenACheckFails() {
+	t := s.T()
+
+	s.redisCheckErr = errors.New("connection refused")
+
+	code, report := readinessReport(t, NewHealthHandler(s.checks, time.Minute))
+
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, StatusFail, report.Status)
+	assert.Equal(t, StatusFail, report.Checks["redis"].Status)
+	assert.Equal(t, "connection refused", report.Checks["redis"].Error)
+	assert.Equal(t, StatusOK, report.Checks["kubernetes"].Status)
+}
+
+func (s *HealthHandlerTestSuite) TestReadinessIsCached() {
+	t := s.T()
+
+	healthHandler := NewHealthHandler(s.checks, time.Minute)
+
+	readinessReport(t, healthHandler)
+	readinessReport(t, healthHandler)
+
+	assert.Equal(t, 1, s.redisCheckCalls)
+}
+
+func (s *HealthHandlerTestSuite) TestReadinessCacheExpires() {
+	t := s.T()
+
+	healthHandler := NewHealthHandler(s.checks, 0)
+
+	readinessReport(t, healthHandler)
+	readinessReport(t, healthHandler)
+
+	assert.Equal(t, 2, s.redisCheckCalls)
+}
+
+func TestHealthHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(HealthHandlerTestSuite))
+}
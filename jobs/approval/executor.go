@@ -0,0 +1,12 @@
+package approval
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrApprovalRequired = errors.New("job requires approval")
+
+type Executor interface {
+	Execute(ctx context.Context, jobName string, args map[string]string, callbackURL string, requester string, approvedBy []string) (string, error)
+}
@@ -0,0 +1,185 @@
+package approval
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gojektech/proctor-engine/jobs/policy"
+	"github.com/gojektech/proctor-engine/jobs/queue"
+	"github.com/gojektech/proctor-engine/logger"
+	"github.com/gojektech/proctor-engine/ratelimit"
+	"github.com/gojektech/proctor-engine/utility"
+
+	"github.com/gorilla/mux"
+)
+
+type approvalHandler struct {
+	store    Store
+	executor Executor
+}
+
+type ApprovalHandler interface {
+	HandleApproval() http.HandlerFunc
+	HandleRejection() http.HandlerFunc
+}
+
+func NewApprovalHandler(store Store, executor Executor) ApprovalHandler {
+	return &approvalHandler{
+		store:    store,
+		executor: executor,
+	}
+}
+
+func (approvalHandler *approvalHandler) HandleApproval() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		log := logger.FromContext(req.Context())
+
+		approver := req.Header.Get(utility.UserEmailHeaderKey)
+		if approver == "" {
+			log.Error("No approver provided for execution request")
+
+			utility.WriteValidationError(w, req, []utility.FieldError{{Field: utility.UserEmailHeaderKey, Message: "header is required"}})
+			return
+		}
+
+		requestID := mux.Vars(req)["id"]
+		request, err := approvalHandler.store.GetRequest(requestID)
+		if err == ErrRequestNotFound {
+			utility.WriteError(w, req, http.StatusNotFound, utility.ErrCodeExecutionRequestNotFound, fmt.Sprintf("execution request %s not found or expired", requestID))
+			return
+		}
+		if err != nil {
+			log.Error("Error fetching execution request", requestID, err.Error())
+
+			utility.WriteError(w, req, http.StatusServiceUnavailable, utility.ErrCodeStoreUnavailable, "unable to fetch execution request")
+			return
+		}
+
+		ctx := logger.WithFields(req.Context(), logger.Fields{logger.JobNameField: request.JobName})
+		log = logger.FromContext(ctx)
+
+		if request.RequestedBy == approver {
+			log.Error("Self approval attempted for execution request", requestID, approver)
+
+			utility.WriteError(w, req, http.StatusForbidden, utility.ErrCodeSelfApprovalForbidden, "requester cannot approve their own execution request")
+			return
+		}
+
+		request, claimed, err := approvalHandler.store.Approve(requestID, approver)
+		if err == ErrRequestNotFound {
+			utility.WriteError(w, req, http.StatusNotFound, utility.ErrCodeExecutionRequestNotFound, fmt.Sprintf("execution request %s not found or expired", requestID))
+			return
+		}
+		if err != nil {
+			log.Error("Error saving approval for execution request", requestID, err.Error())
+
+			utility.WriteError(w, req, http.StatusServiceUnavailable, utility.ErrCodeStoreUnavailable, "unable to save approval")
+			return
+		}
+
+		if request.PendingApprovals() > 0 {
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte(fmt.Sprintf("{ \"request_id\":\"%s\", \"pending_approvals\":%d }", request.ID, request.PendingApprovals())))
+			return
+		}
+
+		if !claimed {
+			utility.WriteError(w, req, http.StatusConflict, utility.ErrCodeRequestInProgress, fmt.Sprintf("execution request %s is already being executed", requestID))
+			return
+		}
+
+		executedJobName, err := approvalHandler.executor.Execute(ctx, request.JobName, request.Args, request.CallbackURL, request.RequestedBy, request.ApprovedBy)
+		if queuedErr, ok := err.(*queue.QueuedError); ok {
+			approvalHandler.deleteRequest(log, requestID)
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusAccepted)
+			json.NewEncoder(w).Encode(queuedErr.Position)
+			return
+		}
+		if err != nil {
+			approvalHandler.releaseClaim(log, requestID)
+		}
+		if err == queue.ErrConcurrencyLimitReached {
+			utility.WriteError(w, req, http.StatusTooManyRequests, utility.ErrCodeConcurrencyLimitReached, "concurrency limit reached for job, retry later")
+			return
+		}
+		if err == ratelimit.ErrLimitExceeded {
+			utility.WriteError(w, req, http.StatusTooManyRequests, utility.ErrCodeRateLimited, fmt.Sprintf("execution rate limit reached for job %s, retry later", request.JobName))
+			return
+		}
+		if err == ErrApprovalRequired {
+			utility.WriteError(w, req, http.StatusForbidden, utility.ErrCodeApprovalRequired, fmt.Sprintf("job %s requires more approvals than execution request %s has", request.JobName, requestID))
+			return
+		}
+		if deniedErr, ok := err.(*policy.DeniedError); ok {
+			utility.WriteError(w, req, http.StatusForbidden, utility.ErrCodePolicyDenied, "execution denied by policy", deniedErr.Details()...)
+			return
+		}
+		if err != nil {
+			log.Error("Error executing approved job", err.Error())
+
+			utility.WriteError(w, req, http.StatusInternalServerError, utility.ErrCodeExecutionFailed, "unable to start approved job")
+			return
+		}
+
+		approvalHandler.deleteRequest(log, requestID)
+
+		log.WithField(logger.ExecutionNameField, executedJobName).Info("Executed approved job", request.ApprovedBy)
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(fmt.Sprintf("{ \"name\":\"%s\" }", executedJobName)))
+	}
+}
+
+func (approvalHandler *approvalHandler) releaseClaim(log *logger.Entry, requestID string) {
+	if err := approvalHandler.store.ReleaseClaim(requestID); err != nil {
+		log.Error("Error releasing claim on execution request", requestID, err.Error())
+	}
+}
+
+func (approvalHandler *approvalHandler) deleteRequest(log *logger.Entry, requestID string) {
+	if err := approvalHandler.store.DeleteRequest(requestID); err != nil {
+		log.Error("Error removing executed execution request", requestID, err.Error())
+	}
+}
+
+func (approvalHandler *approvalHandler) HandleRejection() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		log := logger.FromContext(req.Context())
+
+		rejecter := req.Header.Get(utility.UserEmailHeaderKey)
+		if rejecter == "" {
+			log.Error("No rejecter provided for execution request")
+
+			utility.WriteValidationError(w, req, []utility.FieldError{{Field: utility.UserEmailHeaderKey, Message: "header is required"}})
+			return
+		}
+
+		requestID := mux.Vars(req)["id"]
+		_, err := approvalHandler.store.GetRequest(requestID)
+		if err == ErrRequestNotFound {
+			utility.WriteError(w, req, http.StatusNotFound, utility.ErrCodeExecutionRequestNotFound, fmt.Sprintf("execution request %s not found or expired", requestID))
+			return
+		}
+		if err != nil {
+			log.Error("Error fetching execution request", requestID, err.Error())
+
+			utility.WriteError(w, req, http.StatusServiceUnavailable, utility.ErrCodeStoreUnavailable, "unable to fetch execution request")
+			return
+		}
+
+		err = approvalHandler.store.DeleteRequest(requestID)
+		if err != nil {
+			log.Error("Error discarding execution request", requestID, err.Error())
+
+			utility.WriteError(w, req, http.StatusServiceUnavailable, utility.ErrCodeStoreUnavailable, "unable to discard execution request")
+			return
+		}
+
+		log.Info("Execution request rejected", requestID, rejecter)
+
+		w.WriteHeader(http.StatusOK)
+	}
+}
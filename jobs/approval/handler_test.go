@@ -0,0 +1,320 @@
+package approval
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gojektech/proctor-engine/jobs/policy"
+	"github.com/gojektech/proctor-engine/jobs/queue"
+	"github.com/gojektech/proctor-engine/ratelimit"
+	"github.com/gojektech/proctor-engine/utility"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type ApprovalHandlerTestSuite struct {
+	suite.Suite
+	mockStore           *MockStore
+	mockExecutor        *MockExecutor
+	testApprovalHandler ApprovalHandler
+	testRouter          *mux.Router
+}
+
+func (s *ApprovalHandlerTestSuite) SetupTest() {
+	s.mockStore = &MockStore{}
+	s.mockExecutor = &MockExecutor{}
+
+	s.testApprovalHandler = NewApprovalHandler(s.mockStore, s.mockExecutor)
+
+	s.testRouter = mux.NewRouter()
+	s.testRouter.HandleFunc("/jobs/requests/{id}/approve", s.testApprovalHandler.HandleApproval()).Methods("POST")
+	s.testRouter.HandleFunc("/jobs/requests/{id}/reject", s.testApprovalHandler.HandleRejection()).Methods("POST")
+}
+
+func pendingRequest(requiredApprovals int) *Request {
+	return &Request{
+		ID:                "request-id",
+		JobName:           "job1",
+		Args:              map[string]string{"k1": "v1"},
+		RequestedBy:       "requester@example.com",
+		RequiredApprovals: requiredApprovals,
+		CreatedAt:         time.Now(),
+		ExpiresAt:         time.Now().Add(time.Hour),
+	}
+}
+
+func approved(request *Request, approver string) *Request {
+	approvedRequest := *request
+	approvedRequest.ApprovedBy = append(approvedRequest.ApprovedBy, approver)
+	return &approvedRequest
+}
+
+func (s *ApprovalHandlerTestSuite) serve(path, userEmail string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", path, nil)
+	if userEmail != "" {
+		req.Header.Set(utility.UserEmailHeaderKey, userEmail)
+	}
+	responseRecorder := httptest.NewRecorder()
+
+	s.testRouter.ServeHTTP(responseRecorder, req)
+	return responseRecorder
+}
+
+func errorResponse(t *testing.T, responseRecorder *httptest.ResponseRecorder) utility.Error {
+	var errorResponse utility.ErrorResponse
+	err := json.Unmarshal(responseRecorder.Body.Bytes(), &errorResponse)
+	assert.NoError(t, err)
+	return errorResponse.Error
+}
+
+func (s *ApprovalHandlerTestSuite) TestFinalApprovalExecutesJob() {
+	t := s.T()
+
+	request := pendingRequest(1)
+	s.mockStore.On("GetRequest", "request-id").Return(request, nil).Once()
+	s.mockStore.On("Approve", "request-id", "approver@example.com").Return(approved(request, "approver@example.com"), true, nil).Once()
+	s.mockStore.On("DeleteRequest", "request-id").Return(nil).Once()
+	s.mockExecutor.On("Execute", mock.Anything, "job1", request.Args, request.CallbackURL, request.RequestedBy, []string{"approver@example.com"}).Return("proctor-ipsum-lorem", nil).Once()
+
+	responseRecorder := s.serve("/jobs/requests/request-id/approve", "approver@example.com")
+
+	s.mockStore.AssertExpectations(t)
+	s.mockExecutor.AssertExpectations(t)
+
+	assert.Equal(t, http.StatusCreated, responseRecorder.Code)
+	assert.Equal(t, "{ \"name\":\"proctor-ipsum-lorem\" }", responseRecorder.Body.String())
+}
+
+func (s *ApprovalHandlerTestSuite) TestPartialApprovalIsRecorded() {
+	t := s.T()
+
+	request := pendingRequest(2)
+	s.mockStore.On("GetRequest", "request-id").Return(request, nil).Once()
+	s.mockStore.On("Approve", "request-id", "approver@example.com").Return(approved(request, "approver@example.com"), false, nil).Once()
+
+	responseRecorder := s.serve("/jobs/requests/request-id/approve", "approver@example.com")
+
+	s.mockStore.AssertExpectations(t)
+	s.mockExecutor.AssertNotCalled(t, "Execute", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	assert.Equal(t, http.StatusAccepted, responseRecorder.Code)
+	assert.Equal(t, "{ \"request_id\":\"request-id\", \"pending_approvals\":1 }", responseRecorder.Body.String())
+}
+
+func (s *ApprovalHandlerTestSuite) TestSelfApprovalIsForbidden() {
+	t := s.T()
+
+	s.mockStore.On("GetRequest", "request-id").Return(pendingRequest(1), nil).Once()
+
+	responseRecorder := s.serve("/jobs/requests/request-id/approve", "requester@example.com")
+
+	s.mockStore.AssertNotCalled(t, "Approve", mock.Anything, mock.Anything)
+	s.mockStore.AssertNotCalled(t, "DeleteRequest", mock.Anything)
+	s.mockExecutor.AssertNotCalled(t, "Execute", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	assert.Equal(t, http.StatusForbidden, responseRecorder.Code)
+	assert.Equal(t, utility.ErrCodeSelfApprovalForbidden, errorResponse(t, responseRecorder).Code)
+}
+
+func (s *ApprovalHandlerTestSuite) TestApprovalWithoutApprover() {
+	t := s.T()
+
+	responseRecorder := s.serve("/jobs/requests/request-id/approve", "")
+
+	s.mockStore.AssertNotCalled(t, "GetRequest", mock.Anything)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, responseRecorder.Code)
+	assert.Equal(t, utility.ErrCodeValidationFailed, errorResponse(t, responseRecorder).Code)
+}
+
+func (s *ApprovalHandlerTestSuite) TestApprovalOfExpiredRequest() {
+	t := s.T()
+
+	s.mockStore.On("GetRequest", "request-id").Return(&Request{}, ErrRequestNotFound).Once()
+
+	responseRecorder := s.serve("/jobs/requests/request-id/approve", "approver@example.com")
+
+	s.mockExecutor.AssertNotCalled(t, "Execute", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	assert.Equal(t, http.StatusNotFound, responseRecorder.Code)
+	assert.Equal(t, utility.ErrCodeExecutionRequestNotFound, errorResponse(t, responseRecorder).Code)
+}
+
+func (s *ApprovalHandlerTestSuite) TestApprovalOnExecutionFailure() {
+	t := s.T()
+
+	request := pendingRequest(1)
+	s.mockStore.On("GetRequest", "request-id").Return(request, nil).Once()
+	s.mockStore.On("Approve", "request-id", "approver@example.com").Return(approved(request, "approver@example.com"), true, nil).Once()
+	s.mockStore.On("ReleaseClaim", "request-id").Return(nil).Once()
+	s.mockExecutor.On("Execute", mock.Anything, "job1", request.Args, request.CallbackURL, request.RequestedBy, []string{"approver@example.com"}).Return("", errors.New("error")).Once()
+
+	responseRecorder := s.serve("/jobs/requests/request-id/approve", "approver@example.com")
+
+	s.mockStore.AssertExpectations(t)
+	s.mockStore.AssertNotCalled(t, "DeleteRequest", mock.Anything)
+	s.mockExecutor.AssertExpectations(t)
+
+	assert.Equal(t, http.StatusInternalServerError, responseRecorder.Code)
+	assert.Equal(t, utility.ErrCodeExecutionFailed, errorResponse(t, responseRecorder).Code)
+}
+
+func (s *ApprovalHandlerTestSuite) TestApprovalQueuedOverConcurrencyLimit() {
+	t := s.T()
+
+	request := pendingRequest(1)
+	s.mockStore.On("GetRequest", "request-id").Return(request, nil).Once()
+	s.mockStore.On("Approve", "request-id", "approver@example.com").Return(approved(request, "approver@example.com"), true, nil).Once()
+	s.mockStore.On("DeleteRequest", "request-id").Return(nil).Once()
+	queuedErr := &queue.QueuedError{Position: queue.Position{ID: "queue-id", JobName: "job1", Status: queue.StatusQueued, Position: 2}}
+	s.mockExecutor.On("Execute", mock.Anything, "job1", request.Args, request.CallbackURL, request.RequestedBy, []string{"approver@example.com"}).Return("", queuedErr).Once()
+
+	responseRecorder := s.serve("/jobs/requests/request-id/approve", "approver@example.com")
+
+	s.mockStore.AssertExpectations(t)
+
+	assert.Equal(t, http.StatusAccepted, responseRecorder.Code)
+	var position queue.Position
+	assert.NoError(t, json.NewDecoder(responseRecorder.Body).Decode(&position))
+	assert.Equal(t, queuedErr.Position, position)
+}
+
+func (s *ApprovalHandlerTestSuite) TestApprovalRejectedOverConcurrencyLimit() {
+	t := s.T()
+
+	request := pendingRequest(1)
+	s.mockStore.On("GetRequest", "request-id").Return(request, nil).Once()
+	s.mockStore.On("Approve", "request-id", "approver@example.com").Return(approved(request, "approver@example.com"), true, nil).Once()
+	s.mockStore.On("ReleaseClaim", "request-id").Return(nil).Once()
+	s.mockExecutor.On("Execute", mock.Anything, "job1", request.Args, request.CallbackURL, request.RequestedBy, []string{"approver@example.com"}).Return("", queue.ErrConcurrencyLimitReached).Once()
+
+	responseRecorder := s.serve("/jobs/requests/request-id/approve", "approver@example.com")
+
+	s.mockStore.AssertExpectations(t)
+	s.mockStore.AssertNotCalled(t, "DeleteRequest", mock.Anything)
+
+	assert.Equal(t, http.StatusTooManyRequests, responseRecorder.Code)
+	assert.Equal(t, utility.ErrCodeConcurrencyLimitReached, errorResponse(t, responseRecorder).Code)
+}
+
+func (s *ApprovalHandlerTestSuite) TestApprovalDeniedByPolicy() {
+	t := s.T()
+
+	request := pendingRequest(1)
+	s.mockStore.On("GetRequest", "request-id").Return(request, nil).Once()
+	s.mockStore.On("Approve", "request-id", "approver@example.com").Return(approved(request, "approver@example.com"), true, nil).Once()
+	s.mockStore.On("ReleaseClaim", "request-id").Return(nil).Once()
+	deniedErr := &policy.DeniedError{Denials: []policy.Denial{{Rule: "business-hours", Reason: "refunds run during business hours"}}}
+	s.mockExecutor.On("Execute", mock.Anything, "job1", request.Args, request.CallbackURL, request.RequestedBy, []string{"approver@example.com"}).Return("", deniedErr).Once()
+
+	responseRecorder := s.serve("/jobs/requests/request-id/approve", "approver@example.com")
+
+	s.mockStore.AssertExpectations(t)
+	s.mockStore.AssertNotCalled(t, "DeleteRequest", mock.Anything)
+
+	assert.Equal(t, http.StatusForbidden, responseRecorder.Code)
+	errorBody := errorResponse(t, responseRecorder)
+	assert.Equal(t, utility.ErrCodePolicyDenied, errorBody.Code)
+	assert.Equal(t, []utility.FieldError{{Field: "business-hours", Message: "refunds run during business hours"}}, errorBody.Details)
+}
+
+func (s *ApprovalHandlerTestSuite) TestApprovalOverRateLimit() {
+	t := s.T()
+
+	request := pendingRequest(1)
+	s.mockStore.On("GetRequest", "request-id").Return(request, nil).Once()
+	s.mockStore.On("Approve", "request-id", "approver@example.com").Return(approved(request, "approver@example.com"), true, nil).Once()
+	s.mockStore.On("ReleaseClaim", "request-id").Return(nil).Once()
+	s.mockExecutor.On("Execute", mock.Anything, "job1", request.Args, request.CallbackURL, request.RequestedBy, []string{"approver@example.com"}).Return("", ratelimit.ErrLimitExceeded).Once()
+
+	responseRecorder := s.serve("/jobs/requests/request-id/approve", "approver@example.com")
+
+	s.mockStore.AssertExpectations(t)
+
+	assert.Equal(t, http.StatusTooManyRequests, responseRecorder.Code)
+	assert.Equal(t, utility.ErrCodeRateLimited, errorResponse(t, responseRecorder).Code)
+}
+
+func (s *ApprovalHandlerTestSuite) TestApprovalWithoutEnoughApprovals() {
+	t := s.T()
+
+	request := pendingRequest(1)
+	s.mockStore.On("GetRequest", "request-id").Return(request, nil).Once()
+	s.mockStore.On("Approve", "request-id", "approver@example.com").Return(approved(request, "approver@example.com"), true, nil).Once()
+	s.mockStore.On("ReleaseClaim", "request-id").Return(nil).Once()
+	s.mockExecutor.On("Execute", mock.Anything, "job1", request.Args, request.CallbackURL, request.RequestedBy, []string{"approver@example.com"}).Return("", ErrApprovalRequired).Once()
+
+	responseRecorder := s.serve("/jobs/requests/request-id/approve", "approver@example.com")
+
+	s.mockStore.AssertExpectations(t)
+
+	assert.Equal(t, http.StatusForbidden, responseRecorder.Code)
+	assert.Equal(t, utility.ErrCodeApprovalRequired, errorResponse(t, responseRecorder).Code)
+}
+
+func (s *ApprovalHandlerTestSuite) TestConcurrentFinalApprovalIsNotExecutedTwice() {
+	t := s.T()
+
+	request := pendingRequest(1)
+	s.mockStore.On("GetRequest", "request-id").Return(request, nil).Once()
+	s.mockStore.On("Approve", "request-id", "approver@example.com").Return(approved(request, "approver@example.com"), false, nil).Once()
+
+	responseRecorder := s.serve("/jobs/requests/request-id/approve", "approver@example.com")
+
+	s.mockStore.AssertNotCalled(t, "DeleteRequest", mock.Anything)
+	s.mockExecutor.AssertNotCalled(t, "Execute", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	assert.Equal(t, http.StatusConflict, responseRecorder.Code)
+	assert.Equal(t, utility.ErrCodeRequestInProgress, errorResponse(t, responseRecorder).Code)
+}
+
+func (s *ApprovalHandlerTestSuite) TestApprovalOfRequestExpiredWhileApproving() {
+	t := s.T()
+
+	s.mockStore.On("GetRequest", "request-id").Return(pendingRequest(1), nil).Once()
+	s.mockStore.On("Approve", "request-id", "approver@example.com").Return(&Request{}, false, ErrRequestNotFound).Once()
+
+	responseRecorder := s.serve("/jobs/requests/request-id/approve", "approver@example.com")
+
+	s.mockExecutor.AssertNotCalled(t, "Execute", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	assert.Equal(t, http.StatusNotFound, responseRecorder.Code)
+	assert.Equal(t, utility.ErrCodeExecutionRequestNotFound, errorResponse(t, responseRecorder).Code)
+}
+
+func (s *ApprovalHandlerTestSuite) TestRejection() {
+	t := s.T()
+
+	s.mockStore.On("GetRequest", "request-id").Return(pendingRequest(1), nil).Once()
+	s.mockStore.On("DeleteRequest", "request-id").Return(nil).Once()
+
+	responseRecorder := s.serve("/jobs/requests/request-id/reject", "approver@example.com")
+
+	s.mockStore.AssertExpectations(t)
+	s.mockExecutor.AssertNotCalled(t, "Execute", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+}
+
+func (s *ApprovalHandlerTestSuite) TestRejectionOfUnknownRequest() {
+	t := s.T()
+
+	s.mockStore.On("GetRequest", "request-id").Return(&Request{}, ErrRequestNotFound).Once()
+
+	responseRecorder := s.serve("/jobs/requests/request-id/reject", "approver@example.com")
+
+	s.mockStore.AssertNotCalled(t, "DeleteRequest", mock.Anything)
+
+	assert.Equal(t, http.StatusNotFound, responseRecorder.Code)
+}
+
+func TestApprovalHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(ApprovalHandlerTestSuite))
+}
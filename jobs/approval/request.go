@@ -0,0 +1,36 @@
+package approval
+
+import "time"
+
+type Request struct {
+	ID                string            `json:"id"`
+	JobName           string            `json:"job_name"`
+	Args              map[string]string `json:"args"`
+	CallbackURL       string            `json:"callback_url,omitempty"`
+	RequestedBy       string            `json:"requested_by"`
+	RequiredApprovals int               `json:"required_approvals"`
+	ApprovedBy        []string          `json:"approved_by"`
+	CreatedAt         time.Time         `json:"created_at"`
+	ExpiresAt         time.Time         `json:"expires_at"`
+}
+
+func (request *Request) HasExpired() bool {
+	return time.Now().After(request.ExpiresAt)
+}
+
+func (request *Request) IsApprovedBy(userEmail string) bool {
+	for _, approver := range request.ApprovedBy {
+		if approver == userEmail {
+			return true
+		}
+	}
+	return false
+}
+
+func (request *Request) PendingApprovals() int {
+	pending := request.RequiredApprovals - len(request.ApprovedBy)
+	if pending < 0 {
+		return 0
+	}
+	return pending
+}
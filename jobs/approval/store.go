@@ -0,0 +1,145 @@
+package approval
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gojektech/proctor-engine/redis"
+)
+
+const (
+	RequestKeySuffix = "-execution-request"
+	ClaimKeySuffix   = "-execution-request-claim"
+)
+
+var ErrRequestNotFound = errors.New("execution request not found")
+
+// approveScript records an approval and, once no approvals are pending,
+// claims the request so that concurrent final approvals execute it only once.
+// The claim lives as long as the request unless released after a failed start.
+const approveScript = `
+local raw = redis.call('GET', KEYS[1])
+local ttl = redis.call('PTTL', KEYS[1])
+if not raw or ttl <= 0 then
+	return false
+end
+local request = cjson.decode(raw)
+local approvedBy = request.approved_by
+if type(approvedBy) ~= 'table' then
+	approvedBy = {}
+end
+local approved = false
+for _, approver in ipairs(approvedBy) do
+	if approver == ARGV[1] then
+		approved = true
+	end
+end
+if not approved then
+	table.insert(approvedBy, ARGV[1])
+end
+request.approved_by = approvedBy
+raw = cjson.encode(request)
+redis.call('SET', KEYS[1], raw, 'PX', ttl)
+local claimed = 0
+if #approvedBy >= tonumber(request.required_approvals) and redis.call('SET', KEYS[2], ARGV[1], 'NX', 'PX', ttl) then
+	claimed = 1
+end
+return {raw, claimed}
+`
+
+type Store interface {
+	SaveRequest(Request) error
+	GetRequest(string) (*Request, error)
+	Approve(string, string) (*Request, bool, error)
+	ReleaseClaim(string) error
+	DeleteRequest(string) error
+}
+
+type store struct {
+	redisClient redis.Client
+}
+
+func NewStore(redisClient redis.Client) Store {
+	return &store{
+		redisClient: redisClient,
+	}
+}
+
+func requestKey(requestID string) string {
+	return requestID + RequestKeySuffix
+}
+
+func claimKey(requestID string) string {
+	return requestID + ClaimKeySuffix
+}
+
+func (store *store) SaveRequest(request Request) error {
+	expiryInSeconds := int(request.ExpiresAt.Sub(time.Now()).Seconds())
+	if expiryInSeconds <= 0 {
+		return ErrRequestNotFound
+	}
+
+	binaryRequest, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	return store.redisClient.SETEX(requestKey(request.ID), expiryInSeconds, binaryRequest)
+}
+
+func (store *store) GetRequest(requestID string) (*Request, error) {
+	binaryRequest, err := store.redisClient.GET(requestKey(requestID))
+	if err == redis.ErrNil {
+		return nil, ErrRequestNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var request Request
+	err = json.Unmarshal(binaryRequest, &request)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.HasExpired() {
+		return nil, ErrRequestNotFound
+	}
+	return &request, nil
+}
+
+func (store *store) Approve(requestID string, approver string) (*Request, bool, error) {
+	reply, err := store.redisClient.EVAL(approveScript, 2, requestKey(requestID), claimKey(requestID), approver)
+	if err != nil {
+		return nil, false, err
+	}
+	if reply == nil {
+		return nil, false, ErrRequestNotFound
+	}
+
+	values, ok := reply.([]interface{})
+	if !ok || len(values) != 2 {
+		return nil, false, fmt.Errorf("unexpected reply approving execution request: %v", reply)
+	}
+	binaryRequest, _ := values[0].([]byte)
+	claimed, _ := values[1].(int64)
+
+	var request Request
+	if err := json.Unmarshal(binaryRequest, &request); err != nil {
+		return nil, false, err
+	}
+	return &request, claimed == 1, nil
+}
+
+func (store *store) ReleaseClaim(requestID string) error {
+	return store.redisClient.DEL(claimKey(requestID))
+}
+
+func (store *store) DeleteRequest(requestID string) error {
+	if err := store.redisClient.DEL(requestKey(requestID)); err != nil {
+		return err
+	}
+	return store.redisClient.DEL(claimKey(requestID))
+}
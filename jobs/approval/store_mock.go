@@ -0,0 +1,34 @@
+package approval
+
+import (
+	"github.com/stretchr/testify/mock"
+)
+
+type MockStore struct {
+	mock.Mock
+}
+
+func (m *MockStore) SaveRequest(request Request) error {
+	args := m.Called(request)
+	return args.Error(0)
+}
+
+func (m *MockStore) GetRequest(requestID string) (*Request, error) {
+	args := m.Called(requestID)
+	return args.Get(0).(*Request), args.Error(1)
+}
+
+func (m *MockStore) Approve(requestID string, approver string) (*Request, bool, error) {
+	args := m.Called(requestID, approver)
+	return args.Get(0).(*Request), args.Bool(1), args.Error(2)
+}
+
+func (m *MockStore) ReleaseClaim(requestID string) error {
+	args := m.Called(requestID)
+	return args.Error(0)
+}
+
+func (m *MockStore) DeleteRequest(requestID string) error {
+	args := m.Called(requestID)
+	return args.Error(0)
+}
@@ -0,0 +1,164 @@
+package approval
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gojektech/proctor-engine/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type ApprovalStoreTestSuite struct {
+	suite.Suite
+	mockRedisClient   *redis.MockClient
+	testApprovalStore Store
+}
+
+func (s *ApprovalStoreTestSuite) SetupTest() {
+	s.mockRedisClient = &redis.MockClient{}
+
+	s.testApprovalStore = NewStore(s.mockRedisClient)
+}
+
+func (s *ApprovalStoreTestSuite) TestSaveRequest() {
+	t := s.T()
+
+	request := Request{
+		ID:        "request-id",
+		JobName:   "job1",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	binaryRequest, err := json.Marshal(request)
+	assert.NoError(t, err)
+
+	s.mockRedisClient.On("SETEX", "request-id-execution-request", mock.MatchedBy(func(expiry int) bool {
+		return expiry > 3500 && expiry <= 3600
+	}), binaryRequest).Return(nil).Once()
+
+	err = s.testApprovalStore.SaveRequest(request)
+	assert.NoError(t, err)
+
+	s.mockRedisClient.AssertExpectations(t)
+}
+
+func (s *ApprovalStoreTestSuite) TestSaveExpiredRequest() {
+	t := s.T()
+
+	request := Request{
+		ID:        "request-id",
+		ExpiresAt: time.Now().Add(-time.Minute),
+	}
+
+	err := s.testApprovalStore.SaveRequest(request)
+	assert.Equal(t, ErrRequestNotFound, err)
+
+	s.mockRedisClient.AssertNotCalled(t, "SETEX", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *ApprovalStoreTestSuite) TestGetRequest() {
+	t := s.T()
+
+	request := Request{
+		ID:        "request-id",
+		JobName:   "job1",
+		ExpiresAt: time.Now().Add(time.Hour).Round(time.Second),
+	}
+	binaryRequest, err := json.Marshal(request)
+	assert.NoError(t, err)
+
+	s.mockRedisClient.On("GET", "request-id-execution-request").Return(binaryRequest, nil).Once()
+
+	savedRequest, err := s.testApprovalStore.GetRequest("request-id")
+	assert.NoError(t, err)
+
+	assert.Equal(t, request.JobName, savedRequest.JobName)
+	assert.True(t, request.ExpiresAt.Equal(savedRequest.ExpiresAt))
+	s.mockRedisClient.AssertExpectations(t)
+}
+
+func (s *ApprovalStoreTestSuite) TestGetMissingRequest() {
+	t := s.T()
+
+	s.mockRedisClient.On("GET", "request-id-execution-request").Return([]byte{}, redis.ErrNil).Once()
+
+	_, err := s.testApprovalStore.GetRequest("request-id")
+	assert.Equal(t, ErrRequestNotFound, err)
+}
+
+func (s *ApprovalStoreTestSuite) TestGetRequestRedisFailure() {
+	t := s.T()
+
+	s.mockRedisClient.On("GET", "request-id-execution-request").Return([]byte{}, errors.New("error")).Once()
+
+	_, err := s.testApprovalStore.GetRequest("request-id")
+	assert.EqualError(t, err, "error")
+}
+
+func (s *ApprovalStoreTestSuite) TestDeleteRequest() {
+	t := s.T()
+
+	s.mockRedisClient.On("DEL", "request-id-execution-request").Return(nil).Once()
+	s.mockRedisClient.On("DEL", "request-id-execution-request-claim").Return(nil).Once()
+
+	err := s.testApprovalStore.DeleteRequest("request-id")
+	assert.NoError(t, err)
+
+	s.mockRedisClient.AssertExpectations(t)
+}
+
+func (s *ApprovalStoreTestSuite) TestApproveClaimsFinalApproval() {
+	t := s.T()
+
+	request := Request{ID: "request-id", JobName: "job1", RequiredApprovals: 1, ApprovedBy: []string{"approver@example.com"}}
+	binaryRequest, err := json.Marshal(request)
+	assert.NoError(t, err)
+	s.mockRedisClient.On("EVAL", approveScript, 2, "request-id-execution-request", "request-id-execution-request-claim", "approver@example.com").Return([]interface{}{binaryRequest, int64(1)}, nil).Once()
+
+	approvedRequest, claimed, err := s.testApprovalStore.Approve("request-id", "approver@example.com")
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+	assert.Equal(t, []string{"approver@example.com"}, approvedRequest.ApprovedBy)
+
+	s.mockRedisClient.AssertExpectations(t)
+}
+
+func (s *ApprovalStoreTestSuite) TestApproveWithoutClaim() {
+	t := s.T()
+
+	binaryRequest, err := json.Marshal(Request{ID: "request-id", RequiredApprovals: 2, ApprovedBy: []string{"approver@example.com"}})
+	assert.NoError(t, err)
+	s.mockRedisClient.On("EVAL", approveScript, 2, "request-id-execution-request", "request-id-execution-request-claim", "approver@example.com").Return([]interface{}{binaryRequest, int64(0)}, nil).Once()
+
+	approvedRequest, claimed, err := s.testApprovalStore.Approve("request-id", "approver@example.com")
+	assert.NoError(t, err)
+	assert.False(t, claimed)
+	assert.Equal(t, 1, approvedRequest.PendingApprovals())
+}
+
+func (s *ApprovalStoreTestSuite) TestApproveMissingRequest() {
+	t := s.T()
+
+	s.mockRedisClient.On("EVAL", approveScript, 2, "request-id-execution-request", "request-id-execution-request-claim", "approver@example.com").Return(nil, nil).Once()
+
+	_, _, err := s.testApprovalStore.Approve("request-id", "approver@example.com")
+	assert.Equal(t, ErrRequestNotFound, err)
+}
+
+func (s *ApprovalStoreTestSuite) TestReleaseClaim() {
+	t := s.T()
+
+	s.mockRedisClient.On("DEL", "request-id-execution-request-claim").Return(nil).Once()
+
+	err := s.testApprovalStore.ReleaseClaim("request-id")
+	assert.NoError(t, err)
+
+	s.mockRedisClient.AssertExpectations(t)
+}
+
+func TestApprovalStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(ApprovalStoreTestSuite))
+}
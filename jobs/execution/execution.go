@@ -0,0 +1,46 @@
+package execution
+
+import (
+	"time"
+
+	"github.com/gojektech/proctor-engine/jobs/metadata"
+	"github.com/gojektech/proctor-engine/kubernetes"
+)
+
+type Execution struct {
+	Name            string                   `json:"name"`
+	JobName         string                   `json:"job_name"`
+	Cluster         string                   `json:"cluster,omitempty"`
+	Namespace       string                   `json:"namespace,omitempty"`
+	Status          string                   `json:"status"`
+	ExitCode        *int32                   `json:"exit_code,omitempty"`
+	Output          string                   `json:"output,omitempty"`
+	Attempt         int                      `json:"attempt,omitempty"`
+	PreviousAttempt string                   `json:"previous_attempt,omitempty"`
+	NextAttempt     string                   `json:"next_attempt,omitempty"`
+	RetryPending    bool                     `json:"retry_pending,omitempty"`
+	StartedAt       time.Time                `json:"started_at"`
+	FinishedAt      *time.Time               `json:"finished_at,omitempty"`
+	CallbackURL     string                   `json:"-"`
+	Webhooks        []string                 `json:"-"`
+	Spec            kubernetes.ExecutionSpec `json:"-"`
+	RetryPolicy     *metadata.RetryPolicy    `json:"-"`
+	SlotID          string                   `json:"-"`
+}
+
+func (execution Execution) Target() kubernetes.Target {
+	return kubernetes.Target{Cluster: execution.Cluster, Namespace: execution.Namespace}
+}
+
+func (execution Execution) HasFinished() bool {
+	return execution.FinishedAt != nil
+}
+
+func (execution Execution) WebhookURLs() []string {
+	var urls []string
+	urls = append(urls, execution.Webhooks...)
+	if execution.CallbackURL != "" {
+		urls = append(urls, execution.CallbackURL)
+	}
+	return urls
+}
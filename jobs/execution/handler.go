@@ -1,79 +1,560 @@
 package execution
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"net/http"
+	"time"
 
+	"github.com/gojektech/proctor-engine/config"
+	"github.com/gojektech/proctor-engine/jobs/approval"
+	"github.com/gojektech/proctor-engine/jobs/idempotency"
 	"github.com/gojektech/proctor-engine/jobs/metadata"
+	"github.com/gojektech/proctor-engine/jobs/policy"
+	"github.com/gojektech/proctor-engine/jobs/queue"
 	"github.com/gojektech/proctor-engine/jobs/secrets"
 	"github.com/gojektech/proctor-engine/kubernetes"
 	"github.com/gojektech/proctor-engine/logger"
+	"github.com/gojektech/proctor-engine/metrics"
+	"github.com/gojektech/proctor-engine/ratelimit"
 	"github.com/gojektech/proctor-engine/utility"
+
+	"github.com/gorilla/mux"
 )
 
+const jobRateLimitKeySuffix = "-executions"
+
 type executioner struct {
-	kubeClient    kubernetes.Client
-	metadataStore metadata.Store
-	secretsStore  secrets.Store
+	kubeClient       kubernetes.Client
+	metadataStore    metadata.Store
+	secretsStore     secrets.Store
+	approvalStore    approval.Store
+	store            Store
+	idempotencyStore idempotency.Store
+	queueStore       queue.Store
+	tracker          Tracker
+	policyEngine     policy.Engine
+	rateLimiter      ratelimit.Limiter
 }
 
 type Executioner interface {
 	Handle() http.HandlerFunc
+	HandleExecution() http.HandlerFunc
+	HandleOutput() http.HandlerFunc
+	HandleStatus() http.HandlerFunc
+	HandleCancellation() http.HandlerFunc
+	HandleQueueStatus() http.HandlerFunc
+	HandleQueueCancellation() http.HandlerFunc
+	StartDispatcher(context.Context)
+	Execute(context.Context, string, map[string]string, string, string, []string) (string, error)
 }
 
-func NewExecutioner(kubeClient kubernetes.Client, metadataStore metadata.Store, secretsStore secrets.Store) Executioner {
+func NewExecutioner(kubeClient kubernetes.Client, metadataStore metadata.Store, secretsStore secrets.Store, approvalStore approval.Store, store Store, idempotencyStore idempotency.Store, queueStore queue.Store, tracker Tracker, policyEngine policy.Engine, rateLimiter ratelimit.Limiter) Executioner {
 	return &executioner{
-		kubeClient:    kubeClient,
-		metadataStore: metadataStore,
-		secretsStore:  secretsStore,
+		kubeClient:       kubeClient,
+		metadataStore:    metadataStore,
+		secretsStore:     secretsStore,
+		approvalStore:    approvalStore,
+		store:            store,
+		idempotencyStore: idempotencyStore,
+		queueStore:       queueStore,
+		tracker:          tracker,
+		policyEngine:     policyEngine,
+		rateLimiter:      rateLimiter,
 	}
 }
 
 func (executioner *executioner) Handle() http.HandlerFunc {
+	return executioner.handle(false)
+}
+
+func (executioner *executioner) HandleExecution() http.HandlerFunc {
+	return executioner.handle(true)
+}
+
+func (executioner *executioner) handle(nameFromPath bool) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
-		var job Job
-		err := json.NewDecoder(req.Body).Decode(&job)
+		job, err := decodeJob(w, req)
 		defer req.Body.Close()
+		if err == errFilesTooLarge {
+			utility.WriteError(w, req, http.StatusRequestEntityTooLarge, utility.ErrCodeFilesTooLarge, fmt.Sprintf("uploaded files must not exceed %d bytes", fileInputMaxBytes()))
+			return
+		}
 		if err != nil {
-			logger.Error("Error parsing request body", err.Error())
+			logger.FromContext(req.Context()).Error("Error parsing request body", err.Error())
 
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write([]byte(utility.ClientError))
+			utility.WriteError(w, req, http.StatusBadRequest, utility.ErrCodeMalformedRequest, utility.ClientError)
 			return
 		}
+		if nameFromPath {
+			job.Name = mux.Vars(req)["name"]
+		}
 
-		jobMetadata, err := executioner.metadataStore.GetJobMetadata(job.Name)
-		if err != nil {
-			logger.Error("Error finding job to image", job.Name, err.Error())
+		waitOptions, waitFieldErrors := parseWaitOptions(req.URL.Query())
+		if fieldErrors := append(job.Validate(), waitFieldErrors...); len(fieldErrors) > 0 {
+			utility.WriteValidationError(w, req, fieldErrors)
+			return
+		}
+
+		ctx := logger.WithFields(req.Context(), logger.Fields{logger.JobNameField: job.Name})
 
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(utility.ServerError))
+		idempotencyKey := req.Header.Get(idempotency.HeaderKey)
+		if idempotencyKey != "" {
+			executioner.executeIdempotently(ctx, w, req, idempotencyKey, job, waitOptions)
 			return
 		}
 
-		jobSecrets, err := executioner.secretsStore.GetJobSecrets(job.Name)
-		if err != nil {
-			logger.Error("Error retrieving secrets for job", job.Name, err.Error())
+		executioner.execute(ctx, w, req, job, waitOptions, nil)
+	}
+}
+
+// execute calls started, when given, as soon as the job is running on kubernetes.
+func (executioner *executioner) execute(ctx context.Context, w http.ResponseWriter, req *http.Request, job Job, waitOptions *WaitOptions, started func(executedJobName string)) {
+	log := logger.FromContext(ctx)
+
+	jobMetadata, err := executioner.metadataStore.GetJobMetadata(job.Name)
+	if err == metadata.ErrJobNotFound {
+		utility.WriteError(w, req, http.StatusNotFound, utility.ErrCodeJobNotFound, fmt.Sprintf("job %s not found", job.Name))
+		return
+	}
+	if err != nil {
+		log.Error("Error finding job to image", err.Error())
+
+		utility.WriteError(w, req, http.StatusServiceUnavailable, utility.ErrCodeStoreUnavailable, "unable to fetch job metadata")
+		return
+	}
 
-			w.WriteHeader(http.StatusNotFound)
-			w.Write([]byte(utility.ServerError))
+	if err := jobMetadata.CheckImagePolicy(); err != nil {
+		log.Error("Job image rejected by image policy", err.Error())
+
+		utility.WriteError(w, req, http.StatusForbidden, utility.ErrCodeImageNotAllowed, err.Error())
+		return
+	}
+
+	if _, err := jobMetadata.RenderArgs(job.Args); err != nil {
+		utility.WriteValidationError(w, req, []utility.FieldError{{Field: "args", Message: err.Error()}})
+		return
+	}
+
+	if fieldErrors := jobMetadata.ValidateFiles(job.Files); len(fieldErrors) > 0 {
+		utility.WriteValidationError(w, req, fieldErrors)
+		return
+	}
+	if len(job.Files) > 0 && jobMetadata.RequiredApprovals > 0 {
+		utility.WriteValidationError(w, req, []utility.FieldError{{Field: "files", Message: "are not supported for jobs requiring approval"}})
+		return
+	}
+
+	if job.Cluster != "" || job.Namespace != "" {
+		if jobMetadata.RequiredApprovals > 0 {
+			utility.WriteValidationError(w, req, []utility.FieldError{{Field: "namespace", Message: "cannot be overridden for jobs requiring approval"}})
+			return
+		}
+		if fieldErrors := kubernetes.ValidateTarget(jobMetadata.Target().Override(job.Target())); len(fieldErrors) > 0 {
+			utility.WriteValidationError(w, req, fieldErrors)
 			return
 		}
+	}
 
-		envVars := utility.MergeMaps(job.Args, jobSecrets)
-		imageName := jobMetadata.ImageName
-		executedJobName, err := executioner.kubeClient.ExecuteJob(imageName, envVars)
-		if err != nil {
-			logger.Error("Error executing job: %v", job, imageName, err.Error())
+	policyInput := job.policyInput(jobMetadata, req.Header.Get(utility.UserEmailHeaderKey))
+
+	// Jobs requiring approval are counted and rate limited when the approved
+	// request is executed, so submission only checks whether they could run.
+	if jobMetadata.RequiredApprovals > 0 {
+		policyInput.Approved = true
+		denials, err := executioner.policyEngine.Evaluate(ctx, policyInput)
+		if !allowedByPolicy(w, req, log, denials, err) {
+			return
+		}
 
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(utility.ServerError))
+		executioner.requestApproval(ctx, w, req, job, jobMetadata)
+		return
+	}
+
+	denials, err := executioner.policyEngine.Admit(ctx, policyInput)
+	if !allowedByPolicy(w, req, log, denials, err) {
+		return
+	}
+
+	if limit := jobMetadata.RateLimit.Limit(); limit.Enabled() {
+		if !executioner.allowJobExecution(ctx, w, req, job.Name, limit) {
+			executioner.policyEngine.Release(ctx, policyInput)
 			return
 		}
+	}
+
+	jobSecrets, err := executioner.jobSecrets(job.Name)
+	if err != nil {
+		log.Error("Error retrieving secrets for job", err.Error())
+
+		executioner.policyEngine.Release(ctx, policyInput)
+
+		utility.WriteError(w, req, http.StatusServiceUnavailable, utility.ErrCodeStoreUnavailable, "unable to fetch job secrets")
+		return
+	}
+
+	spec, err := executionSpec(jobMetadata, job, jobSecrets)
+	if err != nil {
+		executioner.policyEngine.Release(ctx, policyInput)
+
+		utility.WriteValidationError(w, req, []utility.FieldError{{Field: "args", Message: err.Error()}})
+		return
+	}
+
+	slotID, err := executioner.admit(ctx, job, jobMetadata)
+	if err != nil {
+		if _, queued := err.(*queue.QueuedError); !queued {
+			executioner.policyEngine.Release(ctx, policyInput)
+		}
+
+		writeAdmissionError(w, req, log, err)
+		return
+	}
+
+	executedJobName, err := executioner.runJob(ctx, job, jobMetadata, spec, slotID)
+	if err != nil {
+		log.Error("Error executing job with image", spec.ImageName, err.Error())
+
+		executioner.releaseSlot(ctx, job.Name, slotID)
+		executioner.policyEngine.Release(ctx, policyInput)
+
+		utility.WriteError(w, req, http.StatusInternalServerError, utility.ErrCodeExecutionFailed, "unable to start job on kubernetes")
+		return
+	}
+
+	if started != nil {
+		started(executedJobName)
+	}
+
+	if waitOptions != nil {
+		executioner.waitForCompletion(ctx, w, spec.Target, executedJobName, waitOptions)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	w.Write(createdBody(executedJobName))
+}
+
+func createdBody(executedJobName string) []byte {
+	return []byte(fmt.Sprintf("{ \"name\":\"%s\" }", executedJobName))
+}
+
+func allowedByPolicy(w http.ResponseWriter, req *http.Request, log *logger.Entry, denials []policy.Denial, err error) bool {
+	if err != nil {
+		log.Error("Error evaluating execution policies", err.Error())
+
+		utility.WriteError(w, req, http.StatusServiceUnavailable, utility.ErrCodeStoreUnavailable, "unable to evaluate execution policies")
+		return false
+	}
+	if len(denials) > 0 {
+		deniedErr := &policy.DeniedError{Denials: denials}
+		utility.WriteError(w, req, http.StatusForbidden, utility.ErrCodePolicyDenied, "execution denied by policy", deniedErr.Details()...)
+		return false
+	}
+	return true
+}
+
+func (executioner *executioner) allowJobExecution(ctx context.Context, w http.ResponseWriter, req *http.Request, jobName string, limit ratelimit.Limit) bool {
+	result, err := executioner.rateLimiter.Allow(jobName+jobRateLimitKeySuffix, limit)
+	if err != nil {
+		logger.FromContext(ctx).Error("Error checking job execution rate limit, allowing execution", err.Error())
+		return true
+	}
+
+	result.WriteHeaders(w)
+	if !result.Allowed {
+		utility.WriteError(w, req, http.StatusTooManyRequests, utility.ErrCodeRateLimited, fmt.Sprintf("execution rate limit reached for job %s, retry later", jobName))
+		return false
+	}
+	return true
+}
+
+func (executioner *executioner) executeIdempotently(ctx context.Context, w http.ResponseWriter, req *http.Request, idempotencyKey string, job Job, waitOptions *WaitOptions) {
+	requester := req.Header.Get(utility.UserEmailHeaderKey)
+	log := logger.FromContext(ctx).WithField("idempotency_key", idempotencyKey)
+
+	requestHash, err := idempotency.RequestHash(struct {
+		Requester string
+		Job       Job
+		Files     map[string][]byte
+		Query     string
+	}{requester, job, job.Files, req.URL.RawQuery})
+	if err != nil {
+		log.Error("Error hashing execution request", err.Error())
+
+		utility.WriteError(w, req, http.StatusInternalServerError, utility.ErrCodeInternal, utility.ServerError)
+		return
+	}
+
+	reserved, err := executioner.idempotencyStore.Reserve(requester, idempotencyKey, requestHash)
+	if err != nil {
+		log.Error("Error reserving idempotency key", err.Error())
+
+		utility.WriteError(w, req, http.StatusServiceUnavailable, utility.ErrCodeStoreUnavailable, "unable to reserve idempotency key")
+		return
+	}
+
+	if !reserved {
+		executioner.replay(w, req, log, requester, idempotencyKey, requestHash)
+		return
+	}
+
+	recorder := idempotency.NewResponseRecorder(w)
+	launched := false
+	executioner.execute(ctx, recorder, req, job, waitOptions, func(executedJobName string) {
+		launched = true
+		// Waiting can outlive the client, so the key is committed to the
+		// running job before waiting starts.
+		if waitOptions != nil {
+			executioner.completeIdempotencyKey(log, requester, idempotencyKey, requestHash, http.StatusCreated, createdBody(executedJobName))
+		}
+	})
 
-		w.WriteHeader(http.StatusCreated)
-		w.Write([]byte(fmt.Sprintf("{ \"name\":\"%s\" }", executedJobName)))
+	if !launched && recorder.Status() != http.StatusAccepted {
+		if err := executioner.idempotencyStore.Release(requester, idempotencyKey); err != nil {
+			log.Error("Error releasing idempotency key", err.Error())
+		}
+		return
+	}
+	if recorder.Status() == 0 {
+		return
+	}
+
+	executioner.completeIdempotencyKey(log, requester, idempotencyKey, requestHash, recorder.Status(), recorder.Body())
+}
 
+func (executioner *executioner) completeIdempotencyKey(log *logger.Entry, requester string, idempotencyKey string, requestHash string, statusCode int, body []byte) {
+	record := idempotency.Record{
+		RequestHash: requestHash,
+		StatusCode:  statusCode,
+		Body:        body,
+		CreatedAt:   time.Now(),
 	}
+	if err := executioner.idempotencyStore.Complete(requester, idempotencyKey, record); err != nil {
+		log.Error("Error saving response for idempotency key", err.Error())
+	}
+}
+
+func (executioner *executioner) replay(w http.ResponseWriter, req *http.Request, log *logger.Entry, requester string, idempotencyKey string, requestHash string) {
+	record, err := executioner.idempotencyStore.Get(requester, idempotencyKey)
+	if err == idempotency.ErrKeyNotFound {
+		utility.WriteError(w, req, http.StatusConflict, utility.ErrCodeRequestInProgress, "request with this idempotency key is being processed, retry later")
+		return
+	}
+	if err != nil {
+		log.Error("Error fetching idempotency key", err.Error())
+
+		utility.WriteError(w, req, http.StatusServiceUnavailable, utility.ErrCodeStoreUnavailable, "unable to fetch idempotency key")
+		return
+	}
+
+	if !record.Matches(requestHash) {
+		utility.WriteError(w, req, http.StatusConflict, utility.ErrCodeIdempotencyKeyReused, "idempotency key was already used for a different request")
+		return
+	}
+
+	if !record.Completed {
+		utility.WriteError(w, req, http.StatusConflict, utility.ErrCodeRequestInProgress, "request with this idempotency key is being processed, retry later")
+		return
+	}
+
+	log.Info("Replaying response for idempotency key")
+
+	w.Header().Set(idempotency.ReplayedHeaderKey, "true")
+	w.WriteHeader(record.StatusCode)
+	w.Write(record.Body)
+}
+
+func (executioner *executioner) requestApproval(ctx context.Context, w http.ResponseWriter, req *http.Request, job Job, jobMetadata *metadata.Metadata) {
+	log := logger.FromContext(ctx)
+
+	requester := req.Header.Get(utility.UserEmailHeaderKey)
+	if requester == "" {
+		log.Error("No requester provided for job requiring approval")
+
+		utility.WriteValidationError(w, req, []utility.FieldError{{Field: utility.UserEmailHeaderKey, Message: "header is required for jobs requiring approval"}})
+		return
+	}
+
+	requestID, err := utility.RandomID()
+	if err != nil {
+		log.Error("Error generating execution request id", err.Error())
+
+		utility.WriteError(w, req, http.StatusInternalServerError, utility.ErrCodeInternal, utility.ServerError)
+		return
+	}
+
+	now := time.Now()
+	request := approval.Request{
+		ID:                requestID,
+		JobName:           job.Name,
+		Args:              job.Args,
+		CallbackURL:       job.CallbackURL,
+		RequestedBy:       requester,
+		RequiredApprovals: jobMetadata.RequiredApprovals,
+		CreatedAt:         now,
+		ExpiresAt:         now.Add(config.ExecutionRequestExpiry()),
+	}
+
+	err = executioner.approvalStore.SaveRequest(request)
+	if err != nil {
+		log.Error("Error saving execution request for job", err.Error())
+
+		utility.WriteError(w, req, http.StatusServiceUnavailable, utility.ErrCodeStoreUnavailable, "unable to save execution request")
+		return
+	}
+
+	log.Info("Execution request awaiting approval", requestID)
+
+	w.WriteHeader(http.StatusAccepted)
+	w.Write([]byte(fmt.Sprintf("{ \"request_id\":\"%s\" }", requestID)))
+}
+
+// Execute runs a job on behalf of approvals and workflows. It enforces the
+// same approval, policy and rate limit checks as an execution request.
+func (executioner *executioner) Execute(ctx context.Context, jobName string, args map[string]string, callbackURL string, requester string, approvedBy []string) (string, error) {
+	ctx = logger.WithFields(ctx, logger.Fields{logger.JobNameField: jobName})
+
+	jobMetadata, err := executioner.metadataStore.GetJobMetadata(jobName)
+	if err != nil {
+		return "", err
+	}
+
+	if fieldErrors := jobMetadata.ValidateFiles(nil); len(fieldErrors) > 0 {
+		return "", fmt.Errorf("job %s %s %s", jobName, fieldErrors[0].Field, fieldErrors[0].Message)
+	}
+
+	if len(approvedBy) < jobMetadata.RequiredApprovals {
+		return "", approval.ErrApprovalRequired
+	}
+
+	job := Job{Name: jobName, Args: args, CallbackURL: callbackURL}
+	policyInput := job.policyInput(jobMetadata, requester)
+	policyInput.Approvers = approvedBy
+	policyInput.Approved = len(approvedBy) > 0
+	denials, err := executioner.policyEngine.Admit(ctx, policyInput)
+	if err != nil {
+		return "", err
+	}
+	if len(denials) > 0 {
+		return "", &policy.DeniedError{Denials: denials}
+	}
+
+	executedJobName, err := executioner.start(ctx, job, jobMetadata)
+	if err != nil {
+		if _, queued := err.(*queue.QueuedError); !queued {
+			executioner.policyEngine.Release(ctx, policyInput)
+		}
+		return "", err
+	}
+	return executedJobName, nil
+}
+
+func (executioner *executioner) start(ctx context.Context, job Job, jobMetadata *metadata.Metadata) (string, error) {
+	if limit := jobMetadata.RateLimit.Limit(); limit.Enabled() {
+		result, err := executioner.rateLimiter.Allow(job.Name+jobRateLimitKeySuffix, limit)
+		if err != nil {
+			logger.FromContext(ctx).Error("Error checking job execution rate limit, allowing execution", err.Error())
+		} else if !result.Allowed {
+			return "", ratelimit.ErrLimitExceeded
+		}
+	}
+
+	jobSecrets, err := executioner.jobSecrets(job.Name)
+	if err != nil {
+		return "", err
+	}
+
+	spec, err := executionSpec(jobMetadata, job, jobSecrets)
+	if err != nil {
+		return "", err
+	}
+
+	slotID, err := executioner.admit(ctx, job, jobMetadata)
+	if err != nil {
+		return "", err
+	}
+
+	executedJobName, err := executioner.runJob(ctx, job, jobMetadata, spec, slotID)
+	if err != nil {
+		executioner.releaseSlot(ctx, job.Name, slotID)
+		return "", err
+	}
+	return executedJobName, nil
+}
+
+// jobSecrets treats a job without a secrets record as having no secrets.
+func (executioner *executioner) jobSecrets(jobName string) (map[string]string, error) {
+	jobSecrets, err := executioner.secretsStore.GetJobSecrets(jobName)
+	if err == secrets.ErrSecretsNotFound {
+		return map[string]string{}, nil
+	}
+	return jobSecrets, err
+}
+
+func executionSpec(jobMetadata *metadata.Metadata, job Job, jobSecrets map[string]string) (kubernetes.ExecutionSpec, error) {
+	if err := jobMetadata.CheckImagePolicy(); err != nil {
+		return kubernetes.ExecutionSpec{}, err
+	}
+
+	args, err := jobMetadata.RenderArgs(job.Args)
+	if err != nil {
+		return kubernetes.ExecutionSpec{}, err
+	}
+
+	var files []kubernetes.File
+	for _, fileInput := range jobMetadata.Files {
+		content, ok := job.Files[fileInput.Name]
+		if !ok {
+			continue
+		}
+		files = append(files, kubernetes.File{
+			Name:      fileInput.Name,
+			MountPath: fileInput.MountPath,
+			Content:   content,
+			Sensitive: fileInput.Sensitive,
+		})
+	}
+
+	return kubernetes.ExecutionSpec{
+		ImageName:    jobMetadata.Image(),
+		Target:       jobMetadata.Target().Override(job.Target()),
+		Command:      jobMetadata.Command,
+		Args:         args,
+		WorkingDir:   jobMetadata.WorkingDir,
+		EnvVars:      utility.MergeMaps(job.Args, jobSecrets),
+		BackoffLimit: jobMetadata.Retry.KubernetesBackoffLimit(),
+		Scheduling:   jobMetadata.Scheduling,
+		Volumes:      jobMetadata.Volumes,
+		Files:        files,
+	}, nil
+}
+
+func (executioner *executioner) runJob(ctx context.Context, job Job, jobMetadata *metadata.Metadata, spec kubernetes.ExecutionSpec, slotID string) (string, error) {
+	if slotID != "" {
+		spec.Slot = kubernetes.Slot{JobName: job.Name, ID: slotID}
+	}
+
+	startedAt := time.Now()
+	executedJobName, err := executioner.kubeClient.ExecuteJob(ctx, spec)
+	if err != nil {
+		metrics.ExecutionFailed(job.Name)
+		return "", err
+	}
+
+	metrics.ExecutionStarted(job.Name)
+	executioner.tracker.Track(ctx, Execution{
+		Name:        executedJobName,
+		JobName:     job.Name,
+		Cluster:     spec.Target.Cluster,
+		Namespace:   spec.Target.Namespace,
+		CallbackURL: job.CallbackURL,
+		Webhooks:    jobMetadata.Webhooks,
+		Attempt:     1,
+		StartedAt:   startedAt,
+		Spec:        spec,
+		RetryPolicy: jobMetadata.Retry,
+		SlotID:      slotID,
+	})
+	return executedJobName, nil
 }
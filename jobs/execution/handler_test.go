@@ -2,18 +2,29 @@ package execution
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"testing"
+	"time"
 
+	"github.com/gojektech/proctor-engine/jobs/approval"
+	"github.com/gojektech/proctor-engine/jobs/idempotency"
 	"github.com/gojektech/proctor-engine/jobs/metadata"
+	"github.com/gojektech/proctor-engine/jobs/policy"
+	"github.com/gojektech/proctor-engine/jobs/queue"
 	"github.com/gojektech/proctor-engine/jobs/secrets"
 	"github.com/gojektech/proctor-engine/kubernetes"
+	"github.com/gojektech/proctor-engine/ratelimit"
 	"github.com/gojektech/proctor-engine/utility"
 
+	"github.com/gorilla/mux"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
@@ -24,6 +35,13 @@ type ExecutionerTestSuite struct {
 	mockKubeClient    kubernetes.MockClient
 	mockMetadataStore *metadata.MockStore
 	mockSecretsStore  *secrets.MockStore
+	mockApprovalStore *approval.MockStore
+	mockStore         *MockStore
+	mockIdempotency   *idempotency.MockStore
+	mockQueueStore    *queue.MockStore
+	mockTracker       *MockTracker
+	mockPolicyEngine  *policy.MockEngine
+	mockRateLimiter   *ratelimit.MockLimiter
 	testExecutioner   Executioner
 }
 
@@ -31,7 +49,35 @@ func (suite *ExecutionerTestSuite) SetupTest() {
 	suite.mockKubeClient = kubernetes.MockClient{}
 	suite.mockMetadataStore = &metadata.MockStore{}
 	suite.mockSecretsStore = &secrets.MockStore{}
-	suite.testExecutioner = NewExecutioner(&suite.mockKubeClient, suite.mockMetadataStore, suite.mockSecretsStore)
+	suite.mockApprovalStore = &approval.MockStore{}
+	suite.mockStore = &MockStore{}
+	suite.mockIdempotency = &idempotency.MockStore{}
+	suite.mockQueueStore = &queue.MockStore{}
+	suite.mockTracker = &MockTracker{}
+	suite.mockPolicyEngine = &policy.MockEngine{}
+	suite.mockPolicyEngine.On("Evaluate", mock.Anything, mock.Anything).Return([]policy.Denial(nil), nil)
+	suite.mockPolicyEngine.On("Admit", mock.Anything, mock.Anything).Return([]policy.Denial(nil), nil)
+	suite.mockPolicyEngine.On("Release", mock.Anything, mock.Anything).Return()
+	suite.mockRateLimiter = &ratelimit.MockLimiter{}
+	suite.testExecutioner = NewExecutioner(&suite.mockKubeClient, suite.mockMetadataStore, suite.mockSecretsStore, suite.mockApprovalStore, suite.mockStore, suite.mockIdempotency, suite.mockQueueStore, suite.mockTracker, suite.mockPolicyEngine, suite.mockRateLimiter)
+}
+
+func (suite *ExecutionerTestSuite) serveOutput(executedJobName string) *httptest.ResponseRecorder {
+	router := mux.NewRouter()
+	router.HandleFunc("/jobs/execute/{name}/output", suite.testExecutioner.HandleOutput()).Methods("GET")
+
+	req := httptest.NewRequest("GET", fmt.Sprintf("/jobs/execute/%s/output", executedJobName), nil)
+	responseRecorder := httptest.NewRecorder()
+
+	router.ServeHTTP(responseRecorder, req)
+	return responseRecorder
+}
+
+func errorResponse(t *testing.T, responseRecorder *httptest.ResponseRecorder) utility.Error {
+	var errorResponse utility.ErrorResponse
+	err := json.Unmarshal(responseRecorder.Body.Bytes(), &errorResponse)
+	assert.NoError(t, err)
+	return errorResponse.Error
 }
 
 func (suite *ExecutionerTestSuite) TestSuccessfulJobExecution() {
@@ -66,18 +112,319 @@ func (suite *ExecutionerTestSuite) TestSuccessfulJobExecution() {
 
 	executedJobName := "proctor-ipsum-lorem"
 	envVarsForImage := utility.MergeMaps(jobArgs, jobSecrets)
-	suite.mockKubeClient.On("ExecuteJob", jobMetadata.ImageName, envVarsForImage).Return(executedJobName, nil).Once()
+	suite.mockKubeClient.On("ExecuteJob", mock.Anything, kubernetes.ExecutionSpec{ImageName: jobMetadata.ImageName, EnvVars: envVarsForImage}).Return(executedJobName, nil).Once()
+	suite.mockTracker.On("Track", mock.Anything, mock.MatchedBy(func(execution Execution) bool {
+		return execution.Name == executedJobName && execution.JobName == jobName
+	})).Once()
 
 	suite.testExecutioner.Handle()(responseRecorder, req)
 
 	suite.mockMetadataStore.AssertExpectations(t)
 	suite.mockSecretsStore.AssertExpectations(t)
 	suite.mockKubeClient.AssertExpectations(t)
+	suite.mockTracker.AssertExpectations(t)
+	suite.mockPolicyEngine.AssertCalled(t, "Admit", mock.Anything, mock.MatchedBy(func(input policy.Input) bool {
+		return input.JobName == jobName
+	}))
+	suite.mockPolicyEngine.AssertNotCalled(t, "Release", mock.Anything, mock.Anything)
 
 	assert.Equal(t, http.StatusCreated, responseRecorder.Code)
 	assert.Equal(t, fmt.Sprintf("{ \"name\":\"%s\" }", executedJobName), responseRecorder.Body.String())
 }
 
+func (suite *ExecutionerTestSuite) TestJobExecutionWithNameFromPath() {
+	t := suite.T()
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/jobs/{name}/executions", suite.testExecutioner.HandleExecution()).Methods("POST")
+
+	requestBody, err := json.Marshal(map[string]interface{}{"args": map[string]string{"argOne": "sample-arg"}})
+	assert.NoError(t, err)
+	req := httptest.NewRequest("POST", "/api/v1/jobs/sample-job-name/executions", bytes.NewReader(requestBody))
+	responseRecorder := httptest.NewRecorder()
+
+	jobMetadata := metadata.Metadata{ImageName: "img"}
+	suite.mockMetadataStore.On("GetJobMetadata", "sample-job-name").Return(&jobMetadata, nil).Once()
+	suite.mockSecretsStore.On("GetJobSecrets", "sample-job-name").Return(map[string]string{}, nil).Once()
+	suite.mockKubeClient.On("ExecuteJob", mock.Anything, kubernetes.ExecutionSpec{ImageName: "img", EnvVars: map[string]string{"argOne": "sample-arg"}}).Return("proctor-ipsum-lorem", nil).Once()
+	suite.mockTracker.On("Track", mock.Anything, mock.Anything).Once()
+
+	router.ServeHTTP(responseRecorder, req)
+
+	suite.mockMetadataStore.AssertExpectations(t)
+	suite.mockKubeClient.AssertExpectations(t)
+	assert.Equal(t, http.StatusCreated, responseRecorder.Code)
+}
+
+func (suite *ExecutionerTestSuite) TestJobExecutionWithContainerArgs() {
+	t := suite.T()
+
+	jobMetadata := metadata.Metadata{
+		ImageName:  "ops-toolbox",
+		Command:    []string{"/bin/sh"},
+		Args:       []string{"vacuum.sh", "--table={{ .TABLE }}"},
+		WorkingDir: "/opt/scripts",
+	}
+	suite.mockMetadataStore.On("GetJobMetadata", "vacuum").Return(&jobMetadata, nil).Once()
+	suite.mockSecretsStore.On("GetJobSecrets", "vacuum").Return(map[string]string{}, nil).Once()
+
+	expectedSpec := kubernetes.ExecutionSpec{
+		ImageName:  "ops-toolbox",
+		Command:    []string{"/bin/sh"},
+		Args:       []string{"vacuum.sh", "--table=orders"},
+		WorkingDir: "/opt/scripts",
+		EnvVars:    map[string]string{"TABLE": "orders"},
+	}
+	suite.mockKubeClient.On("ExecuteJob", mock.Anything, expectedSpec).Return("proctor-ipsum-lorem", nil).Once()
+	suite.mockTracker.On("Track", mock.Anything, mock.MatchedBy(func(execution Execution) bool {
+		return execution.Spec.ImageName == "ops-toolbox"
+	})).Once()
+
+	requestBody, err := json.Marshal(Job{Name: "vacuum", Args: map[string]string{"TABLE": "orders"}})
+	assert.NoError(t, err)
+	responseRecorder := httptest.NewRecorder()
+
+	suite.testExecutioner.Handle()(responseRecorder, httptest.NewRequest("POST", "/execute", bytes.NewReader(requestBody)))
+
+	suite.mockKubeClient.AssertExpectations(t)
+	assert.Equal(t, http.StatusCreated, responseRecorder.Code)
+}
+
+func (suite *ExecutionerTestSuite) TestJobExecutionWithMissingContainerArg() {
+	t := suite.T()
+
+	jobMetadata := metadata.Metadata{ImageName: "ops-toolbox", Args: []string{"--table={{ .TABLE }}"}}
+	suite.mockMetadataStore.On("GetJobMetadata", "vacuum").Return(&jobMetadata, nil).Once()
+
+	requestBody, err := json.Marshal(Job{Name: "vacuum"})
+	assert.NoError(t, err)
+	responseRecorder := httptest.NewRecorder()
+
+	suite.testExecutioner.Handle()(responseRecorder, httptest.NewRequest("POST", "/execute", bytes.NewReader(requestBody)))
+
+	suite.mockKubeClient.AssertNotCalled(t, "ExecuteJob", mock.Anything, mock.Anything)
+	assert.Equal(t, http.StatusUnprocessableEntity, responseRecorder.Code)
+	assert.Equal(t, "args", errorResponse(t, responseRecorder).Details[0].Field)
+}
+
+func (suite *ExecutionerTestSuite) TestJobExecutionWithTargetOverride() {
+	t := suite.T()
+
+	os.Setenv("PROCTOR_KUBE_ALLOWED_NAMESPACES", "batch")
+	viper.AutomaticEnv()
+	defer os.Unsetenv("PROCTOR_KUBE_ALLOWED_NAMESPACES")
+
+	jobMetadata := metadata.Metadata{ImageName: "img"}
+	suite.mockMetadataStore.On("GetJobMetadata", "vacuum").Return(&jobMetadata, nil).Once()
+	suite.mockSecretsStore.On("GetJobSecrets", "vacuum").Return(map[string]string{}, nil).Once()
+
+	target := kubernetes.Target{Namespace: "batch"}
+	suite.mockKubeClient.On("ExecuteJob", mock.Anything, mock.MatchedBy(func(spec kubernetes.ExecutionSpec) bool {
+		return spec.Target == target
+	})).Return("proctor-ipsum-lorem", nil).Once()
+	suite.mockTracker.On("Track", mock.Anything, mock.MatchedBy(func(execution Execution) bool {
+		return execution.Target() == target
+	})).Once()
+
+	requestBody, err := json.Marshal(Job{Name: "vacuum", Namespace: "batch"})
+	assert.NoError(t, err)
+	responseRecorder := httptest.NewRecorder()
+
+	suite.testExecutioner.Handle()(responseRecorder, httptest.NewRequest("POST", "/execute", bytes.NewReader(requestBody)))
+
+	suite.mockKubeClient.AssertExpectations(t)
+	suite.mockTracker.AssertExpectations(t)
+	assert.Equal(t, http.StatusCreated, responseRecorder.Code)
+}
+
+func (suite *ExecutionerTestSuite) TestJobExecutionWithDisallowedNamespace() {
+	t := suite.T()
+
+	jobMetadata := metadata.Metadata{ImageName: "img"}
+	suite.mockMetadataStore.On("GetJobMetadata", "vacuum").Return(&jobMetadata, nil).Once()
+
+	requestBody, err := json.Marshal(Job{Name: "vacuum", Namespace: "kube-system"})
+	assert.NoError(t, err)
+	responseRecorder := httptest.NewRecorder()
+
+	suite.testExecutioner.Handle()(responseRecorder, httptest.NewRequest("POST", "/execute", bytes.NewReader(requestBody)))
+
+	suite.mockKubeClient.AssertNotCalled(t, "ExecuteJob", mock.Anything, mock.Anything)
+	assert.Equal(t, http.StatusUnprocessableEntity, responseRecorder.Code)
+	assert.Equal(t, "namespace", errorResponse(t, responseRecorder).Details[0].Field)
+}
+
+func (suite *ExecutionerTestSuite) TestJobExecutionDeniedByPolicy() {
+	t := suite.T()
+
+	mockPolicyEngine := &policy.MockEngine{}
+	testExecutioner := NewExecutioner(&suite.mockKubeClient, suite.mockMetadataStore, suite.mockSecretsStore, suite.mockApprovalStore, suite.mockStore, suite.mockIdempotency, suite.mockQueueStore, suite.mockTracker, mockPolicyEngine, suite.mockRateLimiter)
+
+	jobMetadata := metadata.Metadata{Name: "refund", ImageName: "img"}
+	suite.mockMetadataStore.On("GetJobMetadata", "refund").Return(&jobMetadata, nil).Once()
+	mockPolicyEngine.On("Admit", mock.Anything, mock.MatchedBy(func(input policy.Input) bool {
+		return input.JobName == "refund" && input.User == "jane@example.com" && input.Args["amount"] == "25000" && input.Metadata == &jobMetadata && !input.Approved
+	})).Return([]policy.Denial{{Rule: "refund-limit", Reason: "amount must be below 10000 unless approved"}}, nil).Once()
+
+	requestBody, err := json.Marshal(Job{Name: "refund", Args: map[string]string{"amount": "25000"}})
+	assert.NoError(t, err)
+	req := httptest.NewRequest("POST", "/execute", bytes.NewReader(requestBody))
+	req.Header.Set(utility.UserEmailHeaderKey, "jane@example.com")
+	responseRecorder := httptest.NewRecorder()
+
+	testExecutioner.Handle()(responseRecorder, req)
+
+	mockPolicyEngine.AssertExpectations(t)
+	mockPolicyEngine.AssertNotCalled(t, "Release", mock.Anything, mock.Anything)
+	suite.mockKubeClient.AssertNotCalled(t, "ExecuteJob", mock.Anything, mock.Anything)
+	assert.Equal(t, http.StatusForbidden, responseRecorder.Code)
+
+	errorBody := errorResponse(t, responseRecorder)
+	assert.Equal(t, utility.ErrCodePolicyDenied, errorBody.Code)
+	assert.Equal(t, []utility.FieldError{{Field: "refund-limit", Message: "amount must be below 10000 unless approved"}}, errorBody.Details)
+}
+
+func (suite *ExecutionerTestSuite) TestJobExecutionOnPolicyEvaluationFailure() {
+	t := suite.T()
+
+	mockPolicyEngine := &policy.MockEngine{}
+	testExecutioner := NewExecutioner(&suite.mockKubeClient, suite.mockMetadataStore, suite.mockSecretsStore, suite.mockApprovalStore, suite.mockStore, suite.mockIdempotency, suite.mockQueueStore, suite.mockTracker, mockPolicyEngine, suite.mockRateLimiter)
+
+	suite.mockMetadataStore.On("GetJobMetadata", "refund").Return(&metadata.Metadata{ImageName: "img"}, nil).Once()
+	mockPolicyEngine.On("Admit", mock.Anything, mock.Anything).Return([]policy.Denial(nil), errors.New("redis down")).Once()
+
+	requestBody, err := json.Marshal(Job{Name: "refund"})
+	assert.NoError(t, err)
+	responseRecorder := httptest.NewRecorder()
+
+	testExecutioner.Handle()(responseRecorder, httptest.NewRequest("POST", "/execute", bytes.NewReader(requestBody)))
+
+	suite.mockKubeClient.AssertNotCalled(t, "ExecuteJob", mock.Anything, mock.Anything)
+	assert.Equal(t, http.StatusServiceUnavailable, responseRecorder.Code)
+	assert.Equal(t, utility.ErrCodeStoreUnavailable, errorResponse(t, responseRecorder).Code)
+}
+
+func (suite *ExecutionerTestSuite) TestJobExecutionOverRateLimit() {
+	t := suite.T()
+
+	jobMetadata := metadata.Metadata{ImageName: "img", RateLimit: &metadata.RateLimit{Executions: 10, PeriodSeconds: 60}}
+	suite.mockMetadataStore.On("GetJobMetadata", "vacuum").Return(&jobMetadata, nil).Once()
+	suite.mockRateLimiter.On("Allow", "vacuum-executions", ratelimit.Limit{Rate: 10.0 / 60, Burst: 10}).Return(ratelimit.Result{Limit: 10, RetryAfter: 5 * time.Second, Reset: time.Minute}, nil).Once()
+
+	requestBody, err := json.Marshal(Job{Name: "vacuum"})
+	assert.NoError(t, err)
+	responseRecorder := httptest.NewRecorder()
+
+	suite.testExecutioner.Handle()(responseRecorder, httptest.NewRequest("POST", "/execute", bytes.NewReader(requestBody)))
+
+	suite.mockRateLimiter.AssertExpectations(t)
+	suite.mockKubeClient.AssertNotCalled(t, "ExecuteJob", mock.Anything, mock.Anything)
+	suite.mockPolicyEngine.AssertCalled(t, "Release", mock.Anything, mock.Anything)
+	assert.Equal(t, http.StatusTooManyRequests, responseRecorder.Code)
+	assert.Equal(t, utility.ErrCodeRateLimited, errorResponse(t, responseRecorder).Code)
+	assert.Equal(t, "5", responseRecorder.Header().Get(ratelimit.RetryAfterHeaderKey))
+	assert.Equal(t, "0", responseRecorder.Header().Get(ratelimit.RemainingHeaderKey))
+	assert.Equal(t, "10", responseRecorder.Header().Get(ratelimit.LimitHeaderKey))
+}
+
+func (suite *ExecutionerTestSuite) TestJobExecutionWithDisallowedImage() {
+	t := suite.T()
+
+	os.Setenv("PROCTOR_IMAGE_ALLOWED_PATTERNS", "registry.example.com/**")
+	defer os.Unsetenv("PROCTOR_IMAGE_ALLOWED_PATTERNS")
+	viper.AutomaticEnv()
+
+	jobMetadata := metadata.Metadata{ImageName: "docker.io/someone/miner:latest"}
+	suite.mockMetadataStore.On("GetJobMetadata", "vacuum").Return(&jobMetadata, nil).Once()
+
+	requestBody, err := json.Marshal(Job{Name: "vacuum"})
+	assert.NoError(t, err)
+	responseRecorder := httptest.NewRecorder()
+
+	suite.testExecutioner.Handle()(responseRecorder, httptest.NewRequest("POST", "/execute", bytes.NewReader(requestBody)))
+
+	suite.mockKubeClient.AssertNotCalled(t, "ExecuteJob", mock.Anything, mock.Anything)
+	assert.Equal(t, http.StatusForbidden, responseRecorder.Code)
+	assert.Equal(t, utility.ErrCodeImageNotAllowed, errorResponse(t, responseRecorder).Code)
+}
+
+func multipartJobRequest(t *testing.T, job Job, files map[string][]byte) *http.Request {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	jobField, err := json.Marshal(job)
+	assert.NoError(t, err)
+	assert.NoError(t, writer.WriteField("job", string(jobField)))
+	for name, content := range files {
+		part, err := writer.CreateFormFile(name, name)
+		assert.NoError(t, err)
+		part.Write(content)
+	}
+	assert.NoError(t, writer.Close())
+
+	req := httptest.NewRequest("POST", "/execute", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func (suite *ExecutionerTestSuite) TestJobExecutionWithFileInputs() {
+	t := suite.T()
+
+	jobMetadata := metadata.Metadata{
+		ImageName: "importer",
+		Files:     []metadata.FileInput{{Name: "users.csv", MountPath: "/data/users.csv", Required: true}},
+		Volumes:   []kubernetes.Volume{{Name: "scratch", MountPath: "/scratch", EmptyDir: &kubernetes.EmptyDirVolume{}}},
+	}
+	suite.mockMetadataStore.On("GetJobMetadata", "import-users").Return(&jobMetadata, nil).Once()
+	suite.mockSecretsStore.On("GetJobSecrets", "import-users").Return(map[string]string{}, nil).Once()
+
+	expectedSpec := kubernetes.ExecutionSpec{
+		ImageName: "importer",
+		EnvVars:   map[string]string{},
+		Volumes:   jobMetadata.Volumes,
+		Files:     []kubernetes.File{{Name: "users.csv", MountPath: "/data/users.csv", Content: []byte("id,email\n")}},
+	}
+	suite.mockKubeClient.On("ExecuteJob", mock.Anything, expectedSpec).Return("proctor-ipsum-lorem", nil).Once()
+	suite.mockTracker.On("Track", mock.Anything, mock.Anything).Once()
+
+	req := multipartJobRequest(t, Job{Name: "import-users"}, map[string][]byte{"users.csv": []byte("id,email\n")})
+	responseRecorder := httptest.NewRecorder()
+
+	suite.testExecutioner.Handle()(responseRecorder, req)
+
+	suite.mockKubeClient.AssertExpectations(t)
+	assert.Equal(t, http.StatusCreated, responseRecorder.Code)
+}
+
+func (suite *ExecutionerTestSuite) TestJobExecutionWithUndeclaredFileInput() {
+	t := suite.T()
+
+	jobMetadata := metadata.Metadata{ImageName: "importer"}
+	suite.mockMetadataStore.On("GetJobMetadata", "import-users").Return(&jobMetadata, nil).Once()
+
+	req := multipartJobRequest(t, Job{Name: "import-users"}, map[string][]byte{"users.csv": []byte("id,email\n")})
+	responseRecorder := httptest.NewRecorder()
+
+	suite.testExecutioner.Handle()(responseRecorder, req)
+
+	suite.mockKubeClient.AssertNotCalled(t, "ExecuteJob", mock.Anything, mock.Anything)
+	assert.Equal(t, http.StatusUnprocessableEntity, responseRecorder.Code)
+	assert.Equal(t, "files.users.csv", errorResponse(t, responseRecorder).Details[0].Field)
+}
+
+func (suite *ExecutionerTestSuite) TestJobExecutionWithFileInputsTooLarge() {
+	t := suite.T()
+
+	req := multipartJobRequest(t, Job{Name: "import-users"}, map[string][]byte{"users.csv": make([]byte, defaultFileInputMaxSize+1)})
+	responseRecorder := httptest.NewRecorder()
+
+	suite.testExecutioner.Handle()(responseRecorder, req)
+
+	suite.mockMetadataStore.AssertNotCalled(t, "GetJobMetadata", mock.Anything)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, responseRecorder.Code)
+	assert.Equal(t, utility.ErrCodeFilesTooLarge, errorResponse(t, responseRecorder).Code)
+}
+
 func (suite *ExecutionerTestSuite) TestJobExecutionOnMalformedRequest() {
 	t := suite.T()
 
@@ -92,7 +439,49 @@ func (suite *ExecutionerTestSuite) TestJobExecutionOnMalformedRequest() {
 	suite.mockKubeClient.AssertNotCalled(t, "ExecuteJob", mock.Anything, mock.Anything)
 
 	assert.Equal(t, http.StatusBadRequest, responseRecorder.Code)
-	assert.Equal(t, utility.ClientError, responseRecorder.Body.String())
+	assert.Equal(t, utility.ErrCodeMalformedRequest, errorResponse(t, responseRecorder).Code)
+}
+
+func (suite *ExecutionerTestSuite) TestJobExecutionOnMissingJobName() {
+	t := suite.T()
+
+	requestBody, err := json.Marshal(Job{})
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest("POST", "/execute", bytes.NewReader(requestBody))
+	req.Header.Set(utility.RequestIDHeaderKey, "request-id")
+	responseRecorder := httptest.NewRecorder()
+
+	suite.testExecutioner.Handle()(responseRecorder, req)
+
+	suite.mockMetadataStore.AssertNotCalled(t, "GetJobMetadata", mock.Anything)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, responseRecorder.Code)
+	jobError := errorResponse(t, responseRecorder)
+	assert.Equal(t, utility.ErrCodeValidationFailed, jobError.Code)
+	assert.Equal(t, []utility.FieldError{{Field: "name", Message: "is required"}}, jobError.Details)
+	assert.Equal(t, "request-id", jobError.RequestID)
+}
+
+func (suite *ExecutionerTestSuite) TestJobExecutionOnUnknownJob() {
+	t := suite.T()
+
+	jobName := "sample-job-name"
+	requestBody, err := json.Marshal(Job{Name: jobName})
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest("POST", "/execute", bytes.NewReader(requestBody))
+	responseRecorder := httptest.NewRecorder()
+
+	suite.mockMetadataStore.On("GetJobMetadata", jobName).Return(&metadata.Metadata{}, metadata.ErrJobNotFound).Once()
+
+	suite.testExecutioner.Handle()(responseRecorder, req)
+
+	suite.mockMetadataStore.AssertExpectations(t)
+	suite.mockSecretsStore.AssertNotCalled(t, "GetJobSecrets", mock.Anything)
+
+	assert.Equal(t, http.StatusNotFound, responseRecorder.Code)
+	assert.Equal(t, utility.ErrCodeJobNotFound, errorResponse(t, responseRecorder).Code)
 }
 
 func (suite *ExecutionerTestSuite) TestJobExecutionOnImageLookupFailuer() {
@@ -116,8 +505,8 @@ func (suite *ExecutionerTestSuite) TestJobExecutionOnImageLookupFailuer() {
 	suite.mockSecretsStore.AssertNotCalled(t, "GetJobSecrets", mock.Anything)
 	suite.mockKubeClient.AssertNotCalled(t, "ExecuteJob", mock.Anything, mock.Anything)
 
-	assert.Equal(t, http.StatusInternalServerError, responseRecorder.Code)
-	assert.Equal(t, utility.ServerError, responseRecorder.Body.String())
+	assert.Equal(t, http.StatusServiceUnavailable, responseRecorder.Code)
+	assert.Equal(t, utility.ErrCodeStoreUnavailable, errorResponse(t, responseRecorder).Code)
 }
 
 func (suite *ExecutionerTestSuite) TestJobExecutionOnSecretsFetchFailuer() {
@@ -142,10 +531,32 @@ func (suite *ExecutionerTestSuite) TestJobExecutionOnSecretsFetchFailuer() {
 
 	suite.mockMetadataStore.AssertExpectations(t)
 	suite.mockSecretsStore.AssertExpectations(t)
-	suite.mockKubeClient.AssertNotCalled(t, "ExecuteJob", mock.Anything, mock.Anything, mock.Anything)
+	suite.mockKubeClient.AssertNotCalled(t, "ExecuteJob", mock.Anything, mock.Anything)
 
-	assert.Equal(t, http.StatusNotFound, responseRecorder.Code)
-	assert.Equal(t, utility.ServerError, responseRecorder.Body.String())
+	assert.Equal(t, http.StatusServiceUnavailable, responseRecorder.Code)
+	assert.Equal(t, utility.ErrCodeStoreUnavailable, errorResponse(t, responseRecorder).Code)
+}
+
+func (suite *ExecutionerTestSuite) TestJobExecutionWithoutSecrets() {
+	t := suite.T()
+
+	jobName := "sample-job-name"
+	requestBody, err := json.Marshal(Job{Name: jobName})
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest("POST", "/execute", bytes.NewReader(requestBody))
+	responseRecorder := httptest.NewRecorder()
+
+	suite.mockMetadataStore.On("GetJobMetadata", jobName).Return(&metadata.Metadata{ImageName: "img"}, nil).Once()
+	suite.mockSecretsStore.On("GetJobSecrets", jobName).Return(map[string]string(nil), secrets.ErrSecretsNotFound).Once()
+	suite.mockKubeClient.On("ExecuteJob", mock.Anything, kubernetes.ExecutionSpec{ImageName: "img", EnvVars: map[string]string{}}).Return("proctor-ipsum-lorem", nil).Once()
+	suite.mockTracker.On("Track", mock.Anything, mock.Anything).Once()
+
+	suite.testExecutioner.Handle()(responseRecorder, req)
+
+	suite.mockKubeClient.AssertExpectations(t)
+
+	assert.Equal(t, http.StatusCreated, responseRecorder.Code)
 }
 
 func (suite *ExecutionerTestSuite) TestJobExecutionOnExecutionFailure() {
@@ -171,16 +582,660 @@ func (suite *ExecutionerTestSuite) TestJobExecutionOnExecutionFailure() {
 
 	suite.mockSecretsStore.On("GetJobSecrets", jobName).Return(emptyMap, nil).Once()
 
-	suite.mockKubeClient.On("ExecuteJob", jobMetadata.ImageName, emptyMap).Return("", errors.New("Kube client job execution error")).Once()
+	suite.mockKubeClient.On("ExecuteJob", mock.Anything, kubernetes.ExecutionSpec{ImageName: jobMetadata.ImageName, EnvVars: emptyMap}).Return("", errors.New("Kube client job execution error")).Once()
 
 	suite.testExecutioner.Handle()(responseRecorder, req)
 
 	suite.mockMetadataStore.AssertExpectations(t)
 	suite.mockSecretsStore.AssertExpectations(t)
 	suite.mockKubeClient.AssertExpectations(t)
+	suite.mockTracker.AssertNotCalled(t, "Track", mock.Anything, mock.Anything)
 
 	assert.Equal(t, http.StatusInternalServerError, responseRecorder.Code)
-	assert.Equal(t, utility.ServerError, responseRecorder.Body.String())
+	assert.Equal(t, utility.ErrCodeExecutionFailed, errorResponse(t, responseRecorder).Code)
+}
+
+func (suite *ExecutionerTestSuite) TestJobExecutionWithWait() {
+	t := suite.T()
+
+	jobName := "sample-job-name"
+	requestBody, err := json.Marshal(Job{Name: jobName})
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest("POST", "/execute?wait=true&timeout=1m&tail_lines=2", bytes.NewReader(requestBody))
+	responseRecorder := httptest.NewRecorder()
+
+	jobMetadata := metadata.Metadata{
+		ImageName: "img",
+	}
+	suite.mockMetadataStore.On("GetJobMetadata", jobName).Return(&jobMetadata, nil).Once()
+	suite.mockSecretsStore.On("GetJobSecrets", jobName).Return(map[string]string{}, nil).Once()
+
+	executedJobName := "proctor-ipsum-lorem"
+	suite.mockKubeClient.On("ExecuteJob", mock.Anything, kubernetes.ExecutionSpec{ImageName: "img", EnvVars: map[string]string{}}).Return(executedJobName, nil).Once()
+	suite.mockTracker.On("Track", mock.Anything, mock.MatchedBy(func(execution Execution) bool {
+		return execution.Name == executedJobName && execution.JobName == jobName
+	})).Once()
+
+	exitCode := int32(1)
+	startTime := time.Now()
+	jobStatus := &kubernetes.JobStatus{
+		Succeeded:      false,
+		ExitCode:       &exitCode,
+		StartTime:      startTime,
+		CompletionTime: startTime.Add(30 * time.Second),
+	}
+	suite.mockKubeClient.On("WaitForJobCompletion", mock.Anything, kubernetes.Target{}, executedJobName).Return(jobStatus, nil).Once()
+	finishedAt := startTime.Add(30 * time.Second)
+	suite.mockStore.On("GetExecution", executedJobName).Return(&Execution{Name: executedJobName, FinishedAt: &finishedAt}, nil).Once()
+	suite.mockKubeClient.On("JobLogsTail", mock.Anything, kubernetes.Target{}, executedJobName, 2).Return([]string{"step 2", "boom"}, nil).Once()
+
+	suite.testExecutioner.Handle()(responseRecorder, req)
+
+	suite.mockKubeClient.AssertExpectations(t)
+
+	assert.Equal(t, http.StatusFailedDependency, responseRecorder.Code)
+
+	var executionResult ExecutionResult
+	err = json.Unmarshal(responseRecorder.Body.Bytes(), &executionResult)
+	assert.NoError(t, err)
+
+	assert.Equal(t, ExecutionResult{
+		Name:            executedJobName,
+		Status:          StatusFailed,
+		ExitCode:        &exitCode,
+		DurationSeconds: 30,
+		Logs:            []string{"step 2", "boom"},
+	}, executionResult)
+}
+
+func (suite *ExecutionerTestSuite) TestJobExecutionWithInvalidWaitTimeout() {
+	t := suite.T()
+
+	requestBody, err := json.Marshal(Job{Name: "sample-job-name"})
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest("POST", "/execute?wait=true&timeout=forever", bytes.NewReader(requestBody))
+	responseRecorder := httptest.NewRecorder()
+
+	suite.testExecutioner.Handle()(responseRecorder, req)
+
+	suite.mockMetadataStore.AssertNotCalled(t, "GetJobMetadata", mock.Anything)
+	suite.mockKubeClient.AssertNotCalled(t, "ExecuteJob", mock.Anything, mock.Anything)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, responseRecorder.Code)
+	assert.Equal(t, "timeout", errorResponse(t, responseRecorder).Details[0].Field)
+}
+
+func (suite *ExecutionerTestSuite) TestJobExecutionRequiringApproval() {
+	t := suite.T()
+
+	jobName := "sample-job-name"
+	jobArgs := map[string]string{"argOne": "sample-arg"}
+	job := Job{
+		Name: jobName,
+		Args: jobArgs,
+	}
+	requestBody, err := json.Marshal(job)
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest("POST", "/execute", bytes.NewReader(requestBody))
+	req.Header.Set(utility.UserEmailHeaderKey, "requester@example.com")
+	responseRecorder := httptest.NewRecorder()
+
+	jobMetadata := metadata.Metadata{
+		ImageName:         "img",
+		RequiredApprovals: 2,
+	}
+	suite.mockMetadataStore.On("GetJobMetadata", jobName).Return(&jobMetadata, nil).Once()
+
+	var savedRequest approval.Request
+	suite.mockApprovalStore.On("SaveRequest", mock.AnythingOfType("approval.Request")).Run(func(args mock.Arguments) {
+		savedRequest = args.Get(0).(approval.Request)
+	}).Return(nil).Once()
+
+	suite.testExecutioner.Handle()(responseRecorder, req)
+
+	suite.mockMetadataStore.AssertExpectations(t)
+	suite.mockApprovalStore.AssertExpectations(t)
+	suite.mockSecretsStore.AssertNotCalled(t, "GetJobSecrets", mock.Anything)
+	suite.mockKubeClient.AssertNotCalled(t, "ExecuteJob", mock.Anything, mock.Anything)
+	suite.mockPolicyEngine.AssertCalled(t, "Evaluate", mock.Anything, mock.MatchedBy(func(input policy.Input) bool {
+		return input.JobName == jobName && input.Approved
+	}))
+	suite.mockPolicyEngine.AssertNotCalled(t, "Admit", mock.Anything, mock.Anything)
+
+	assert.Equal(t, jobName, savedRequest.JobName)
+	assert.Equal(t, jobArgs, savedRequest.Args)
+	assert.Equal(t, "requester@example.com", savedRequest.RequestedBy)
+	assert.Equal(t, 2, savedRequest.RequiredApprovals)
+	assert.True(t, savedRequest.ExpiresAt.After(savedRequest.CreatedAt))
+
+	assert.Equal(t, http.StatusAccepted, responseRecorder.Code)
+	assert.Equal(t, fmt.Sprintf("{ \"request_id\":\"%s\" }", savedRequest.ID), responseRecorder.Body.String())
+}
+
+func (suite *ExecutionerTestSuite) TestJobExecutionRequiringApprovalWithoutRequester() {
+	t := suite.T()
+
+	jobName := "sample-job-name"
+	requestBody, err := json.Marshal(Job{Name: jobName})
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest("POST", "/execute", bytes.NewReader(requestBody))
+	responseRecorder := httptest.NewRecorder()
+
+	jobMetadata := metadata.Metadata{
+		ImageName:         "img",
+		RequiredApprovals: 1,
+	}
+	suite.mockMetadataStore.On("GetJobMetadata", jobName).Return(&jobMetadata, nil).Once()
+
+	suite.testExecutioner.Handle()(responseRecorder, req)
+
+	suite.mockApprovalStore.AssertNotCalled(t, "SaveRequest", mock.Anything)
+	suite.mockKubeClient.AssertNotCalled(t, "ExecuteJob", mock.Anything, mock.Anything)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, responseRecorder.Code)
+	assert.Equal(t, utility.ErrCodeValidationFailed, errorResponse(t, responseRecorder).Code)
+}
+
+func (suite *ExecutionerTestSuite) TestExecute() {
+	t := suite.T()
+
+	jobName := "sample-job-name"
+	jobArgs := map[string]string{"argOne": "sample-arg"}
+
+	jobMetadata := metadata.Metadata{
+		ImageName: "img",
+	}
+	suite.mockMetadataStore.On("GetJobMetadata", jobName).Return(&jobMetadata, nil).Once()
+
+	jobSecrets := map[string]string{"secretOne": "sample-secrets"}
+	suite.mockSecretsStore.On("GetJobSecrets", jobName).Return(jobSecrets, nil).Once()
+
+	suite.mockKubeClient.On("ExecuteJob", mock.Anything, kubernetes.ExecutionSpec{ImageName: "img", EnvVars: utility.MergeMaps(jobArgs, jobSecrets)}).Return("proctor-ipsum-lorem", nil).Once()
+	suite.mockTracker.On("Track", mock.Anything, mock.MatchedBy(func(execution Execution) bool {
+		return execution.Name == "proctor-ipsum-lorem" && execution.CallbackURL == "https://example.com/callback"
+	})).Once()
+
+	executedJobName, err := suite.testExecutioner.Execute(context.Background(), jobName, jobArgs, "https://example.com/callback", "jane@example.com", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "proctor-ipsum-lorem", executedJobName)
+
+	suite.mockMetadataStore.AssertExpectations(t)
+	suite.mockSecretsStore.AssertExpectations(t)
+	suite.mockKubeClient.AssertExpectations(t)
+	suite.mockPolicyEngine.AssertCalled(t, "Admit", mock.Anything, mock.MatchedBy(func(input policy.Input) bool {
+		return input.JobName == jobName && input.User == "jane@example.com" && !input.Approved
+	}))
+}
+
+func (suite *ExecutionerTestSuite) TestExecuteApprovedJob() {
+	t := suite.T()
+
+	jobMetadata := metadata.Metadata{ImageName: "img", RequiredApprovals: 1}
+	suite.mockMetadataStore.On("GetJobMetadata", "refund").Return(&jobMetadata, nil).Once()
+	suite.mockSecretsStore.On("GetJobSecrets", "refund").Return(map[string]string{}, nil).Once()
+	suite.mockKubeClient.On("ExecuteJob", mock.Anything, mock.Anything).Return("proctor-refund", nil).Once()
+	suite.mockTracker.On("Track", mock.Anything, mock.Anything).Once()
+
+	executedJobName, err := suite.testExecutioner.Execute(context.Background(), "refund", nil, "", "jane@example.com", []string{"joe@example.com"})
+	assert.NoError(t, err)
+	assert.Equal(t, "proctor-refund", executedJobName)
+
+	suite.mockPolicyEngine.AssertCalled(t, "Admit", mock.Anything, mock.MatchedBy(func(input policy.Input) bool {
+		return input.Approved && input.Approvers[0] == "joe@example.com"
+	}))
+}
+
+func (suite *ExecutionerTestSuite) TestExecuteWithoutRequiredApprovals() {
+	t := suite.T()
+
+	jobMetadata := metadata.Metadata{ImageName: "img", RequiredApprovals: 1}
+	suite.mockMetadataStore.On("GetJobMetadata", "refund").Return(&jobMetadata, nil).Once()
+
+	_, err := suite.testExecutioner.Execute(context.Background(), "refund", nil, "", "jane@example.com", nil)
+	assert.Equal(t, approval.ErrApprovalRequired, err)
+
+	suite.mockPolicyEngine.AssertNotCalled(t, "Admit", mock.Anything, mock.Anything)
+	suite.mockKubeClient.AssertNotCalled(t, "ExecuteJob", mock.Anything, mock.Anything)
+}
+
+func (suite *ExecutionerTestSuite) TestExecuteDeniedByPolicy() {
+	t := suite.T()
+
+	mockPolicyEngine := &policy.MockEngine{}
+	testExecutioner := NewExecutioner(&suite.mockKubeClient, suite.mockMetadataStore, suite.mockSecretsStore, suite.mockApprovalStore, suite.mockStore, suite.mockIdempotency, suite.mockQueueStore, suite.mockTracker, mockPolicyEngine, suite.mockRateLimiter)
+
+	suite.mockMetadataStore.On("GetJobMetadata", "refund").Return(&metadata.Metadata{ImageName: "img"}, nil).Once()
+	denials := []policy.Denial{{Rule: "business-hours", Reason: "refunds run during business hours"}}
+	mockPolicyEngine.On("Admit", mock.Anything, mock.Anything).Return(denials, nil).Once()
+
+	_, err := testExecutioner.Execute(context.Background(), "refund", nil, "", "jane@example.com", nil)
+	assert.Equal(t, &policy.DeniedError{Denials: denials}, err)
+
+	suite.mockKubeClient.AssertNotCalled(t, "ExecuteJob", mock.Anything, mock.Anything)
+}
+
+func (suite *ExecutionerTestSuite) TestExecuteOverRateLimit() {
+	t := suite.T()
+
+	jobMetadata := metadata.Metadata{ImageName: "img", RateLimit: &metadata.RateLimit{Executions: 10, PeriodSeconds: 60}}
+	suite.mockMetadataStore.On("GetJobMetadata", "vacuum").Return(&jobMetadata, nil).Once()
+	suite.mockRateLimiter.On("Allow", "vacuum-executions", ratelimit.Limit{Rate: 10.0 / 60, Burst: 10}).Return(ratelimit.Result{Limit: 10}, nil).Once()
+
+	_, err := suite.testExecutioner.Execute(context.Background(), "vacuum", nil, "", "jane@example.com", nil)
+	assert.Equal(t, ratelimit.ErrLimitExceeded, err)
+
+	suite.mockKubeClient.AssertNotCalled(t, "ExecuteJob", mock.Anything, mock.Anything)
+	suite.mockPolicyEngine.AssertCalled(t, "Release", mock.Anything, mock.Anything)
+}
+
+func (suite *ExecutionerTestSuite) idempotentRequest(job Job) *http.Request {
+	requestBody, err := json.Marshal(job)
+	assert.NoError(suite.T(), err)
+
+	req := httptest.NewRequest("POST", "/execute", bytes.NewReader(requestBody))
+	req.Header.Set(idempotency.HeaderKey, "idempotency-key")
+	req.Header.Set(utility.UserEmailHeaderKey, "user@example.com")
+	return req
+}
+
+func (suite *ExecutionerTestSuite) TestIdempotentJobExecutionStoresResponse() {
+	t := suite.T()
+
+	jobName := "sample-job-name"
+	req := suite.idempotentRequest(Job{Name: jobName})
+	responseRecorder := httptest.NewRecorder()
+
+	suite.mockIdempotency.On("Reserve", "user@example.com", "idempotency-key", mock.Anything).Return(true, nil).Once()
+	suite.mockMetadataStore.On("GetJobMetadata", jobName).Return(&metadata.Metadata{ImageName: "img"}, nil).Once()
+	suite.mockSecretsStore.On("GetJobSecrets", jobName).Return(map[string]string{}, nil).Once()
+	suite.mockKubeClient.On("ExecuteJob", mock.Anything, kubernetes.ExecutionSpec{ImageName: "img", EnvVars: map[string]string{}}).Return("proctor-ipsum-lorem", nil).Once()
+	suite.mockTracker.On("Track", mock.Anything, mock.Anything).Once()
+	suite.mockIdempotency.On("Complete", "user@example.com", "idempotency-key", mock.MatchedBy(func(record idempotency.Record) bool {
+		return record.StatusCode == http.StatusCreated && string(record.Body) == `{ "name":"proctor-ipsum-lorem" }` && record.RequestHash != ""
+	})).Return(nil).Once()
+
+	suite.testExecutioner.Handle()(responseRecorder, req)
+
+	suite.mockIdempotency.AssertExpectations(t)
+	assert.Equal(t, http.StatusCreated, responseRecorder.Code)
+}
+
+func (suite *ExecutionerTestSuite) TestIdempotentJobExecutionIsScopedToRequester() {
+	t := suite.T()
+
+	jobName := "sample-job-name"
+	req := suite.idempotentRequest(Job{Name: jobName})
+	req.Header.Set(utility.UserEmailHeaderKey, "another-user@example.com")
+	responseRecorder := httptest.NewRecorder()
+
+	suite.mockIdempotency.On("Reserve", "another-user@example.com", "idempotency-key", mock.Anything).Return(true, nil).Once()
+	suite.mockMetadataStore.On("GetJobMetadata", jobName).Return(&metadata.Metadata{ImageName: "img"}, nil).Once()
+	suite.mockSecretsStore.On("GetJobSecrets", jobName).Return(map[string]string{}, nil).Once()
+	suite.mockKubeClient.On("ExecuteJob", mock.Anything, kubernetes.ExecutionSpec{ImageName: "img", EnvVars: map[string]string{}}).Return("proctor-ipsum-lorem", nil).Once()
+	suite.mockTracker.On("Track", mock.Anything, mock.Anything).Once()
+	suite.mockIdempotency.On("Complete", "another-user@example.com", "idempotency-key", mock.Anything).Return(nil).Once()
+
+	suite.testExecutioner.Handle()(responseRecorder, req)
+
+	suite.mockIdempotency.AssertExpectations(t)
+	suite.mockIdempotency.AssertNotCalled(t, "Reserve", "user@example.com", mock.Anything, mock.Anything)
+	assert.Equal(t, http.StatusCreated, responseRecorder.Code)
+}
+
+func (suite *ExecutionerTestSuite) TestIdempotentJobExecutionReleasesKeyOnFailure() {
+	t := suite.T()
+
+	jobName := "sample-job-name"
+	req := suite.idempotentRequest(Job{Name: jobName})
+	responseRecorder := httptest.NewRecorder()
+
+	suite.mockIdempotency.On("Reserve", "user@example.com", "idempotency-key", mock.Anything).Return(true, nil).Once()
+	suite.mockMetadataStore.On("GetJobMetadata", jobName).Return(&metadata.Metadata{}, metadata.ErrJobNotFound).Once()
+	suite.mockIdempotency.On("Release", "user@example.com", "idempotency-key").Return(nil).Once()
+
+	suite.testExecutioner.Handle()(responseRecorder, req)
+
+	suite.mockIdempotency.AssertExpectations(t)
+	suite.mockIdempotency.AssertNotCalled(t, "Complete", mock.Anything, mock.Anything, mock.Anything)
+	assert.Equal(t, http.StatusNotFound, responseRecorder.Code)
+}
+
+func (suite *ExecutionerTestSuite) TestIdempotentJobExecutionKeepsKeyOfFailedWaitedJob() {
+	t := suite.T()
+
+	jobName := "sample-job-name"
+	req := suite.idempotentRequest(Job{Name: jobName})
+	req.URL.RawQuery = "wait=true&tail_lines=0"
+	responseRecorder := httptest.NewRecorder()
+
+	suite.mockIdempotency.On("Reserve", "user@example.com", "idempotency-key", mock.Anything).Return(true, nil).Once()
+	suite.mockMetadataStore.On("GetJobMetadata", jobName).Return(&metadata.Metadata{ImageName: "img"}, nil).Once()
+	suite.mockSecretsStore.On("GetJobSecrets", jobName).Return(map[string]string{}, nil).Once()
+	suite.mockKubeClient.On("ExecuteJob", mock.Anything, kubernetes.ExecutionSpec{ImageName: "img", EnvVars: map[string]string{}}).Return("proctor-ipsum-lorem", nil).Once()
+	suite.mockTracker.On("Track", mock.Anything, mock.Anything).Once()
+	suite.mockIdempotency.On("Complete", "user@example.com", "idempotency-key", mock.MatchedBy(func(record idempotency.Record) bool {
+		return record.StatusCode == http.StatusCreated && string(record.Body) == `{ "name":"proctor-ipsum-lorem" }`
+	})).Return(nil).Once()
+	suite.mockKubeClient.On("WaitForJobCompletion", mock.Anything, kubernetes.Target{}, "proctor-ipsum-lorem").Return(&kubernetes.JobStatus{Succeeded: false}, nil).Once()
+	suite.mockStore.On("GetExecution", "proctor-ipsum-lorem").Return(&Execution{}, ErrExecutionNotFound).Once()
+	suite.mockIdempotency.On("Complete", "user@example.com", "idempotency-key", mock.MatchedBy(func(record idempotency.Record) bool {
+		return record.StatusCode == http.StatusFailedDependency
+	})).Return(nil).Once()
+
+	suite.testExecutioner.Handle()(responseRecorder, req)
+
+	suite.mockIdempotency.AssertExpectations(t)
+	suite.mockIdempotency.AssertNotCalled(t, "Release", mock.Anything, mock.Anything)
+	assert.Equal(t, http.StatusFailedDependency, responseRecorder.Code)
+}
+
+func (suite *ExecutionerTestSuite) TestIdempotentJobExecutionKeepsKeyWhenClientDisconnectsWhileWaiting() {
+	t := suite.T()
+
+	jobName := "sample-job-name"
+	req := suite.idempotentRequest(Job{Name: jobName})
+	req.URL.RawQuery = "wait=true"
+	responseRecorder := httptest.NewRecorder()
+
+	suite.mockIdempotency.On("Reserve", "user@example.com", "idempotency-key", mock.Anything).Return(true, nil).Once()
+	suite.mockMetadataStore.On("GetJobMetadata", jobName).Return(&metadata.Metadata{ImageName: "img"}, nil).Once()
+	suite.mockSecretsStore.On("GetJobSecrets", jobName).Return(map[string]string{}, nil).Once()
+	suite.mockKubeClient.On("ExecuteJob", mock.Anything, kubernetes.ExecutionSpec{ImageName: "img", EnvVars: map[string]string{}}).Return("proctor-ipsum-lorem", nil).Once()
+	suite.mockTracker.On("Track", mock.Anything, mock.Anything).Once()
+	suite.mockIdempotency.On("Complete", "user@example.com", "idempotency-key", mock.MatchedBy(func(record idempotency.Record) bool {
+		return record.StatusCode == http.StatusCreated
+	})).Return(nil).Once()
+	suite.mockKubeClient.On("WaitForJobCompletion", mock.Anything, kubernetes.Target{}, "proctor-ipsum-lorem").Return(&kubernetes.JobStatus{}, context.Canceled).Once()
+
+	suite.testExecutioner.Handle()(responseRecorder, req)
+
+	suite.mockIdempotency.AssertExpectations(t)
+	suite.mockIdempotency.AssertNotCalled(t, "Release", mock.Anything, mock.Anything)
+}
+
+func (suite *ExecutionerTestSuite) TestIdempotentJobExecutionReplaysResponse() {
+	t := suite.T()
+
+	job := Job{Name: "sample-job-name"}
+	req := suite.idempotentRequest(job)
+	responseRecorder := httptest.NewRecorder()
+
+	requestHash, err := idempotency.RequestHash(struct {
+		Requester string
+		Job       Job
+		Files     map[string][]byte
+		Query     string
+	}{"user@example.com", job, job.Files, ""})
+	assert.NoError(t, err)
+
+	record := &idempotency.Record{RequestHash: requestHash, Completed: true, StatusCode: http.StatusCreated, Body: []byte(`{ "name":"proctor-ipsum-lorem" }`)}
+	suite.mockIdempotency.On("Reserve", "user@example.com", "idempotency-key", requestHash).Return(false, nil).Once()
+	suite.mockIdempotency.On("Get", "user@example.com", "idempotency-key").Return(record, nil).Once()
+
+	suite.testExecutioner.Handle()(responseRecorder, req)
+
+	suite.mockMetadataStore.AssertNotCalled(t, "GetJobMetadata", mock.Anything)
+	suite.mockKubeClient.AssertNotCalled(t, "ExecuteJob", mock.Anything, mock.Anything)
+
+	assert.Equal(t, http.StatusCreated, responseRecorder.Code)
+	assert.Equal(t, "true", responseRecorder.Header().Get(idempotency.ReplayedHeaderKey))
+	assert.Equal(t, `{ "name":"proctor-ipsum-lorem" }`, responseRecorder.Body.String())
+}
+
+func (suite *ExecutionerTestSuite) TestIdempotentJobExecutionWithDifferentPayload() {
+	t := suite.T()
+
+	req := suite.idempotentRequest(Job{Name: "sample-job-name", Args: map[string]string{"k1": "v2"}})
+	responseRecorder := httptest.NewRecorder()
+
+	record := &idempotency.Record{RequestHash: "another-hash", Completed: true, StatusCode: http.StatusCreated}
+	suite.mockIdempotency.On("Reserve", "user@example.com", "idempotency-key", mock.Anything).Return(false, nil).Once()
+	suite.mockIdempotency.On("Get", "user@example.com", "idempotency-key").Return(record, nil).Once()
+
+	suite.testExecutioner.Handle()(responseRecorder, req)
+
+	suite.mockKubeClient.AssertNotCalled(t, "ExecuteJob", mock.Anything, mock.Anything)
+
+	assert.Equal(t, http.StatusConflict, responseRecorder.Code)
+	assert.Equal(t, utility.ErrCodeIdempotencyKeyReused, errorResponse(t, responseRecorder).Code)
+}
+
+func (suite *ExecutionerTestSuite) TestIdempotentJobExecutionInProgress() {
+	t := suite.T()
+
+	job := Job{Name: "sample-job-name"}
+	req := suite.idempotentRequest(job)
+	responseRecorder := httptest.NewRecorder()
+
+	requestHash, err := idempotency.RequestHash(struct {
+		Requester string
+		Job       Job
+		Files     map[string][]byte
+		Query     string
+	}{"user@example.com", job, job.Files, ""})
+	assert.NoError(t, err)
+
+	suite.mockIdempotency.On("Reserve", "user@example.com", "idempotency-key", requestHash).Return(false, nil).Once()
+	suite.mockIdempotency.On("Get", "user@example.com", "idempotency-key").Return(&idempotency.Record{RequestHash: requestHash}, nil).Once()
+
+	suite.testExecutioner.Handle()(responseRecorder, req)
+
+	assert.Equal(t, http.StatusConflict, responseRecorder.Code)
+	assert.Equal(t, utility.ErrCodeRequestInProgress, errorResponse(t, responseRecorder).Code)
+}
+
+func (suite *ExecutionerTestSuite) TestHandleOutput() {
+	t := suite.T()
+
+	finishedAt := time.Now()
+	execution := &Execution{Name: "proctor-ipsum-lorem", Status: StatusSucceeded, Output: `{"rows_updated":1234}`, FinishedAt: &finishedAt}
+	suite.mockStore.On("GetExecution", "proctor-ipsum-lorem").Return(execution, nil).Once()
+
+	responseRecorder := suite.serveOutput("proctor-ipsum-lorem")
+
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+	assert.Equal(t, "application/json", responseRecorder.Header().Get("Content-Type"))
+	assert.Equal(t, `{"rows_updated":1234}`, responseRecorder.Body.String())
+}
+
+func (suite *ExecutionerTestSuite) TestHandleOutputWrapsNonJSONOutput() {
+	t := suite.T()
+
+	finishedAt := time.Now()
+	execution := &Execution{Name: "proctor-ipsum-lorem", Status: StatusFailed, Output: "rows updated: 1234", FinishedAt: &finishedAt}
+	suite.mockStore.On("GetExecution", "proctor-ipsum-lorem").Return(execution, nil).Once()
+
+	responseRecorder := suite.serveOutput("proctor-ipsum-lorem")
+
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+	assert.Equal(t, `"rows updated: 1234"`, responseRecorder.Body.String())
+}
+
+func (suite *ExecutionerTestSuite) TestHandleOutputForUnknownExecution() {
+	t := suite.T()
+
+	suite.mockStore.On("GetExecution", "proctor-ipsum-lorem").Return(&Execution{}, ErrExecutionNotFound).Once()
+
+	responseRecorder := suite.serveOutput("proctor-ipsum-lorem")
+
+	assert.Equal(t, http.StatusNotFound, responseRecorder.Code)
+	assert.Equal(t, utility.ErrCodeExecutionNotFound, errorResponse(t, responseRecorder).Code)
+}
+
+func (suite *ExecutionerTestSuite) TestHandleOutputForRunningExecution() {
+	t := suite.T()
+
+	suite.mockStore.On("GetExecution", "proctor-ipsum-lorem").Return(&Execution{Name: "proctor-ipsum-lorem", Status: StatusRunning}, nil).Once()
+
+	responseRecorder := suite.serveOutput("proctor-ipsum-lorem")
+
+	assert.Equal(t, http.StatusConflict, responseRecorder.Code)
+	assert.Equal(t, utility.ErrCodeExecutionNotFinished, errorResponse(t, responseRecorder).Code)
+}
+
+func (suite *ExecutionerTestSuite) TestHandleOutputWithoutOutput() {
+	t := suite.T()
+
+	finishedAt := time.Now()
+	suite.mockStore.On("GetExecution", "proctor-ipsum-lorem").Return(&Execution{Name: "proctor-ipsum-lorem", Status: StatusSucceeded, FinishedAt: &finishedAt}, nil).Once()
+
+	responseRecorder := suite.serveOutput("proctor-ipsum-lorem")
+
+	assert.Equal(t, http.StatusNotFound, responseRecorder.Code)
+	assert.Equal(t, utility.ErrCodeOutputNotFound, errorResponse(t, responseRecorder).Code)
+}
+
+func (suite *ExecutionerTestSuite) TestHandleOutputStoreFailure() {
+	t := suite.T()
+
+	suite.mockStore.On("GetExecution", "proctor-ipsum-lorem").Return(&Execution{}, errors.New("error")).Once()
+
+	responseRecorder := suite.serveOutput("proctor-ipsum-lorem")
+
+	assert.Equal(t, http.StatusServiceUnavailable, responseRecorder.Code)
+	assert.Equal(t, utility.ErrCodeStoreUnavailable, errorResponse(t, responseRecorder).Code)
+}
+
+func (suite *ExecutionerTestSuite) serveExecution(method, path string) *httptest.ResponseRecorder {
+	router := mux.NewRouter()
+	router.HandleFunc("/executions/{name}", suite.testExecutioner.HandleStatus()).Methods("GET")
+	router.HandleFunc("/executions/{name}/cancel", suite.testExecutioner.HandleCancellation()).Methods("POST")
+
+	responseRecorder := httptest.NewRecorder()
+	router.ServeHTTP(responseRecorder, httptest.NewRequest(method, path, nil))
+	return responseRecorder
+}
+
+func (suite *ExecutionerTestSuite) TestHandleStatus() {
+	t := suite.T()
+
+	execution := &Execution{Name: "proctor-ipsum-lorem", JobName: "sample-job-name", Status: StatusRunning}
+	suite.mockStore.On("GetExecution", "proctor-ipsum-lorem").Return(execution, nil).Once()
+
+	responseRecorder := suite.serveExecution("GET", "/executions/proctor-ipsum-lorem")
+
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+	var body Execution
+	assert.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &body))
+	assert.Equal(t, "sample-job-name", body.JobName)
+	assert.Equal(t, StatusRunning, body.Status)
+}
+
+func (suite *ExecutionerTestSuite) TestHandleStatusForUnknownExecution() {
+	t := suite.T()
+
+	suite.mockStore.On("GetExecution", "proctor-ipsum-lorem").Return(&Execution{}, ErrExecutionNotFound).Once()
+
+	responseRecorder := suite.serveExecution("GET", "/executions/proctor-ipsum-lorem")
+
+	assert.Equal(t, http.StatusNotFound, responseRecorder.Code)
+	assert.Equal(t, utility.ErrCodeExecutionNotFound, errorResponse(t, responseRecorder).Code)
+}
+
+func (suite *ExecutionerTestSuite) TestHandleCancellation() {
+	t := suite.T()
+
+	execution := &Execution{Name: "proctor-ipsum-lorem", Namespace: "batch", Status: StatusRunning}
+	suite.mockStore.On("GetExecution", "proctor-ipsum-lorem").Return(execution, nil).Once()
+	suite.mockKubeClient.On("DeleteJob", kubernetes.Target{Namespace: "batch"}, "proctor-ipsum-lorem").Return(nil).Once()
+	suite.mockStore.On("SaveExecution", mock.MatchedBy(func(execution Execution) bool {
+		return execution.Status == StatusCancelled && execution.HasFinished()
+	})).Return(nil).Once()
+
+	responseRecorder := suite.serveExecution("POST", "/executions/proctor-ipsum-lorem/cancel")
+
+	suite.mockKubeClient.AssertExpectations(t)
+	suite.mockStore.AssertExpectations(t)
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+}
+
+func (suite *ExecutionerTestSuite) TestHandleCancellationForFinishedExecution() {
+	t := suite.T()
+
+	finishedAt := time.Now()
+	suite.mockStore.On("GetExecution", "proctor-ipsum-lorem").Return(&Execution{Name: "proctor-ipsum-lorem", Status: StatusSucceeded, FinishedAt: &finishedAt}, nil).Once()
+
+	responseRecorder := suite.serveExecution("POST", "/executions/proctor-ipsum-lorem/cancel")
+
+	suite.mockKubeClient.AssertNotCalled(t, "DeleteJob", mock.Anything, mock.Anything)
+	assert.Equal(t, http.StatusConflict, responseRecorder.Code)
+	assert.Equal(t, utility.ErrCodeExecutionFinished, errorResponse(t, responseRecorder).Code)
+}
+
+func (suite *ExecutionerTestSuite) TestHandleCancellationOnDeleteFailure() {
+	t := suite.T()
+
+	suite.mockStore.On("GetExecution", "proctor-ipsum-lorem").Return(&Execution{Name: "proctor-ipsum-lorem", Status: StatusRunning}, nil).Once()
+	suite.mockStore.On("SaveExecution", mock.MatchedBy(func(execution Execution) bool {
+		return execution.Status == StatusCancelled
+	})).Return(nil).Once()
+	suite.mockKubeClient.On("DeleteJob", kubernetes.Target{}, "proctor-ipsum-lorem").Return(errors.New("error")).Once()
+	suite.mockStore.On("SaveExecution", Execution{Name: "proctor-ipsum-lorem", Status: StatusRunning}).Return(nil).Once()
+
+	responseRecorder := suite.serveExecution("POST", "/executions/proctor-ipsum-lorem/cancel")
+
+	suite.mockStore.AssertExpectations(t)
+	assert.Equal(t, http.StatusInternalServerError, responseRecorder.Code)
+	assert.Equal(t, utility.ErrCodeExecutionFailed, errorResponse(t, responseRecorder).Code)
+}
+
+func (suite *ExecutionerTestSuite) TestHandleCancellationForUnknownExecution() {
+	t := suite.T()
+
+	suite.mockStore.On("GetExecution", "proctor-ipsum-lorem").Return(&Execution{}, ErrExecutionNotFound).Once()
+
+	responseRecorder := suite.serveExecution("POST", "/executions/proctor-ipsum-lorem/cancel")
+
+	suite.mockKubeClient.AssertNotCalled(t, "DeleteJob", mock.Anything, mock.Anything)
+	assert.Equal(t, http.StatusNotFound, responseRecorder.Code)
+	assert.Equal(t, utility.ErrCodeExecutionNotFound, errorResponse(t, responseRecorder).Code)
+}
+
+func (suite *ExecutionerTestSuite) TestHandleCancellationOnStoreFailure() {
+	t := suite.T()
+
+	suite.mockStore.On("GetExecution", "proctor-ipsum-lorem").Return(&Execution{}, errors.New("error")).Once()
+
+	responseRecorder := suite.serveExecution("POST", "/executions/proctor-ipsum-lorem/cancel")
+
+	suite.mockKubeClient.AssertNotCalled(t, "DeleteJob", mock.Anything, mock.Anything)
+	assert.Equal(t, http.StatusServiceUnavailable, responseRecorder.Code)
+	assert.Equal(t, utility.ErrCodeStoreUnavailable, errorResponse(t, responseRecorder).Code)
+}
+
+func (suite *ExecutionerTestSuite) TestHandleCancellationOnSaveFailure() {
+	t := suite.T()
+
+	suite.mockStore.On("GetExecution", "proctor-ipsum-lorem").Return(&Execution{Name: "proctor-ipsum-lorem", Status: StatusRunning}, nil).Once()
+	suite.mockStore.On("SaveExecution", mock.Anything).Return(errors.New("error")).Once()
+
+	responseRecorder := suite.serveExecution("POST", "/executions/proctor-ipsum-lorem/cancel")
+
+	suite.mockKubeClient.AssertNotCalled(t, "DeleteJob", mock.Anything, mock.Anything)
+	assert.Equal(t, http.StatusServiceUnavailable, responseRecorder.Code)
+	assert.Equal(t, utility.ErrCodeStoreUnavailable, errorResponse(t, responseRecorder).Code)
+}
+
+func (suite *ExecutionerTestSuite) TestHandleCancellationSavesBeforeDeletingJob() {
+	t := suite.T()
+
+	var saved bool
+	suite.mockStore.On("GetExecution", "proctor-ipsum-lorem").Return(&Execution{Name: "proctor-ipsum-lorem", Status: StatusRunning}, nil).Once()
+	suite.mockStore.On("SaveExecution", mock.Anything).Return(nil).Run(func(mock.Arguments) {
+		saved = true
+	}).Once()
+	suite.mockKubeClient.On("DeleteJob", kubernetes.Target{}, "proctor-ipsum-lorem").Return(nil).Run(func(mock.Arguments) {
+		assert.True(t, saved, "expected CANCELLED to be saved before the job is deleted")
+	}).Once()
+
+	responseRecorder := suite.serveExecution("POST", "/executions/proctor-ipsum-lorem/cancel")
+
+	suite.mockKubeClient.AssertExpectations(t)
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
 }
 
 func TestExecutionerTestSuite(t *testing.T) {
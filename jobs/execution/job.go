@@ -1,6 +1,134 @@
 package execution
 
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gojektech/proctor-engine/config"
+	"github.com/gojektech/proctor-engine/jobs/metadata"
+	"github.com/gojektech/proctor-engine/jobs/policy"
+	"github.com/gojektech/proctor-engine/jobs/webhook"
+	"github.com/gojektech/proctor-engine/kubernetes"
+	"github.com/gojektech/proctor-engine/utility"
+)
+
+const (
+	JobFormField            = "job"
+	defaultFileInputMaxSize = 1 << 20
+	multipartOverheadBytes  = 64 << 10
+)
+
+var errFilesTooLarge = errors.New("uploaded files are too large")
+
 type Job struct {
-	Name string            `json:"name"`
-	Args map[string]string `json:"args"`
+	Name        string            `json:"name"`
+	Args        map[string]string `json:"args"`
+	CallbackURL string            `json:"callback_url,omitempty"`
+	Cluster     string            `json:"cluster,omitempty"`
+	Namespace   string            `json:"namespace,omitempty"`
+	Files       map[string][]byte `json:"-"`
+}
+
+func (job Job) Target() kubernetes.Target {
+	return kubernetes.Target{Cluster: job.Cluster, Namespace: job.Namespace}
+}
+
+func (job Job) policyInput(jobMetadata *metadata.Metadata, user string) policy.Input {
+	var files []string
+	for name := range job.Files {
+		files = append(files, name)
+	}
+	sort.Strings(files)
+
+	target := jobMetadata.Target().Override(job.Target())
+	return policy.Input{
+		JobName:   job.Name,
+		Args:      job.Args,
+		Cluster:   target.Cluster,
+		Namespace: target.Namespace,
+		Files:     files,
+		User:      user,
+		Metadata:  jobMetadata,
+		Time:      time.Now(),
+	}
+}
+
+func (job Job) Validate() []utility.FieldError {
+	var fieldErrors []utility.FieldError
+	if job.Name == "" {
+		fieldErrors = append(fieldErrors, utility.FieldError{Field: "name", Message: "is required"})
+	}
+	switch {
+	case job.CallbackURL == "":
+	case !config.WebhooksEnabled():
+		fieldErrors = append(fieldErrors, utility.FieldError{Field: "callback_url", Message: "is not allowed while webhooks are disabled"})
+	case !utility.IsHTTPURL(job.CallbackURL):
+		fieldErrors = append(fieldErrors, utility.FieldError{Field: "callback_url", Message: "must be an http or https url"})
+	case !webhook.IsPermittedURL(job.CallbackURL):
+		fieldErrors = append(fieldErrors, utility.FieldError{Field: "callback_url", Message: "must not target a private address"})
+	}
+	return fieldErrors
+}
+
+func fileInputMaxBytes() int64 {
+	maxBytes := config.FileInputMaxBytes()
+	if maxBytes <= 0 {
+		return defaultFileInputMaxSize
+	}
+	return maxBytes
+}
+
+func decodeJob(w http.ResponseWriter, req *http.Request) (Job, error) {
+	var job Job
+
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		err := json.NewDecoder(req.Body).Decode(&job)
+		return job, err
+	}
+
+	maxBytes := fileInputMaxBytes()
+	req.Body = http.MaxBytesReader(w, req.Body, maxBytes+multipartOverheadBytes)
+	if err := req.ParseMultipartForm(maxBytes); err != nil {
+		if err.Error() == "http: request body too large" {
+			return job, errFilesTooLarge
+		}
+		return job, err
+	}
+
+	if err := json.Unmarshal([]byte(req.FormValue(JobFormField)), &job); err != nil {
+		return job, err
+	}
+
+	var totalBytes int64
+	job.Files = map[string][]byte{}
+	for name, fileHeaders := range req.MultipartForm.File {
+		if len(fileHeaders) != 1 {
+			return job, fmt.Errorf("expected a single file for %s", name)
+		}
+
+		file, err := fileHeaders[0].Open()
+		if err != nil {
+			return job, err
+		}
+		content, err := ioutil.ReadAll(io.LimitReader(file, maxBytes+1))
+		file.Close()
+		if err != nil {
+			return job, err
+		}
+
+		totalBytes += int64(len(content))
+		if totalBytes > maxBytes {
+			return job, errFilesTooLarge
+		}
+		job.Files[name] = content
+	}
+	return job, nil
 }
@@ -0,0 +1,48 @@
+package execution
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gojektech/proctor-engine/logger"
+	"github.com/gojektech/proctor-engine/utility"
+
+	"github.com/gorilla/mux"
+)
+
+func (executioner *executioner) HandleOutput() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		executedJobName := mux.Vars(req)["name"]
+		execution, ok := executioner.execution(w, req)
+		if !ok {
+			return
+		}
+
+		if !execution.HasFinished() {
+			utility.WriteError(w, req, http.StatusConflict, utility.ErrCodeExecutionNotFinished, fmt.Sprintf("execution %s has not finished", executedJobName))
+			return
+		}
+
+		if execution.Output == "" {
+			utility.WriteError(w, req, http.StatusNotFound, utility.ErrCodeOutputNotFound, fmt.Sprintf("execution %s did not write any output", executedJobName))
+			return
+		}
+
+		output := []byte(execution.Output)
+		if !json.Valid(output) {
+			var err error
+			output, err = json.Marshal(execution.Output)
+			if err != nil {
+				logger.FromContext(req.Context()).WithField(logger.ExecutionNameField, executedJobName).Error("Error encoding execution output", err.Error())
+
+				utility.WriteError(w, req, http.StatusInternalServerError, utility.ErrCodeInternal, utility.ServerError)
+				return
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(output)
+	}
+}
@@ -0,0 +1,418 @@
+package execution
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gojektech/proctor-engine/config"
+	"github.com/gojektech/proctor-engine/jobs/metadata"
+	"github.com/gojektech/proctor-engine/jobs/queue"
+	"github.com/gojektech/proctor-engine/kubernetes"
+	"github.com/gojektech/proctor-engine/logger"
+	"github.com/gojektech/proctor-engine/utility"
+
+	"github.com/gorilla/mux"
+)
+
+const defaultDispatchInterval = 5 * time.Second
+
+func limitsFor(jobMetadata *metadata.Metadata) queue.Limits {
+	return queue.Limits{
+		Job:    jobMetadata.MaxConcurrent,
+		Global: config.MaxConcurrentExecutions(),
+	}
+}
+
+func (executioner *executioner) admit(ctx context.Context, job Job, jobMetadata *metadata.Metadata) (string, error) {
+	limits := limitsFor(jobMetadata)
+	if !limits.Enabled() {
+		return "", nil
+	}
+
+	slotID, err := utility.RandomID()
+	if err != nil {
+		return "", err
+	}
+
+	admitted, err := executioner.queueStore.Admit(job.Name, slotID, limits)
+	if err != nil {
+		return "", err
+	}
+	if admitted {
+		return slotID, nil
+	}
+
+	if jobMetadata.RejectsOverConcurrencyLimit() {
+		return "", queue.ErrConcurrencyLimitReached
+	}
+
+	files, sensitiveFiles := splitSensitiveFiles(jobMetadata, job.Files)
+	entry := queue.Entry{
+		ID:             slotID,
+		JobName:        job.Name,
+		Args:           job.Args,
+		CallbackURL:    job.CallbackURL,
+		Cluster:        job.Cluster,
+		Namespace:      job.Namespace,
+		Files:          files,
+		SensitiveFiles: sensitiveFiles,
+		Status:         queue.StatusQueued,
+		EnqueuedAt:     time.Now(),
+	}
+	position, err := executioner.queueStore.Enqueue(entry)
+	if err != nil {
+		return "", err
+	}
+
+	logger.FromContext(ctx).Info("Execution queued at position ", position)
+	return "", &queue.QueuedError{Position: executioner.positionOf(ctx, entry, position, limits.Job)}
+}
+
+func splitSensitiveFiles(jobMetadata *metadata.Metadata, jobFiles map[string][]byte) (map[string][]byte, map[string][]byte) {
+	sensitive := map[string]bool{}
+	for _, fileInput := range jobMetadata.Files {
+		sensitive[fileInput.Name] = fileInput.Sensitive
+	}
+
+	var files, sensitiveFiles map[string][]byte
+	for name, content := range jobFiles {
+		if sensitive[name] {
+			if sensitiveFiles == nil {
+				sensitiveFiles = map[string][]byte{}
+			}
+			sensitiveFiles[name] = content
+			continue
+		}
+		if files == nil {
+			files = map[string][]byte{}
+		}
+		files[name] = content
+	}
+	return files, sensitiveFiles
+}
+
+func mergeFiles(files map[string][]byte, sensitiveFiles map[string][]byte) map[string][]byte {
+	merged := map[string][]byte{}
+	for name, content := range files {
+		merged[name] = content
+	}
+	for name, content := range sensitiveFiles {
+		merged[name] = content
+	}
+	return merged
+}
+
+func (executioner *executioner) positionOf(ctx context.Context, entry queue.Entry, position int, jobLimit int) queue.Position {
+	estimatedDuration, err := executioner.queueStore.EstimatedDuration(entry.JobName)
+	if err != nil {
+		logger.FromContext(ctx).Error("Error fetching estimated duration of job", err.Error())
+	}
+
+	return queue.Position{
+		ID:         entry.ID,
+		JobName:    entry.JobName,
+		Status:     entry.Status,
+		Position:   position,
+		ETASeconds: queue.EstimatedWait(position, jobLimit, estimatedDuration).Seconds(),
+	}
+}
+
+func (executioner *executioner) releaseSlot(ctx context.Context, jobName string, slotID string) {
+	if slotID == "" {
+		return
+	}
+
+	if err := executioner.queueStore.Release(jobName, slotID); err != nil {
+		logger.FromContext(ctx).Error("Error releasing concurrency slot", err.Error())
+	}
+}
+
+func writeAdmissionError(w http.ResponseWriter, req *http.Request, log *logger.Entry, err error) {
+	if queuedErr, ok := err.(*queue.QueuedError); ok {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(queuedErr.Position)
+		return
+	}
+
+	if err == queue.ErrConcurrencyLimitReached {
+		utility.WriteError(w, req, http.StatusTooManyRequests, utility.ErrCodeConcurrencyLimitReached, "concurrency limit reached for job, retry later")
+		return
+	}
+
+	if err == queue.ErrEncryptionKeyMissing {
+		log.Error("Rejecting execution with sensitive files, queue encryption key is not configured")
+
+		utility.WriteError(w, req, http.StatusInternalServerError, utility.ErrCodeQueueUnavailable, "executions with sensitive files cannot be queued, the queue encryption key is not configured")
+		return
+	}
+
+	log.Error("Error checking concurrency limits for job", err.Error())
+
+	utility.WriteError(w, req, http.StatusServiceUnavailable, utility.ErrCodeStoreUnavailable, "unable to check concurrency limits")
+}
+
+func (executioner *executioner) StartDispatcher(ctx context.Context) {
+	interval := config.QueueDispatchInterval()
+	if interval <= 0 {
+		interval = defaultDispatchInterval
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-executioner.tracker.Finished():
+			}
+			executioner.dispatchQueued(ctx)
+		}
+	}()
+
+	go func() {
+		ticker := time.NewTicker(queue.SlotLease() / 3)
+		defer ticker.Stop()
+
+		for {
+			executioner.reconcileSlots(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// reconcileSlots renews the leases of slots whose kubernetes jobs are still
+// running, whichever engine started them. Slots without a live job are left
+// to expire.
+func (executioner *executioner) reconcileSlots(ctx context.Context) {
+	log := logger.FromContext(ctx)
+
+	slots, err := executioner.kubeClient.ListActiveSlots()
+	if err != nil {
+		log.Error("Error listing running kubernetes jobs to reconcile concurrency slots", err.Error())
+		return
+	}
+
+	for _, slot := range slots {
+		if err := executioner.queueStore.Renew(slot.JobName, slot.ID); err != nil {
+			log.Error("Error renewing concurrency slot ", slot.ID, " ", err.Error())
+		}
+	}
+}
+
+func (executioner *executioner) dispatchQueued(ctx context.Context) {
+	jobNames, err := executioner.queueStore.QueuedJobs()
+	if err != nil {
+		logger.FromContext(ctx).Error("Error listing queued jobs", err.Error())
+		return
+	}
+
+	for _, jobName := range jobNames {
+		jobCtx := logger.WithFields(ctx, logger.Fields{logger.JobNameField: jobName})
+		log := logger.FromContext(jobCtx)
+
+		jobMetadata, err := executioner.metadataStore.GetJobMetadata(jobName)
+		if err == metadata.ErrJobNotFound {
+			executioner.failQueued(jobCtx, jobName)
+			continue
+		}
+		if err != nil {
+			log.Error("Error fetching metadata of queued job", err.Error())
+			continue
+		}
+
+		limits := limitsFor(jobMetadata)
+		for {
+			entry, err := executioner.queueStore.Dispatch(jobName, limits)
+			if err != nil {
+				log.Error("Error dispatching queued execution", err.Error())
+				break
+			}
+			if entry == nil {
+				break
+			}
+
+			executioner.dispatch(jobCtx, *entry, jobMetadata)
+		}
+	}
+}
+
+// failQueued fails the queued executions of a job whose metadata was deleted,
+// since they can never be dispatched.
+func (executioner *executioner) failQueued(ctx context.Context, jobName string) {
+	log := logger.FromContext(ctx)
+
+	ids, err := executioner.queueStore.Drop(jobName)
+	if err != nil {
+		log.Error("Error dropping queued executions of deleted job", err.Error())
+		return
+	}
+
+	for _, id := range ids {
+		entry, err := executioner.queueStore.GetEntry(id)
+		if err != nil {
+			log.Error("Error fetching queue entry ", id, " of deleted job ", err.Error())
+			continue
+		}
+
+		log.WithField("queue_id", id).Info("Failing queued execution of deleted job")
+		entry.Status = queue.StatusFailed
+		entry.Files = nil
+		entry.SensitiveFiles = nil
+		if err := executioner.queueStore.SaveEntry(*entry); err != nil {
+			log.Error("Error saving failed queue entry ", id, " ", err.Error())
+		}
+	}
+}
+
+func (executioner *executioner) dispatch(ctx context.Context, entry queue.Entry, jobMetadata *metadata.Metadata) {
+	log := logger.FromContext(ctx).WithField("queue_id", entry.ID)
+
+	if entry.Status == queue.StatusCancelled {
+		log.Info("Skipping cancelled queue entry")
+		executioner.releaseSlot(ctx, entry.JobName, entry.ID)
+		return
+	}
+
+	files := mergeFiles(entry.Files, entry.SensitiveFiles)
+	entry.Files = nil
+	entry.SensitiveFiles = nil
+
+	var executedJobName string
+	jobSecrets, err := executioner.jobSecrets(entry.JobName)
+	if err == nil {
+		job := Job{
+			Name:        entry.JobName,
+			Args:        entry.Args,
+			CallbackURL: entry.CallbackURL,
+			Cluster:     entry.Cluster,
+			Namespace:   entry.Namespace,
+			Files:       files,
+		}
+		var spec kubernetes.ExecutionSpec
+		spec, err = executionSpec(jobMetadata, job, jobSecrets)
+		if err == nil {
+			executedJobName, err = executioner.runJob(ctx, job, jobMetadata, spec, entry.ID)
+		}
+	}
+
+	dispatchedAt := time.Now()
+	entry.DispatchedAt = &dispatchedAt
+	if err != nil {
+		log.Error("Error executing queued job", err.Error())
+
+		executioner.releaseSlot(ctx, entry.JobName, entry.ID)
+		entry.Status = queue.StatusFailed
+	} else {
+		log.Info("Dispatched queued execution ", executedJobName)
+
+		entry.Status = queue.StatusDispatched
+		entry.ExecutionName = executedJobName
+	}
+
+	if err := executioner.queueStore.SaveEntry(entry); err != nil {
+		log.Error("Error saving dispatched queue entry", err.Error())
+	}
+}
+
+func (executioner *executioner) HandleQueueStatus() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		entry, ok := executioner.queueEntry(w, req)
+		if !ok {
+			return
+		}
+
+		position := queue.Position{
+			ID:            entry.ID,
+			JobName:       entry.JobName,
+			Status:        entry.Status,
+			ExecutionName: entry.ExecutionName,
+		}
+
+		if entry.Status == queue.StatusQueued {
+			index, err := executioner.queueStore.PositionOf(*entry)
+			if err != nil {
+				logger.FromContext(req.Context()).WithField("queue_id", entry.ID).Error("Error finding position of queue entry", err.Error())
+
+				utility.WriteError(w, req, http.StatusServiceUnavailable, utility.ErrCodeStoreUnavailable, "unable to fetch queue position")
+				return
+			}
+
+			jobLimit := 1
+			if jobMetadata, err := executioner.metadataStore.GetJobMetadata(entry.JobName); err == nil {
+				jobLimit = jobMetadata.MaxConcurrent
+			}
+			position = executioner.positionOf(req.Context(), *entry, index, jobLimit)
+		}
+
+		writePosition(w, position)
+	}
+}
+
+func (executioner *executioner) HandleQueueCancellation() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		entry, ok := executioner.queueEntry(w, req)
+		if !ok {
+			return
+		}
+		log := logger.FromContext(req.Context()).WithField("queue_id", entry.ID)
+
+		removed := false
+		if entry.Status == queue.StatusQueued {
+			var err error
+			removed, err = executioner.queueStore.Remove(*entry)
+			if err != nil {
+				log.Error("Error removing queue entry", err.Error())
+
+				utility.WriteError(w, req, http.StatusServiceUnavailable, utility.ErrCodeStoreUnavailable, "unable to remove queue entry")
+				return
+			}
+		}
+		if !removed {
+			utility.WriteError(w, req, http.StatusConflict, utility.ErrCodeQueueEntryNotQueued, fmt.Sprintf("queue entry %s is no longer queued", entry.ID))
+			return
+		}
+
+		entry.Status = queue.StatusCancelled
+		entry.Files = nil
+		entry.SensitiveFiles = nil
+		if err := executioner.queueStore.SaveEntry(*entry); err != nil {
+			log.Error("Error saving cancelled queue entry", err.Error())
+		}
+
+		writePosition(w, queue.Position{ID: entry.ID, JobName: entry.JobName, Status: entry.Status})
+	}
+}
+
+func (executioner *executioner) queueEntry(w http.ResponseWriter, req *http.Request) (*queue.Entry, bool) {
+	queueID := mux.Vars(req)["id"]
+
+	entry, err := executioner.queueStore.GetEntry(queueID)
+	if err == queue.ErrEntryNotFound {
+		utility.WriteError(w, req, http.StatusNotFound, utility.ErrCodeQueueEntryNotFound, fmt.Sprintf("queue entry %s not found", queueID))
+		return nil, false
+	}
+	if err != nil {
+		logger.FromContext(req.Context()).WithField("queue_id", queueID).Error("Error fetching queue entry", err.Error())
+
+		utility.WriteError(w, req, http.StatusServiceUnavailable, utility.ErrCodeStoreUnavailable, "unable to fetch queue entry")
+		return nil, false
+	}
+	return entry, true
+}
+
+func writePosition(w http.ResponseWriter, position queue.Position) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(position)
+}
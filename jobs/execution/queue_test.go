@@ -0,0 +1,413 @@
+package execution
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/gojektech/proctor-engine/jobs/metadata"
+	"github.com/gojektech/proctor-engine/jobs/queue"
+	"github.com/gojektech/proctor-engine/kubernetes"
+	"github.com/gojektech/proctor-engine/utility"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func (suite *ExecutionerTestSuite) executeRequest(job Job) *httptest.ResponseRecorder {
+	requestBody, err := json.Marshal(job)
+	assert.NoError(suite.T(), err)
+
+	req := httptest.NewRequest("POST", "/execute", bytes.NewReader(requestBody))
+	responseRecorder := httptest.NewRecorder()
+
+	suite.testExecutioner.Handle()(responseRecorder, req)
+	return responseRecorder
+}
+
+func (suite *ExecutionerTestSuite) serveQueueStatus(queueID string) *httptest.ResponseRecorder {
+	router := mux.NewRouter()
+	router.HandleFunc("/jobs/queue/{id}", suite.testExecutioner.HandleQueueStatus()).Methods("GET")
+
+	req := httptest.NewRequest("GET", "/jobs/queue/"+queueID, nil)
+	responseRecorder := httptest.NewRecorder()
+
+	router.ServeHTTP(responseRecorder, req)
+	return responseRecorder
+}
+
+func (suite *ExecutionerTestSuite) serveQueueCancellation(queueID string) *httptest.ResponseRecorder {
+	router := mux.NewRouter()
+	router.HandleFunc("/queue/{id}/cancel", suite.testExecutioner.HandleQueueCancellation()).Methods("POST")
+
+	req := httptest.NewRequest("POST", "/queue/"+queueID+"/cancel", nil)
+	responseRecorder := httptest.NewRecorder()
+
+	router.ServeHTTP(responseRecorder, req)
+	return responseRecorder
+}
+
+func (suite *ExecutionerTestSuite) TestJobExecutionWithinConcurrencyLimit() {
+	t := suite.T()
+
+	jobMetadata := metadata.Metadata{ImageName: "img", MaxConcurrent: 2}
+	suite.mockMetadataStore.On("GetJobMetadata", "sample-job-name").Return(&jobMetadata, nil).Once()
+	suite.mockSecretsStore.On("GetJobSecrets", "sample-job-name").Return(map[string]string{}, nil).Once()
+
+	var slotID string
+	suite.mockQueueStore.On("Admit", "sample-job-name", mock.Anything, queue.Limits{Job: 2}).Return(true, nil).Run(func(args mock.Arguments) {
+		slotID = args.String(1)
+	}).Once()
+	suite.mockKubeClient.On("ExecuteJob", mock.Anything, mock.Anything).Return("proctor-ipsum-lorem", nil).Once()
+	suite.mockTracker.On("Track", mock.Anything, mock.MatchedBy(func(execution Execution) bool {
+		return execution.SlotID != "" && execution.SlotID == slotID
+	})).Once()
+
+	responseRecorder := suite.executeRequest(Job{Name: "sample-job-name"})
+
+	suite.mockQueueStore.AssertExpectations(t)
+	suite.mockTracker.AssertExpectations(t)
+
+	assert.Equal(t, http.StatusCreated, responseRecorder.Code)
+}
+
+func (suite *ExecutionerTestSuite) TestJobExecutionQueuedOverConcurrencyLimit() {
+	t := suite.T()
+
+	jobMetadata := metadata.Metadata{ImageName: "img", MaxConcurrent: 2}
+	suite.mockMetadataStore.On("GetJobMetadata", "sample-job-name").Return(&jobMetadata, nil).Once()
+	suite.mockSecretsStore.On("GetJobSecrets", "sample-job-name").Return(map[string]string{}, nil).Once()
+
+	suite.mockQueueStore.On("Admit", "sample-job-name", mock.Anything, queue.Limits{Job: 2}).Return(false, nil).Once()
+	suite.mockQueueStore.On("Enqueue", mock.MatchedBy(func(entry queue.Entry) bool {
+		return entry.ID != "" && entry.JobName == "sample-job-name" && entry.Status == queue.StatusQueued
+	})).Return(3, nil).Once()
+	suite.mockQueueStore.On("EstimatedDuration", "sample-job-name").Return(time.Minute, nil).Once()
+
+	responseRecorder := suite.executeRequest(Job{Name: "sample-job-name"})
+
+	suite.mockQueueStore.AssertExpectations(t)
+	suite.mockKubeClient.AssertNotCalled(t, "ExecuteJob", mock.Anything, mock.Anything)
+
+	assert.Equal(t, http.StatusAccepted, responseRecorder.Code)
+	var position queue.Position
+	assert.NoError(t, json.NewDecoder(responseRecorder.Body).Decode(&position))
+	assert.Equal(t, 3, position.Position)
+	assert.Equal(t, queue.StatusQueued, position.Status)
+	assert.Equal(t, float64(120), position.ETASeconds)
+}
+
+func (suite *ExecutionerTestSuite) TestJobExecutionRejectedOverConcurrencyLimit() {
+	t := suite.T()
+
+	jobMetadata := metadata.Metadata{ImageName: "img", MaxConcurrent: 1, OnConcurrencyLimit: metadata.OnConcurrencyLimitReject}
+	suite.mockMetadataStore.On("GetJobMetadata", "sample-job-name").Return(&jobMetadata, nil).Once()
+	suite.mockSecretsStore.On("GetJobSecrets", "sample-job-name").Return(map[string]string{}, nil).Once()
+	suite.mockQueueStore.On("Admit", "sample-job-name", mock.Anything, queue.Limits{Job: 1}).Return(false, nil).Once()
+
+	responseRecorder := suite.executeRequest(Job{Name: "sample-job-name"})
+
+	suite.mockQueueStore.AssertNotCalled(t, "Enqueue", mock.Anything)
+
+	assert.Equal(t, http.StatusTooManyRequests, responseRecorder.Code)
+	assert.Equal(t, utility.ErrCodeConcurrencyLimitReached, errorResponse(t, responseRecorder).Code)
+}
+
+func (suite *ExecutionerTestSuite) TestJobExecutionReleasesSlotOnExecutionFailure() {
+	t := suite.T()
+
+	jobMetadata := metadata.Metadata{ImageName: "img", MaxConcurrent: 1}
+	suite.mockMetadataStore.On("GetJobMetadata", "sample-job-name").Return(&jobMetadata, nil).Once()
+	suite.mockSecretsStore.On("GetJobSecrets", "sample-job-name").Return(map[string]string{}, nil).Once()
+	suite.mockQueueStore.On("Admit", "sample-job-name", mock.Anything, queue.Limits{Job: 1}).Return(true, nil).Once()
+	suite.mockKubeClient.On("ExecuteJob", mock.Anything, mock.Anything).Return("", assert.AnError).Once()
+	suite.mockQueueStore.On("Release", "sample-job-name", mock.Anything).Return(nil).Once()
+
+	responseRecorder := suite.executeRequest(Job{Name: "sample-job-name"})
+
+	suite.mockQueueStore.AssertExpectations(t)
+
+	assert.Equal(t, http.StatusInternalServerError, responseRecorder.Code)
+}
+
+func (suite *ExecutionerTestSuite) TestHandleQueueStatus() {
+	t := suite.T()
+
+	entry := &queue.Entry{ID: "queue-id", JobName: "sample-job-name", Status: queue.StatusQueued}
+	suite.mockQueueStore.On("GetEntry", "queue-id").Return(entry, nil).Once()
+	suite.mockQueueStore.On("PositionOf", *entry).Return(2, nil).Once()
+	suite.mockMetadataStore.On("GetJobMetadata", "sample-job-name").Return(&metadata.Metadata{MaxConcurrent: 1}, nil).Once()
+	suite.mockQueueStore.On("EstimatedDuration", "sample-job-name").Return(30*time.Second, nil).Once()
+
+	responseRecorder := suite.serveQueueStatus("queue-id")
+
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+	var position queue.Position
+	assert.NoError(t, json.NewDecoder(responseRecorder.Body).Decode(&position))
+	assert.Equal(t, queue.Position{ID: "queue-id", JobName: "sample-job-name", Status: queue.StatusQueued, Position: 2, ETASeconds: 60}, position)
+}
+
+func (suite *ExecutionerTestSuite) TestHandleQueueStatusOfDispatchedEntry() {
+	t := suite.T()
+
+	entry := &queue.Entry{ID: "queue-id", JobName: "sample-job-name", Status: queue.StatusDispatched, ExecutionName: "proctor-ipsum-lorem"}
+	suite.mockQueueStore.On("GetEntry", "queue-id").Return(entry, nil).Once()
+
+	responseRecorder := suite.serveQueueStatus("queue-id")
+
+	suite.mockQueueStore.AssertNotCalled(t, "PositionOf", mock.Anything)
+
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+	var position queue.Position
+	assert.NoError(t, json.NewDecoder(responseRecorder.Body).Decode(&position))
+	assert.Equal(t, "proctor-ipsum-lorem", position.ExecutionName)
+}
+
+func (suite *ExecutionerTestSuite) TestHandleQueueStatusForUnknownEntry() {
+	t := suite.T()
+
+	suite.mockQueueStore.On("GetEntry", "queue-id").Return((*queue.Entry)(nil), queue.ErrEntryNotFound).Once()
+
+	responseRecorder := suite.serveQueueStatus("queue-id")
+
+	assert.Equal(t, http.StatusNotFound, responseRecorder.Code)
+	assert.Equal(t, utility.ErrCodeQueueEntryNotFound, errorResponse(t, responseRecorder).Code)
+}
+
+func (suite *ExecutionerTestSuite) TestDispatchQueued() {
+	t := suite.T()
+
+	jobMetadata := metadata.Metadata{ImageName: "img", MaxConcurrent: 1}
+	entry := &queue.Entry{ID: "queue-id", JobName: "sample-job-name", Args: map[string]string{"argOne": "sample-arg"}, Status: queue.StatusQueued}
+
+	suite.mockQueueStore.On("QueuedJobs").Return([]string{"sample-job-name"}, nil).Once()
+	suite.mockMetadataStore.On("GetJobMetadata", "sample-job-name").Return(&jobMetadata, nil).Once()
+	suite.mockQueueStore.On("Dispatch", "sample-job-name", queue.Limits{Job: 1}).Return(entry, nil).Once()
+	suite.mockQueueStore.On("Dispatch", "sample-job-name", queue.Limits{Job: 1}).Return((*queue.Entry)(nil), nil).Once()
+	suite.mockSecretsStore.On("GetJobSecrets", "sample-job-name").Return(map[string]string{"secretOne": "sample-secret"}, nil).Once()
+	suite.mockKubeClient.On("ExecuteJob", mock.Anything, kubernetes.ExecutionSpec{ImageName: "img", EnvVars: map[string]string{"argOne": "sample-arg", "secretOne": "sample-secret"}, Slot: kubernetes.Slot{JobName: "sample-job-name", ID: "queue-id"}}).Return("proctor-ipsum-lorem", nil).Once()
+	suite.mockTracker.On("Track", mock.Anything, mock.MatchedBy(func(execution Execution) bool {
+		return execution.Name == "proctor-ipsum-lorem" && execution.SlotID == "queue-id"
+	})).Once()
+	suite.mockQueueStore.On("SaveEntry", mock.MatchedBy(func(entry queue.Entry) bool {
+		return entry.Status == queue.StatusDispatched && entry.ExecutionName == "proctor-ipsum-lorem" && entry.DispatchedAt != nil
+	})).Return(nil).Once()
+
+	suite.testExecutioner.(*executioner).dispatchQueued(context.Background())
+
+	suite.mockQueueStore.AssertExpectations(t)
+	suite.mockKubeClient.AssertExpectations(t)
+	suite.mockTracker.AssertExpectations(t)
+}
+
+func (suite *ExecutionerTestSuite) TestDispatchQueuedReleasesSlotOnFailure() {
+	t := suite.T()
+
+	jobMetadata := metadata.Metadata{ImageName: "img", MaxConcurrent: 1}
+	entry := &queue.Entry{ID: "queue-id", JobName: "sample-job-name", Status: queue.StatusQueued}
+
+	suite.mockQueueStore.On("QueuedJobs").Return([]string{"sample-job-name"}, nil).Once()
+	suite.mockMetadataStore.On("GetJobMetadata", "sample-job-name").Return(&jobMetadata, nil).Once()
+	suite.mockQueueStore.On("Dispatch", "sample-job-name", queue.Limits{Job: 1}).Return(entry, nil).Once()
+	suite.mockQueueStore.On("Dispatch", "sample-job-name", queue.Limits{Job: 1}).Return((*queue.Entry)(nil), nil).Once()
+	suite.mockSecretsStore.On("GetJobSecrets", "sample-job-name").Return(map[string]string{}, nil).Once()
+	suite.mockKubeClient.On("ExecuteJob", mock.Anything, mock.Anything).Return("", assert.AnError).Once()
+	suite.mockQueueStore.On("Release", "sample-job-name", "queue-id").Return(nil).Once()
+	suite.mockQueueStore.On("SaveEntry", mock.MatchedBy(func(entry queue.Entry) bool {
+		return entry.Status == queue.StatusFailed
+	})).Return(nil).Once()
+
+	suite.testExecutioner.(*executioner).dispatchQueued(context.Background())
+
+	suite.mockQueueStore.AssertExpectations(t)
+}
+
+func (suite *ExecutionerTestSuite) TestDispatchQueuedFailsEntriesOfDeletedJob() {
+	t := suite.T()
+
+	entry := &queue.Entry{ID: "queue-id", JobName: "sample-job-name", Files: map[string][]byte{"users.csv": []byte("id\n")}, Status: queue.StatusQueued}
+
+	suite.mockQueueStore.On("QueuedJobs").Return([]string{"sample-job-name"}, nil).Once()
+	suite.mockMetadataStore.On("GetJobMetadata", "sample-job-name").Return(&metadata.Metadata{}, metadata.ErrJobNotFound).Once()
+	suite.mockQueueStore.On("Drop", "sample-job-name").Return([]string{"queue-id"}, nil).Once()
+	suite.mockQueueStore.On("GetEntry", "queue-id").Return(entry, nil).Once()
+	suite.mockQueueStore.On("SaveEntry", mock.MatchedBy(func(entry queue.Entry) bool {
+		return entry.Status == queue.StatusFailed && entry.Files == nil
+	})).Return(nil).Once()
+
+	suite.testExecutioner.(*executioner).dispatchQueued(context.Background())
+
+	suite.mockQueueStore.AssertExpectations(t)
+	suite.mockQueueStore.AssertNotCalled(t, "Dispatch", mock.Anything, mock.Anything)
+}
+
+func (suite *ExecutionerTestSuite) TestDispatchQueuedKeepsEntriesWhenMetadataIsUnavailable() {
+	t := suite.T()
+
+	suite.mockQueueStore.On("QueuedJobs").Return([]string{"sample-job-name"}, nil).Once()
+	suite.mockMetadataStore.On("GetJobMetadata", "sample-job-name").Return(&metadata.Metadata{}, assert.AnError).Once()
+
+	suite.testExecutioner.(*executioner).dispatchQueued(context.Background())
+
+	suite.mockQueueStore.AssertNotCalled(t, "Drop", mock.Anything)
+}
+
+func (suite *ExecutionerTestSuite) TestHandleQueueCancellation() {
+	t := suite.T()
+
+	entry := &queue.Entry{ID: "queue-id", JobName: "sample-job-name", Status: queue.StatusQueued}
+	suite.mockQueueStore.On("GetEntry", "queue-id").Return(entry, nil).Once()
+	suite.mockQueueStore.On("Remove", *entry).Return(true, nil).Once()
+	suite.mockQueueStore.On("SaveEntry", mock.MatchedBy(func(entry queue.Entry) bool {
+		return entry.ID == "queue-id" && entry.Status == queue.StatusCancelled
+	})).Return(nil).Once()
+
+	responseRecorder := suite.serveQueueCancellation("queue-id")
+
+	suite.mockQueueStore.AssertExpectations(t)
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+	var position queue.Position
+	assert.NoError(t, json.NewDecoder(responseRecorder.Body).Decode(&position))
+	assert.Equal(t, queue.StatusCancelled, position.Status)
+}
+
+func (suite *ExecutionerTestSuite) TestHandleQueueCancellationOfDispatchedEntry() {
+	t := suite.T()
+
+	entry := &queue.Entry{ID: "queue-id", JobName: "sample-job-name", Status: queue.StatusDispatched, ExecutionName: "proctor-ipsum-lorem"}
+	suite.mockQueueStore.On("GetEntry", "queue-id").Return(entry, nil).Once()
+
+	responseRecorder := suite.serveQueueCancellation("queue-id")
+
+	suite.mockQueueStore.AssertNotCalled(t, "Remove", mock.Anything)
+	assert.Equal(t, http.StatusConflict, responseRecorder.Code)
+	assert.Equal(t, utility.ErrCodeQueueEntryNotQueued, errorResponse(t, responseRecorder).Code)
+}
+
+func (suite *ExecutionerTestSuite) TestHandleQueueCancellationRacingDispatch() {
+	t := suite.T()
+
+	entry := &queue.Entry{ID: "queue-id", JobName: "sample-job-name", Status: queue.StatusQueued}
+	suite.mockQueueStore.On("GetEntry", "queue-id").Return(entry, nil).Once()
+	suite.mockQueueStore.On("Remove", *entry).Return(false, nil).Once()
+
+	responseRecorder := suite.serveQueueCancellation("queue-id")
+
+	suite.mockQueueStore.AssertNotCalled(t, "SaveEntry", mock.Anything)
+	assert.Equal(t, http.StatusConflict, responseRecorder.Code)
+}
+
+func (suite *ExecutionerTestSuite) TestHandleQueueCancellationForUnknownEntry() {
+	t := suite.T()
+
+	suite.mockQueueStore.On("GetEntry", "queue-id").Return((*queue.Entry)(nil), queue.ErrEntryNotFound).Once()
+
+	responseRecorder := suite.serveQueueCancellation("queue-id")
+
+	assert.Equal(t, http.StatusNotFound, responseRecorder.Code)
+	assert.Equal(t, utility.ErrCodeQueueEntryNotFound, errorResponse(t, responseRecorder).Code)
+}
+
+func (suite *ExecutionerTestSuite) TestJobExecutionQueuesSensitiveFilesSeparately() {
+	t := suite.T()
+
+	jobMetadata := metadata.Metadata{
+		ImageName:     "img",
+		MaxConcurrent: 1,
+		Files: []metadata.FileInput{
+			{Name: "users.csv", MountPath: "/data/users.csv"},
+			{Name: "key.pem", MountPath: "/data/key.pem", Sensitive: true},
+		},
+	}
+	suite.mockMetadataStore.On("GetJobMetadata", "import-users").Return(&jobMetadata, nil).Once()
+	suite.mockSecretsStore.On("GetJobSecrets", "import-users").Return(map[string]string{}, nil).Once()
+	suite.mockQueueStore.On("Admit", "import-users", mock.Anything, queue.Limits{Job: 1}).Return(false, nil).Once()
+	suite.mockQueueStore.On("Enqueue", mock.MatchedBy(func(entry queue.Entry) bool {
+		return string(entry.Files["users.csv"]) == "id,email\n" && len(entry.Files) == 1 &&
+			string(entry.SensitiveFiles["key.pem"]) == "private key" && len(entry.SensitiveFiles) == 1
+	})).Return(1, nil).Once()
+	suite.mockQueueStore.On("EstimatedDuration", "import-users").Return(time.Duration(0), nil).Once()
+
+	req := multipartJobRequest(t, Job{Name: "import-users"}, map[string][]byte{"users.csv": []byte("id,email\n"), "key.pem": []byte("private key")})
+	responseRecorder := httptest.NewRecorder()
+	suite.testExecutioner.Handle()(responseRecorder, req)
+
+	suite.mockQueueStore.AssertExpectations(t)
+	assert.Equal(t, http.StatusAccepted, responseRecorder.Code)
+}
+
+func (suite *ExecutionerTestSuite) TestJobExecutionRejectedWhenSensitiveFilesCannotBeQueued() {
+	t := suite.T()
+
+	jobMetadata := metadata.Metadata{
+		ImageName:     "img",
+		MaxConcurrent: 1,
+		Files:         []metadata.FileInput{{Name: "key.pem", MountPath: "/data/key.pem", Sensitive: true}},
+	}
+	suite.mockMetadataStore.On("GetJobMetadata", "import-users").Return(&jobMetadata, nil).Once()
+	suite.mockSecretsStore.On("GetJobSecrets", "import-users").Return(map[string]string{}, nil).Once()
+	suite.mockQueueStore.On("Admit", "import-users", mock.Anything, queue.Limits{Job: 1}).Return(false, nil).Once()
+	suite.mockQueueStore.On("Enqueue", mock.Anything).Return(0, queue.ErrEncryptionKeyMissing).Once()
+
+	req := multipartJobRequest(t, Job{Name: "import-users"}, map[string][]byte{"key.pem": []byte("private key")})
+	responseRecorder := httptest.NewRecorder()
+	suite.testExecutioner.Handle()(responseRecorder, req)
+
+	assert.Equal(t, http.StatusInternalServerError, responseRecorder.Code)
+	assert.Equal(t, utility.ErrCodeQueueUnavailable, errorResponse(t, responseRecorder).Code)
+}
+
+func (suite *ExecutionerTestSuite) TestDispatchQueuedDropsFilesFromEntry() {
+	t := suite.T()
+
+	jobMetadata := metadata.Metadata{
+		ImageName:     "img",
+		MaxConcurrent: 1,
+		Files:         []metadata.FileInput{{Name: "key.pem", MountPath: "/data/key.pem", Sensitive: true}},
+	}
+	entry := &queue.Entry{ID: "queue-id", JobName: "import-users", SensitiveFiles: map[string][]byte{"key.pem": []byte("private key")}, Status: queue.StatusQueued}
+
+	suite.mockQueueStore.On("QueuedJobs").Return([]string{"import-users"}, nil).Once()
+	suite.mockMetadataStore.On("GetJobMetadata", "import-users").Return(&jobMetadata, nil).Once()
+	suite.mockQueueStore.On("Dispatch", "import-users", queue.Limits{Job: 1}).Return(entry, nil).Once()
+	suite.mockQueueStore.On("Dispatch", "import-users", queue.Limits{Job: 1}).Return((*queue.Entry)(nil), nil).Once()
+	suite.mockSecretsStore.On("GetJobSecrets", "import-users").Return(map[string]string{}, nil).Once()
+	suite.mockKubeClient.On("ExecuteJob", mock.Anything, mock.MatchedBy(func(spec kubernetes.ExecutionSpec) bool {
+		return len(spec.Files) == 1 && string(spec.Files[0].Content) == "private key" && spec.Files[0].Sensitive
+	})).Return("proctor-ipsum-lorem", nil).Once()
+	suite.mockTracker.On("Track", mock.Anything, mock.Anything).Once()
+	suite.mockQueueStore.On("SaveEntry", mock.MatchedBy(func(entry queue.Entry) bool {
+		return entry.Status == queue.StatusDispatched && entry.Files == nil && entry.SensitiveFiles == nil
+	})).Return(nil).Once()
+
+	suite.testExecutioner.(*executioner).dispatchQueued(context.Background())
+
+	suite.mockQueueStore.AssertExpectations(t)
+	suite.mockKubeClient.AssertExpectations(t)
+}
+
+func (suite *ExecutionerTestSuite) TestReconcileSlotsRenewsSlotsOfRunningJobs() {
+	t := suite.T()
+
+	suite.mockKubeClient.On("ListActiveSlots").Return([]kubernetes.Slot{{JobName: "sample-job-name", ID: "slot-one"}, {JobName: "another-job", ID: "slot-two"}}, nil).Once()
+	suite.mockQueueStore.On("Renew", "sample-job-name", "slot-one").Return(nil).Once()
+	suite.mockQueueStore.On("Renew", "another-job", "slot-two").Return(assert.AnError).Once()
+
+	suite.testExecutioner.(*executioner).reconcileSlots(context.Background())
+
+	suite.mockQueueStore.AssertExpectations(t)
+}
+
+func (suite *ExecutionerTestSuite) TestReconcileSlotsWhenJobsCannotBeListed() {
+	t := suite.T()
+
+	suite.mockKubeClient.On("ListActiveSlots").Return([]kubernetes.Slot(nil), assert.AnError).Once()
+
+	suite.testExecutioner.(*executioner).reconcileSlots(context.Background())
+
+	suite.mockQueueStore.AssertNotCalled(t, "Renew", mock.Anything, mock.Anything)
+}
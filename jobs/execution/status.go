@@ -0,0 +1,88 @@
+package execution
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gojektech/proctor-engine/logger"
+	"github.com/gojektech/proctor-engine/utility"
+
+	"github.com/gorilla/mux"
+)
+
+func (executioner *executioner) HandleStatus() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		execution, ok := executioner.execution(w, req)
+		if !ok {
+			return
+		}
+
+		writeExecution(w, execution)
+	}
+}
+
+func (executioner *executioner) HandleCancellation() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		execution, ok := executioner.execution(w, req)
+		if !ok {
+			return
+		}
+		log := logger.FromContext(req.Context()).WithField(logger.ExecutionNameField, execution.Name)
+
+		if execution.HasFinished() {
+			utility.WriteError(w, req, http.StatusConflict, utility.ErrCodeExecutionFinished, fmt.Sprintf("execution %s has already finished", execution.Name))
+			return
+		}
+
+		// CANCELLED is recorded before the job is deleted so its tracker, woken
+		// by the deletion, finds the cancellation instead of failing the run.
+		cancelled := *execution
+		finishedAt := time.Now()
+		cancelled.Status = StatusCancelled
+		cancelled.FinishedAt = &finishedAt
+		if err := executioner.store.SaveExecution(cancelled); err != nil {
+			log.Error("Error saving cancelled execution", err.Error())
+
+			utility.WriteError(w, req, http.StatusServiceUnavailable, utility.ErrCodeStoreUnavailable, "unable to save cancelled execution")
+			return
+		}
+
+		if err := executioner.kubeClient.DeleteJob(execution.Target(), execution.Name); err != nil {
+			log.Error("Error deleting job for cancelled execution", err.Error())
+
+			if err := executioner.store.SaveExecution(*execution); err != nil {
+				log.Error("Error restoring execution after failed cancellation", err.Error())
+			}
+			utility.WriteError(w, req, http.StatusInternalServerError, utility.ErrCodeExecutionFailed, "unable to delete job on kubernetes")
+			return
+		}
+
+		execution = &cancelled
+		writeExecution(w, execution)
+	}
+}
+
+func (executioner *executioner) execution(w http.ResponseWriter, req *http.Request) (*Execution, bool) {
+	executedJobName := mux.Vars(req)["name"]
+
+	execution, err := executioner.store.GetExecution(executedJobName)
+	if err == ErrExecutionNotFound {
+		utility.WriteError(w, req, http.StatusNotFound, utility.ErrCodeExecutionNotFound, fmt.Sprintf("execution %s not found", executedJobName))
+		return nil, false
+	}
+	if err != nil {
+		logger.FromContext(req.Context()).WithField(logger.ExecutionNameField, executedJobName).Error("Error fetching execution", err.Error())
+
+		utility.WriteError(w, req, http.StatusServiceUnavailable, utility.ErrCodeStoreUnavailable, "unable to fetch execution")
+		return nil, false
+	}
+	return execution, true
+}
+
+func writeExecution(w http.ResponseWriter, execution *Execution) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(execution)
+}
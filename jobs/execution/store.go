@@ -0,0 +1,195 @@
+package execution
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/gojektech/proctor-engine/config"
+	"github.com/gojektech/proctor-engine/redis"
+)
+
+const (
+	ExecutionKeySuffix    = "-execution"
+	TrackingKeySuffix     = "-execution-tracking"
+	TrackerLeaseKeySuffix = "-execution-tracker-lease"
+	TrackedExecutionsKey  = "proctor-tracked-executions"
+)
+
+// A tracker lease is held by the tracker following an execution; renewing
+// and releasing only succeed for the owner so a tracker never extends a lease
+// taken over by another replica.
+const renewTrackerLeaseScript = `
+if redis.call('GET', KEYS[1]) ~= ARGV[1] then
+  return 0
+end
+redis.call('EXPIRE', KEYS[1], ARGV[2])
+return 1
+`
+
+const releaseTrackerLeaseScript = `
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+  redis.call('DEL', KEYS[1])
+end
+return 1
+`
+
+var ErrExecutionNotFound = errors.New("execution not found")
+
+type Store interface {
+	SaveExecution(Execution) error
+	GetExecution(string) (*Execution, error)
+	TrackExecution(Execution) error
+	UntrackExecution(string) error
+	TrackedExecutions() ([]Execution, error)
+	AcquireTrackerLease(string, string, time.Duration) (bool, error)
+	RenewTrackerLease(string, string, time.Duration) (bool, error)
+	ReleaseTrackerLease(string, string) error
+}
+
+// tracking keeps the parts of an execution that are not part of its public
+// record but are needed to finish it after a restart. The job spec is left
+// out since it carries secrets, so resumed executions are never resubmitted.
+type tracking struct {
+	CallbackURL string   `json:"callback_url,omitempty"`
+	Webhooks    []string `json:"webhooks,omitempty"`
+	SlotID      string   `json:"slot_id,omitempty"`
+}
+
+type store struct {
+	redisClient redis.Client
+}
+
+func NewStore(redisClient redis.Client) Store {
+	return &store{
+		redisClient: redisClient,
+	}
+}
+
+func executionKey(executedJobName string) string {
+	return executedJobName + ExecutionKeySuffix
+}
+
+func trackingKey(executedJobName string) string {
+	return executedJobName + TrackingKeySuffix
+}
+
+func trackerLeaseKey(executedJobName string) string {
+	return executedJobName + TrackerLeaseKeySuffix
+}
+
+func leaseSeconds(lease time.Duration) int {
+	if seconds := int(lease.Seconds()); seconds > 0 {
+		return seconds
+	}
+	return 1
+}
+
+func (store *store) SaveExecution(execution Execution) error {
+	binaryExecution, err := json.Marshal(execution)
+	if err != nil {
+		return err
+	}
+
+	expiryInSeconds := int(config.ExecutionRecordExpiry().Seconds())
+	if expiryInSeconds <= 0 {
+		return store.redisClient.SET(executionKey(execution.Name), binaryExecution)
+	}
+	return store.redisClient.SETEX(executionKey(execution.Name), expiryInSeconds, binaryExecution)
+}
+
+func (store *store) GetExecution(executedJobName string) (*Execution, error) {
+	binaryExecution, err := store.redisClient.GET(executionKey(executedJobName))
+	if err == redis.ErrNil {
+		return nil, ErrExecutionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var execution Execution
+	err = json.Unmarshal(binaryExecution, &execution)
+	if err != nil {
+		return nil, err
+	}
+	return &execution, nil
+}
+
+func (store *store) TrackExecution(execution Execution) error {
+	binaryTracking, err := json.Marshal(tracking{
+		CallbackURL: execution.CallbackURL,
+		Webhooks:    execution.Webhooks,
+		SlotID:      execution.SlotID,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := store.redisClient.SET(trackingKey(execution.Name), binaryTracking); err != nil {
+		return err
+	}
+	return store.redisClient.SADD(TrackedExecutionsKey, execution.Name)
+}
+
+func (store *store) UntrackExecution(executedJobName string) error {
+	if err := store.redisClient.SREM(TrackedExecutionsKey, executedJobName); err != nil {
+		return err
+	}
+	return store.redisClient.DEL(trackingKey(executedJobName))
+}
+
+// TrackedExecutions drops executions whose record has expired, since there is
+// nothing left to finish for them.
+func (store *store) TrackedExecutions() ([]Execution, error) {
+	names, err := store.redisClient.SMEMBERS(TrackedExecutionsKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var executions []Execution
+	for _, name := range names {
+		execution, err := store.GetExecution(name)
+		if err == ErrExecutionNotFound {
+			store.UntrackExecution(name)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		binaryTracking, err := store.redisClient.GET(trackingKey(name))
+		if err != nil && err != redis.ErrNil {
+			return nil, err
+		}
+		if err == nil {
+			var tracked tracking
+			if err := json.Unmarshal(binaryTracking, &tracked); err != nil {
+				return nil, err
+			}
+			execution.CallbackURL = tracked.CallbackURL
+			execution.Webhooks = tracked.Webhooks
+			execution.SlotID = tracked.SlotID
+		}
+
+		executions = append(executions, *execution)
+	}
+	return executions, nil
+}
+
+func (store *store) AcquireTrackerLease(executedJobName string, owner string, lease time.Duration) (bool, error) {
+	return store.redisClient.SETNX(trackerLeaseKey(executedJobName), leaseSeconds(lease), []byte(owner))
+}
+
+func (store *store) RenewTrackerLease(executedJobName string, owner string, lease time.Duration) (bool, error) {
+	reply, err := store.redisClient.EVAL(renewTrackerLeaseScript, 1, trackerLeaseKey(executedJobName), owner, leaseSeconds(lease))
+	if err != nil {
+		return false, err
+	}
+	renewed, _ := reply.(int64)
+	return renewed == 1, nil
+}
+
+func (store *store) ReleaseTrackerLease(executedJobName string, owner string) error {
+	_, err := store.redisClient.EVAL(releaseTrackerLeaseScript, 1, trackerLeaseKey(executedJobName), owner)
+	return err
+}
@@ -0,0 +1,51 @@
+package execution
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockStore struct {
+	mock.Mock
+}
+
+func (m *MockStore) SaveExecution(execution Execution) error {
+	args := m.Called(execution)
+	return args.Error(0)
+}
+
+func (m *MockStore) GetExecution(executedJobName string) (*Execution, error) {
+	args := m.Called(executedJobName)
+	return args.Get(0).(*Execution), args.Error(1)
+}
+
+func (m *MockStore) TrackExecution(execution Execution) error {
+	args := m.Called(execution)
+	return args.Error(0)
+}
+
+func (m *MockStore) UntrackExecution(executedJobName string) error {
+	args := m.Called(executedJobName)
+	return args.Error(0)
+}
+
+func (m *MockStore) TrackedExecutions() ([]Execution, error) {
+	args := m.Called()
+	return args.Get(0).([]Execution), args.Error(1)
+}
+
+func (m *MockStore) AcquireTrackerLease(executedJobName string, owner string, lease time.Duration) (bool, error) {
+	args := m.Called(executedJobName, owner, lease)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockStore) RenewTrackerLease(executedJobName string, owner string, lease time.Duration) (bool, error) {
+	args := m.Called(executedJobName, owner, lease)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockStore) ReleaseTrackerLease(executedJobName string, owner string) error {
+	args := m.Called(executedJobName, owner)
+	return args.Error(0)
+}
@@ -0,0 +1,148 @@
+package execution
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gojektech/proctor-engine/redis"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ExecutionStoreTestSuite struct {
+	suite.Suite
+	mockRedisClient    *redis.MockClient
+	testExecutionStore Store
+}
+
+func (s *ExecutionStoreTestSuite) SetupTest() {
+	s.mockRedisClient = &redis.MockClient{}
+
+	s.testExecutionStore = NewStore(s.mockRedisClient)
+}
+
+func (s *ExecutionStoreTestSuite) TestSaveExecution() {
+	t := s.T()
+
+	os.Setenv("PROCTOR_EXECUTION_RECORD_EXPIRY_SECONDS", "86400")
+	viper.AutomaticEnv()
+	defer os.Unsetenv("PROCTOR_EXECUTION_RECORD_EXPIRY_SECONDS")
+
+	execution := Execution{Name: "proctor-ipsum-lorem", JobName: "job1", Status: StatusRunning}
+	binaryExecution, err := json.Marshal(execution)
+	assert.NoError(t, err)
+
+	s.mockRedisClient.On("SETEX", "proctor-ipsum-lorem-execution", 86400, binaryExecution).Return(nil).Once()
+
+	err = s.testExecutionStore.SaveExecution(execution)
+	assert.NoError(t, err)
+
+	s.mockRedisClient.AssertExpectations(t)
+}
+
+func (s *ExecutionStoreTestSuite) TestGetExecution() {
+	t := s.T()
+
+	execution := Execution{Name: "proctor-ipsum-lorem", JobName: "job1", Status: StatusSucceeded, Output: `{"rows_updated":1234}`}
+	binaryExecution, err := json.Marshal(execution)
+	assert.NoError(t, err)
+
+	s.mockRedisClient.On("GET", "proctor-ipsum-lorem-execution").Return(binaryExecution, nil).Once()
+
+	fetchedExecution, err := s.testExecutionStore.GetExecution("proctor-ipsum-lorem")
+	assert.NoError(t, err)
+	assert.Equal(t, execution, *fetchedExecution)
+}
+
+func (s *ExecutionStoreTestSuite) TestGetExecutionNotFound() {
+	t := s.T()
+
+	s.mockRedisClient.On("GET", "proctor-ipsum-lorem-execution").Return([]byte{}, redis.ErrNil).Once()
+
+	_, err := s.testExecutionStore.GetExecution("proctor-ipsum-lorem")
+	assert.Equal(t, ErrExecutionNotFound, err)
+}
+
+func (s *ExecutionStoreTestSuite) TestGetExecutionRedisFailure() {
+	t := s.T()
+
+	s.mockRedisClient.On("GET", "proctor-ipsum-lorem-execution").Return([]byte{}, errors.New("error")).Once()
+
+	_, err := s.testExecutionStore.GetExecution("proctor-ipsum-lorem")
+	assert.EqualError(t, err, "error")
+}
+
+func (s *ExecutionStoreTestSuite) TestTrackExecution() {
+	t := s.T()
+
+	s.mockRedisClient.On("SET", "proctor-ipsum-lorem-execution-tracking", []byte(`{"callback_url":"https://example.com/callback","slot_id":"slot-id"}`)).Return(nil).Once()
+	s.mockRedisClient.On("SADD", TrackedExecutionsKey, "proctor-ipsum-lorem").Return(nil).Once()
+
+	err := s.testExecutionStore.TrackExecution(Execution{Name: "proctor-ipsum-lorem", CallbackURL: "https://example.com/callback", SlotID: "slot-id"})
+	assert.NoError(t, err)
+
+	s.mockRedisClient.AssertExpectations(t)
+}
+
+func (s *ExecutionStoreTestSuite) TestUntrackExecution() {
+	t := s.T()
+
+	s.mockRedisClient.On("SREM", TrackedExecutionsKey, "proctor-ipsum-lorem").Return(nil).Once()
+	s.mockRedisClient.On("DEL", "proctor-ipsum-lorem-execution-tracking").Return(nil).Once()
+
+	err := s.testExecutionStore.UntrackExecution("proctor-ipsum-lorem")
+	assert.NoError(t, err)
+
+	s.mockRedisClient.AssertExpectations(t)
+}
+
+func (s *ExecutionStoreTestSuite) TestTrackedExecutions() {
+	t := s.T()
+
+	execution := Execution{Name: "proctor-ipsum-lorem", JobName: "job1", Status: StatusRunning}
+	binaryExecution, err := json.Marshal(execution)
+	assert.NoError(t, err)
+
+	s.mockRedisClient.On("SMEMBERS", TrackedExecutionsKey).Return([]string{"proctor-ipsum-lorem", "proctor-expired"}, nil).Once()
+	s.mockRedisClient.On("GET", "proctor-ipsum-lorem-execution").Return(binaryExecution, nil).Once()
+	s.mockRedisClient.On("GET", "proctor-ipsum-lorem-execution-tracking").Return([]byte(`{"webhooks":["https://example.com/hook"],"slot_id":"slot-id"}`), nil).Once()
+	s.mockRedisClient.On("GET", "proctor-expired-execution").Return([]byte{}, redis.ErrNil).Once()
+	s.mockRedisClient.On("SREM", TrackedExecutionsKey, "proctor-expired").Return(nil).Once()
+	s.mockRedisClient.On("DEL", "proctor-expired-execution-tracking").Return(nil).Once()
+
+	executions, err := s.testExecutionStore.TrackedExecutions()
+	assert.NoError(t, err)
+
+	execution.Webhooks = []string{"https://example.com/hook"}
+	execution.SlotID = "slot-id"
+	assert.Equal(t, []Execution{execution}, executions)
+	s.mockRedisClient.AssertExpectations(t)
+}
+
+func (s *ExecutionStoreTestSuite) TestAcquireTrackerLease() {
+	t := s.T()
+
+	s.mockRedisClient.On("SETNX", "proctor-ipsum-lorem-execution-tracker-lease", 300, []byte("owner")).Return(true, nil).Once()
+
+	acquired, err := s.testExecutionStore.AcquireTrackerLease("proctor-ipsum-lorem", "owner", 5*time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+}
+
+func (s *ExecutionStoreTestSuite) TestRenewTrackerLeaseTakenOver() {
+	t := s.T()
+
+	s.mockRedisClient.On("EVAL", renewTrackerLeaseScript, 1, "proctor-ipsum-lorem-execution-tracker-lease", "owner", 300).Return(int64(0), nil).Once()
+
+	renewed, err := s.testExecutionStore.RenewTrackerLease("proctor-ipsum-lorem", "owner", 5*time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, renewed)
+}
+
+func TestExecutionStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(ExecutionStoreTestSuite))
+}
@@ -0,0 +1,405 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gojektech/proctor-engine/config"
+	"github.com/gojektech/proctor-engine/jobs/queue"
+	"github.com/gojektech/proctor-engine/jobs/webhook"
+	"github.com/gojektech/proctor-engine/kubernetes"
+	"github.com/gojektech/proctor-engine/logger"
+	"github.com/gojektech/proctor-engine/metrics"
+	"github.com/gojektech/proctor-engine/utility"
+)
+
+type tracker struct {
+	kubeClient kubernetes.Client
+	store      Store
+	queueStore queue.Store
+	notifier   webhook.Notifier
+	finished   chan struct{}
+	owner      string
+}
+
+type Tracker interface {
+	Track(ctx context.Context, execution Execution)
+	Finished() <-chan struct{}
+	StartResumer(ctx context.Context)
+}
+
+func NewTracker(kubeClient kubernetes.Client, store Store, queueStore queue.Store, notifier webhook.Notifier) Tracker {
+	owner, _ := utility.RandomID()
+
+	return &tracker{
+		kubeClient: kubeClient,
+		store:      store,
+		queueStore: queueStore,
+		notifier:   notifier,
+		finished:   make(chan struct{}, 1),
+		owner:      owner,
+	}
+}
+
+func (tracker *tracker) Finished() <-chan struct{} {
+	return tracker.finished
+}
+
+func trackingContext(ctx context.Context, execution Execution) context.Context {
+	return logger.WithFields(logger.Detach(ctx), logger.Fields{
+		logger.JobNameField:       execution.JobName,
+		logger.ExecutionNameField: execution.Name,
+	})
+}
+
+func (tracker *tracker) Track(ctx context.Context, execution Execution) {
+	ctx = trackingContext(ctx, execution)
+	log := logger.FromContext(ctx)
+
+	execution.Status = StatusRunning
+	if err := tracker.store.SaveExecution(execution); err != nil {
+		log.Error("Error saving execution", err.Error())
+	}
+	if err := tracker.store.TrackExecution(execution); err != nil {
+		log.Error("Error recording tracked execution", err.Error())
+	}
+	if _, err := tracker.store.AcquireTrackerLease(execution.Name, tracker.owner, queue.SlotLease()); err != nil {
+		log.Error("Error acquiring lease of execution", err.Error())
+	}
+
+	go tracker.follow(ctx, execution)
+}
+
+// StartResumer periodically takes over executions whose tracker lease lapsed,
+// such as executions left RUNNING by a replica that stopped.
+func (tracker *tracker) StartResumer(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(queue.SlotLease())
+		defer ticker.Stop()
+
+		for {
+			tracker.resume(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (tracker *tracker) resume(ctx context.Context) {
+	executions, err := tracker.store.TrackedExecutions()
+	if err != nil {
+		logger.FromContext(ctx).Error("Error listing tracked executions", err.Error())
+		return
+	}
+
+	for _, execution := range executions {
+		executionCtx := trackingContext(ctx, execution)
+		log := logger.FromContext(executionCtx)
+
+		acquired, err := tracker.store.AcquireTrackerLease(execution.Name, tracker.owner, queue.SlotLease())
+		if err != nil {
+			log.Error("Error acquiring lease of execution", err.Error())
+			continue
+		}
+		if !acquired {
+			continue
+		}
+
+		if execution.HasFinished() {
+			tracker.settle(executionCtx, execution)
+			continue
+		}
+
+		log.Info("Resuming tracking of execution")
+		go tracker.follow(executionCtx, execution)
+	}
+}
+
+// settle finishes off an execution that was still tracked after it finished,
+// because its tracker stopped in between. Resumed executions have no job spec,
+// so a pending retry is abandoned rather than resubmitted.
+func (tracker *tracker) settle(ctx context.Context, execution Execution) {
+	switch {
+	case execution.RetryPending:
+		logger.FromContext(ctx).Info("Abandoning retry of resumed execution")
+		tracker.abandonRetry(ctx, execution)
+		tracker.notify(ctx, execution)
+	case execution.Status == StatusCancelled:
+		tracker.release(ctx, execution)
+		tracker.notify(ctx, execution)
+	default:
+		tracker.release(ctx, execution)
+	}
+
+	tracker.untrack(ctx, execution.Name)
+}
+
+// follow holds the tracker lease while waiting for the execution. When the
+// wait is interrupted the execution stays tracked, so the resumer takes it
+// over once the lease is released or lapses.
+func (tracker *tracker) follow(ctx context.Context, execution Execution) {
+	waitCtx, cancel := context.WithCancel(ctx)
+	go tracker.holdLease(waitCtx, cancel, execution.Name)
+
+	finished := tracker.waitForCompletion(waitCtx, execution)
+	cancel()
+
+	if finished {
+		tracker.untrack(ctx, execution.Name)
+		return
+	}
+	if err := tracker.store.ReleaseTrackerLease(execution.Name, tracker.owner); err != nil {
+		logger.FromContext(ctx).Error("Error releasing lease of execution", err.Error())
+	}
+}
+
+func (tracker *tracker) holdLease(ctx context.Context, lost context.CancelFunc, executedJobName string) {
+	log := logger.FromContext(ctx)
+
+	ticker := time.NewTicker(queue.SlotLease() / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		renewed, err := tracker.store.RenewTrackerLease(executedJobName, tracker.owner, queue.SlotLease())
+		if err != nil {
+			log.Error("Error renewing lease of execution", err.Error())
+		} else if !renewed {
+			log.Info("Execution taken over by another tracker")
+			lost()
+			return
+		}
+	}
+}
+
+func (tracker *tracker) untrack(ctx context.Context, executedJobName string) {
+	log := logger.FromContext(ctx)
+
+	if err := tracker.store.UntrackExecution(executedJobName); err != nil {
+		log.Error("Error removing tracked execution", err.Error())
+	}
+	if err := tracker.store.ReleaseTrackerLease(executedJobName, tracker.owner); err != nil {
+		log.Error("Error releasing lease of execution", err.Error())
+	}
+}
+
+// waitForCompletion reports whether the execution reached an outcome. Other
+// errors, such as losing the watch, leave it RUNNING for the resumer.
+func (tracker *tracker) waitForCompletion(ctx context.Context, execution Execution) bool {
+	log := logger.FromContext(ctx)
+
+	jobStatus, err := tracker.kubeClient.WaitForJobCompletion(ctx, execution.Target(), execution.Name)
+	if err == kubernetes.ErrJobNotFound {
+		tracker.deleted(ctx, execution)
+		return true
+	}
+	if err == kubernetes.ErrJobWaitTimedOut {
+		tracker.release(ctx, execution)
+		tracker.fail(ctx, execution, "Job did not complete before the wait deadline")
+		return true
+	}
+	if err != nil {
+		log.Error("Error tracking completion of job", err.Error())
+		return false
+	}
+
+	log.Info("Job completed, succeeded: ", jobStatus.Succeeded)
+
+	execution.Status = StatusFailed
+	if jobStatus.Succeeded {
+		execution.Status = StatusSucceeded
+		metrics.ExecutionSucceeded(execution.JobName)
+	} else {
+		metrics.ExecutionFailed(execution.JobName)
+	}
+
+	finish(&execution, jobStatus)
+	execution.RetryPending = !jobStatus.Succeeded && execution.RetryPolicy.ShouldResubmit(execution.Attempt)
+	if err := tracker.store.SaveExecution(execution); err != nil {
+		log.Error("Error saving finished execution", err.Error())
+	}
+
+	if execution.RetryPending {
+		tracker.resubmit(ctx, execution)
+		return true
+	}
+
+	if jobStatus.Succeeded {
+		tracker.recordDuration(ctx, execution, jobStatus)
+	}
+	tracker.release(ctx, execution)
+	tracker.notify(ctx, execution)
+	return true
+}
+
+// deleted never retries: a job is only removed from under its tracker by
+// HandleCancellation, which records CANCELLED before deleting it, or by hand.
+func (tracker *tracker) deleted(ctx context.Context, execution Execution) {
+	log := logger.FromContext(ctx)
+	tracker.release(ctx, execution)
+
+	stored, err := tracker.store.GetExecution(execution.Name)
+	if err != nil {
+		log.Error("Error fetching execution of deleted job", err.Error())
+		return
+	}
+	if stored.Status == StatusCancelled && stored.HasFinished() {
+		log.Info("Job deleted after cancellation")
+		execution.Status = stored.Status
+		execution.FinishedAt = stored.FinishedAt
+		tracker.notify(ctx, execution)
+		return
+	}
+
+	tracker.fail(ctx, execution, "Job deleted before completion")
+}
+
+func (tracker *tracker) fail(ctx context.Context, execution Execution, reason string) {
+	log := logger.FromContext(ctx)
+	log.Error(reason)
+
+	finishedAt := time.Now()
+	execution.Status = StatusFailed
+	execution.FinishedAt = &finishedAt
+	metrics.ExecutionFailed(execution.JobName)
+	if err := tracker.store.SaveExecution(execution); err != nil {
+		log.Error("Error saving failed execution", err.Error())
+	}
+	tracker.notify(ctx, execution)
+}
+
+func (tracker *tracker) resubmit(ctx context.Context, execution Execution) {
+	log := logger.FromContext(ctx)
+
+	nextAttempt := execution.Attempt + 1
+	delay := execution.RetryPolicy.DelayBeforeAttempt(nextAttempt)
+	log.Info("Resubmitting failed job as attempt ", nextAttempt, " after ", delay)
+
+	if execution.SlotID != "" {
+		if err := tracker.queueStore.Renew(execution.JobName, execution.SlotID); err != nil {
+			log.Error("Error renewing concurrency slot before resubmission", err.Error())
+		}
+	}
+
+	select {
+	case <-ctx.Done():
+		tracker.abandonRetry(ctx, execution)
+		return
+	case <-time.After(delay):
+	}
+
+	executedJobName, err := tracker.kubeClient.ExecuteJob(ctx, execution.Spec)
+	if err != nil {
+		log.Error("Error resubmitting job", err.Error())
+		metrics.ExecutionFailed(execution.JobName)
+		tracker.abandonRetry(ctx, execution)
+		tracker.notify(ctx, execution)
+		return
+	}
+	metrics.ExecutionStarted(execution.JobName)
+
+	execution.RetryPending = false
+	execution.NextAttempt = executedJobName
+	if err := tracker.store.SaveExecution(execution); err != nil {
+		log.Error("Error linking execution to its next attempt", err.Error())
+	}
+
+	tracker.Track(ctx, Execution{
+		Name:            executedJobName,
+		JobName:         execution.JobName,
+		Cluster:         execution.Cluster,
+		Namespace:       execution.Namespace,
+		Attempt:         nextAttempt,
+		PreviousAttempt: execution.Name,
+		StartedAt:       time.Now(),
+		CallbackURL:     execution.CallbackURL,
+		Webhooks:        execution.Webhooks,
+		Spec:            execution.Spec,
+		RetryPolicy:     execution.RetryPolicy,
+		SlotID:          execution.SlotID,
+	})
+}
+
+func (tracker *tracker) abandonRetry(ctx context.Context, execution Execution) {
+	execution.RetryPending = false
+	if err := tracker.store.SaveExecution(execution); err != nil {
+		logger.FromContext(ctx).Error("Error saving execution after abandoning retry", err.Error())
+	}
+	tracker.release(ctx, execution)
+}
+
+func (tracker *tracker) recordDuration(ctx context.Context, execution Execution, jobStatus *kubernetes.JobStatus) {
+	duration := jobStatus.Duration()
+	if duration <= 0 {
+		return
+	}
+
+	if err := tracker.queueStore.RecordDuration(execution.JobName, duration); err != nil {
+		logger.FromContext(ctx).Error("Error recording duration of job", err.Error())
+	}
+}
+
+func (tracker *tracker) release(ctx context.Context, execution Execution) {
+	if execution.SlotID != "" {
+		if err := tracker.queueStore.Release(execution.JobName, execution.SlotID); err != nil {
+			logger.FromContext(ctx).Error("Error releasing concurrency slot", err.Error())
+		}
+	}
+
+	select {
+	case tracker.finished <- struct{}{}:
+	default:
+	}
+}
+
+func (tracker *tracker) notify(ctx context.Context, execution Execution) {
+	urls := execution.WebhookURLs()
+	if len(urls) == 0 {
+		return
+	}
+
+	tracker.notifier.Notify(ctx, urls, completionPayload(execution))
+}
+
+func finish(execution *Execution, jobStatus *kubernetes.JobStatus) {
+	if !jobStatus.StartTime.IsZero() {
+		execution.StartedAt = jobStatus.StartTime
+	}
+	finishedAt := jobStatus.CompletionTime
+	if finishedAt.IsZero() {
+		finishedAt = time.Now()
+	}
+
+	execution.FinishedAt = &finishedAt
+	execution.ExitCode = jobStatus.ExitCode
+	execution.Output = jobStatus.Output
+}
+
+func completionPayload(execution Execution) webhook.Payload {
+	return webhook.Payload{
+		ExecutionName:   execution.Name,
+		JobName:         execution.JobName,
+		Status:          execution.Status,
+		ExitCode:        execution.ExitCode,
+		StartedAt:       execution.StartedAt,
+		FinishedAt:      *execution.FinishedAt,
+		DurationSeconds: execution.FinishedAt.Sub(execution.StartedAt).Seconds(),
+		LogsURL:         logsURL(execution.Name),
+	}
+}
+
+func logsURL(executedJobName string) string {
+	return fmt.Sprintf("%s/api/v1/executions/%s/logs", strings.TrimSuffix(config.PublicURL(), "/"), url.PathEscape(executedJobName))
+}
@@ -0,0 +1,24 @@
+package execution
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTracker struct {
+	mock.Mock
+}
+
+func (m *MockTracker) Track(ctx context.Context, execution Execution) {
+	m.Called(ctx, execution)
+}
+
+func (m *MockTracker) Finished() <-chan struct{} {
+	args := m.Called()
+	return args.Get(0).(chan struct{})
+}
+
+func (m *MockTracker) StartResumer(ctx context.Context) {
+	m.Called(ctx)
+}
@@ -0,0 +1,416 @@
+package execution
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gojektech/proctor-engine/jobs/metadata"
+	"github.com/gojektech/proctor-engine/jobs/queue"
+	"github.com/gojektech/proctor-engine/jobs/webhook"
+	"github.com/gojektech/proctor-engine/kubernetes"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type TrackerTestSuite struct {
+	suite.Suite
+	mockKubeClient *kubernetes.MockClient
+	mockStore      *MockStore
+	mockQueueStore *queue.MockStore
+	mockNotifier   *webhook.MockNotifier
+	testTracker    *tracker
+}
+
+func (s *TrackerTestSuite) SetupTest() {
+	s.mockKubeClient = &kubernetes.MockClient{}
+	s.mockStore = &MockStore{}
+	s.mockQueueStore = &queue.MockStore{}
+	s.mockNotifier = &webhook.MockNotifier{}
+	s.testTracker = &tracker{
+		kubeClient: s.mockKubeClient,
+		store:      s.mockStore,
+		queueStore: s.mockQueueStore,
+		notifier:   s.mockNotifier,
+		finished:   make(chan struct{}, 1),
+		owner:      "tracker-owner",
+	}
+}
+
+func (s *TrackerTestSuite) expectTracking() {
+	s.mockStore.On("TrackExecution", mock.Anything).Return(nil)
+	s.mockStore.On("AcquireTrackerLease", mock.Anything, s.testTracker.owner, mock.Anything).Return(true, nil)
+	s.mockStore.On("ReleaseTrackerLease", mock.Anything, s.testTracker.owner).Return(nil)
+}
+
+func (s *TrackerTestSuite) TestTrackSavesRunningExecution() {
+	t := s.T()
+
+	waitCalled := make(chan struct{})
+	s.mockStore.On("SaveExecution", Execution{Name: "proctor-ipsum-lorem", JobName: "sample-job-name", Status: StatusRunning}).Return(nil).Once()
+	s.mockStore.On("TrackExecution", Execution{Name: "proctor-ipsum-lorem", JobName: "sample-job-name", Status: StatusRunning}).Return(nil).Once()
+	s.mockStore.On("AcquireTrackerLease", "proctor-ipsum-lorem", s.testTracker.owner, mock.Anything).Return(true, nil).Once()
+	s.mockStore.On("ReleaseTrackerLease", "proctor-ipsum-lorem", s.testTracker.owner).Return(nil)
+	s.mockKubeClient.On("WaitForJobCompletion", mock.Anything, kubernetes.Target{}, "proctor-ipsum-lorem").Return(&kubernetes.JobStatus{}, errors.New("error")).Run(func(mock.Arguments) {
+		close(waitCalled)
+	}).Once()
+
+	s.testTracker.Track(context.Background(), Execution{Name: "proctor-ipsum-lorem", JobName: "sample-job-name"})
+	<-waitCalled
+
+	s.mockStore.AssertExpectations(t)
+}
+
+func (s *TrackerTestSuite) TestWaitForCompletion() {
+	t := s.T()
+
+	s.mockKubeClient.On("WaitForJobCompletion", mock.Anything, kubernetes.Target{}, "proctor-ipsum-lorem").Return(&kubernetes.JobStatus{Succeeded: true, Output: `{"rows_updated":1234}`}, nil).Once()
+	s.mockStore.On("SaveExecution", mock.MatchedBy(func(execution Execution) bool {
+		return execution.Status == StatusSucceeded && execution.HasFinished() && execution.Output == `{"rows_updated":1234}`
+	})).Return(nil).Once()
+
+	s.testTracker.waitForCompletion(context.Background(), Execution{Name: "proctor-ipsum-lorem", JobName: "sample-job-name"})
+
+	s.mockKubeClient.AssertExpectations(t)
+	s.mockStore.AssertExpectations(t)
+	s.mockNotifier.AssertNotCalled(t, "Notify", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *TrackerTestSuite) TestWaitForCompletionNotifiesWebhooks() {
+	t := s.T()
+
+	exitCode := int32(2)
+	startTime := time.Now()
+	jobStatus := &kubernetes.JobStatus{
+		Succeeded:      false,
+		ExitCode:       &exitCode,
+		StartTime:      startTime,
+		CompletionTime: startTime.Add(10 * time.Second),
+	}
+	s.mockKubeClient.On("WaitForJobCompletion", mock.Anything, kubernetes.Target{}, "proctor-ipsum-lorem").Return(jobStatus, nil).Once()
+	s.mockStore.On("SaveExecution", mock.Anything).Return(nil).Once()
+
+	expectedURLs := []string{"https://example.com/hook", "https://example.com/callback"}
+	s.mockNotifier.On("Notify", mock.Anything, expectedURLs, mock.MatchedBy(func(payload webhook.Payload) bool {
+		return payload.ExecutionName == "proctor-ipsum-lorem" &&
+			payload.JobName == "sample-job-name" &&
+			payload.Status == StatusFailed &&
+			*payload.ExitCode == 2 &&
+			payload.DurationSeconds == 10
+	})).Once()
+
+	s.testTracker.waitForCompletion(context.Background(), Execution{
+		Name:        "proctor-ipsum-lorem",
+		JobName:     "sample-job-name",
+		CallbackURL: "https://example.com/callback",
+		Webhooks:    []string{"https://example.com/hook"},
+	})
+
+	s.mockKubeClient.AssertExpectations(t)
+	s.mockNotifier.AssertExpectations(t)
+}
+
+func (s *TrackerTestSuite) TestWaitForCompletionKubeClientFailure() {
+	t := s.T()
+
+	target := kubernetes.Target{Cluster: "staging", Namespace: "batch"}
+	s.mockKubeClient.On("WaitForJobCompletion", mock.Anything, target, "proctor-ipsum-lorem").Return(&kubernetes.JobStatus{}, errors.New("error")).Once()
+
+	finished := s.testTracker.waitForCompletion(context.Background(), Execution{Name: "proctor-ipsum-lorem", JobName: "sample-job-name", Cluster: "staging", Namespace: "batch", CallbackURL: "https://example.com/callback", SlotID: "slot-id"})
+
+	assert.False(t, finished)
+	s.mockKubeClient.AssertExpectations(t)
+	s.mockStore.AssertNotCalled(t, "SaveExecution", mock.Anything)
+	s.mockQueueStore.AssertNotCalled(t, "Release", mock.Anything, mock.Anything)
+	s.mockNotifier.AssertNotCalled(t, "Notify", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *TrackerTestSuite) TestWaitForCompletionFailsExecutionPastWaitDeadline() {
+	t := s.T()
+
+	s.mockKubeClient.On("WaitForJobCompletion", mock.Anything, kubernetes.Target{}, "proctor-ipsum-lorem").Return(&kubernetes.JobStatus{}, kubernetes.ErrJobWaitTimedOut).Once()
+	s.mockQueueStore.On("Release", "sample-job-name", "slot-id").Return(nil).Once()
+	s.mockStore.On("SaveExecution", mock.MatchedBy(func(execution Execution) bool {
+		return execution.Status == StatusFailed && execution.HasFinished()
+	})).Return(nil).Once()
+	s.mockNotifier.On("Notify", mock.Anything, []string{"https://example.com/callback"}, mock.MatchedBy(func(payload webhook.Payload) bool {
+		return payload.Status == StatusFailed
+	})).Once()
+
+	finished := s.testTracker.waitForCompletion(context.Background(), Execution{Name: "proctor-ipsum-lorem", JobName: "sample-job-name", CallbackURL: "https://example.com/callback", SlotID: "slot-id"})
+
+	assert.True(t, finished)
+	s.mockQueueStore.AssertExpectations(t)
+	s.mockStore.AssertExpectations(t)
+	s.mockNotifier.AssertExpectations(t)
+}
+
+func (s *TrackerTestSuite) TestFollowLeavesInterruptedExecutionTracked() {
+	t := s.T()
+
+	s.mockKubeClient.On("WaitForJobCompletion", mock.Anything, kubernetes.Target{}, "proctor-ipsum-lorem").Return(&kubernetes.JobStatus{}, errors.New("error")).Once()
+	s.mockStore.On("ReleaseTrackerLease", "proctor-ipsum-lorem", s.testTracker.owner).Return(nil).Once()
+
+	s.testTracker.follow(context.Background(), Execution{Name: "proctor-ipsum-lorem", JobName: "sample-job-name"})
+
+	s.mockStore.AssertExpectations(t)
+	s.mockStore.AssertNotCalled(t, "UntrackExecution", mock.Anything)
+}
+
+func (s *TrackerTestSuite) TestFollowUntracksFinishedExecution() {
+	t := s.T()
+
+	s.mockKubeClient.On("WaitForJobCompletion", mock.Anything, kubernetes.Target{}, "proctor-ipsum-lorem").Return(&kubernetes.JobStatus{Succeeded: true}, nil).Once()
+	s.mockStore.On("SaveExecution", mock.Anything).Return(nil).Once()
+	s.mockStore.On("UntrackExecution", "proctor-ipsum-lorem").Return(nil).Once()
+	s.mockStore.On("ReleaseTrackerLease", "proctor-ipsum-lorem", s.testTracker.owner).Return(nil).Once()
+
+	s.testTracker.follow(context.Background(), Execution{Name: "proctor-ipsum-lorem", JobName: "sample-job-name"})
+
+	s.mockStore.AssertExpectations(t)
+}
+
+func (s *TrackerTestSuite) TestResumeFollowsExecutionsWithLapsedLease() {
+	t := s.T()
+
+	s.mockStore.On("TrackedExecutions").Return([]Execution{
+		{Name: "proctor-orphaned", JobName: "sample-job-name", Status: StatusRunning},
+		{Name: "proctor-followed", JobName: "sample-job-name", Status: StatusRunning},
+	}, nil).Once()
+	s.mockStore.On("AcquireTrackerLease", "proctor-orphaned", s.testTracker.owner, mock.Anything).Return(true, nil).Once()
+	s.mockStore.On("AcquireTrackerLease", "proctor-followed", s.testTracker.owner, mock.Anything).Return(false, nil).Once()
+
+	resumed := make(chan struct{})
+	s.mockKubeClient.On("WaitForJobCompletion", mock.Anything, kubernetes.Target{}, "proctor-orphaned").Return(&kubernetes.JobStatus{}, errors.New("error")).Once()
+	s.mockStore.On("ReleaseTrackerLease", "proctor-orphaned", s.testTracker.owner).Return(nil).Run(func(mock.Arguments) {
+		close(resumed)
+	}).Once()
+
+	s.testTracker.resume(context.Background())
+	<-resumed
+
+	s.mockStore.AssertExpectations(t)
+	s.mockKubeClient.AssertNotCalled(t, "WaitForJobCompletion", mock.Anything, mock.Anything, "proctor-followed")
+}
+
+func (s *TrackerTestSuite) TestResumeAbandonsPendingRetry() {
+	t := s.T()
+
+	finishedAt := time.Now()
+	s.mockStore.On("TrackedExecutions").Return([]Execution{
+		{Name: "proctor-first", JobName: "sample-job-name", Status: StatusFailed, FinishedAt: &finishedAt, RetryPending: true, CallbackURL: "https://example.com/callback", SlotID: "slot-id"},
+	}, nil).Once()
+	s.mockStore.On("AcquireTrackerLease", "proctor-first", s.testTracker.owner, mock.Anything).Return(true, nil).Once()
+	s.mockStore.On("SaveExecution", mock.MatchedBy(func(execution Execution) bool {
+		return execution.Name == "proctor-first" && !execution.RetryPending
+	})).Return(nil).Once()
+	s.mockQueueStore.On("Release", "sample-job-name", "slot-id").Return(nil).Once()
+	s.mockNotifier.On("Notify", mock.Anything, []string{"https://example.com/callback"}, mock.Anything).Once()
+	s.mockStore.On("UntrackExecution", "proctor-first").Return(nil).Once()
+	s.mockStore.On("ReleaseTrackerLease", "proctor-first", s.testTracker.owner).Return(nil).Once()
+
+	s.testTracker.resume(context.Background())
+
+	s.mockStore.AssertExpectations(t)
+	s.mockQueueStore.AssertExpectations(t)
+	s.mockNotifier.AssertExpectations(t)
+}
+
+func (s *TrackerTestSuite) TestWaitForCompletionKeepsCancelledExecution() {
+	t := s.T()
+
+	finishedAt := time.Now()
+	retryPolicy := &metadata.RetryPolicy{Strategy: metadata.RetryStrategyEngine, MaxAttempts: 2}
+	tracked := Execution{
+		Name:        "proctor-ipsum-lorem",
+		JobName:     "sample-job-name",
+		Attempt:     1,
+		RetryPolicy: retryPolicy,
+		SlotID:      "slot-id",
+		CallbackURL: "https://example.com/callback",
+		Webhooks:    []string{"https://example.com/hook"},
+	}
+
+	cancelled := tracked
+	cancelled.Status = StatusCancelled
+	cancelled.FinishedAt = &finishedAt
+	record, err := json.Marshal(cancelled)
+	assert.NoError(t, err)
+	var stored Execution
+	assert.NoError(t, json.Unmarshal(record, &stored))
+
+	s.mockKubeClient.On("WaitForJobCompletion", mock.Anything, kubernetes.Target{}, "proctor-ipsum-lorem").Return(&kubernetes.JobStatus{}, kubernetes.ErrJobNotFound).Once()
+	s.mockQueueStore.On("Release", "sample-job-name", "slot-id").Return(nil).Once()
+	s.mockStore.On("GetExecution", "proctor-ipsum-lorem").Return(&stored, nil).Once()
+	s.mockNotifier.On("Notify", mock.Anything, []string{"https://example.com/hook", "https://example.com/callback"}, mock.MatchedBy(func(payload webhook.Payload) bool {
+		return payload.Status == StatusCancelled
+	})).Once()
+
+	s.testTracker.waitForCompletion(context.Background(), tracked)
+
+	s.mockQueueStore.AssertExpectations(t)
+	s.mockNotifier.AssertExpectations(t)
+	s.mockStore.AssertNotCalled(t, "SaveExecution", mock.Anything)
+	s.mockKubeClient.AssertNotCalled(t, "ExecuteJob", mock.Anything, mock.Anything)
+	select {
+	case <-s.testTracker.Finished():
+	default:
+		t.Error("expected finished signal after cancelled job was deleted")
+	}
+}
+
+func (s *TrackerTestSuite) TestWaitForCompletionFailsExecutionOfJobDeletedWithoutCancellation() {
+	t := s.T()
+
+	s.mockKubeClient.On("WaitForJobCompletion", mock.Anything, kubernetes.Target{}, "proctor-ipsum-lorem").Return(&kubernetes.JobStatus{}, kubernetes.ErrJobNotFound).Once()
+	s.mockStore.On("GetExecution", "proctor-ipsum-lorem").Return(&Execution{Name: "proctor-ipsum-lorem", Status: StatusRunning}, nil).Once()
+	s.mockStore.On("SaveExecution", mock.MatchedBy(func(execution Execution) bool {
+		return execution.Status == StatusFailed && execution.HasFinished()
+	})).Return(nil).Once()
+	s.mockNotifier.On("Notify", mock.Anything, []string{"https://example.com/callback"}, mock.MatchedBy(func(payload webhook.Payload) bool {
+		return payload.Status == StatusFailed
+	})).Once()
+
+	s.testTracker.waitForCompletion(context.Background(), Execution{Name: "proctor-ipsum-lorem", JobName: "sample-job-name", CallbackURL: "https://example.com/callback"})
+
+	s.mockStore.AssertExpectations(t)
+	s.mockNotifier.AssertExpectations(t)
+}
+
+func (s *TrackerTestSuite) TestWaitForCompletionResubmitsFailedAttempt() {
+	t := s.T()
+
+	retryPolicy := &metadata.RetryPolicy{Strategy: metadata.RetryStrategyEngine, MaxAttempts: 2}
+	noRetries := int32(0)
+	spec := kubernetes.ExecutionSpec{ImageName: "img", EnvVars: map[string]string{"k1": "v1"}, BackoffLimit: &noRetries}
+	s.mockKubeClient.On("WaitForJobCompletion", mock.Anything, kubernetes.Target{}, "proctor-first").Return(&kubernetes.JobStatus{Succeeded: false}, nil).Once()
+	s.mockStore.On("SaveExecution", mock.MatchedBy(func(execution Execution) bool {
+		return execution.Name == "proctor-first" && execution.Status == StatusFailed && execution.NextAttempt == "" && execution.RetryPending
+	})).Return(nil).Once()
+
+	s.mockKubeClient.On("ExecuteJob", mock.Anything, spec).Return("proctor-second", nil).Once()
+	s.mockStore.On("SaveExecution", mock.MatchedBy(func(execution Execution) bool {
+		return execution.Name == "proctor-first" && execution.NextAttempt == "proctor-second" && !execution.RetryPending
+	})).Return(nil).Once()
+	s.mockStore.On("SaveExecution", mock.MatchedBy(func(execution Execution) bool {
+		return execution.Name == "proctor-second" && execution.Status == StatusRunning && execution.Attempt == 2 && execution.PreviousAttempt == "proctor-first"
+	})).Return(nil).Once()
+
+	s.expectTracking()
+
+	secondAttemptTracked := make(chan struct{})
+	s.mockKubeClient.On("WaitForJobCompletion", mock.Anything, kubernetes.Target{}, "proctor-second").Return(&kubernetes.JobStatus{}, errors.New("error")).Run(func(mock.Arguments) {
+		close(secondAttemptTracked)
+	}).Once()
+
+	s.testTracker.waitForCompletion(context.Background(), Execution{
+		Name:        "proctor-first",
+		JobName:     "sample-job-name",
+		Attempt:     1,
+		CallbackURL: "https://example.com/callback",
+		Spec:        spec,
+		RetryPolicy: retryPolicy,
+	})
+	<-secondAttemptTracked
+
+	s.mockKubeClient.AssertExpectations(t)
+	s.mockStore.AssertExpectations(t)
+	s.mockNotifier.AssertNotCalled(t, "Notify", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *TrackerTestSuite) TestWaitForCompletionNotifiesAfterLastAttempt() {
+	t := s.T()
+
+	retryPolicy := &metadata.RetryPolicy{Strategy: metadata.RetryStrategyEngine, MaxAttempts: 2}
+	s.mockKubeClient.On("WaitForJobCompletion", mock.Anything, kubernetes.Target{}, "proctor-second").Return(&kubernetes.JobStatus{Succeeded: false}, nil).Once()
+	s.mockStore.On("SaveExecution", mock.Anything).Return(nil).Once()
+	s.mockNotifier.On("Notify", mock.Anything, []string{"https://example.com/callback"}, mock.MatchedBy(func(payload webhook.Payload) bool {
+		return payload.ExecutionName == "proctor-second" && payload.Status == StatusFailed
+	})).Once()
+
+	s.testTracker.waitForCompletion(context.Background(), Execution{
+		Name:        "proctor-second",
+		JobName:     "sample-job-name",
+		Attempt:     2,
+		CallbackURL: "https://example.com/callback",
+		RetryPolicy: retryPolicy,
+	})
+
+	s.mockKubeClient.AssertNotCalled(t, "ExecuteJob", mock.Anything, mock.Anything)
+	s.mockNotifier.AssertExpectations(t)
+}
+
+func (s *TrackerTestSuite) TestWaitForCompletionReleasesConcurrencySlot() {
+	t := s.T()
+
+	startTime := time.Now()
+	jobStatus := &kubernetes.JobStatus{
+		Succeeded:      true,
+		StartTime:      startTime,
+		CompletionTime: startTime.Add(30 * time.Second),
+	}
+	s.mockKubeClient.On("WaitForJobCompletion", mock.Anything, kubernetes.Target{}, "proctor-ipsum-lorem").Return(jobStatus, nil).Once()
+	s.mockStore.On("SaveExecution", mock.Anything).Return(nil).Once()
+	s.mockQueueStore.On("RecordDuration", "sample-job-name", 30*time.Second).Return(nil).Once()
+	s.mockQueueStore.On("Release", "sample-job-name", "slot-id").Return(nil).Once()
+
+	s.testTracker.waitForCompletion(context.Background(), Execution{Name: "proctor-ipsum-lorem", JobName: "sample-job-name", SlotID: "slot-id"})
+
+	s.mockQueueStore.AssertExpectations(t)
+	select {
+	case <-s.testTracker.Finished():
+	default:
+		t.Error("expected finished signal after execution completed")
+	}
+}
+
+func (s *TrackerTestSuite) TestWaitForCompletionKeepsSlotWhenResubmitting() {
+	t := s.T()
+
+	retryPolicy := &metadata.RetryPolicy{Strategy: metadata.RetryStrategyEngine, MaxAttempts: 2}
+	s.mockKubeClient.On("WaitForJobCompletion", mock.Anything, kubernetes.Target{}, "proctor-first").Return(&kubernetes.JobStatus{Succeeded: false}, nil).Once()
+	s.mockStore.On("SaveExecution", mock.Anything).Return(nil)
+	s.mockQueueStore.On("Renew", "sample-job-name", "slot-id").Return(nil).Once()
+
+	spec := kubernetes.ExecutionSpec{ImageName: "img", Slot: kubernetes.Slot{JobName: "sample-job-name", ID: "slot-id"}}
+	s.mockKubeClient.On("ExecuteJob", mock.Anything, spec).Return("proctor-second", nil).Once()
+	s.expectTracking()
+	s.mockStore.On("UntrackExecution", "proctor-second").Return(nil)
+
+	secondAttemptTracked := make(chan struct{})
+	s.mockKubeClient.On("WaitForJobCompletion", mock.Anything, kubernetes.Target{}, "proctor-second").Return(&kubernetes.JobStatus{Succeeded: true}, nil).Run(func(mock.Arguments) {
+		close(secondAttemptTracked)
+	}).Once()
+	s.mockQueueStore.On("Release", "sample-job-name", "slot-id").Return(nil).Once()
+
+	s.testTracker.waitForCompletion(context.Background(), Execution{
+		Name:        "proctor-first",
+		JobName:     "sample-job-name",
+		Attempt:     1,
+		Spec:        spec,
+		RetryPolicy: retryPolicy,
+		SlotID:      "slot-id",
+	})
+	<-secondAttemptTracked
+	<-s.testTracker.Finished()
+
+	s.mockQueueStore.AssertExpectations(t)
+}
+
+func TestCompletionPayloadLogsURL(t *testing.T) {
+	os.Setenv("PROCTOR_PUBLIC_URL", "https://proctor.example.com/")
+	viper.AutomaticEnv()
+	defer os.Unsetenv("PROCTOR_PUBLIC_URL")
+
+	assert.Equal(t, "https://proctor.example.com/api/v1/executions/proctor-ipsum-lorem/logs", logsURL("proctor-ipsum-lorem"))
+}
+
+func TestTrackerTestSuite(t *testing.T) {
+	suite.Run(t, new(TrackerTestSuite))
+}
@@ -0,0 +1,227 @@
+package execution
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gojektech/proctor-engine/kubernetes"
+	"github.com/gojektech/proctor-engine/logger"
+	"github.com/gojektech/proctor-engine/utility"
+)
+
+const (
+	StatusRunning      = "RUNNING"
+	StatusSucceeded    = "SUCCEEDED"
+	StatusFailed       = "FAILED"
+	StatusCancelled    = "CANCELLED"
+	StatusWaitTimedOut = "WAIT_TIMED_OUT"
+	StatusUnknown      = "UNKNOWN"
+)
+
+const (
+	defaultWaitTimeout = 10 * time.Minute
+	defaultTailLines   = 50
+)
+
+// ExecutionStatusTrailerKey carries the final status once heartbeats have
+// committed the response status.
+const ExecutionStatusTrailerKey = "X-Execution-Status"
+
+var (
+	heartbeatInterval   = 15 * time.Second
+	attemptPollInterval = time.Second
+)
+
+type WaitOptions struct {
+	Timeout   time.Duration
+	TailLines int
+}
+
+type ExecutionResult struct {
+	Name            string   `json:"name"`
+	Status          string   `json:"status"`
+	ExitCode        *int32   `json:"exit_code,omitempty"`
+	DurationSeconds float64  `json:"duration_seconds"`
+	Logs            []string `json:"logs"`
+	Error           string   `json:"error,omitempty"`
+}
+
+type waitResult struct {
+	executedJobName string
+	jobStatus       *kubernetes.JobStatus
+	err             error
+}
+
+func parseWaitOptions(query url.Values) (*WaitOptions, []utility.FieldError) {
+	if query.Get("wait") != "true" {
+		return nil, nil
+	}
+
+	waitOptions := &WaitOptions{
+		Timeout:   defaultWaitTimeout,
+		TailLines: defaultTailLines,
+	}
+
+	var fieldErrors []utility.FieldError
+	if timeout := query.Get("timeout"); timeout != "" {
+		parsedTimeout, err := time.ParseDuration(timeout)
+		if err != nil || parsedTimeout <= 0 {
+			fieldErrors = append(fieldErrors, utility.FieldError{Field: "timeout", Message: "must be a positive duration such as 10m"})
+		}
+		waitOptions.Timeout = parsedTimeout
+	}
+	if tailLines := query.Get("tail_lines"); tailLines != "" {
+		parsedTailLines, err := strconv.Atoi(tailLines)
+		if err != nil || parsedTailLines < 0 {
+			fieldErrors = append(fieldErrors, utility.FieldError{Field: "tail_lines", Message: "must be a non-negative integer"})
+		}
+		waitOptions.TailLines = parsedTailLines
+	}
+	return waitOptions, fieldErrors
+}
+
+func flush(w http.ResponseWriter) {
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (executioner *executioner) waitForCompletion(ctx context.Context, w http.ResponseWriter, target kubernetes.Target, executedJobName string, waitOptions *WaitOptions) {
+	ctx = logger.WithFields(ctx, logger.Fields{logger.ExecutionNameField: executedJobName})
+
+	waitCtx, cancel := context.WithTimeout(ctx, waitOptions.Timeout)
+	defer cancel()
+
+	w.Header().Set("Content-Type", "application/json")
+
+	completion := make(chan waitResult, 1)
+	go func() {
+		completion <- executioner.waitForLastAttempt(waitCtx, target, executedJobName)
+	}()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	streaming := false
+	for {
+		select {
+		case <-heartbeat.C:
+			if !streaming {
+				w.Header().Set("Trailer", ExecutionStatusTrailerKey)
+				w.WriteHeader(http.StatusAccepted)
+				streaming = true
+			}
+			w.Write([]byte("\n"))
+			flush(w)
+		case result := <-completion:
+			if result.err == context.Canceled {
+				logger.FromContext(ctx).Info("Client disconnected while waiting for job completion")
+				return
+			}
+
+			executionResult := executioner.executionResult(ctx, target, result, waitOptions)
+			if streaming {
+				w.Header().Set(ExecutionStatusTrailerKey, executionResult.Status)
+			} else {
+				w.WriteHeader(resultStatusCode(executionResult.Status))
+			}
+			json.NewEncoder(w).Encode(executionResult)
+			return
+		}
+	}
+}
+
+func resultStatusCode(status string) int {
+	switch status {
+	case StatusSucceeded:
+		return http.StatusCreated
+	case StatusFailed, StatusCancelled:
+		return http.StatusFailedDependency
+	case StatusWaitTimedOut:
+		return http.StatusAccepted
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// waitForLastAttempt follows engine resubmissions so that the result reflects
+// the attempt that finished the execution.
+func (executioner *executioner) waitForLastAttempt(ctx context.Context, target kubernetes.Target, executedJobName string) waitResult {
+	for {
+		jobStatus, err := executioner.kubeClient.WaitForJobCompletion(ctx, target, executedJobName)
+		if err != nil || jobStatus.Succeeded {
+			return waitResult{executedJobName, jobStatus, err}
+		}
+
+		nextAttempt, err := executioner.nextAttempt(ctx, executedJobName)
+		if err != nil || nextAttempt == "" {
+			return waitResult{executedJobName, jobStatus, err}
+		}
+		executedJobName = nextAttempt
+	}
+}
+
+// nextAttempt polls the execution record until the tracker has decided
+// whether the failed attempt is resubmitted.
+func (executioner *executioner) nextAttempt(ctx context.Context, executedJobName string) (string, error) {
+	for {
+		execution, err := executioner.store.GetExecution(executedJobName)
+		if err == ErrExecutionNotFound {
+			return "", nil
+		}
+		if err != nil {
+			return "", err
+		}
+		if execution.HasFinished() && !execution.RetryPending {
+			return execution.NextAttempt, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(attemptPollInterval):
+		}
+	}
+}
+
+func (executioner *executioner) executionResult(ctx context.Context, target kubernetes.Target, result waitResult, waitOptions *WaitOptions) ExecutionResult {
+	log := logger.FromContext(ctx)
+
+	executedJobName := result.executedJobName
+	executionResult := ExecutionResult{
+		Name: executedJobName,
+	}
+
+	switch {
+	case result.err == context.DeadlineExceeded:
+		executionResult.Status = StatusWaitTimedOut
+	case result.err == kubernetes.ErrJobNotFound:
+		executionResult.Status = StatusCancelled
+	case result.err != nil:
+		log.Error("Error waiting for job completion", result.err.Error())
+		executionResult.Status = StatusUnknown
+		executionResult.Error = result.err.Error()
+	case result.jobStatus.Succeeded:
+		executionResult.Status = StatusSucceeded
+	default:
+		executionResult.Status = StatusFailed
+	}
+
+	if result.jobStatus != nil {
+		executionResult.ExitCode = result.jobStatus.ExitCode
+		executionResult.DurationSeconds = result.jobStatus.Duration().Seconds()
+	}
+
+	if waitOptions.TailLines > 0 {
+		logs, err := executioner.kubeClient.JobLogsTail(ctx, target, executedJobName, waitOptions.TailLines)
+		if err != nil {
+			log.Error("Error fetching last log lines of job", err.Error())
+		}
+		executionResult.Logs = logs
+	}
+	return executionResult
+}
@@ -0,0 +1,141 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gojektech/proctor-engine/kubernetes"
+	"github.com/gojektech/proctor-engine/utility"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestParseWaitOptionsWithoutWait(t *testing.T) {
+	waitOptions, fieldErrors := parseWaitOptions(url.Values{"timeout": {"1m"}})
+
+	assert.Nil(t, waitOptions)
+	assert.Empty(t, fieldErrors)
+}
+
+func TestParseWaitOptionsDefaults(t *testing.T) {
+	waitOptions, fieldErrors := parseWaitOptions(url.Values{"wait": {"true"}})
+
+	assert.Empty(t, fieldErrors)
+	assert.Equal(t, &WaitOptions{Timeout: defaultWaitTimeout, TailLines: defaultTailLines}, waitOptions)
+}
+
+func TestParseWaitOptionsForInvalidValues(t *testing.T) {
+	_, fieldErrors := parseWaitOptions(url.Values{"wait": {"true"}, "timeout": {"-1m"}, "tail_lines": {"many"}})
+
+	assert.Equal(t, []utility.FieldError{
+		{Field: "timeout", Message: "must be a positive duration such as 10m"},
+		{Field: "tail_lines", Message: "must be a non-negative integer"},
+	}, fieldErrors)
+}
+
+func TestWaitForCompletionSendsHeartbeats(t *testing.T) {
+	heartbeatInterval = time.Millisecond
+	defer func() { heartbeatInterval = 15 * time.Second }()
+
+	mockKubeClient := &kubernetes.MockClient{}
+	testExecutioner := &executioner{kubeClient: mockKubeClient}
+
+	mockKubeClient.On("WaitForJobCompletion", mock.Anything, kubernetes.Target{}, "proctor-ipsum-lorem").Return(&kubernetes.JobStatus{}, errors.New("error")).After(20 * time.Millisecond).Once()
+
+	responseRecorder := httptest.NewRecorder()
+	testExecutioner.waitForCompletion(context.Background(), responseRecorder, kubernetes.Target{}, "proctor-ipsum-lorem", &WaitOptions{Timeout: time.Minute})
+
+	assert.Equal(t, http.StatusAccepted, responseRecorder.Code)
+	assert.Contains(t, responseRecorder.Body.String(), "\n\n")
+	assert.Contains(t, responseRecorder.Body.String(), "\"status\":\"UNKNOWN\"")
+	assert.Equal(t, StatusUnknown, responseRecorder.Result().Trailer.Get(ExecutionStatusTrailerKey))
+}
+
+func TestWaitForCompletionTimeout(t *testing.T) {
+	mockKubeClient := &kubernetes.MockClient{}
+	testExecutioner := &executioner{kubeClient: mockKubeClient}
+
+	mockKubeClient.On("WaitForJobCompletion", mock.Anything, kubernetes.Target{}, "proctor-ipsum-lorem").Return(&kubernetes.JobStatus{}, context.DeadlineExceeded).Once()
+
+	responseRecorder := httptest.NewRecorder()
+	testExecutioner.waitForCompletion(context.Background(), responseRecorder, kubernetes.Target{}, "proctor-ipsum-lorem", &WaitOptions{Timeout: time.Minute})
+
+	assert.Equal(t, http.StatusAccepted, responseRecorder.Code)
+	assert.Contains(t, responseRecorder.Body.String(), "\"status\":\"WAIT_TIMED_OUT\"")
+	mockKubeClient.AssertNotCalled(t, "JobLogsTail", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestWaitForCompletionOnClientDisconnect(t *testing.T) {
+	mockKubeClient := &kubernetes.MockClient{}
+	testExecutioner := &executioner{kubeClient: mockKubeClient}
+
+	mockKubeClient.On("WaitForJobCompletion", mock.Anything, kubernetes.Target{}, "proctor-ipsum-lorem").Return(&kubernetes.JobStatus{}, context.Canceled).Once()
+
+	responseRecorder := httptest.NewRecorder()
+	testExecutioner.waitForCompletion(context.Background(), responseRecorder, kubernetes.Target{}, "proctor-ipsum-lorem", &WaitOptions{Timeout: time.Minute, TailLines: 10})
+
+	assert.Empty(t, responseRecorder.Body.String())
+	mockKubeClient.AssertNotCalled(t, "JobLogsTail", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestWaitForCompletionOfCancelledJob(t *testing.T) {
+	mockKubeClient := &kubernetes.MockClient{}
+	testExecutioner := &executioner{kubeClient: mockKubeClient}
+
+	mockKubeClient.On("WaitForJobCompletion", mock.Anything, kubernetes.Target{}, "proctor-ipsum-lorem").Return(&kubernetes.JobStatus{}, kubernetes.ErrJobNotFound).Once()
+
+	responseRecorder := httptest.NewRecorder()
+	testExecutioner.waitForCompletion(context.Background(), responseRecorder, kubernetes.Target{}, "proctor-ipsum-lorem", &WaitOptions{Timeout: time.Minute})
+
+	assert.Equal(t, http.StatusFailedDependency, responseRecorder.Code)
+	assert.Contains(t, responseRecorder.Body.String(), "\"status\":\"CANCELLED\"")
+}
+
+func TestWaitForCompletionFollowsResubmittedAttempts(t *testing.T) {
+	attemptPollInterval = time.Millisecond
+	defer func() { attemptPollInterval = time.Second }()
+
+	mockKubeClient := &kubernetes.MockClient{}
+	mockStore := &MockStore{}
+	testExecutioner := &executioner{kubeClient: mockKubeClient, store: mockStore}
+
+	finishedAt := time.Now()
+	mockKubeClient.On("WaitForJobCompletion", mock.Anything, kubernetes.Target{}, "proctor-first").Return(&kubernetes.JobStatus{Succeeded: false}, nil).Once()
+	mockStore.On("GetExecution", "proctor-first").Return(&Execution{Name: "proctor-first", FinishedAt: &finishedAt, RetryPending: true}, nil).Once()
+	mockStore.On("GetExecution", "proctor-first").Return(&Execution{Name: "proctor-first", FinishedAt: &finishedAt, NextAttempt: "proctor-second"}, nil).Once()
+	mockKubeClient.On("WaitForJobCompletion", mock.Anything, kubernetes.Target{}, "proctor-second").Return(&kubernetes.JobStatus{Succeeded: true}, nil).Once()
+
+	responseRecorder := httptest.NewRecorder()
+	testExecutioner.waitForCompletion(context.Background(), responseRecorder, kubernetes.Target{}, "proctor-first", &WaitOptions{Timeout: time.Minute})
+
+	mockKubeClient.AssertExpectations(t)
+	mockStore.AssertExpectations(t)
+
+	assert.Equal(t, http.StatusCreated, responseRecorder.Code)
+	assert.Contains(t, responseRecorder.Body.String(), "\"name\":\"proctor-second\"")
+	assert.Contains(t, responseRecorder.Body.String(), "\"status\":\"SUCCEEDED\"")
+}
+
+func TestWaitForCompletionStopsAfterLastAttempt(t *testing.T) {
+	mockKubeClient := &kubernetes.MockClient{}
+	mockStore := &MockStore{}
+	testExecutioner := &executioner{kubeClient: mockKubeClient, store: mockStore}
+
+	finishedAt := time.Now()
+	mockKubeClient.On("WaitForJobCompletion", mock.Anything, kubernetes.Target{}, "proctor-ipsum-lorem").Return(&kubernetes.JobStatus{Succeeded: false}, nil).Once()
+	mockStore.On("GetExecution", "proctor-ipsum-lorem").Return(&Execution{Name: "proctor-ipsum-lorem", FinishedAt: &finishedAt}, nil).Once()
+
+	responseRecorder := httptest.NewRecorder()
+	testExecutioner.waitForCompletion(context.Background(), responseRecorder, kubernetes.Target{}, "proctor-ipsum-lorem", &WaitOptions{Timeout: time.Minute})
+
+	mockKubeClient.AssertExpectations(t)
+
+	assert.Equal(t, http.StatusFailedDependency, responseRecorder.Code)
+	assert.Contains(t, responseRecorder.Body.String(), "\"status\":\"FAILED\"")
+}
@@ -0,0 +1,33 @@
+package idempotency
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+const HeaderKey = "Idempotency-Key"
+const ReplayedHeaderKey = "Idempotent-Replayed"
+
+type Record struct {
+	RequestHash string    `json:"request_hash"`
+	Completed   bool      `json:"completed"`
+	StatusCode  int       `json:"status_code,omitempty"`
+	Body        []byte    `json:"body,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func (record *Record) Matches(requestHash string) bool {
+	return record.RequestHash == requestHash
+}
+
+func RequestHash(request interface{}) (string, error) {
+	binaryRequest, err := json.Marshal(request)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(binaryRequest)
+	return hex.EncodeToString(hash[:]), nil
+}
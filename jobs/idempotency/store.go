@@ -0,0 +1,85 @@
+package idempotency
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/gojektech/proctor-engine/config"
+	"github.com/gojektech/proctor-engine/redis"
+)
+
+const KeySuffix = "-idempotency-key"
+
+const defaultExpirySeconds = 24 * 60 * 60
+
+var ErrKeyNotFound = errors.New("idempotency key not found")
+
+type Store interface {
+	Reserve(string, string, string) (bool, error)
+	Get(string, string) (*Record, error)
+	Complete(string, string, Record) error
+	Release(string, string) error
+}
+
+type store struct {
+	redisClient redis.Client
+}
+
+func NewStore(redisClient redis.Client) Store {
+	return &store{
+		redisClient: redisClient,
+	}
+}
+
+func recordKey(requester string, idempotencyKey string) string {
+	return requester + ":" + idempotencyKey + KeySuffix
+}
+
+func expirySeconds() int {
+	if expiry := int(config.IdempotencyKeyExpiry().Seconds()); expiry > 0 {
+		return expiry
+	}
+	return defaultExpirySeconds
+}
+
+func (store *store) Reserve(requester string, idempotencyKey string, requestHash string) (bool, error) {
+	binaryRecord, err := json.Marshal(Record{RequestHash: requestHash, CreatedAt: time.Now()})
+	if err != nil {
+		return false, err
+	}
+
+	return store.redisClient.SETNX(recordKey(requester, idempotencyKey), expirySeconds(), binaryRecord)
+}
+
+func (store *store) Get(requester string, idempotencyKey string) (*Record, error) {
+	binaryRecord, err := store.redisClient.GET(recordKey(requester, idempotencyKey))
+	if err == redis.ErrNil {
+		return nil, ErrKeyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var record Record
+	err = json.Unmarshal(binaryRecord, &record)
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (store *store) Complete(requester string, idempotencyKey string, record Record) error {
+	record.Completed = true
+
+	binaryRecord, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	return store.redisClient.SETEX(recordKey(requester, idempotencyKey), expirySeconds(), binaryRecord)
+}
+
+func (store *store) Release(requester string, idempotencyKey string) error {
+	return store.redisClient.DEL(recordKey(requester, idempotencyKey))
+}
@@ -0,0 +1,29 @@
+package idempotency
+
+import (
+	"github.com/stretchr/testify/mock"
+)
+
+type MockStore struct {
+	mock.Mock
+}
+
+func (m *MockStore) Reserve(requester string, idempotencyKey string, requestHash string) (bool, error) {
+	args := m.Called(requester, idempotencyKey, requestHash)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockStore) Get(requester string, idempotencyKey string) (*Record, error) {
+	args := m.Called(requester, idempotencyKey)
+	return args.Get(0).(*Record), args.Error(1)
+}
+
+func (m *MockStore) Complete(requester string, idempotencyKey string, record Record) error {
+	args := m.Called(requester, idempotencyKey, record)
+	return args.Error(0)
+}
+
+func (m *MockStore) Release(requester string, idempotencyKey string) error {
+	args := m.Called(requester, idempotencyKey)
+	return args.Error(0)
+}
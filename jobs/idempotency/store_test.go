@@ -0,0 +1,95 @@
+package idempotency
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/gojektech/proctor-engine/redis"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type IdempotencyStoreTestSuite struct {
+	suite.Suite
+	mockRedisClient      *redis.MockClient
+	testIdempotencyStore Store
+}
+
+func (s *IdempotencyStoreTestSuite) SetupTest() {
+	os.Setenv("PROCTOR_IDEMPOTENCY_KEY_EXPIRY_SECONDS", "86400")
+	viper.AutomaticEnv()
+
+	s.mockRedisClient = &redis.MockClient{}
+
+	s.testIdempotencyStore = NewStore(s.mockRedisClient)
+}
+
+func (s *IdempotencyStoreTestSuite) TestReserve() {
+	t := s.T()
+
+	s.mockRedisClient.On("SETNX", "user@example.com:key-idempotency-key", 86400, mock.MatchedBy(func(binaryRecord []byte) bool {
+		var record Record
+		err := json.Unmarshal(binaryRecord, &record)
+		return err == nil && record.RequestHash == "hash" && !record.Completed
+	})).Return(true, nil).Once()
+
+	reserved, err := s.testIdempotencyStore.Reserve("user@example.com", "key", "hash")
+	assert.NoError(t, err)
+	assert.True(t, reserved)
+
+	s.mockRedisClient.AssertExpectations(t)
+}
+
+func (s *IdempotencyStoreTestSuite) TestGet() {
+	t := s.T()
+
+	binaryRecord, err := json.Marshal(Record{RequestHash: "hash", Completed: true, StatusCode: 201, Body: []byte(`{ "name":"proctor-ipsum-lorem" }`)})
+	assert.NoError(t, err)
+
+	s.mockRedisClient.On("GET", "user@example.com:key-idempotency-key").Return(binaryRecord, nil).Once()
+
+	record, err := s.testIdempotencyStore.Get("user@example.com", "key")
+	assert.NoError(t, err)
+	assert.Equal(t, 201, record.StatusCode)
+	assert.Equal(t, `{ "name":"proctor-ipsum-lorem" }`, string(record.Body))
+}
+
+func (s *IdempotencyStoreTestSuite) TestGetNotFound() {
+	t := s.T()
+
+	s.mockRedisClient.On("GET", "user@example.com:key-idempotency-key").Return([]byte{}, redis.ErrNil).Once()
+
+	_, err := s.testIdempotencyStore.Get("user@example.com", "key")
+	assert.Equal(t, ErrKeyNotFound, err)
+}
+
+func (s *IdempotencyStoreTestSuite) TestComplete() {
+	t := s.T()
+
+	binaryRecord, err := json.Marshal(Record{RequestHash: "hash", Completed: true, StatusCode: 201})
+	assert.NoError(t, err)
+
+	s.mockRedisClient.On("SETEX", "user@example.com:key-idempotency-key", 86400, binaryRecord).Return(nil).Once()
+
+	err = s.testIdempotencyStore.Complete("user@example.com", "key", Record{RequestHash: "hash", StatusCode: 201})
+	assert.NoError(t, err)
+
+	s.mockRedisClient.AssertExpectations(t)
+}
+
+func (s *IdempotencyStoreTestSuite) TestRelease() {
+	t := s.T()
+
+	s.mockRedisClient.On("DEL", "user@example.com:key-idempotency-key").Return(errors.New("error")).Once()
+
+	err := s.testIdempotencyStore.Release("user@example.com", "key")
+	assert.EqualError(t, err, "error")
+}
+
+func TestIdempotencyStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(IdempotencyStoreTestSuite))
+}
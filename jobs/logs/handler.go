@@ -4,32 +4,41 @@ import (
 	"bufio"
 	"io"
 	"net/http"
-	"strings"
 
 	"github.com/gojektech/proctor-engine/config"
+	"github.com/gojektech/proctor-engine/jobs/execution"
 	"github.com/gojektech/proctor-engine/kubernetes"
 	_logger "github.com/gojektech/proctor-engine/logger"
+	"github.com/gojektech/proctor-engine/metrics"
 	"github.com/gojektech/proctor-engine/utility"
 
+	"github.com/gorilla/mux"
 	"github.com/gorilla/websocket"
 )
 
 var upgrader = websocket.Upgrader{
 	ReadBufferSize:  config.LogsStreamReadBufferSize(),
 	WriteBufferSize: config.LogsStreamWriteBufferSize(),
+	Error: func(w http.ResponseWriter, req *http.Request, status int, reason error) {
+		_logger.FromContext(req.Context()).Error("Error upgrading connection to websocket protocol: ", reason)
+		utility.WriteError(w, req, status, utility.ErrCodeMalformedRequest, reason.Error())
+	},
 }
 
 type logger struct {
-	kubeClient kubernetes.Client
+	kubeClient     kubernetes.Client
+	executionStore execution.Store
 }
 
 type Logger interface {
 	Stream() http.HandlerFunc
+	StreamExecution() http.HandlerFunc
 }
 
-func NewLogger(kubeClient kubernetes.Client) Logger {
+func NewLogger(kubeClient kubernetes.Client, executionStore execution.Store) Logger {
 	return &logger{
-		kubeClient: kubeClient,
+		kubeClient:     kubeClient,
+		executionStore: executionStore,
 	}
 }
 
@@ -43,53 +52,79 @@ func CloseWebSocket(message string, conn *websocket.Conn) {
 
 func (l *logger) Stream() http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
-		conn, err := upgrader.Upgrade(w, req, nil)
-		if err != nil {
-			_logger.Error("Error upgrading connection to websocket protocol: ", err)
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write([]byte(utility.ClientError))
-			return
-		}
-		defer conn.Close()
-
-		jobName := strings.TrimLeft(req.URL.RawQuery, "job_name=")
+		jobName := req.URL.Query().Get("job_name")
 		if jobName == "" {
-			_logger.Error("No job name provided as part of URL: ", req.URL.RawQuery)
-			CloseWebSocket("No job name provided while requesting for logs", conn)
+			_logger.FromContext(req.Context()).Error("No job name provided as part of URL: ", req.URL.RawQuery)
+			utility.WriteValidationError(w, req, []utility.FieldError{{Field: "job_name", Message: "query parameter is required"}})
 			return
 		}
 
-		logStream, err := l.kubeClient.StreamJobLogs(jobName)
-		if err != nil {
-			_logger.Error("Error streaming logs from kube client: ", err)
-			CloseWebSocket("Something went wrong", conn)
-			return
-		}
-		defer logStream.Close()
+		l.stream(w, req, jobName)
+	}
+}
 
-		bufioReader := bufio.NewReader(logStream)
+func (l *logger) StreamExecution() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		l.stream(w, req, mux.Vars(req)["name"])
+	}
+}
 
-		for {
-			jobLogSingleLine, _, err := bufioReader.ReadLine()
-			if err != nil {
-				if err == io.EOF {
-					_logger.Debug("Finished streaming logs for job: ", jobName)
-					CloseWebSocket("All logs are read", conn)
-					return
-				}
+func (l *logger) stream(w http.ResponseWriter, req *http.Request, jobName string) {
+	ctx := _logger.WithFields(req.Context(), _logger.Fields{_logger.ExecutionNameField: jobName})
+	log := _logger.FromContext(ctx)
 
-				_logger.Error("Error reading from reader: ", err.Error())
-				CloseWebSocket("Something went wrong", conn)
-				return
-			}
+	var target kubernetes.Target
+	jobExecution, err := l.executionStore.GetExecution(jobName)
+	switch {
+	case err == execution.ErrExecutionNotFound:
+		log.Debug("No execution record for job, streaming logs from default cluster")
+	case err != nil:
+		log.Error("Error fetching execution to stream logs: ", err)
+		utility.WriteError(w, req, http.StatusServiceUnavailable, utility.ErrCodeStoreUnavailable, "unable to fetch execution")
+		return
+	default:
+		target = jobExecution.Target()
+	}
 
-			_logger.Debug("writing to web socket ", string(jobLogSingleLine[:]))
-			err = conn.WriteMessage(websocket.TextMessage, jobLogSingleLine[:])
-			if err != nil {
-				_logger.Error("Error writing logs to client: ", err)
-				CloseWebSocket("Something went wrong", conn)
+	conn, err := upgrader.Upgrade(w, req, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	logStream, err := l.kubeClient.StreamJobLogs(ctx, target, jobName)
+	if err != nil {
+		log.Error("Error streaming logs from kube client: ", err)
+		CloseWebSocket("Something went wrong", conn)
+		return
+	}
+	defer logStream.Close()
+
+	metrics.LogStreamOpened()
+	defer metrics.LogStreamClosed()
+
+	bufioReader := bufio.NewReader(logStream)
+
+	for {
+		jobLogSingleLine, _, err := bufioReader.ReadLine()
+		if err != nil {
+			if err == io.EOF {
+				log.Debug("Finished streaming logs for job")
+				CloseWebSocket("All logs are read", conn)
 				return
 			}
+
+			log.Error("Error reading from reader: ", err.Error())
+			CloseWebSocket("Something went wrong", conn)
+			return
+		}
+
+		log.Debug("writing to web socket ", string(jobLogSingleLine[:]))
+		err = conn.WriteMessage(websocket.TextMessage, jobLogSingleLine[:])
+		if err != nil {
+			log.Error("Error writing logs to client: ", err)
+			CloseWebSocket("Something went wrong", conn)
+			return
 		}
 	}
 }
@@ -1,14 +1,17 @@
 package logs
 
 import (
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 
+	"github.com/gojektech/proctor-engine/jobs/execution"
 	"github.com/gojektech/proctor-engine/kubernetes"
 	"github.com/gojektech/proctor-engine/utility"
+	"github.com/gorilla/mux"
 	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -17,13 +20,15 @@ import (
 
 type LoggerTestSuite struct {
 	suite.Suite
-	testLogger     Logger
-	mockKubeClient *kubernetes.MockClient
+	testLogger         Logger
+	mockKubeClient     *kubernetes.MockClient
+	mockExecutionStore *execution.MockStore
 }
 
 func (suite *LoggerTestSuite) SetupTest() {
 	suite.mockKubeClient = &kubernetes.MockClient{}
-	suite.testLogger = NewLogger(suite.mockKubeClient)
+	suite.mockExecutionStore = &execution.MockStore{}
+	suite.testLogger = NewLogger(suite.mockKubeClient, suite.mockExecutionStore)
 }
 
 type logsHandlerServer struct {
@@ -61,7 +66,8 @@ func (suite *LoggerTestSuite) TestLoggerStream() {
 
 	buffer := utility.NewBuffer()
 	buffer.Write([]byte("first line\nsecond line\n"))
-	suite.mockKubeClient.On("StreamJobLogs", "sample").Return(buffer, nil).Once()
+	suite.mockExecutionStore.On("GetExecution", "sample").Return((*execution.Execution)(nil), execution.ErrExecutionNotFound).Once()
+	suite.mockKubeClient.On("StreamJobLogs", mock.Anything, kubernetes.Target{}, "sample").Return(buffer, nil).Once()
 
 	c, _, err := websocket.DefaultDialer.Dial(s.URL+"?"+logsHandlerRawQuery, nil)
 	assert.NoError(t, err)
@@ -84,36 +90,69 @@ func (suite *LoggerTestSuite) TestLoggerStream() {
 	assert.True(t, buffer.WasClosed())
 }
 
+func (suite *LoggerTestSuite) TestLoggerStreamExecution() {
+	t := suite.T()
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/executions/{name}/logs", suite.testLogger.StreamExecution()).Methods("GET")
+	s := httptest.NewServer(router)
+	defer s.Close()
+
+	buffer := utility.NewBuffer()
+	buffer.Write([]byte("only line\n"))
+	suite.mockExecutionStore.On("GetExecution", "sample").Return((*execution.Execution)(nil), execution.ErrExecutionNotFound).Once()
+	suite.mockKubeClient.On("StreamJobLogs", mock.Anything, kubernetes.Target{}, "sample").Return(buffer, nil).Once()
+
+	c, _, err := websocket.DefaultDialer.Dial(makeWsProto(s.URL)+"/api/v1/executions/sample/logs", nil)
+	assert.NoError(t, err)
+	defer c.Close()
+
+	_, message, err := c.ReadMessage()
+	assert.NoError(t, err)
+	assert.Equal(t, "only line", string(message))
+
+	_, _, err = c.ReadMessage()
+	assert.Error(t, err)
+
+	suite.mockKubeClient.AssertExpectations(t)
+}
+
 func (suite *LoggerTestSuite) TestLoggerStreamConnectionUpgradeFailure() {
 	t := suite.T()
 
-	req := httptest.NewRequest("GET", "/jobs/logs", &utility.Buffer{})
+	req := httptest.NewRequest("GET", "/jobs/logs?"+logsHandlerRawQuery, &utility.Buffer{})
 	responseRecorder := httptest.NewRecorder()
+	suite.mockExecutionStore.On("GetExecution", "sample").Return(&execution.Execution{Name: "sample"}, nil).Once()
 
 	suite.testLogger.Stream()(responseRecorder, req)
 
-	suite.mockKubeClient.AssertNotCalled(t, "StreamJobLogs", mock.Anything)
+	suite.mockKubeClient.AssertNotCalled(t, "StreamJobLogs", mock.Anything, mock.Anything, mock.Anything)
 
 	assert.Equal(t, http.StatusBadRequest, responseRecorder.Code)
-	assert.Equal(t, "Bad Request\n"+utility.ClientError, responseRecorder.Body.String())
+
+	var errorResponse utility.ErrorResponse
+	err := json.Unmarshal(responseRecorder.Body.Bytes(), &errorResponse)
+	assert.NoError(t, err)
+	assert.Equal(t, utility.ErrCodeMalformedRequest, errorResponse.Error.Code)
 }
 
 func (suite *LoggerTestSuite) TestLoggerStreamForNoJobName() {
 	t := suite.T()
 
-	s := suite.newServer()
-	defer s.Close()
+	req := httptest.NewRequest("GET", "/jobs/logs", &utility.Buffer{})
+	responseRecorder := httptest.NewRecorder()
 
-	c, _, err := websocket.DefaultDialer.Dial(s.URL, nil)
-	assert.NoError(t, err)
-	defer c.Close()
+	suite.testLogger.Stream()(responseRecorder, req)
 
-	suite.mockKubeClient.AssertNotCalled(t, "StreamJobLogs", mock.Anything)
+	suite.mockKubeClient.AssertNotCalled(t, "StreamJobLogs", mock.Anything, mock.Anything, mock.Anything)
 
-	_, finalMessage, err := c.ReadMessage()
-	assert.Error(t, err)
-	assert.Equal(t, "", string(finalMessage))
-	assert.Equal(t, "websocket: close 1000 (normal): No job name provided while requesting for logs", err.Error())
+	assert.Equal(t, http.StatusUnprocessableEntity, responseRecorder.Code)
+
+	var errorResponse utility.ErrorResponse
+	err := json.Unmarshal(responseRecorder.Body.Bytes(), &errorResponse)
+	assert.NoError(t, err)
+	assert.Equal(t, utility.ErrCodeValidationFailed, errorResponse.Error.Code)
+	assert.Equal(t, []utility.FieldError{{Field: "job_name", Message: "query parameter is required"}}, errorResponse.Error.Details)
 }
 
 func (suite *LoggerTestSuite) TestLoggerStreamKubeClientFailure() {
@@ -122,7 +161,9 @@ func (suite *LoggerTestSuite) TestLoggerStreamKubeClientFailure() {
 	s := suite.newServer()
 	defer s.Close()
 
-	suite.mockKubeClient.On("StreamJobLogs", "sample").Return(&utility.Buffer{}, errors.New("error")).Once()
+	target := kubernetes.Target{Cluster: "staging", Namespace: "batch"}
+	suite.mockExecutionStore.On("GetExecution", "sample").Return(&execution.Execution{Name: "sample", Cluster: "staging", Namespace: "batch"}, nil).Once()
+	suite.mockKubeClient.On("StreamJobLogs", mock.Anything, target, "sample").Return(&utility.Buffer{}, errors.New("error")).Once()
 
 	c, _, err := websocket.DefaultDialer.Dial(s.URL+"?"+logsHandlerRawQuery, nil)
 	assert.NoError(t, err)
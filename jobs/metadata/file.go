@@ -0,0 +1,55 @@
+package metadata
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+
+	"github.com/gojektech/proctor-engine/utility"
+)
+
+var fileNamePattern = regexp.MustCompile(`^[-._a-zA-Z0-9]+$`)
+
+type FileInput struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MountPath   string `json:"mount_path"`
+	Required    bool   `json:"required,omitempty"`
+	Sensitive   bool   `json:"sensitive,omitempty"`
+}
+
+func (fileInput FileInput) Validate() []utility.FieldError {
+	var fieldErrors []utility.FieldError
+	if !fileNamePattern.MatchString(fileInput.Name) {
+		fieldErrors = append(fieldErrors, utility.FieldError{Field: "name", Message: "must contain only letters, digits, '-', '_' or '.'"})
+	}
+	if !path.IsAbs(fileInput.MountPath) {
+		fieldErrors = append(fieldErrors, utility.FieldError{Field: "mount_path", Message: "must be an absolute path"})
+	}
+	return fieldErrors
+}
+
+func (metadata Metadata) ValidateFiles(files map[string][]byte) []utility.FieldError {
+	var fieldErrors []utility.FieldError
+
+	declared := map[string]bool{}
+	for _, fileInput := range metadata.Files {
+		declared[fileInput.Name] = true
+		if _, ok := files[fileInput.Name]; fileInput.Required && !ok {
+			fieldErrors = append(fieldErrors, utility.FieldError{Field: fmt.Sprintf("files.%s", fileInput.Name), Message: "is required"})
+		}
+	}
+	for name := range files {
+		if !declared[name] {
+			fieldErrors = append(fieldErrors, utility.FieldError{Field: fmt.Sprintf("files.%s", name), Message: "is not declared by the job"})
+		}
+	}
+	return fieldErrors
+}
+
+func prefixFieldErrors(prefix string, fieldErrors []utility.FieldError) []utility.FieldError {
+	for i := range fieldErrors {
+		fieldErrors[i].Field = prefix + "." + fieldErrors[i].Field
+	}
+	return fieldErrors
+}
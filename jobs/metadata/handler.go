@@ -2,47 +2,76 @@ package metadata
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 
 	"github.com/gojektech/proctor-engine/logger"
+	"github.com/gojektech/proctor-engine/registry"
 	"github.com/gojektech/proctor-engine/utility"
+
+	"github.com/gorilla/mux"
 )
 
 type metadataHandler struct {
-	store Store
+	store    Store
+	resolver registry.Resolver
 }
 
 type MetadataHandler interface {
 	HandleSubmission() http.HandlerFunc
 	HandleBulkDisplay() http.HandlerFunc
+	HandleDisplay() http.HandlerFunc
 }
 
-func NewMetadataHandler(store Store) MetadataHandler {
+func NewMetadataHandler(store Store, resolver registry.Resolver) MetadataHandler {
 	return &metadataHandler{
-		store: store,
+		store:    store,
+		resolver: resolver,
 	}
 }
 
 func (metadataHandler *metadataHandler) HandleSubmission() http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
+		log := logger.FromContext(req.Context())
 		var jobMetadata []Metadata
 		err := json.NewDecoder(req.Body).Decode(&jobMetadata)
 		defer req.Body.Close()
 		if err != nil {
-			logger.Error("Error parsing request body", err.Error())
+			log.Error("Error parsing request body", err.Error())
+
+			utility.WriteError(w, req, http.StatusBadRequest, utility.ErrCodeMalformedRequest, utility.ClientError)
+			return
+		}
+
+		var fieldErrors []utility.FieldError
+		for i, metadata := range jobMetadata {
+			for _, fieldError := range metadata.Validate() {
+				fieldError.Field = fmt.Sprintf("[%d].%s", i, fieldError.Field)
+				fieldErrors = append(fieldErrors, fieldError)
+			}
+		}
+		if len(fieldErrors) > 0 {
+			utility.WriteValidationError(w, req, fieldErrors)
+			return
+		}
 
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write([]byte(utility.ClientError))
+		for i := range jobMetadata {
+			if fieldError := pinImage(req.Context(), metadataHandler.resolver, &jobMetadata[i]); fieldError != nil {
+				fieldError.Field = fmt.Sprintf("[%d].%s", i, fieldError.Field)
+				fieldErrors = append(fieldErrors, *fieldError)
+			}
+		}
+		if len(fieldErrors) > 0 {
+			utility.WriteValidationError(w, req, fieldErrors)
 			return
 		}
 
 		for _, metadata := range jobMetadata {
 			err = metadataHandler.store.CreateOrUpdateJobMetadata(metadata)
 			if err != nil {
-				logger.Error("Error updating metadata", err.Error())
+				log.WithField(logger.JobNameField, metadata.Name).Error("Error updating metadata", err.Error())
 
-				w.WriteHeader(http.StatusInternalServerError)
-				w.Write([]byte(utility.ServerError))
+				utility.WriteError(w, req, http.StatusServiceUnavailable, utility.ErrCodeStoreUnavailable, "unable to save job metadata")
 				return
 			}
 		}
@@ -53,25 +82,53 @@ func (metadataHandler *metadataHandler) HandleSubmission() http.HandlerFunc {
 
 func (metadataHandler *metadataHandler) HandleBulkDisplay() http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
+		log := logger.FromContext(req.Context())
 
 		jobMetadata, err := metadataHandler.store.GetAllJobsMetadata()
 		if err != nil {
-			logger.Error("Error fetching metadata", err.Error())
+			log.Error("Error fetching metadata", err.Error())
 
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(utility.ServerError))
+			utility.WriteError(w, req, http.StatusServiceUnavailable, utility.ErrCodeStoreUnavailable, "unable to fetch job metadata")
 			return
 		}
 
 		jobsMetadataInJSON, err := json.Marshal(jobMetadata)
 		if err != nil {
-			logger.Error("Error marshalling jobs metadata in json", err.Error())
+			log.Error("Error marshalling jobs metadata in json", err.Error())
 
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(utility.ServerError))
+			utility.WriteError(w, req, http.StatusInternalServerError, utility.ErrCodeInternal, utility.ServerError)
 			return
 		}
 
 		w.Write(jobsMetadataInJSON)
 	}
 }
+
+func (metadataHandler *metadataHandler) HandleDisplay() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		jobName := mux.Vars(req)["name"]
+		log := logger.FromContext(req.Context()).WithField(logger.JobNameField, jobName)
+
+		jobMetadata, err := metadataHandler.store.GetJobMetadata(jobName)
+		if err == ErrJobNotFound {
+			utility.WriteError(w, req, http.StatusNotFound, utility.ErrCodeJobNotFound, fmt.Sprintf("job %s not found", jobName))
+			return
+		}
+		if err != nil {
+			log.Error("Error fetching metadata", err.Error())
+
+			utility.WriteError(w, req, http.StatusServiceUnavailable, utility.ErrCodeStoreUnavailable, "unable to fetch job metadata")
+			return
+		}
+
+		jobMetadataInJSON, err := json.Marshal(jobMetadata)
+		if err != nil {
+			log.Error("Error marshalling job metadata in json", err.Error())
+
+			utility.WriteError(w, req, http.StatusInternalServerError, utility.ErrCodeInternal, utility.ServerError)
+			return
+		}
+
+		w.Write(jobMetadataInJSON)
+	}
+}
@@ -7,11 +7,15 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"testing"
 
 	"github.com/gojektech/proctor-engine/jobs/metadata/env"
+	"github.com/gojektech/proctor-engine/registry"
 
 	"github.com/gojektech/proctor-engine/utility"
+	"github.com/gorilla/mux"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
@@ -20,18 +24,27 @@ import (
 type MetadataHandlerTestSuite struct {
 	suite.Suite
 	mockStore           *MockStore
+	mockResolver        *registry.MockResolver
 	testMetadataHandler MetadataHandler
 	serverError         string
 }
 
 func (s *MetadataHandlerTestSuite) SetupTest() {
 	s.mockStore = &MockStore{}
+	s.mockResolver = &registry.MockResolver{}
 
-	s.testMetadataHandler = NewMetadataHandler(s.mockStore)
+	s.testMetadataHandler = NewMetadataHandler(s.mockStore, s.mockResolver)
 
 	s.serverError = "Something went wrong"
 }
 
+func errorResponse(t *testing.T, responseRecorder *httptest.ResponseRecorder) utility.Error {
+	var errorResponse utility.ErrorResponse
+	err := json.Unmarshal(responseRecorder.Body.Bytes(), &errorResponse)
+	assert.NoError(t, err)
+	return errorResponse.Error
+}
+
 func (s *MetadataHandlerTestSuite) TestSuccessfulMetadataSubmission() {
 	t := s.T()
 
@@ -86,13 +99,119 @@ func (s *MetadataHandlerTestSuite) TestJobMetadataSubmissionMalformedRequest() {
 	s.mockStore.AssertNotCalled(t, "CreateOrUpdateJobMetadata", mock.Anything)
 
 	assert.Equal(t, http.StatusBadRequest, responseRecorder.Code)
-	assert.Equal(t, utility.ClientError, responseRecorder.Body.String())
+	assert.Equal(t, utility.ErrCodeMalformedRequest, errorResponse(t, responseRecorder).Code)
+}
+
+func (s *MetadataHandlerTestSuite) TestJobMetadataSubmissionValidationFailure() {
+	t := s.T()
+
+	jobMetadata := []Metadata{
+		Metadata{Name: "run-sample", ImageName: "proctor-jobs-run-sample"},
+		Metadata{Name: "run-sample-without-image"},
+	}
+
+	metadataSubmissionRequestBody, err := json.Marshal(jobMetadata)
+	assert.NoError(t, err)
+	req := httptest.NewRequest("PUT", "/jobs/metadata", bytes.NewReader(metadataSubmissionRequestBody))
+	responseRecorder := httptest.NewRecorder()
+
+	s.testMetadataHandler.HandleSubmission()(responseRecorder, req)
+
+	s.mockStore.AssertNotCalled(t, "CreateOrUpdateJobMetadata", mock.Anything)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, responseRecorder.Code)
+	metadataError := errorResponse(t, responseRecorder)
+	assert.Equal(t, utility.ErrCodeValidationFailed, metadataError.Code)
+	assert.Equal(t, []utility.FieldError{{Field: "[1].image_name", Message: "is required"}}, metadataError.Details)
+}
+
+func (s *MetadataHandlerTestSuite) submit(jobMetadata []Metadata) *httptest.ResponseRecorder {
+	metadataSubmissionRequestBody, err := json.Marshal(jobMetadata)
+	assert.NoError(s.T(), err)
+	req := httptest.NewRequest("PUT", "/jobs/metadata", bytes.NewReader(metadataSubmissionRequestBody))
+	responseRecorder := httptest.NewRecorder()
+
+	s.testMetadataHandler.HandleSubmission()(responseRecorder, req)
+	return responseRecorder
+}
+
+func (s *MetadataHandlerTestSuite) TestJobMetadataSubmissionWithDisallowedImage() {
+	t := s.T()
+
+	os.Setenv("PROCTOR_IMAGE_ALLOWED_PATTERNS", "gcr.io/ops/*")
+	viper.AutomaticEnv()
+	defer os.Unsetenv("PROCTOR_IMAGE_ALLOWED_PATTERNS")
+
+	responseRecorder := s.submit([]Metadata{
+		{Name: "allowed", ImageName: "gcr.io/ops/toolbox:3"},
+		{Name: "disallowed", ImageName: "evil.io/miner:latest"},
+	})
+
+	s.mockStore.AssertNotCalled(t, "CreateOrUpdateJobMetadata", mock.Anything)
+	s.mockResolver.AssertNotCalled(t, "Resolve", mock.Anything, mock.Anything)
+	assert.Equal(t, http.StatusUnprocessableEntity, responseRecorder.Code)
+	assert.Equal(t, []utility.FieldError{{Field: "[1].image_name", Message: "evil.io/miner is not in an allowed registry or repository"}}, errorResponse(t, responseRecorder).Details)
+}
+
+func (s *MetadataHandlerTestSuite) TestJobMetadataSubmissionResolvesImageDigest() {
+	t := s.T()
+
+	os.Setenv("PROCTOR_IMAGE_RESOLVE_DIGESTS", "true")
+	os.Setenv("PROCTOR_IMAGE_REQUIRE_DIGEST", "true")
+	viper.AutomaticEnv()
+	defer os.Unsetenv("PROCTOR_IMAGE_RESOLVE_DIGESTS")
+	defer os.Unsetenv("PROCTOR_IMAGE_REQUIRE_DIGEST")
+
+	digest := "sha256:4bcff63911fcb4448bd4fdacec207030997caf25e9bea4045fa6c8c44de311d1"
+	s.mockResolver.On("Resolve", mock.Anything, mock.MatchedBy(func(reference registry.Reference) bool {
+		return reference.FullName() == "gcr.io/ops/toolbox" && reference.Tag == "3"
+	})).Return(digest, nil).Once()
+	s.mockStore.On("CreateOrUpdateJobMetadata", Metadata{Name: "vacuum", ImageName: "gcr.io/ops/toolbox:3", ImageDigest: digest}).Return(nil).Once()
+
+	responseRecorder := s.submit([]Metadata{{Name: "vacuum", ImageName: "gcr.io/ops/toolbox:3"}})
+
+	s.mockResolver.AssertExpectations(t)
+	s.mockStore.AssertExpectations(t)
+	assert.Equal(t, http.StatusCreated, responseRecorder.Code)
+}
+
+func (s *MetadataHandlerTestSuite) TestJobMetadataSubmissionWithUnresolvableImage() {
+	t := s.T()
+
+	os.Setenv("PROCTOR_IMAGE_RESOLVE_DIGESTS", "true")
+	viper.AutomaticEnv()
+	defer os.Unsetenv("PROCTOR_IMAGE_RESOLVE_DIGESTS")
+
+	s.mockResolver.On("Resolve", mock.Anything, mock.Anything).Return("", registry.ErrManifestNotFound).Once()
+
+	responseRecorder := s.submit([]Metadata{{Name: "vacuum", ImageName: "gcr.io/ops/toolbox:missing"}})
+
+	s.mockStore.AssertNotCalled(t, "CreateOrUpdateJobMetadata", mock.Anything)
+	assert.Equal(t, http.StatusUnprocessableEntity, responseRecorder.Code)
+	assert.Equal(t, "[0].image_name", errorResponse(t, responseRecorder).Details[0].Field)
+}
+
+func (s *MetadataHandlerTestSuite) TestJobMetadataSubmissionRequiringDigest() {
+	t := s.T()
+
+	os.Setenv("PROCTOR_IMAGE_REQUIRE_DIGEST", "true")
+	viper.AutomaticEnv()
+	defer os.Unsetenv("PROCTOR_IMAGE_REQUIRE_DIGEST")
+
+	responseRecorder := s.submit([]Metadata{{Name: "vacuum", ImageName: "gcr.io/ops/toolbox:3"}})
+
+	s.mockStore.AssertNotCalled(t, "CreateOrUpdateJobMetadata", mock.Anything)
+	assert.Equal(t, http.StatusUnprocessableEntity, responseRecorder.Code)
+	assert.Equal(t, []utility.FieldError{{Field: "[0].image_name", Message: "image gcr.io/ops/toolbox:3 must be pinned by a sha256 digest"}}, errorResponse(t, responseRecorder).Details)
 }
 
 func (s *MetadataHandlerTestSuite) TestJobMetadataSubmissionForStoreFailure() {
 	t := s.T()
 
-	metadata := Metadata{}
+	metadata := Metadata{
+		Name:      "run-sample",
+		ImageName: "proctor-jobs-run-sample",
+	}
 
 	jobMetadata := []Metadata{metadata}
 
@@ -107,8 +226,8 @@ func (s *MetadataHandlerTestSuite) TestJobMetadataSubmissionForStoreFailure() {
 
 	s.mockStore.AssertExpectations(t)
 
-	assert.Equal(t, http.StatusInternalServerError, responseRecorder.Code)
-	assert.Equal(t, utility.ServerError, responseRecorder.Body.String())
+	assert.Equal(t, http.StatusServiceUnavailable, responseRecorder.Code)
+	assert.Equal(t, utility.ErrCodeStoreUnavailable, errorResponse(t, responseRecorder).Code)
 }
 
 func (s *MetadataHandlerTestSuite) TestHandleBulkDisplay() {
@@ -144,8 +263,53 @@ func (s *MetadataHandlerTestSuite) TestHandleBulkDisplayStoreFailure() {
 
 	s.mockStore.AssertExpectations(t)
 
-	assert.Equal(t, http.StatusInternalServerError, responseRecorder.Code)
-	assert.Equal(t, utility.ServerError, responseRecorder.Body.String())
+	assert.Equal(t, http.StatusServiceUnavailable, responseRecorder.Code)
+	assert.Equal(t, utility.ErrCodeStoreUnavailable, errorResponse(t, responseRecorder).Code)
+}
+
+func (s *MetadataHandlerTestSuite) display(jobName string) *httptest.ResponseRecorder {
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/jobs/{name}", s.testMetadataHandler.HandleDisplay()).Methods("GET")
+
+	responseRecorder := httptest.NewRecorder()
+	router.ServeHTTP(responseRecorder, httptest.NewRequest("GET", "/api/v1/jobs/"+jobName, nil))
+	return responseRecorder
+}
+
+func (s *MetadataHandlerTestSuite) TestJobMetadataDisplay() {
+	t := s.T()
+
+	jobMetadata := Metadata{Name: "vacuum", ImageName: "ops-toolbox"}
+	s.mockStore.On("GetJobMetadata", "vacuum").Return(&jobMetadata, nil).Once()
+
+	responseRecorder := s.display("vacuum")
+
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+	expectedBody, err := json.Marshal(jobMetadata)
+	assert.NoError(t, err)
+	assert.Equal(t, string(expectedBody), responseRecorder.Body.String())
+}
+
+func (s *MetadataHandlerTestSuite) TestJobMetadataDisplayForUnknownJob() {
+	t := s.T()
+
+	s.mockStore.On("GetJobMetadata", "vacuum").Return((*Metadata)(nil), ErrJobNotFound).Once()
+
+	responseRecorder := s.display("vacuum")
+
+	assert.Equal(t, http.StatusNotFound, responseRecorder.Code)
+	assert.Equal(t, utility.ErrCodeJobNotFound, errorResponse(t, responseRecorder).Code)
+}
+
+func (s *MetadataHandlerTestSuite) TestJobMetadataDisplayForStoreFailure() {
+	t := s.T()
+
+	s.mockStore.On("GetJobMetadata", "vacuum").Return((*Metadata)(nil), errors.New("redis down")).Once()
+
+	responseRecorder := s.display("vacuum")
+
+	assert.Equal(t, http.StatusServiceUnavailable, responseRecorder.Code)
+	assert.Equal(t, utility.ErrCodeStoreUnavailable, errorResponse(t, responseRecorder).Code)
 }
 
 func TestMetadataHandlerTestSuite(t *testing.T) {
@@ -0,0 +1,47 @@
+package metadata
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gojektech/proctor-engine/registry"
+	"github.com/gojektech/proctor-engine/utility"
+)
+
+func (metadata Metadata) CheckImagePolicy() error {
+	policy := registry.DefaultPolicy()
+	if len(policy.AllowedPatterns) == 0 && !policy.RequireDigest {
+		return nil
+	}
+
+	reference, err := registry.ParseReference(metadata.Image())
+	if err != nil {
+		return err
+	}
+	return policy.Check(reference)
+}
+
+func pinImage(ctx context.Context, resolver registry.Resolver, metadata *Metadata) *utility.FieldError {
+	reference, err := registry.ParseReference(metadata.ImageName)
+	if err != nil {
+		return &utility.FieldError{Field: "image_name", Message: fmt.Sprintf("must be a valid image reference: %s", err)}
+	}
+
+	policy := registry.DefaultPolicy()
+	if !policy.Allows(reference) {
+		return &utility.FieldError{Field: "image_name", Message: fmt.Sprintf("%s is not in an allowed registry or repository", reference.FullName())}
+	}
+
+	if reference.Digest == "" && policy.ResolveDigests {
+		reference.Digest, err = resolver.Resolve(ctx, reference)
+		if err != nil {
+			return &utility.FieldError{Field: "image_name", Message: fmt.Sprintf("could not be resolved to a digest: %s", err)}
+		}
+	}
+	metadata.ImageDigest = reference.Digest
+
+	if err := policy.Check(reference); err != nil {
+		return &utility.FieldError{Field: "image_name", Message: err.Error()}
+	}
+	return nil
+}
@@ -1,10 +1,131 @@
 package metadata
 
-import "github.com/gojektech/proctor-engine/jobs/metadata/env"
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"strings"
+	"text/template"
+
+	"github.com/gojektech/proctor-engine/config"
+	"github.com/gojektech/proctor-engine/jobs/metadata/env"
+	"github.com/gojektech/proctor-engine/jobs/webhook"
+	"github.com/gojektech/proctor-engine/kubernetes"
+	"github.com/gojektech/proctor-engine/utility"
+)
+
+const (
+	OnConcurrencyLimitQueue  = "queue"
+	OnConcurrencyLimitReject = "reject"
+)
 
 type Metadata struct {
-	Name        string   `json:"name"`
-	Description string   `json:"description"`
-	ImageName   string   `json:"image_name"`
-	EnvVars     env.Vars `json:"env_vars"`
+	Name               string                 `json:"name"`
+	Description        string                 `json:"description"`
+	ImageName          string                 `json:"image_name"`
+	ImageDigest        string                 `json:"image_digest,omitempty"`
+	Cluster            string                 `json:"cluster,omitempty"`
+	Namespace          string                 `json:"namespace,omitempty"`
+	Command            []string               `json:"command,omitempty"`
+	Args               []string               `json:"args,omitempty"`
+	WorkingDir         string                 `json:"working_dir,omitempty"`
+	Scheduling         *kubernetes.Scheduling `json:"scheduling,omitempty"`
+	Volumes            []kubernetes.Volume    `json:"volumes,omitempty"`
+	Files              []FileInput            `json:"files,omitempty"`
+	EnvVars            env.Vars               `json:"env_vars"`
+	RequiredApprovals  int                    `json:"required_approvals,omitempty"`
+	Webhooks           []string               `json:"webhooks,omitempty"`
+	Retry              *RetryPolicy           `json:"retry,omitempty"`
+	MaxConcurrent      int                    `json:"max_concurrent,omitempty"`
+	OnConcurrencyLimit string                 `json:"on_concurrency_limit,omitempty"`
+	RateLimit          *RateLimit             `json:"rate_limit,omitempty"`
+}
+
+func (metadata Metadata) Image() string {
+	if metadata.ImageDigest == "" || strings.Contains(metadata.ImageName, "@") {
+		return metadata.ImageName
+	}
+	return metadata.ImageName + "@" + metadata.ImageDigest
+}
+
+func (metadata Metadata) Target() kubernetes.Target {
+	return kubernetes.Target{Cluster: metadata.Cluster, Namespace: metadata.Namespace}
+}
+
+func (metadata Metadata) Validate() []utility.FieldError {
+	var fieldErrors []utility.FieldError
+	if metadata.Name == "" {
+		fieldErrors = append(fieldErrors, utility.FieldError{Field: "name", Message: "is required"})
+	}
+	if metadata.ImageName == "" {
+		fieldErrors = append(fieldErrors, utility.FieldError{Field: "image_name", Message: "is required"})
+	}
+	for i, arg := range metadata.Args {
+		if _, err := template.New(arg).Parse(arg); err != nil {
+			fieldErrors = append(fieldErrors, utility.FieldError{Field: fmt.Sprintf("args[%d]", i), Message: "must be a valid template"})
+		}
+	}
+	if metadata.Cluster != "" || metadata.Namespace != "" {
+		fieldErrors = append(fieldErrors, kubernetes.ValidateTarget(metadata.Target())...)
+	}
+	if metadata.WorkingDir != "" && !path.IsAbs(metadata.WorkingDir) {
+		fieldErrors = append(fieldErrors, utility.FieldError{Field: "working_dir", Message: "must be an absolute path"})
+	}
+	for i, volume := range metadata.Volumes {
+		fieldErrors = append(fieldErrors, prefixFieldErrors(fmt.Sprintf("volumes[%d]", i), volume.Validate())...)
+	}
+	for i, fileInput := range metadata.Files {
+		fieldErrors = append(fieldErrors, prefixFieldErrors(fmt.Sprintf("files[%d]", i), fileInput.Validate())...)
+	}
+	if metadata.RequiredApprovals < 0 {
+		fieldErrors = append(fieldErrors, utility.FieldError{Field: "required_approvals", Message: "must not be negative"})
+	}
+	if len(metadata.Webhooks) > 0 && !config.WebhooksEnabled() {
+		fieldErrors = append(fieldErrors, utility.FieldError{Field: "webhooks", Message: "are not allowed while webhooks are disabled"})
+	}
+	for i, webhookURL := range metadata.Webhooks {
+		if !utility.IsHTTPURL(webhookURL) {
+			fieldErrors = append(fieldErrors, utility.FieldError{Field: fmt.Sprintf("webhooks[%d]", i), Message: "must be an http or https url"})
+		} else if !webhook.IsPermittedURL(webhookURL) {
+			fieldErrors = append(fieldErrors, utility.FieldError{Field: fmt.Sprintf("webhooks[%d]", i), Message: "must not target a private address"})
+		}
+	}
+	if metadata.MaxConcurrent < 0 {
+		fieldErrors = append(fieldErrors, utility.FieldError{Field: "max_concurrent", Message: "must not be negative"})
+	}
+	if metadata.OnConcurrencyLimit != "" && metadata.OnConcurrencyLimit != OnConcurrencyLimitQueue && metadata.OnConcurrencyLimit != OnConcurrencyLimitReject {
+		fieldErrors = append(fieldErrors, utility.FieldError{Field: "on_concurrency_limit", Message: "must be one of queue, reject"})
+	}
+	if metadata.Retry != nil {
+		fieldErrors = append(fieldErrors, metadata.Retry.Validate()...)
+	}
+	if metadata.RateLimit != nil {
+		fieldErrors = append(fieldErrors, metadata.RateLimit.Validate()...)
+	}
+	return fieldErrors
+}
+
+func (metadata Metadata) RejectsOverConcurrencyLimit() bool {
+	return metadata.OnConcurrencyLimit == OnConcurrencyLimitReject
+}
+
+func (metadata Metadata) RenderArgs(executionArgs map[string]string) ([]string, error) {
+	if executionArgs == nil {
+		executionArgs = map[string]string{}
+	}
+
+	var renderedArgs []string
+	for _, arg := range metadata.Args {
+		argTemplate, err := template.New(arg).Option("missingkey=error").Parse(arg)
+		if err != nil {
+			return nil, err
+		}
+
+		var rendered bytes.Buffer
+		if err := argTemplate.Execute(&rendered, executionArgs); err != nil {
+			return nil, err
+		}
+		renderedArgs = append(renderedArgs, rendered.String())
+	}
+	return renderedArgs, nil
 }
@@ -0,0 +1,95 @@
+package metadata
+
+import (
+	"os"
+	"testing"
+
+	"github.com/gojektech/proctor-engine/utility"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateContainerFields(t *testing.T) {
+	metadata := Metadata{Name: "vacuum", ImageName: "ops-toolbox", Args: []string{"--table={{ .TABLE }}", "{{ .TABLE"}, WorkingDir: "scripts"}
+
+	fieldErrors := metadata.Validate()
+	assert.Len(t, fieldErrors, 2)
+	assert.Equal(t, "args[1]", fieldErrors[0].Field)
+	assert.Equal(t, "working_dir", fieldErrors[1].Field)
+}
+
+func TestRenderArgs(t *testing.T) {
+	metadata := Metadata{Args: []string{"vacuum.sh", "--table={{ .TABLE }}"}}
+
+	args, err := metadata.RenderArgs(map[string]string{"TABLE": "orders"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"vacuum.sh", "--table=orders"}, args)
+}
+
+func TestRenderArgsWithMissingExecutionArg(t *testing.T) {
+	metadata := Metadata{Args: []string{"--table={{ .TABLE }}"}}
+
+	_, err := metadata.RenderArgs(nil)
+	assert.Error(t, err)
+}
+
+func TestRenderArgsWithoutArgs(t *testing.T) {
+	args, err := Metadata{}.RenderArgs(map[string]string{"TABLE": "orders"})
+	assert.NoError(t, err)
+	assert.Nil(t, args)
+}
+
+func TestValidateFileInputs(t *testing.T) {
+	metadata := Metadata{Name: "import-users", ImageName: "importer", Files: []FileInput{{Name: "users csv", MountPath: "data/users.csv"}}}
+
+	fieldErrors := metadata.Validate()
+	assert.Len(t, fieldErrors, 2)
+	assert.Equal(t, "files[0].name", fieldErrors[0].Field)
+	assert.Equal(t, "files[0].mount_path", fieldErrors[1].Field)
+}
+
+func TestValidateFiles(t *testing.T) {
+	metadata := Metadata{Files: []FileInput{
+		{Name: "users.csv", MountPath: "/data/users.csv", Required: true},
+		{Name: "overrides.json", MountPath: "/data/overrides.json"},
+	}}
+
+	assert.Empty(t, metadata.ValidateFiles(map[string][]byte{"users.csv": []byte("id")}))
+
+	fieldErrors := metadata.ValidateFiles(map[string][]byte{"extra.txt": []byte("x")})
+	assert.Len(t, fieldErrors, 2)
+	assert.Equal(t, utility.FieldError{Field: "files.users.csv", Message: "is required"}, fieldErrors[0])
+	assert.Equal(t, "files.extra.txt", fieldErrors[1].Field)
+}
+
+func TestValidateTarget(t *testing.T) {
+	metadata := Metadata{Name: "vacuum", ImageName: "ops-toolbox", Cluster: "production"}
+
+	fieldErrors := metadata.Validate()
+	assert.Len(t, fieldErrors, 1)
+	assert.Equal(t, "cluster", fieldErrors[0].Field)
+}
+
+func TestValidateWebhooksWhileDisabled(t *testing.T) {
+	os.Setenv("PROCTOR_WEBHOOKS_ENABLED", "false")
+	defer os.Unsetenv("PROCTOR_WEBHOOKS_ENABLED")
+	viper.AutomaticEnv()
+
+	metadata := Metadata{Name: "vacuum", ImageName: "ops-toolbox", Webhooks: []string{"https://example.com/hook"}}
+
+	assert.Equal(t, []utility.FieldError{{Field: "webhooks", Message: "are not allowed while webhooks are disabled"}}, metadata.Validate())
+}
+
+func TestValidateWebhooksTargetingPrivateAddresses(t *testing.T) {
+	metadata := Metadata{Name: "vacuum", ImageName: "ops-toolbox", Webhooks: []string{"https://hooks.example.com/proctor", "http://169.254.169.254/latest"}}
+
+	assert.Equal(t, []utility.FieldError{{Field: "webhooks[1]", Message: "must not target a private address"}}, metadata.Validate())
+}
+
+func TestImage(t *testing.T) {
+	digest := "sha256:4bcff63911fcb4448bd4fdacec207030997caf25e9bea4045fa6c8c44de311d1"
+
+	assert.Equal(t, "gcr.io/ops/toolbox:3", Metadata{ImageName: "gcr.io/ops/toolbox:3"}.Image())
+	assert.Equal(t, "gcr.io/ops/toolbox:3@"+digest, Metadata{ImageName: "gcr.io/ops/toolbox:3", ImageDigest: digest}.Image())
+	assert.Equal(t, "gcr.io/ops/toolbox@"+digest, Metadata{ImageName: "gcr.io/ops/toolbox@" + digest, ImageDigest: digest}.Image())
+}
@@ -0,0 +1,41 @@
+package metadata
+
+import (
+	"github.com/gojektech/proctor-engine/ratelimit"
+	"github.com/gojektech/proctor-engine/utility"
+)
+
+type RateLimit struct {
+	Executions    int `json:"executions"`
+	PeriodSeconds int `json:"period_seconds"`
+	Burst         int `json:"burst,omitempty"`
+}
+
+func (rateLimit *RateLimit) Limit() ratelimit.Limit {
+	if rateLimit == nil || rateLimit.PeriodSeconds <= 0 {
+		return ratelimit.Limit{}
+	}
+
+	burst := rateLimit.Burst
+	if burst == 0 {
+		burst = rateLimit.Executions
+	}
+	return ratelimit.Limit{
+		Rate:  float64(rateLimit.Executions) / float64(rateLimit.PeriodSeconds),
+		Burst: burst,
+	}
+}
+
+func (rateLimit RateLimit) Validate() []utility.FieldError {
+	var fieldErrors []utility.FieldError
+	if rateLimit.Executions < 1 {
+		fieldErrors = append(fieldErrors, utility.FieldError{Field: "rate_limit.executions", Message: "must be at least 1"})
+	}
+	if rateLimit.PeriodSeconds < 1 {
+		fieldErrors = append(fieldErrors, utility.FieldError{Field: "rate_limit.period_seconds", Message: "must be at least 1"})
+	}
+	if rateLimit.Burst < 0 {
+		fieldErrors = append(fieldErrors, utility.FieldError{Field: "rate_limit.burst", Message: "must not be negative"})
+	}
+	return fieldErrors
+}
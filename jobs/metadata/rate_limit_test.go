@@ -0,0 +1,26 @@
+package metadata
+
+import (
+	"testing"
+
+	"github.com/gojektech/proctor-engine/ratelimit"
+	"github.com/gojektech/proctor-engine/utility"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitLimit(t *testing.T) {
+	var rateLimit *RateLimit
+	assert.False(t, rateLimit.Limit().Enabled())
+
+	assert.Equal(t, ratelimit.Limit{Rate: 0.5, Burst: 30}, (&RateLimit{Executions: 30, PeriodSeconds: 60}).Limit())
+	assert.Equal(t, ratelimit.Limit{Rate: 0.5, Burst: 5}, (&RateLimit{Executions: 30, PeriodSeconds: 60, Burst: 5}).Limit())
+}
+
+func TestRateLimitValidate(t *testing.T) {
+	assert.Empty(t, RateLimit{Executions: 5, PeriodSeconds: 3600}.Validate())
+	assert.Equal(t, []utility.FieldError{
+		{Field: "rate_limit.executions", Message: "must be at least 1"},
+		{Field: "rate_limit.period_seconds", Message: "must be at least 1"},
+		{Field: "rate_limit.burst", Message: "must not be negative"},
+	}, RateLimit{Burst: -1}.Validate())
+}
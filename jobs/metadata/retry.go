@@ -0,0 +1,63 @@
+package metadata
+
+import (
+	"math"
+	"time"
+
+	"github.com/gojektech/proctor-engine/utility"
+)
+
+const (
+	RetryStrategyNone       = "none"
+	RetryStrategyKubernetes = "kubernetes"
+	RetryStrategyEngine     = "engine"
+)
+
+type RetryPolicy struct {
+	Strategy            string `json:"strategy"`
+	BackoffLimit        int32  `json:"backoff_limit,omitempty"`
+	MaxAttempts         int    `json:"max_attempts,omitempty"`
+	InitialDelaySeconds int    `json:"initial_delay_seconds,omitempty"`
+}
+
+func (retryPolicy *RetryPolicy) KubernetesBackoffLimit() *int32 {
+	if retryPolicy == nil {
+		return nil
+	}
+
+	backoffLimit := int32(0)
+	if retryPolicy.Strategy == RetryStrategyKubernetes {
+		backoffLimit = retryPolicy.BackoffLimit
+	}
+	return &backoffLimit
+}
+
+func (retryPolicy *RetryPolicy) ShouldResubmit(attempt int) bool {
+	return retryPolicy != nil && retryPolicy.Strategy == RetryStrategyEngine && attempt < retryPolicy.MaxAttempts
+}
+
+func (retryPolicy *RetryPolicy) DelayBeforeAttempt(attempt int) time.Duration {
+	initialDelay := time.Duration(retryPolicy.InitialDelaySeconds) * time.Second
+	return initialDelay * time.Duration(math.Pow(2, float64(attempt-2)))
+}
+
+func (retryPolicy RetryPolicy) Validate() []utility.FieldError {
+	var fieldErrors []utility.FieldError
+	switch retryPolicy.Strategy {
+	case RetryStrategyNone:
+	case RetryStrategyKubernetes:
+		if retryPolicy.BackoffLimit < 0 {
+			fieldErrors = append(fieldErrors, utility.FieldError{Field: "retry.backoff_limit", Message: "must not be negative"})
+		}
+	case RetryStrategyEngine:
+		if retryPolicy.MaxAttempts < 1 {
+			fieldErrors = append(fieldErrors, utility.FieldError{Field: "retry.max_attempts", Message: "must be at least 1"})
+		}
+		if retryPolicy.InitialDelaySeconds < 0 {
+			fieldErrors = append(fieldErrors, utility.FieldError{Field: "retry.initial_delay_seconds", Message: "must not be negative"})
+		}
+	default:
+		fieldErrors = append(fieldErrors, utility.FieldError{Field: "retry.strategy", Message: "must be one of none, kubernetes, engine"})
+	}
+	return fieldErrors
+}
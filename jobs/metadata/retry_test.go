@@ -0,0 +1,47 @@
+package metadata
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKubernetesBackoffLimit(t *testing.T) {
+	var noPolicy *RetryPolicy
+	assert.Nil(t, noPolicy.KubernetesBackoffLimit())
+
+	assert.Equal(t, int32(0), *(&RetryPolicy{Strategy: RetryStrategyNone}).KubernetesBackoffLimit())
+	assert.Equal(t, int32(0), *(&RetryPolicy{Strategy: RetryStrategyEngine, MaxAttempts: 3}).KubernetesBackoffLimit())
+	assert.Equal(t, int32(4), *(&RetryPolicy{Strategy: RetryStrategyKubernetes, BackoffLimit: 4}).KubernetesBackoffLimit())
+}
+
+func TestShouldResubmit(t *testing.T) {
+	var noPolicy *RetryPolicy
+	assert.False(t, noPolicy.ShouldResubmit(1))
+
+	retryPolicy := &RetryPolicy{Strategy: RetryStrategyEngine, MaxAttempts: 3}
+	assert.True(t, retryPolicy.ShouldResubmit(1))
+	assert.True(t, retryPolicy.ShouldResubmit(2))
+	assert.False(t, retryPolicy.ShouldResubmit(3))
+
+	assert.False(t, (&RetryPolicy{Strategy: RetryStrategyKubernetes, BackoffLimit: 3}).ShouldResubmit(1))
+}
+
+func TestDelayBeforeAttempt(t *testing.T) {
+	retryPolicy := &RetryPolicy{Strategy: RetryStrategyEngine, MaxAttempts: 4, InitialDelaySeconds: 10}
+
+	assert.Equal(t, 10*time.Second, retryPolicy.DelayBeforeAttempt(2))
+	assert.Equal(t, 20*time.Second, retryPolicy.DelayBeforeAttempt(3))
+	assert.Equal(t, 40*time.Second, retryPolicy.DelayBeforeAttempt(4))
+}
+
+func TestRetryPolicyValidate(t *testing.T) {
+	assert.Empty(t, RetryPolicy{Strategy: RetryStrategyNone}.Validate())
+	assert.Empty(t, RetryPolicy{Strategy: RetryStrategyKubernetes, BackoffLimit: 2}.Validate())
+	assert.Empty(t, RetryPolicy{Strategy: RetryStrategyEngine, MaxAttempts: 3, InitialDelaySeconds: 5}.Validate())
+
+	assert.Equal(t, "retry.strategy", RetryPolicy{Strategy: "sometimes"}.Validate()[0].Field)
+	assert.Equal(t, "retry.backoff_limit", RetryPolicy{Strategy: RetryStrategyKubernetes, BackoffLimit: -1}.Validate()[0].Field)
+	assert.Equal(t, "retry.max_attempts", RetryPolicy{Strategy: RetryStrategyEngine}.Validate()[0].Field)
+}
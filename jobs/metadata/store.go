@@ -2,12 +2,15 @@ package metadata
 
 import (
 	"encoding/json"
+	"errors"
 
 	"github.com/gojektech/proctor-engine/redis"
 )
 
 const JobNameKeySuffix = "-metadata"
 
+var ErrJobNotFound = errors.New("job metadata not found")
+
 type Store interface {
 	CreateOrUpdateJobMetadata(metadata Metadata) error
 	GetAllJobsMetadata() ([]Metadata, error)
@@ -69,6 +72,9 @@ func (store *store) GetAllJobsMetadata() ([]Metadata, error) {
 
 func (store *store) GetJobMetadata(jobName string) (*Metadata, error) {
 	binaryJobMetadata, err := store.redisClient.GET(jobMetadataKey(jobName))
+	if err == redis.ErrNil {
+		return nil, ErrJobNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
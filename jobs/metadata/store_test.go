@@ -141,6 +141,16 @@ func (s *MetadataStoreTestSuite) TestGetJobMetadata() {
 	s.mockRedisClient.AssertExpectations(t)
 }
 
+func (s *MetadataStoreTestSuite) TestGetJobMetadataForUnknownJob() {
+	t := s.T()
+
+	s.mockRedisClient.On("GET", "job1-metadata").Return([]byte{}, redis.ErrNil).Once()
+
+	_, err := s.testMetadataStore.GetJobMetadata("job1")
+	assert.Equal(t, ErrJobNotFound, err)
+	s.mockRedisClient.AssertExpectations(t)
+}
+
 func TestMetadataStoreTestSuite(t *testing.T) {
 	suite.Run(t, new(MetadataStoreTestSuite))
 }
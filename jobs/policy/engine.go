@@ -0,0 +1,164 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/gojektech/proctor-engine/logger"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/checker/decls"
+)
+
+type engine struct {
+	store    Store
+	mutex    sync.Mutex
+	programs map[string]cel.Program
+}
+
+type Engine interface {
+	Rules() ([]Rule, error)
+	Evaluate(context.Context, Input) ([]Denial, error)
+	Admit(context.Context, Input) ([]Denial, error)
+	Release(context.Context, Input)
+}
+
+func NewEngine(store Store) Engine {
+	return &engine{
+		store:    store,
+		programs: map[string]cel.Program{},
+	}
+}
+
+func compile(expression string) (cel.Program, error) {
+	objectType := decls.NewMapType(decls.String, decls.Dyn)
+	env, err := cel.NewEnv(cel.Declarations(
+		decls.NewIdent("request", objectType, nil),
+		decls.NewIdent("user", objectType, nil),
+		decls.NewIdent("job", objectType, nil),
+		decls.NewIdent("time", objectType, nil),
+		decls.NewIdent("approved", decls.Bool, nil),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, issues := env.Parse(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, issues.Err()
+	}
+	checked, issues := env.Check(parsed)
+	if issues != nil && issues.Err() != nil {
+		return nil, issues.Err()
+	}
+	return env.Program(checked)
+}
+
+func (engine *engine) program(expression string) (cel.Program, error) {
+	engine.mutex.Lock()
+	defer engine.mutex.Unlock()
+
+	if program, ok := engine.programs[expression]; ok {
+		return program, nil
+	}
+	program, err := compile(expression)
+	if err != nil {
+		return nil, err
+	}
+	engine.programs[expression] = program
+	return program, nil
+}
+
+func (engine *engine) Rules() ([]Rule, error) {
+	rules, err := ConfiguredRules()
+	if err != nil {
+		return nil, err
+	}
+
+	storedRules, err := engine.store.GetRules()
+	if err != nil {
+		return nil, err
+	}
+	return append(rules, storedRules...), nil
+}
+
+// Evaluate checks an execution against the rules without counting it.
+func (engine *engine) Evaluate(ctx context.Context, input Input) ([]Denial, error) {
+	rules, err := engine.Rules()
+	if err != nil {
+		return nil, err
+	}
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	executionsInLastHour, err := engine.store.ExecutionsInLastHour(input.usageUser(), input.Time)
+	if err != nil {
+		return nil, err
+	}
+	return engine.deny(ctx, rules, input.activation(executionsInLastHour)), nil
+}
+
+// Admit counts an execution against the user's usage before checking the
+// rules, so concurrent executions cannot all see the same count. The count is
+// given back when the execution is denied.
+func (engine *engine) Admit(ctx context.Context, input Input) ([]Denial, error) {
+	rules, err := engine.Rules()
+	if err != nil {
+		return nil, err
+	}
+	executions, err := engine.store.ReserveExecution(input.usageUser(), input.Time)
+	if err != nil {
+		return nil, err
+	}
+
+	denials := engine.deny(ctx, rules, input.activation(executions-1))
+	if len(denials) > 0 {
+		engine.Release(ctx, input)
+	}
+	return denials, nil
+}
+
+func (engine *engine) deny(ctx context.Context, rules []Rule, activation map[string]interface{}) []Denial {
+	log := logger.FromContext(ctx)
+
+	var denials []Denial
+	for _, rule := range rules {
+		denied, err := engine.evaluate(rule, activation)
+		if err != nil {
+			log.WithField("rule", rule.Name).Error("Error evaluating policy rule", err.Error())
+
+			denials = append(denials, Denial{Rule: rule.Name, Reason: "rule could not be evaluated"})
+			continue
+		}
+		if denied {
+			denials = append(denials, Denial{Rule: rule.Name, Reason: rule.Reason})
+		}
+	}
+	return denials
+}
+
+func (engine *engine) evaluate(rule Rule, activation map[string]interface{}) (bool, error) {
+	program, err := engine.program(rule.Deny)
+	if err != nil {
+		return false, err
+	}
+
+	result, _, err := program.Eval(activation)
+	if err != nil {
+		return false, err
+	}
+	denied, ok := result.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("deny expression evaluated to %v instead of a boolean", result.Value())
+	}
+	return denied, nil
+}
+
+// Release gives back an execution counted by Admit that did not start.
+func (engine *engine) Release(ctx context.Context, input Input) {
+	if err := engine.store.ReleaseExecution(input.usageUser(), input.Time); err != nil {
+		logger.FromContext(ctx).Error("Error releasing execution from policy usage", err.Error())
+	}
+}
@@ -0,0 +1,30 @@
+package policy
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockEngine struct {
+	mock.Mock
+}
+
+func (m *MockEngine) Rules() ([]Rule, error) {
+	args := m.Called()
+	return args.Get(0).([]Rule), args.Error(1)
+}
+
+func (m *MockEngine) Evaluate(ctx context.Context, input Input) ([]Denial, error) {
+	args := m.Called(ctx, input)
+	return args.Get(0).([]Denial), args.Error(1)
+}
+
+func (m *MockEngine) Admit(ctx context.Context, input Input) ([]Denial, error) {
+	args := m.Called(ctx, input)
+	return args.Get(0).([]Denial), args.Error(1)
+}
+
+func (m *MockEngine) Release(ctx context.Context, input Input) {
+	m.Called(ctx, input)
+}
@@ -0,0 +1,211 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gojektech/proctor-engine/jobs/metadata"
+	"github.com/gojektech/proctor-engine/utility"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type EngineTestSuite struct {
+	suite.Suite
+	mockStore  *MockStore
+	testEngine Engine
+	now        time.Time
+}
+
+func (s *EngineTestSuite) SetupTest() {
+	s.mockStore = &MockStore{}
+	s.testEngine = NewEngine(s.mockStore)
+	s.now = time.Date(2018, time.November, 12, 23, 30, 0, 0, time.UTC)
+}
+
+func (s *EngineTestSuite) input() Input {
+	return Input{
+		JobName:   "refund",
+		Args:      map[string]string{"amount": "25000"},
+		Namespace: "prod",
+		User:      "jane@example.com",
+		Metadata:  &metadata.Metadata{Name: "refund", ImageName: "refunds", RequiredApprovals: 0},
+		Time:      s.now,
+	}
+}
+
+func (s *EngineTestSuite) TestEvaluateWithoutRules() {
+	t := s.T()
+
+	s.mockStore.On("GetRules").Return([]Rule(nil), nil).Once()
+
+	denials, err := s.testEngine.Evaluate(context.Background(), s.input())
+	assert.NoError(t, err)
+	assert.Empty(t, denials)
+
+	s.mockStore.AssertNotCalled(t, "ExecutionsInLastHour", "jane@example.com", s.now)
+}
+
+func (s *EngineTestSuite) TestEvaluate() {
+	t := s.T()
+
+	rules := []Rule{
+		{Name: "quiet-hours", Deny: `request.namespace == "prod" && (time.hour >= 22 || time.hour < 6)`, Reason: "no prod executions between 22:00 and 06:00"},
+		{Name: "refund-limit", Deny: `int(request.args.amount) >= 10000 && job.required_approvals == 0`, Reason: "amount must be below 10000 unless approved"},
+		{Name: "hourly-limit", Deny: `user.executions_last_hour >= 5`, Reason: "at most 5 executions per user per hour"},
+	}
+	s.mockStore.On("GetRules").Return(rules, nil).Once()
+	s.mockStore.On("ExecutionsInLastHour", "jane@example.com", s.now).Return(2, nil).Once()
+
+	denials, err := s.testEngine.Evaluate(context.Background(), s.input())
+	assert.NoError(t, err)
+	assert.Equal(t, []Denial{
+		{Rule: "quiet-hours", Reason: "no prod executions between 22:00 and 06:00"},
+		{Rule: "refund-limit", Reason: "amount must be below 10000 unless approved"},
+	}, denials)
+}
+
+func (s *EngineTestSuite) TestEvaluateWithConfiguredRules() {
+	t := s.T()
+
+	os.Setenv("PROCTOR_POLICY_RULES", `[{"name":"hourly-limit","deny":"user.executions_last_hour >= 5","reason":"at most 5 executions per user per hour"}]`)
+	defer os.Unsetenv("PROCTOR_POLICY_RULES")
+	viper.AutomaticEnv()
+
+	s.mockStore.On("GetRules").Return([]Rule(nil), nil).Once()
+	s.mockStore.On("ExecutionsInLastHour", "jane@example.com", s.now).Return(5, nil).Once()
+
+	denials, err := s.testEngine.Evaluate(context.Background(), s.input())
+	assert.NoError(t, err)
+	assert.Equal(t, []Denial{{Rule: "hourly-limit", Reason: "at most 5 executions per user per hour"}}, denials)
+}
+
+func (s *EngineTestSuite) TestEvaluateDeniesWhenRuleCannotBeEvaluated() {
+	t := s.T()
+
+	rules := []Rule{{Name: "missing-arg", Deny: `int(request.args.limit) > 10`, Reason: "limit too high"}}
+	s.mockStore.On("GetRules").Return(rules, nil).Once()
+	s.mockStore.On("ExecutionsInLastHour", "jane@example.com", s.now).Return(0, nil).Once()
+
+	denials, err := s.testEngine.Evaluate(context.Background(), s.input())
+	assert.NoError(t, err)
+	assert.Equal(t, []Denial{{Rule: "missing-arg", Reason: "rule could not be evaluated"}}, denials)
+}
+
+func (s *EngineTestSuite) TestEvaluateOnStoreFailure() {
+	t := s.T()
+
+	s.mockStore.On("GetRules").Return([]Rule(nil), errors.New("redis down")).Once()
+
+	_, err := s.testEngine.Evaluate(context.Background(), s.input())
+	assert.Error(t, err)
+}
+
+func (s *EngineTestSuite) TestEvaluateWithApproval() {
+	t := s.T()
+
+	rules := []Rule{{Name: "prod-needs-approval", Deny: `request.namespace == "prod" && !approved`, Reason: "prod executions must be approved"}}
+	s.mockStore.On("GetRules").Return(rules, nil)
+	s.mockStore.On("ExecutionsInLastHour", "jane@example.com", s.now).Return(0, nil)
+
+	denials, err := s.testEngine.Evaluate(context.Background(), s.input())
+	assert.NoError(t, err)
+	assert.Equal(t, []Denial{{Rule: "prod-needs-approval", Reason: "prod executions must be approved"}}, denials)
+
+	input := s.input()
+	input.Approvers = []string{"joe@example.com"}
+	input.Approved = true
+	denials, err = s.testEngine.Evaluate(context.Background(), input)
+	assert.NoError(t, err)
+	assert.Empty(t, denials)
+}
+
+func (s *EngineTestSuite) TestAdmitCountsExecution() {
+	t := s.T()
+
+	rules := []Rule{{Name: "hourly-limit", Deny: `user.executions_last_hour >= 5`, Reason: "at most 5 executions per user per hour"}}
+	s.mockStore.On("GetRules").Return(rules, nil).Once()
+	s.mockStore.On("ReserveExecution", "jane@example.com", s.now).Return(5, nil).Once()
+
+	denials, err := s.testEngine.Admit(context.Background(), s.input())
+	assert.NoError(t, err)
+	assert.Empty(t, denials)
+
+	s.mockStore.AssertExpectations(t)
+	s.mockStore.AssertNotCalled(t, "ReleaseExecution", "jane@example.com", s.now)
+}
+
+func (s *EngineTestSuite) TestAdmitGivesBackDeniedExecution() {
+	t := s.T()
+
+	rules := []Rule{{Name: "hourly-limit", Deny: `user.executions_last_hour >= 5`, Reason: "at most 5 executions per user per hour"}}
+	s.mockStore.On("GetRules").Return(rules, nil).Once()
+	s.mockStore.On("ReserveExecution", "jane@example.com", s.now).Return(6, nil).Once()
+	s.mockStore.On("ReleaseExecution", "jane@example.com", s.now).Return(nil).Once()
+
+	denials, err := s.testEngine.Admit(context.Background(), s.input())
+	assert.NoError(t, err)
+	assert.Equal(t, []Denial{{Rule: "hourly-limit", Reason: "at most 5 executions per user per hour"}}, denials)
+
+	s.mockStore.AssertExpectations(t)
+}
+
+func (s *EngineTestSuite) TestAdmitWithoutUserCountsAnonymousUsage() {
+	t := s.T()
+
+	rules := []Rule{{Name: "hourly-limit", Deny: `user.executions_last_hour >= 5`, Reason: "at most 5 executions per user per hour"}}
+	s.mockStore.On("GetRules").Return(rules, nil).Once()
+	s.mockStore.On("ReserveExecution", AnonymousUser, s.now).Return(6, nil).Once()
+	s.mockStore.On("ReleaseExecution", AnonymousUser, s.now).Return(nil).Once()
+
+	input := s.input()
+	input.User = ""
+	denials, err := s.testEngine.Admit(context.Background(), input)
+	assert.NoError(t, err)
+	assert.Equal(t, []Denial{{Rule: "hourly-limit", Reason: "at most 5 executions per user per hour"}}, denials)
+
+	s.mockStore.AssertExpectations(t)
+}
+
+func (s *EngineTestSuite) TestAdmitOnStoreFailure() {
+	t := s.T()
+
+	s.mockStore.On("GetRules").Return([]Rule(nil), nil).Once()
+	s.mockStore.On("ReserveExecution", "jane@example.com", s.now).Return(0, errors.New("redis down")).Once()
+
+	_, err := s.testEngine.Admit(context.Background(), s.input())
+	assert.Error(t, err)
+}
+
+func (s *EngineTestSuite) TestRelease() {
+	t := s.T()
+
+	s.mockStore.On("ReleaseExecution", "jane@example.com", s.now).Return(nil).Once()
+
+	s.testEngine.Release(context.Background(), s.input())
+
+	s.mockStore.AssertExpectations(t)
+}
+
+func TestEngineTestSuite(t *testing.T) {
+	suite.Run(t, new(EngineTestSuite))
+}
+
+func fields(fieldErrors []utility.FieldError) []string {
+	var names []string
+	for _, fieldError := range fieldErrors {
+		names = append(names, fieldError.Field)
+	}
+	return names
+}
+
+func TestRuleValidate(t *testing.T) {
+	assert.Empty(t, Rule{Name: "quiet-hours", Deny: "time.hour >= 22", Reason: "too late"}.Validate())
+	assert.Equal(t, []string{"name", "reason", "deny"}, fields(Rule{}.Validate()))
+	assert.Equal(t, []string{"deny"}, fields(Rule{Name: "broken", Deny: "time.hour >=", Reason: "broken"}.Validate()))
+	assert.Equal(t, []string{"deny"}, fields(Rule{Name: "unknown", Deny: "weather.raining", Reason: "unknown"}.Validate()))
+}
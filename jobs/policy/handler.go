@@ -0,0 +1,125 @@
+package policy
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gojektech/proctor-engine/config"
+	"github.com/gojektech/proctor-engine/logger"
+	"github.com/gojektech/proctor-engine/utility"
+)
+
+type policyHandler struct {
+	store  Store
+	engine Engine
+}
+
+type PolicyHandler interface {
+	HandleSubmission() http.HandlerFunc
+	HandleDisplay() http.HandlerFunc
+}
+
+func NewPolicyHandler(store Store, engine Engine) PolicyHandler {
+	return &policyHandler{
+		store:  store,
+		engine: engine,
+	}
+}
+
+// authorize lets policy submissions through only with the configured admin
+// token; submissions are disabled when no token is configured.
+func authorize(w http.ResponseWriter, req *http.Request) bool {
+	adminToken := config.PolicyAdminToken()
+	if adminToken == "" {
+		utility.WriteError(w, req, http.StatusForbidden, utility.ErrCodeForbidden, "policy submission is disabled")
+		return false
+	}
+
+	authorization := req.Header.Get("Authorization")
+	if !strings.HasPrefix(authorization, "Bearer ") {
+		utility.WriteError(w, req, http.StatusUnauthorized, utility.ErrCodeUnauthorized, "bearer token is required")
+		return false
+	}
+	token := strings.TrimPrefix(authorization, "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) != 1 {
+		utility.WriteError(w, req, http.StatusUnauthorized, utility.ErrCodeUnauthorized, "invalid bearer token")
+		return false
+	}
+	return true
+}
+
+func (policyHandler *policyHandler) HandleSubmission() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		log := logger.FromContext(req.Context())
+		if !authorize(w, req) {
+			log.Error("Unauthorized policy submission")
+			return
+		}
+
+		var rules []Rule
+		err := json.NewDecoder(req.Body).Decode(&rules)
+		defer req.Body.Close()
+		if err != nil {
+			log.Error("Error parsing request body", err.Error())
+
+			utility.WriteError(w, req, http.StatusBadRequest, utility.ErrCodeMalformedRequest, utility.ClientError)
+			return
+		}
+
+		var fieldErrors []utility.FieldError
+		names := map[string]bool{}
+		for i, rule := range rules {
+			for _, fieldError := range rule.Validate() {
+				fieldError.Field = fmt.Sprintf("[%d].%s", i, fieldError.Field)
+				fieldErrors = append(fieldErrors, fieldError)
+			}
+			if names[rule.Name] {
+				fieldErrors = append(fieldErrors, utility.FieldError{Field: fmt.Sprintf("[%d].name", i), Message: "must be unique"})
+			}
+			names[rule.Name] = true
+		}
+		if len(fieldErrors) > 0 {
+			utility.WriteValidationError(w, req, fieldErrors)
+			return
+		}
+
+		if err := policyHandler.store.SaveRules(rules); err != nil {
+			log.Error("Error saving policy rules", err.Error())
+
+			utility.WriteError(w, req, http.StatusServiceUnavailable, utility.ErrCodeStoreUnavailable, "unable to save policy rules")
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+	}
+}
+
+func (policyHandler *policyHandler) HandleDisplay() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		log := logger.FromContext(req.Context())
+
+		rules, err := policyHandler.engine.Rules()
+		if err != nil {
+			log.Error("Error fetching policy rules", err.Error())
+
+			utility.WriteError(w, req, http.StatusServiceUnavailable, utility.ErrCodeStoreUnavailable, "unable to fetch policy rules")
+			return
+		}
+		if rules == nil {
+			rules = []Rule{}
+		}
+
+		rulesInJSON, err := json.Marshal(rules)
+		if err != nil {
+			log.Error("Error marshalling policy rules in json", err.Error())
+
+			utility.WriteError(w, req, http.StatusInternalServerError, utility.ErrCodeInternal, utility.ServerError)
+			return
+		}
+
+		w.Write(rulesInJSON)
+	}
+}
@@ -0,0 +1,146 @@
+package policy
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gojektech/proctor-engine/utility"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type PolicyHandlerTestSuite struct {
+	suite.Suite
+	mockStore         *MockStore
+	mockEngine        *MockEngine
+	testPolicyHandler PolicyHandler
+}
+
+func (s *PolicyHandlerTestSuite) SetupTest() {
+	os.Setenv("PROCTOR_POLICY_ADMIN_TOKEN", "policy-admin-token")
+	viper.AutomaticEnv()
+
+	s.mockStore = &MockStore{}
+	s.mockEngine = &MockEngine{}
+	s.testPolicyHandler = NewPolicyHandler(s.mockStore, s.mockEngine)
+}
+
+func (s *PolicyHandlerTestSuite) TearDownTest() {
+	os.Unsetenv("PROCTOR_POLICY_ADMIN_TOKEN")
+}
+
+func (s *PolicyHandlerTestSuite) submitWithToken(rules interface{}, token string) *httptest.ResponseRecorder {
+	requestBody, err := json.Marshal(rules)
+	assert.NoError(s.T(), err)
+
+	req := httptest.NewRequest("POST", "/jobs/policies", bytes.NewReader(requestBody))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	responseRecorder := httptest.NewRecorder()
+	s.testPolicyHandler.HandleSubmission()(responseRecorder, req)
+	return responseRecorder
+}
+
+func (s *PolicyHandlerTestSuite) submit(rules interface{}) *httptest.ResponseRecorder {
+	return s.submitWithToken(rules, "policy-admin-token")
+}
+
+func (s *PolicyHandlerTestSuite) TestSuccessfulSubmission() {
+	t := s.T()
+
+	rules := []Rule{{Name: "quiet-hours", Deny: "time.hour >= 22", Reason: "too late"}}
+	s.mockStore.On("SaveRules", rules).Return(nil).Once()
+
+	responseRecorder := s.submit(rules)
+
+	s.mockStore.AssertExpectations(t)
+	assert.Equal(t, http.StatusCreated, responseRecorder.Code)
+}
+
+func (s *PolicyHandlerTestSuite) TestSubmissionWithInvalidRules() {
+	t := s.T()
+
+	rules := []Rule{
+		{Name: "quiet-hours", Deny: "time.hour >=", Reason: "too late"},
+		{Name: "quiet-hours", Deny: "time.hour < 6", Reason: "too early"},
+	}
+
+	responseRecorder := s.submit(rules)
+
+	s.mockStore.AssertNotCalled(t, "SaveRules", mock.Anything)
+	assert.Equal(t, http.StatusUnprocessableEntity, responseRecorder.Code)
+
+	var errorResponse utility.ErrorResponse
+	assert.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &errorResponse))
+	assert.Equal(t, []string{"[0].deny", "[1].name"}, fields(errorResponse.Error.Details))
+}
+
+func (s *PolicyHandlerTestSuite) TestSubmissionOnStoreFailure() {
+	t := s.T()
+
+	s.mockStore.On("SaveRules", mock.Anything).Return(errors.New("redis down")).Once()
+
+	responseRecorder := s.submit([]Rule{{Name: "quiet-hours", Deny: "time.hour >= 22", Reason: "too late"}})
+
+	assert.Equal(t, http.StatusServiceUnavailable, responseRecorder.Code)
+}
+
+func (s *PolicyHandlerTestSuite) TestSubmissionWithoutToken() {
+	t := s.T()
+
+	responseRecorder := s.submitWithToken([]Rule{{Name: "quiet-hours", Deny: "time.hour >= 22", Reason: "too late"}}, "")
+
+	s.mockStore.AssertNotCalled(t, "SaveRules", mock.Anything)
+	assert.Equal(t, http.StatusUnauthorized, responseRecorder.Code)
+}
+
+func (s *PolicyHandlerTestSuite) TestSubmissionWithWrongToken() {
+	t := s.T()
+
+	responseRecorder := s.submitWithToken([]Rule{{Name: "quiet-hours", Deny: "time.hour >= 22", Reason: "too late"}}, "guess")
+
+	s.mockStore.AssertNotCalled(t, "SaveRules", mock.Anything)
+	assert.Equal(t, http.StatusUnauthorized, responseRecorder.Code)
+
+	var errorResponse utility.ErrorResponse
+	assert.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &errorResponse))
+	assert.Equal(t, utility.ErrCodeUnauthorized, errorResponse.Error.Code)
+}
+
+func (s *PolicyHandlerTestSuite) TestSubmissionWithoutConfiguredToken() {
+	t := s.T()
+
+	os.Unsetenv("PROCTOR_POLICY_ADMIN_TOKEN")
+
+	responseRecorder := s.submit([]Rule{{Name: "quiet-hours", Deny: "time.hour >= 22", Reason: "too late"}})
+
+	s.mockStore.AssertNotCalled(t, "SaveRules", mock.Anything)
+	assert.Equal(t, http.StatusForbidden, responseRecorder.Code)
+}
+
+func (s *PolicyHandlerTestSuite) TestDisplay() {
+	t := s.T()
+
+	rules := []Rule{{Name: "quiet-hours", Deny: "time.hour >= 22", Reason: "too late"}}
+	s.mockEngine.On("Rules").Return(rules, nil).Once()
+
+	responseRecorder := httptest.NewRecorder()
+	s.testPolicyHandler.HandleDisplay()(responseRecorder, httptest.NewRequest("GET", "/jobs/policies", nil))
+
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+	expectedBody, err := json.Marshal(rules)
+	assert.NoError(t, err)
+	assert.Equal(t, string(expectedBody), responseRecorder.Body.String())
+}
+
+func TestPolicyHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(PolicyHandlerTestSuite))
+}
@@ -0,0 +1,152 @@
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gojektech/proctor-engine/config"
+	"github.com/gojektech/proctor-engine/jobs/metadata"
+	"github.com/gojektech/proctor-engine/utility"
+)
+
+type Rule struct {
+	Name   string `json:"name"`
+	Deny   string `json:"deny"`
+	Reason string `json:"reason"`
+}
+
+type Denial struct {
+	Rule   string `json:"rule"`
+	Reason string `json:"reason"`
+}
+
+// AnonymousUser is the usage bucket shared by executions requested without an
+// Email-Id, so leaving the header out cannot escape per-user limits.
+const AnonymousUser = "anonymous"
+
+type Input struct {
+	JobName   string
+	Args      map[string]string
+	Cluster   string
+	Namespace string
+	Files     []string
+	User      string
+	Approvers []string
+	Approved  bool
+	Metadata  *metadata.Metadata
+	Time      time.Time
+}
+
+type DeniedError struct {
+	Denials []Denial
+}
+
+func (err *DeniedError) Error() string {
+	reasons := make([]string, len(err.Denials))
+	for i, denial := range err.Denials {
+		reasons[i] = fmt.Sprintf("%s: %s", denial.Rule, denial.Reason)
+	}
+	return fmt.Sprintf("execution denied by policy (%s)", strings.Join(reasons, ", "))
+}
+
+func (err *DeniedError) Details() []utility.FieldError {
+	details := make([]utility.FieldError, len(err.Denials))
+	for i, denial := range err.Denials {
+		details[i] = utility.FieldError{Field: denial.Rule, Message: denial.Reason}
+	}
+	return details
+}
+
+func ConfiguredRules() ([]Rule, error) {
+	var rules []Rule
+	if config.PolicyRules() == "" {
+		return rules, nil
+	}
+	if err := json.Unmarshal([]byte(config.PolicyRules()), &rules); err != nil {
+		return nil, fmt.Errorf("invalid PROCTOR_POLICY_RULES: %s", err)
+	}
+	return rules, nil
+}
+
+func (rule Rule) Validate() []utility.FieldError {
+	var fieldErrors []utility.FieldError
+	if rule.Name == "" {
+		fieldErrors = append(fieldErrors, utility.FieldError{Field: "name", Message: "is required"})
+	}
+	if rule.Reason == "" {
+		fieldErrors = append(fieldErrors, utility.FieldError{Field: "reason", Message: "is required"})
+	}
+	if rule.Deny == "" {
+		fieldErrors = append(fieldErrors, utility.FieldError{Field: "deny", Message: "is required"})
+	} else if _, err := compile(rule.Deny); err != nil {
+		fieldErrors = append(fieldErrors, utility.FieldError{Field: "deny", Message: err.Error()})
+	}
+	return fieldErrors
+}
+
+func (input Input) usageUser() string {
+	if input.User == "" {
+		return AnonymousUser
+	}
+	return input.User
+}
+
+func location() *time.Location {
+	if location, err := time.LoadLocation(config.PolicyTimezone()); err == nil {
+		return location
+	}
+	return time.UTC
+}
+
+func (input Input) activation(executionsInLastHour int) map[string]interface{} {
+	args := map[string]interface{}{}
+	for key, value := range input.Args {
+		args[key] = value
+	}
+	files := []interface{}{}
+	for _, file := range input.Files {
+		files = append(files, file)
+	}
+	approvers := []interface{}{}
+	for _, approver := range input.Approvers {
+		approvers = append(approvers, approver)
+	}
+
+	job := map[string]interface{}{}
+	if input.Metadata != nil {
+		job = map[string]interface{}{
+			"name":               input.Metadata.Name,
+			"image":              input.Metadata.Image(),
+			"cluster":            input.Metadata.Cluster,
+			"namespace":          input.Metadata.Namespace,
+			"required_approvals": int64(input.Metadata.RequiredApprovals),
+			"max_concurrent":     int64(input.Metadata.MaxConcurrent),
+		}
+	}
+
+	now := input.Time.In(location())
+	return map[string]interface{}{
+		"request": map[string]interface{}{
+			"job_name":    input.JobName,
+			"args":        args,
+			"cluster":     input.Cluster,
+			"namespace":   input.Namespace,
+			"files":       files,
+			"approved_by": approvers,
+		},
+		"user": map[string]interface{}{
+			"email":                input.User,
+			"executions_last_hour": int64(executionsInLastHour),
+		},
+		"job": job,
+		"time": map[string]interface{}{
+			"hour":    int64(now.Hour()),
+			"minute":  int64(now.Minute()),
+			"weekday": int64(now.Weekday()),
+			"unix":    now.Unix(),
+		},
+		"approved": input.Approved,
+	}
+}
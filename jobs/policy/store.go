@@ -0,0 +1,124 @@
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gojektech/proctor-engine/redis"
+	"github.com/gojektech/proctor-engine/utility"
+)
+
+const RulesKey = "proctor-policy-rules"
+
+const usageWindow = time.Hour
+
+// Usage is a sorted set of executions scored by the millisecond they were
+// reserved at, so counts cover the hour before each request rather than the
+// clock hour.
+const countExecutionsScript = `
+redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", ARGV[1])
+return redis.call("ZCARD", KEYS[1])
+`
+
+const reserveExecutionScript = `
+redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", ARGV[1])
+redis.call("ZADD", KEYS[1], ARGV[2], ARGV[3])
+redis.call("EXPIRE", KEYS[1], ARGV[4])
+return redis.call("ZCARD", KEYS[1])
+`
+
+const releaseExecutionScript = `
+local reserved = redis.call("ZRANGEBYSCORE", KEYS[1], ARGV[1], ARGV[1], "LIMIT", 0, 1)
+if reserved[1] then
+	return redis.call("ZREM", KEYS[1], reserved[1])
+end
+return 0
+`
+
+type Store interface {
+	GetRules() ([]Rule, error)
+	SaveRules([]Rule) error
+	ExecutionsInLastHour(string, time.Time) (int, error)
+	ReserveExecution(string, time.Time) (int, error)
+	ReleaseExecution(string, time.Time) error
+}
+
+type store struct {
+	redisClient redis.Client
+}
+
+func NewStore(redisClient redis.Client) Store {
+	return &store{
+		redisClient: redisClient,
+	}
+}
+
+func usageKey(user string) string {
+	return "proctor-policy-executions-" + user
+}
+
+func usageScore(at time.Time) int64 {
+	return at.UnixNano() / int64(time.Millisecond)
+}
+
+func (store *store) GetRules() ([]Rule, error) {
+	binaryRules, err := store.redisClient.GET(RulesKey)
+	if err == redis.ErrNil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []Rule
+	err = json.Unmarshal(binaryRules, &rules)
+	return rules, err
+}
+
+func (store *store) SaveRules(rules []Rule) error {
+	binaryRules, err := json.Marshal(rules)
+	if err != nil {
+		return err
+	}
+
+	return store.redisClient.SET(RulesKey, binaryRules)
+}
+
+func (store *store) ExecutionsInLastHour(user string, at time.Time) (int, error) {
+	reply, err := store.redisClient.EVAL(countExecutionsScript, 1, usageKey(user), usageScore(at.Add(-usageWindow)))
+	if err != nil {
+		return 0, err
+	}
+
+	count, ok := reply.(int64)
+	if !ok {
+		return 0, fmt.Errorf("unexpected reply %v counting executions", reply)
+	}
+	return int(count), nil
+}
+
+func (store *store) ReserveExecution(user string, at time.Time) (int, error) {
+	id, err := utility.RandomID()
+	if err != nil {
+		return 0, err
+	}
+	member := fmt.Sprintf("%d-%s", at.UnixNano(), id)
+
+	reply, err := store.redisClient.EVAL(reserveExecutionScript, 1, usageKey(user), usageScore(at.Add(-usageWindow)), usageScore(at), member, int(usageWindow.Seconds()))
+	if err != nil {
+		return 0, err
+	}
+
+	count, ok := reply.(int64)
+	if !ok {
+		return 0, fmt.Errorf("unexpected reply %v counting execution", reply)
+	}
+	return int(count), nil
+}
+
+// ReleaseExecution removes an execution reserved at the given time.
+func (store *store) ReleaseExecution(user string, at time.Time) error {
+	_, err := store.redisClient.EVAL(releaseExecutionScript, 1, usageKey(user), usageScore(at))
+	return err
+}
@@ -0,0 +1,36 @@
+package policy
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockStore struct {
+	mock.Mock
+}
+
+func (m *MockStore) GetRules() ([]Rule, error) {
+	args := m.Called()
+	return args.Get(0).([]Rule), args.Error(1)
+}
+
+func (m *MockStore) SaveRules(rules []Rule) error {
+	args := m.Called(rules)
+	return args.Error(0)
+}
+
+func (m *MockStore) ExecutionsInLastHour(user string, at time.Time) (int, error) {
+	args := m.Called(user, at)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockStore) ReserveExecution(user string, at time.Time) (int, error) {
+	args := m.Called(user, at)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockStore) ReleaseExecution(user string, at time.Time) error {
+	args := m.Called(user, at)
+	return args.Error(0)
+}
@@ -0,0 +1,91 @@
+package policy
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gojektech/proctor-engine/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type PolicyStoreTestSuite struct {
+	suite.Suite
+	mockRedisClient *redis.MockClient
+	testStore       Store
+}
+
+func (s *PolicyStoreTestSuite) SetupTest() {
+	s.mockRedisClient = &redis.MockClient{}
+	s.testStore = NewStore(s.mockRedisClient)
+}
+
+func (s *PolicyStoreTestSuite) TestSaveAndGetRules() {
+	t := s.T()
+
+	rules := []Rule{{Name: "quiet-hours", Deny: "time.hour >= 22", Reason: "too late"}}
+	binaryRules, err := json.Marshal(rules)
+	assert.NoError(t, err)
+
+	s.mockRedisClient.On("SET", RulesKey, binaryRules).Return(nil).Once()
+	s.mockRedisClient.On("GET", RulesKey).Return(binaryRules, nil).Once()
+
+	assert.NoError(t, s.testStore.SaveRules(rules))
+	storedRules, err := s.testStore.GetRules()
+	assert.NoError(t, err)
+	assert.Equal(t, rules, storedRules)
+
+	s.mockRedisClient.AssertExpectations(t)
+}
+
+func (s *PolicyStoreTestSuite) TestGetRulesWhenNoneStored() {
+	t := s.T()
+
+	s.mockRedisClient.On("GET", RulesKey).Return([]byte(nil), redis.ErrNil).Once()
+
+	rules, err := s.testStore.GetRules()
+	assert.NoError(t, err)
+	assert.Empty(t, rules)
+}
+
+func (s *PolicyStoreTestSuite) TestExecutionsInLastHour() {
+	t := s.T()
+
+	at := time.Unix(7200, 0)
+	s.mockRedisClient.On("EVAL", countExecutionsScript, 1, "proctor-policy-executions-jane@example.com", int64(3600*1000)).Return(int64(3), nil).Once()
+
+	count, err := s.testStore.ExecutionsInLastHour("jane@example.com", at)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+}
+
+func (s *PolicyStoreTestSuite) TestReserveExecution() {
+	t := s.T()
+
+	s.mockRedisClient.On("EVAL", reserveExecutionScript, 1, "proctor-policy-executions-jane@example.com", int64(3600*1000), int64(7200*1000), mock.MatchedBy(func(member string) bool {
+		return strings.HasPrefix(member, "7200000000000-")
+	}), 3600).Return(int64(3), nil).Once()
+
+	count, err := s.testStore.ReserveExecution("jane@example.com", time.Unix(7200, 0))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	s.mockRedisClient.AssertExpectations(t)
+}
+
+func (s *PolicyStoreTestSuite) TestReleaseExecution() {
+	t := s.T()
+
+	s.mockRedisClient.On("EVAL", releaseExecutionScript, 1, "proctor-policy-executions-jane@example.com", int64(7200*1000)).Return(int64(1), nil).Once()
+
+	assert.NoError(t, s.testStore.ReleaseExecution("jane@example.com", time.Unix(7200, 0)))
+
+	s.mockRedisClient.AssertExpectations(t)
+}
+
+func TestPolicyStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(PolicyStoreTestSuite))
+}
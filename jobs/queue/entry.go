@@ -0,0 +1,70 @@
+package queue
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	StatusQueued     = "QUEUED"
+	StatusDispatched = "DISPATCHED"
+	StatusFailed     = "FAILED"
+	StatusCancelled  = "CANCELLED"
+)
+
+var ErrConcurrencyLimitReached = errors.New("concurrency limit reached")
+
+type Limits struct {
+	Job    int
+	Global int
+}
+
+func (limits Limits) Enabled() bool {
+	return limits.Job > 0 || limits.Global > 0
+}
+
+type Entry struct {
+	ID             string            `json:"queue_id"`
+	JobName        string            `json:"job_name"`
+	Args           map[string]string `json:"args,omitempty"`
+	CallbackURL    string            `json:"callback_url,omitempty"`
+	Cluster        string            `json:"cluster,omitempty"`
+	Namespace      string            `json:"namespace,omitempty"`
+	Files          map[string][]byte `json:"files,omitempty"`
+	SensitiveFiles map[string][]byte `json:"-"`
+	SealedFiles    []byte            `json:"sealed_files,omitempty"`
+	Status         string            `json:"status"`
+	ExecutionName  string            `json:"execution_name,omitempty"`
+	EnqueuedAt     time.Time         `json:"enqueued_at"`
+	DispatchedAt   *time.Time        `json:"dispatched_at,omitempty"`
+}
+
+type Position struct {
+	ID            string  `json:"queue_id"`
+	JobName       string  `json:"job_name"`
+	Status        string  `json:"status"`
+	Position      int     `json:"position"`
+	ETASeconds    float64 `json:"eta_seconds"`
+	ExecutionName string  `json:"execution_name,omitempty"`
+}
+
+type QueuedError struct {
+	Position Position
+}
+
+func (err *QueuedError) Error() string {
+	return fmt.Sprintf("execution of %s queued at position %d", err.Position.JobName, err.Position.Position)
+}
+
+func EstimatedWait(position int, jobLimit int, estimatedDuration time.Duration) time.Duration {
+	if position <= 0 || estimatedDuration <= 0 {
+		return 0
+	}
+	if jobLimit < 1 {
+		jobLimit = 1
+	}
+
+	rounds := (position + jobLimit - 1) / jobLimit
+	return time.Duration(rounds) * estimatedDuration
+}
@@ -0,0 +1,66 @@
+package queue
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/gojektech/proctor-engine/config"
+)
+
+var ErrEncryptionKeyMissing = errors.New("queue encryption key is not configured")
+
+func fileCipher() (cipher.AEAD, error) {
+	key := config.QueueEncryptionKey()
+	if key == "" {
+		return nil, ErrEncryptionKeyMissing
+	}
+
+	sum := sha256.Sum256([]byte(key))
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func sealFiles(files map[string][]byte) ([]byte, error) {
+	aead, err := fileCipher()
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := json.Marshal(files)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func openFiles(sealedFiles []byte) (map[string][]byte, error) {
+	aead, err := fileCipher()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealedFiles) < aead.NonceSize() {
+		return nil, errors.New("sealed queue files are truncated")
+	}
+	nonce, ciphertext := sealedFiles[:aead.NonceSize()], sealedFiles[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var files map[string][]byte
+	return files, json.Unmarshal(plaintext, &files)
+}
@@ -0,0 +1,322 @@
+package queue
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gojektech/proctor-engine/config"
+	"github.com/gojektech/proctor-engine/redis"
+)
+
+const (
+	QueueKeySuffix             = "-execution-queue"
+	EntryKeySuffix             = "-queue-entry"
+	RunningKeySuffix           = "-running-slots"
+	DurationEstimateKeySuffix  = "-duration-estimate"
+	QueuedJobsKey              = "proctor-queued-jobs"
+	GlobalRunningExecutionsKey = "proctor-running-slots"
+)
+
+const entryExpirySeconds = 7 * 24 * 60 * 60
+
+const defaultSlotLease = 5 * time.Minute
+
+const durationSmoothingFactor = 0.3
+
+var ErrEntryNotFound = errors.New("queue entry not found")
+
+// Running slots are sorted sets scored by lease deadline, so slots held by
+// an engine that died without releasing them expire on their own.
+const admitScript = `
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', ARGV[4])
+redis.call('ZREMRANGEBYSCORE', KEYS[2], '-inf', ARGV[4])
+if redis.call('LLEN', KEYS[3]) > 0 then
+	return 0
+end
+local jobLimit = tonumber(ARGV[2])
+local globalLimit = tonumber(ARGV[3])
+if jobLimit > 0 and redis.call('ZCARD', KEYS[1]) >= jobLimit then
+	return 0
+end
+if globalLimit > 0 and redis.call('ZCARD', KEYS[2]) >= globalLimit then
+	return 0
+end
+redis.call('ZADD', KEYS[1], ARGV[5], ARGV[1])
+redis.call('ZADD', KEYS[2], ARGV[5], ARGV[1])
+return 1
+`
+
+const dispatchScript = `
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', ARGV[4])
+redis.call('ZREMRANGEBYSCORE', KEYS[2], '-inf', ARGV[4])
+local jobLimit = tonumber(ARGV[1])
+local globalLimit = tonumber(ARGV[2])
+if jobLimit > 0 and redis.call('ZCARD', KEYS[1]) >= jobLimit then
+	return false
+end
+if globalLimit > 0 and redis.call('ZCARD', KEYS[2]) >= globalLimit then
+	return false
+end
+local id = redis.call('LPOP', KEYS[3])
+if redis.call('LLEN', KEYS[3]) == 0 then
+	redis.call('SREM', KEYS[4], ARGV[3])
+end
+if not id then
+	return false
+end
+redis.call('ZADD', KEYS[1], ARGV[5], id)
+redis.call('ZADD', KEYS[2], ARGV[5], id)
+return id
+`
+
+const renewScript = `
+redis.call('ZADD', KEYS[1], ARGV[2], ARGV[1])
+redis.call('ZADD', KEYS[2], ARGV[2], ARGV[1])
+return 1
+`
+
+const releaseScript = `
+redis.call('ZREM', KEYS[1], ARGV[1])
+redis.call('ZREM', KEYS[2], ARGV[1])
+return 1
+`
+
+const removeScript = `
+local removed = redis.call('LREM', KEYS[1], 0, ARGV[1])
+if redis.call('LLEN', KEYS[1]) == 0 then
+	redis.call('SREM', KEYS[2], ARGV[2])
+end
+return removed
+`
+
+const dropScript = `
+local ids = redis.call('LRANGE', KEYS[1], 0, -1)
+redis.call('DEL', KEYS[1])
+redis.call('SREM', KEYS[2], ARGV[1])
+return ids
+`
+
+type Store interface {
+	Admit(string, string, Limits) (bool, error)
+	Renew(string, string) error
+	Release(string, string) error
+	Enqueue(Entry) (int, error)
+	Dispatch(string, Limits) (*Entry, error)
+	Remove(Entry) (bool, error)
+	Drop(string) ([]string, error)
+	QueuedJobs() ([]string, error)
+	GetEntry(string) (*Entry, error)
+	SaveEntry(Entry) error
+	PositionOf(Entry) (int, error)
+	EstimatedDuration(string) (time.Duration, error)
+	RecordDuration(string, time.Duration) error
+}
+
+type store struct {
+	redisClient redis.Client
+}
+
+func NewStore(redisClient redis.Client) Store {
+	return &store{
+		redisClient: redisClient,
+	}
+}
+
+func queueKey(jobName string) string {
+	return jobName + QueueKeySuffix
+}
+
+func entryKey(id string) string {
+	return id + EntryKeySuffix
+}
+
+func runningKey(jobName string) string {
+	return jobName + RunningKeySuffix
+}
+
+func durationEstimateKey(jobName string) string {
+	return jobName + DurationEstimateKeySuffix
+}
+
+func SlotLease() time.Duration {
+	if lease := config.RunningSlotLease(); lease > 0 {
+		return lease
+	}
+	return defaultSlotLease
+}
+
+func leaseDeadline(now time.Time) int64 {
+	return now.Add(SlotLease()).Unix()
+}
+
+func (store *store) Admit(jobName string, slotID string, limits Limits) (bool, error) {
+	now := time.Now()
+	reply, err := store.redisClient.EVAL(admitScript, 3, runningKey(jobName), GlobalRunningExecutionsKey, queueKey(jobName), slotID, limits.Job, limits.Global, now.Unix(), leaseDeadline(now))
+	if err != nil {
+		return false, err
+	}
+
+	admitted, ok := reply.(int64)
+	return ok && admitted == 1, nil
+}
+
+// Renew extends the lease of a slot, taking it again if it already lapsed.
+func (store *store) Renew(jobName string, slotID string) error {
+	_, err := store.redisClient.EVAL(renewScript, 2, runningKey(jobName), GlobalRunningExecutionsKey, slotID, leaseDeadline(time.Now()))
+	return err
+}
+
+func (store *store) Release(jobName string, slotID string) error {
+	_, err := store.redisClient.EVAL(releaseScript, 2, runningKey(jobName), GlobalRunningExecutionsKey, slotID)
+	return err
+}
+
+func (store *store) Enqueue(entry Entry) (int, error) {
+	entry.Status = StatusQueued
+	err := store.SaveEntry(entry)
+	if err != nil {
+		return 0, err
+	}
+
+	position, err := store.redisClient.RPUSH(queueKey(entry.JobName), []byte(entry.ID))
+	if err != nil {
+		return 0, err
+	}
+
+	return position, store.redisClient.SADD(QueuedJobsKey, entry.JobName)
+}
+
+func (store *store) Dispatch(jobName string, limits Limits) (*Entry, error) {
+	now := time.Now()
+	reply, err := store.redisClient.EVAL(dispatchScript, 4, runningKey(jobName), GlobalRunningExecutionsKey, queueKey(jobName), QueuedJobsKey, limits.Job, limits.Global, jobName, now.Unix(), leaseDeadline(now))
+	if err != nil {
+		return nil, err
+	}
+
+	id, ok := reply.([]byte)
+	if !ok {
+		return nil, nil
+	}
+	return store.GetEntry(string(id))
+}
+
+// Remove takes a queued entry out of its job's queue. It reports false when
+// the entry was no longer queued, for example because it was just dispatched.
+func (store *store) Remove(entry Entry) (bool, error) {
+	reply, err := store.redisClient.EVAL(removeScript, 2, queueKey(entry.JobName), QueuedJobsKey, entry.ID, entry.JobName)
+	if err != nil {
+		return false, err
+	}
+
+	removed, _ := reply.(int64)
+	return removed > 0, nil
+}
+
+// Drop empties the queue of a job and returns the ids of the entries it held.
+func (store *store) Drop(jobName string) ([]string, error) {
+	reply, err := store.redisClient.EVAL(dropScript, 2, queueKey(jobName), QueuedJobsKey, jobName)
+	if err != nil {
+		return nil, err
+	}
+
+	values, _ := reply.([]interface{})
+	ids := make([]string, 0, len(values))
+	for _, value := range values {
+		if id, ok := value.([]byte); ok {
+			ids = append(ids, string(id))
+		}
+	}
+	return ids, nil
+}
+
+func (store *store) QueuedJobs() ([]string, error) {
+	return store.redisClient.SMEMBERS(QueuedJobsKey)
+}
+
+func (store *store) GetEntry(id string) (*Entry, error) {
+	binaryEntry, err := store.redisClient.GET(entryKey(id))
+	if err == redis.ErrNil {
+		return nil, ErrEntryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entry Entry
+	err = json.Unmarshal(binaryEntry, &entry)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entry.SealedFiles) > 0 {
+		entry.SensitiveFiles, err = openFiles(entry.SealedFiles)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &entry, nil
+}
+
+func (store *store) SaveEntry(entry Entry) error {
+	entry.SealedFiles = nil
+	if len(entry.SensitiveFiles) > 0 {
+		sealedFiles, err := sealFiles(entry.SensitiveFiles)
+		if err != nil {
+			return err
+		}
+		entry.SealedFiles = sealedFiles
+	}
+
+	binaryEntry, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	return store.redisClient.SETEX(entryKey(entry.ID), entryExpirySeconds, binaryEntry)
+}
+
+func (store *store) PositionOf(entry Entry) (int, error) {
+	ids, err := store.redisClient.LRANGE(queueKey(entry.JobName), 0, -1)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, id := range ids {
+		if id == entry.ID {
+			return i + 1, nil
+		}
+	}
+	return 0, nil
+}
+
+func (store *store) EstimatedDuration(jobName string) (time.Duration, error) {
+	binaryEstimate, err := store.redisClient.GET(durationEstimateKey(jobName))
+	if err == redis.ErrNil {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	seconds, err := strconv.ParseFloat(string(binaryEstimate), 64)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+func (store *store) RecordDuration(jobName string, duration time.Duration) error {
+	estimate, err := store.EstimatedDuration(jobName)
+	if err != nil {
+		return err
+	}
+
+	seconds := duration.Seconds()
+	if estimate > 0 {
+		seconds = durationSmoothingFactor*seconds + (1-durationSmoothingFactor)*estimate.Seconds()
+	}
+
+	return store.redisClient.SET(durationEstimateKey(jobName), []byte(strconv.FormatFloat(seconds, 'f', 3, 64)))
+}
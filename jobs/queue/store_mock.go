@@ -0,0 +1,76 @@
+package queue
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockStore struct {
+	mock.Mock
+}
+
+func (m *MockStore) Admit(jobName string, slotID string, limits Limits) (bool, error) {
+	args := m.Called(jobName, slotID, limits)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockStore) Renew(jobName string, slotID string) error {
+	args := m.Called(jobName, slotID)
+	return args.Error(0)
+}
+
+func (m *MockStore) Release(jobName string, slotID string) error {
+	args := m.Called(jobName, slotID)
+	return args.Error(0)
+}
+
+func (m *MockStore) Enqueue(entry Entry) (int, error) {
+	args := m.Called(entry)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockStore) Dispatch(jobName string, limits Limits) (*Entry, error) {
+	args := m.Called(jobName, limits)
+	return args.Get(0).(*Entry), args.Error(1)
+}
+
+func (m *MockStore) Remove(entry Entry) (bool, error) {
+	args := m.Called(entry)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockStore) Drop(jobName string) ([]string, error) {
+	args := m.Called(jobName)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockStore) QueuedJobs() ([]string, error) {
+	args := m.Called()
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockStore) GetEntry(id string) (*Entry, error) {
+	args := m.Called(id)
+	return args.Get(0).(*Entry), args.Error(1)
+}
+
+func (m *MockStore) SaveEntry(entry Entry) error {
+	args := m.Called(entry)
+	return args.Error(0)
+}
+
+func (m *MockStore) PositionOf(entry Entry) (int, error) {
+	args := m.Called(entry)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockStore) EstimatedDuration(jobName string) (time.Duration, error) {
+	args := m.Called(jobName)
+	return args.Get(0).(time.Duration), args.Error(1)
+}
+
+func (m *MockStore) RecordDuration(jobName string, duration time.Duration) error {
+	args := m.Called(jobName, duration)
+	return args.Error(0)
+}
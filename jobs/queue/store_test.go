@@ -0,0 +1,228 @@
+package queue
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gojektech/proctor-engine/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type QueueStoreTestSuite struct {
+	suite.Suite
+	mockRedisClient *redis.MockClient
+	testQueueStore  Store
+}
+
+func (s *QueueStoreTestSuite) SetupTest() {
+	s.mockRedisClient = &redis.MockClient{}
+
+	s.testQueueStore = NewStore(s.mockRedisClient)
+}
+
+func (s *QueueStoreTestSuite) TestAdmit() {
+	t := s.T()
+
+	s.mockRedisClient.On("EVAL", admitScript, 3, "job1-running-slots", GlobalRunningExecutionsKey, "job1-execution-queue", "slot-id", 1, 10, mock.AnythingOfType("int64"), mock.AnythingOfType("int64")).Return(int64(1), nil).Once()
+
+	admitted, err := s.testQueueStore.Admit("job1", "slot-id", Limits{Job: 1, Global: 10})
+	assert.NoError(t, err)
+	assert.True(t, admitted)
+}
+
+func (s *QueueStoreTestSuite) TestAdmitWhenLimitReached() {
+	t := s.T()
+
+	s.mockRedisClient.On("EVAL", admitScript, 3, "job1-running-slots", GlobalRunningExecutionsKey, "job1-execution-queue", "slot-id", 1, 0, mock.AnythingOfType("int64"), mock.AnythingOfType("int64")).Return(int64(0), nil).Once()
+
+	admitted, err := s.testQueueStore.Admit("job1", "slot-id", Limits{Job: 1})
+	assert.NoError(t, err)
+	assert.False(t, admitted)
+}
+
+func (s *QueueStoreTestSuite) TestRelease() {
+	t := s.T()
+
+	s.mockRedisClient.On("EVAL", releaseScript, 2, "job1-running-slots", GlobalRunningExecutionsKey, "slot-id").Return(int64(1), nil).Once()
+
+	err := s.testQueueStore.Release("job1", "slot-id")
+	assert.NoError(t, err)
+
+	s.mockRedisClient.AssertExpectations(t)
+}
+
+func (s *QueueStoreTestSuite) TestRenew() {
+	t := s.T()
+
+	before := time.Now().Add(SlotLease()).Unix()
+	s.mockRedisClient.On("EVAL", renewScript, 2, "job1-running-slots", GlobalRunningExecutionsKey, "slot-id", mock.MatchedBy(func(deadline int64) bool {
+		return deadline >= before && deadline <= time.Now().Add(SlotLease()).Unix()
+	})).Return(int64(1), nil).Once()
+
+	err := s.testQueueStore.Renew("job1", "slot-id")
+	assert.NoError(t, err)
+
+	s.mockRedisClient.AssertExpectations(t)
+}
+
+func (s *QueueStoreTestSuite) TestEnqueue() {
+	t := s.T()
+
+	entry := Entry{ID: "entry-id", JobName: "job1"}
+	queuedEntry := entry
+	queuedEntry.Status = StatusQueued
+	binaryEntry, err := json.Marshal(queuedEntry)
+	assert.NoError(t, err)
+
+	s.mockRedisClient.On("SETEX", "entry-id-queue-entry", entryExpirySeconds, binaryEntry).Return(nil).Once()
+	s.mockRedisClient.On("RPUSH", "job1-execution-queue", []byte("entry-id")).Return(3, nil).Once()
+	s.mockRedisClient.On("SADD", QueuedJobsKey, "job1").Return(nil).Once()
+
+	position, err := s.testQueueStore.Enqueue(entry)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, position)
+
+	s.mockRedisClient.AssertExpectations(t)
+}
+
+func (s *QueueStoreTestSuite) TestEnqueueSealsSensitiveFiles() {
+	t := s.T()
+
+	os.Setenv("PROCTOR_QUEUE_ENCRYPTION_KEY", "queue-key")
+	defer os.Unsetenv("PROCTOR_QUEUE_ENCRYPTION_KEY")
+
+	var savedEntry []byte
+	s.mockRedisClient.On("SETEX", "entry-id-queue-entry", entryExpirySeconds, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		savedEntry = args.Get(2).([]byte)
+	}).Once()
+	s.mockRedisClient.On("RPUSH", "job1-execution-queue", []byte("entry-id")).Return(1, nil).Once()
+	s.mockRedisClient.On("SADD", QueuedJobsKey, "job1").Return(nil).Once()
+
+	_, err := s.testQueueStore.Enqueue(Entry{ID: "entry-id", JobName: "job1", SensitiveFiles: map[string][]byte{"key.pem": []byte("private key")}})
+	assert.NoError(t, err)
+	assert.NotContains(t, string(savedEntry), "key.pem")
+
+	s.mockRedisClient.On("GET", "entry-id-queue-entry").Return(savedEntry, nil).Once()
+
+	entry, err := s.testQueueStore.GetEntry("entry-id")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"key.pem": []byte("private key")}, entry.SensitiveFiles)
+}
+
+func (s *QueueStoreTestSuite) TestEnqueueSensitiveFilesWithoutEncryptionKey() {
+	t := s.T()
+
+	os.Unsetenv("PROCTOR_QUEUE_ENCRYPTION_KEY")
+
+	_, err := s.testQueueStore.Enqueue(Entry{ID: "entry-id", JobName: "job1", SensitiveFiles: map[string][]byte{"key.pem": []byte("private key")}})
+	assert.Equal(t, ErrEncryptionKeyMissing, err)
+
+	s.mockRedisClient.AssertNotCalled(t, "SETEX", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *QueueStoreTestSuite) TestDispatch() {
+	t := s.T()
+
+	binaryEntry, err := json.Marshal(Entry{ID: "entry-id", JobName: "job1", Status: StatusQueued})
+	assert.NoError(t, err)
+
+	s.mockRedisClient.On("EVAL", dispatchScript, 4, "job1-running-slots", GlobalRunningExecutionsKey, "job1-execution-queue", QueuedJobsKey, 1, 0, "job1", mock.AnythingOfType("int64"), mock.AnythingOfType("int64")).Return([]byte("entry-id"), nil).Once()
+	s.mockRedisClient.On("GET", "entry-id-queue-entry").Return(binaryEntry, nil).Once()
+
+	entry, err := s.testQueueStore.Dispatch("job1", Limits{Job: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, "entry-id", entry.ID)
+}
+
+func (s *QueueStoreTestSuite) TestDispatchWithNothingToDispatch() {
+	t := s.T()
+
+	s.mockRedisClient.On("EVAL", dispatchScript, 4, "job1-running-slots", GlobalRunningExecutionsKey, "job1-execution-queue", QueuedJobsKey, 1, 0, "job1", mock.AnythingOfType("int64"), mock.AnythingOfType("int64")).Return(nil, nil).Once()
+
+	entry, err := s.testQueueStore.Dispatch("job1", Limits{Job: 1})
+	assert.NoError(t, err)
+	assert.Nil(t, entry)
+}
+
+func (s *QueueStoreTestSuite) TestRemove() {
+	t := s.T()
+
+	s.mockRedisClient.On("EVAL", removeScript, 2, "job1-execution-queue", QueuedJobsKey, "entry-id", "job1").Return(int64(1), nil).Once()
+
+	removed, err := s.testQueueStore.Remove(Entry{ID: "entry-id", JobName: "job1"})
+	assert.NoError(t, err)
+	assert.True(t, removed)
+}
+
+func (s *QueueStoreTestSuite) TestRemoveOfDispatchedEntry() {
+	t := s.T()
+
+	s.mockRedisClient.On("EVAL", removeScript, 2, "job1-execution-queue", QueuedJobsKey, "entry-id", "job1").Return(int64(0), nil).Once()
+
+	removed, err := s.testQueueStore.Remove(Entry{ID: "entry-id", JobName: "job1"})
+	assert.NoError(t, err)
+	assert.False(t, removed)
+}
+
+func (s *QueueStoreTestSuite) TestDrop() {
+	t := s.T()
+
+	s.mockRedisClient.On("EVAL", dropScript, 2, "job1-execution-queue", QueuedJobsKey, "job1").Return([]interface{}{[]byte("entry-one"), []byte("entry-two")}, nil).Once()
+
+	ids, err := s.testQueueStore.Drop("job1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"entry-one", "entry-two"}, ids)
+}
+
+func (s *QueueStoreTestSuite) TestPositionOf() {
+	t := s.T()
+
+	s.mockRedisClient.On("LRANGE", "job1-execution-queue", 0, -1).Return([]string{"first", "entry-id", "third"}, nil).Twice()
+
+	position, err := s.testQueueStore.PositionOf(Entry{ID: "entry-id", JobName: "job1"})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, position)
+
+	position, err = s.testQueueStore.PositionOf(Entry{ID: "missing", JobName: "job1"})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, position)
+}
+
+func (s *QueueStoreTestSuite) TestRecordDuration() {
+	t := s.T()
+
+	s.mockRedisClient.On("GET", "job1-duration-estimate").Return([]byte("100.000"), nil).Once()
+	s.mockRedisClient.On("SET", "job1-duration-estimate", []byte("76.000")).Return(nil).Once()
+
+	err := s.testQueueStore.RecordDuration("job1", 20*time.Second)
+	assert.NoError(t, err)
+
+	s.mockRedisClient.AssertExpectations(t)
+}
+
+func (s *QueueStoreTestSuite) TestRecordFirstDuration() {
+	t := s.T()
+
+	s.mockRedisClient.On("GET", "job1-duration-estimate").Return([]byte{}, redis.ErrNil).Once()
+	s.mockRedisClient.On("SET", "job1-duration-estimate", mock.Anything).Return(nil).Once()
+
+	err := s.testQueueStore.RecordDuration("job1", 20*time.Second)
+	assert.NoError(t, err)
+
+	s.mockRedisClient.AssertCalled(t, "SET", "job1-duration-estimate", []byte("20.000"))
+}
+
+func TestEstimatedWait(t *testing.T) {
+	assert.Equal(t, time.Duration(0), EstimatedWait(3, 1, 0))
+	assert.Equal(t, 3*time.Minute, EstimatedWait(3, 1, time.Minute))
+	assert.Equal(t, 2*time.Minute, EstimatedWait(3, 2, time.Minute))
+	assert.Equal(t, time.Minute, EstimatedWait(1, 0, time.Minute))
+}
+
+func TestQueueStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(QueueStoreTestSuite))
+}
@@ -6,6 +6,8 @@ import (
 
 	"github.com/gojektech/proctor-engine/logger"
 	"github.com/gojektech/proctor-engine/utility"
+
+	"github.com/gorilla/mux"
 )
 
 type secretsHandler struct {
@@ -14,6 +16,7 @@ type secretsHandler struct {
 
 type SecretsHandler interface {
 	HandleSubmission() http.HandlerFunc
+	HandleJobSubmission() http.HandlerFunc
 }
 
 func NewSecretsHandler(secretsStore Store) SecretsHandler {
@@ -23,24 +26,39 @@ func NewSecretsHandler(secretsStore Store) SecretsHandler {
 }
 
 func (secretsHandler *secretsHandler) HandleSubmission() http.HandlerFunc {
+	return secretsHandler.handleSubmission(false)
+}
+
+func (secretsHandler *secretsHandler) HandleJobSubmission() http.HandlerFunc {
+	return secretsHandler.handleSubmission(true)
+}
+
+func (secretsHandler *secretsHandler) handleSubmission(nameFromPath bool) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
+		log := logger.FromContext(req.Context())
 		var secret Secret
 		err := json.NewDecoder(req.Body).Decode(&secret)
 		defer req.Body.Close()
 		if err != nil {
-			logger.Error("Error parsing request body", err.Error())
+			log.Error("Error parsing request body", err.Error())
+
+			utility.WriteError(w, req, http.StatusBadRequest, utility.ErrCodeMalformedRequest, utility.ClientError)
+			return
+		}
+		if nameFromPath {
+			secret.JobName = mux.Vars(req)["name"]
+		}
 
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write([]byte(utility.ClientError))
+		if fieldErrors := secret.Validate(); len(fieldErrors) > 0 {
+			utility.WriteValidationError(w, req, fieldErrors)
 			return
 		}
 
 		err = secretsHandler.secretsStore.CreateOrUpdateJobSecret(secret)
 		if err != nil {
-			logger.Error("Error updating secrets", err.Error())
+			log.WithField(logger.JobNameField, secret.JobName).Error("Error updating secrets", err.Error())
 
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(utility.ServerError))
+			utility.WriteError(w, req, http.StatusServiceUnavailable, utility.ErrCodeStoreUnavailable, "unable to save job secrets")
 			return
 		}
 
@@ -10,6 +10,7 @@ import (
 	"errors"
 
 	"github.com/gojektech/proctor-engine/utility"
+	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
@@ -27,6 +28,13 @@ func (suite *SecretsHandlerTestSuite) SetupTest() {
 	suite.testSecretsHandler = NewSecretsHandler(suite.mockSecretsStore)
 }
 
+func errorResponse(t *testing.T, responseRecorder *httptest.ResponseRecorder) utility.Error {
+	var errorResponse utility.ErrorResponse
+	err := json.Unmarshal(responseRecorder.Body.Bytes(), &errorResponse)
+	assert.NoError(t, err)
+	return errorResponse.Error
+}
+
 func (suite *SecretsHandlerTestSuite) TestSuccessfulSecretsUpdation() {
 	t := suite.T()
 
@@ -63,7 +71,26 @@ func (suite *SecretsHandlerTestSuite) TestSecretsUpdationSecretsMalformedData()
 
 	suite.mockSecretsStore.AssertNotCalled(t, "CreateOrUpdateJobSecret", mock.Anything)
 	assert.Equal(t, http.StatusBadRequest, responseRecorder.Code)
-	assert.Equal(t, utility.ClientError, responseRecorder.Body.String())
+	assert.Equal(t, utility.ErrCodeMalformedRequest, errorResponse(t, responseRecorder).Code)
+}
+
+func (suite *SecretsHandlerTestSuite) TestSecretsUpdationValidationFailure() {
+	t := suite.T()
+
+	requestBody, err := json.Marshal(Secret{JobName: "job1"})
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest("POST", "/job-secrets", bytes.NewReader(requestBody))
+	responseRecorder := httptest.NewRecorder()
+
+	suite.testSecretsHandler.HandleSubmission()(responseRecorder, req)
+
+	suite.mockSecretsStore.AssertNotCalled(t, "CreateOrUpdateJobSecret", mock.Anything)
+	assert.Equal(t, http.StatusUnprocessableEntity, responseRecorder.Code)
+
+	secretError := errorResponse(t, responseRecorder)
+	assert.Equal(t, utility.ErrCodeValidationFailed, secretError.Code)
+	assert.Equal(t, []utility.FieldError{{Field: "secrets", Message: "must not be empty"}}, secretError.Details)
 }
 
 func (suite *SecretsHandlerTestSuite) TestSecretsUpdationSecretsStoreFailure() {
@@ -86,8 +113,27 @@ func (suite *SecretsHandlerTestSuite) TestSecretsUpdationSecretsStoreFailure() {
 
 	suite.mockSecretsStore.AssertExpectations(t)
 
-	assert.Equal(t, http.StatusInternalServerError, responseRecorder.Code)
-	assert.Equal(t, utility.ServerError, responseRecorder.Body.String())
+	assert.Equal(t, http.StatusServiceUnavailable, responseRecorder.Code)
+	assert.Equal(t, utility.ErrCodeStoreUnavailable, errorResponse(t, responseRecorder).Code)
+}
+
+func (suite *SecretsHandlerTestSuite) TestJobSecretsUpdation() {
+	t := suite.T()
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/jobs/{name}/secrets", suite.testSecretsHandler.HandleJobSubmission()).Methods("PUT")
+
+	requestBody, err := json.Marshal(map[string]interface{}{"secrets": map[string]string{"k1": "v1"}})
+	assert.NoError(t, err)
+	req := httptest.NewRequest("PUT", "/api/v1/jobs/job1/secrets", bytes.NewReader(requestBody))
+	responseRecorder := httptest.NewRecorder()
+
+	suite.mockSecretsStore.On("CreateOrUpdateJobSecret", Secret{JobName: "job1", Secrets: map[string]string{"k1": "v1"}}).Return(nil).Once()
+
+	router.ServeHTTP(responseRecorder, req)
+
+	suite.mockSecretsStore.AssertExpectations(t)
+	assert.Equal(t, http.StatusCreated, responseRecorder.Code)
 }
 
 func TestSecretsHandlerTestSuite(t *testing.T) {
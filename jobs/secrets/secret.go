@@ -1,6 +1,19 @@
 package secrets
 
+import "github.com/gojektech/proctor-engine/utility"
+
 type Secret struct {
 	JobName string            `json:"job_name"`
 	Secrets map[string]string `json:"secrets"`
 }
+
+func (secret Secret) Validate() []utility.FieldError {
+	var fieldErrors []utility.FieldError
+	if secret.JobName == "" {
+		fieldErrors = append(fieldErrors, utility.FieldError{Field: "job_name", Message: "is required"})
+	}
+	if len(secret.Secrets) == 0 {
+		fieldErrors = append(fieldErrors, utility.FieldError{Field: "secrets", Message: "must not be empty"})
+	}
+	return fieldErrors
+}
@@ -2,12 +2,15 @@ package secrets
 
 import (
 	"encoding/json"
+	"errors"
 
 	"github.com/gojektech/proctor-engine/redis"
 )
 
 const JobsSecretsKeySuffix = "-secret"
 
+var ErrSecretsNotFound = errors.New("job secrets not found")
+
 type Store interface {
 	CreateOrUpdateJobSecret(Secret) error
 	GetJobSecrets(string) (map[string]string, error)
@@ -40,6 +43,9 @@ func (store *store) CreateOrUpdateJobSecret(secret Secret) error {
 func (store *store) GetJobSecrets(jobName string) (map[string]string, error) {
 	var secrets map[string]string
 	binarySecrets, err := store.redisClient.GET(jobSecretsKey(jobName))
+	if err == redis.ErrNil {
+		return secrets, ErrSecretsNotFound
+	}
 	if err != nil {
 		return secrets, err
 	}
@@ -77,6 +77,15 @@ func (s *SecretsStoreTestSuite) TestGetJobSecretsRedisFailure() {
 	assert.Error(t, err)
 }
 
+func (s *SecretsStoreTestSuite) TestGetJobSecretsForUnknownJob() {
+	t := s.T()
+
+	s.mockRedisClient.On("GET", "job1-secret").Return([]byte{}, redis.ErrNil).Once()
+
+	_, err := s.testSecretStore.GetJobSecrets("job1")
+	assert.Equal(t, ErrSecretsNotFound, err)
+}
+
 func (s *SecretsStoreTestSuite) TestGetJobSecretsCorruptData() {
 	t := s.T()
 
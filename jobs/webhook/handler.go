@@ -0,0 +1,67 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gojektech/proctor-engine/logger"
+	"github.com/gojektech/proctor-engine/utility"
+
+	"github.com/gorilla/mux"
+)
+
+type webhookHandler struct {
+	store    Store
+	notifier Notifier
+}
+
+type WebhookHandler interface {
+	HandleDeadLetters() http.HandlerFunc
+	HandleRedelivery() http.HandlerFunc
+}
+
+func NewWebhookHandler(store Store, notifier Notifier) WebhookHandler {
+	return &webhookHandler{
+		store:    store,
+		notifier: notifier,
+	}
+}
+
+func (webhookHandler *webhookHandler) HandleDeadLetters() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		log := logger.FromContext(req.Context())
+
+		deadLetters, err := webhookHandler.store.DeadLetters()
+		if err != nil {
+			log.Error("Error fetching webhook dead letters", err.Error())
+
+			utility.WriteError(w, req, http.StatusServiceUnavailable, utility.ErrCodeStoreUnavailable, "unable to fetch webhook dead letters")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(deadLetters)
+	}
+}
+
+func (webhookHandler *webhookHandler) HandleRedelivery() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		log := logger.FromContext(req.Context())
+
+		deliveryID := mux.Vars(req)["id"]
+		err := webhookHandler.notifier.Redeliver(deliveryID)
+		if err == ErrDeadLetterNotFound {
+			utility.WriteError(w, req, http.StatusNotFound, utility.ErrCodeDeadLetterNotFound, fmt.Sprintf("webhook dead letter %s not found or expired", deliveryID))
+			return
+		}
+		if err != nil {
+			log.Error("Error scheduling webhook redelivery", deliveryID, err.Error())
+
+			utility.WriteError(w, req, http.StatusServiceUnavailable, utility.ErrCodeStoreUnavailable, "unable to schedule webhook redelivery")
+			return
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
@@ -0,0 +1,92 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gojektech/proctor-engine/utility"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type WebhookHandlerTestSuite struct {
+	suite.Suite
+	mockStore          *MockStore
+	mockNotifier       *MockNotifier
+	testWebhookHandler WebhookHandler
+	testRouter         *mux.Router
+}
+
+func (s *WebhookHandlerTestSuite) SetupTest() {
+	s.mockStore = &MockStore{}
+	s.mockNotifier = &MockNotifier{}
+
+	s.testWebhookHandler = NewWebhookHandler(s.mockStore, s.mockNotifier)
+
+	s.testRouter = mux.NewRouter()
+	s.testRouter.HandleFunc("/webhooks/dead-letters", s.testWebhookHandler.HandleDeadLetters()).Methods("GET")
+	s.testRouter.HandleFunc("/webhooks/dead-letters/{id}/redeliver", s.testWebhookHandler.HandleRedelivery()).Methods("POST")
+}
+
+func (s *WebhookHandlerTestSuite) serve(method, path string) *httptest.ResponseRecorder {
+	responseRecorder := httptest.NewRecorder()
+	s.testRouter.ServeHTTP(responseRecorder, httptest.NewRequest(method, path, nil))
+	return responseRecorder
+}
+
+func (s *WebhookHandlerTestSuite) TestDeadLetters() {
+	t := s.T()
+
+	deadLetters := []DeadLetter{{DeliveryID: "delivery-id", URL: "http://example.com/hook", Attempts: 5}}
+	s.mockStore.On("DeadLetters").Return(deadLetters, nil).Once()
+
+	responseRecorder := s.serve("GET", "/webhooks/dead-letters")
+
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+	var listed []DeadLetter
+	assert.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &listed))
+	assert.Equal(t, "delivery-id", listed[0].DeliveryID)
+}
+
+func (s *WebhookHandlerTestSuite) TestDeadLettersStoreFailure() {
+	t := s.T()
+
+	s.mockStore.On("DeadLetters").Return([]DeadLetter(nil), errors.New("error")).Once()
+
+	responseRecorder := s.serve("GET", "/webhooks/dead-letters")
+
+	assert.Equal(t, http.StatusServiceUnavailable, responseRecorder.Code)
+}
+
+func (s *WebhookHandlerTestSuite) TestRedelivery() {
+	t := s.T()
+
+	s.mockNotifier.On("Redeliver", "delivery-id").Return(nil).Once()
+
+	responseRecorder := s.serve("POST", "/webhooks/dead-letters/delivery-id/redeliver")
+
+	s.mockNotifier.AssertExpectations(t)
+	assert.Equal(t, http.StatusAccepted, responseRecorder.Code)
+}
+
+func (s *WebhookHandlerTestSuite) TestRedeliveryOfUnknownDeadLetter() {
+	t := s.T()
+
+	s.mockNotifier.On("Redeliver", "delivery-id").Return(ErrDeadLetterNotFound).Once()
+
+	responseRecorder := s.serve("POST", "/webhooks/dead-letters/delivery-id/redeliver")
+
+	assert.Equal(t, http.StatusNotFound, responseRecorder.Code)
+	var errorResponse utility.ErrorResponse
+	assert.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &errorResponse))
+	assert.Equal(t, utility.ErrCodeDeadLetterNotFound, errorResponse.Error.Code)
+}
+
+func TestWebhookHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(WebhookHandlerTestSuite))
+}
@@ -0,0 +1,230 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gojektech/proctor-engine/logger"
+	"github.com/gojektech/proctor-engine/utility"
+)
+
+const (
+	SignatureHeaderKey  = "X-Proctor-Signature"
+	TimestampHeaderKey  = "X-Proctor-Timestamp"
+	DeliveryIDHeaderKey = "X-Proctor-Delivery"
+)
+
+const deliveryClaimLease = time.Minute
+
+var retryPollInterval = time.Second
+
+type notifier struct {
+	store         Store
+	httpClient    *http.Client
+	signingSecret string
+	maxAttempts   int
+	initialDelay  time.Duration
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, urls []string, payload Payload)
+	Redeliver(deliveryID string) error
+	StartRetrier(ctx context.Context)
+}
+
+func NewNotifier(store Store, signingSecret string, maxAttempts int, initialDelay time.Duration, allowedHosts []string) Notifier {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	return &notifier{
+		store:         store,
+		httpClient:    &http.Client{Timeout: 10 * time.Second, Transport: newTransport(allowedHosts)},
+		signingSecret: signingSecret,
+		maxAttempts:   maxAttempts,
+		initialDelay:  initialDelay,
+	}
+}
+
+// Sign covers the timestamp as well as the body so that receivers can reject
+// replayed deliveries by checking the age of TimestampHeaderKey.
+func Sign(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (notifier *notifier) Notify(ctx context.Context, urls []string, payload Payload) {
+	if len(urls) > 0 && notifier.signingSecret == "" {
+		logger.FromContext(ctx).Error("Skipping webhook deliveries without a signing secret")
+		return
+	}
+
+	for _, url := range urls {
+		deliveryID, err := utility.RandomID()
+		if err != nil {
+			logger.FromContext(ctx).Error("Error generating webhook delivery id", err.Error())
+			continue
+		}
+
+		notifier.attempt(ctx, Delivery{ID: deliveryID, URL: url, Payload: payload}, false)
+	}
+}
+
+// attempt makes one delivery attempt. Failed deliveries are persisted and
+// retried by the retrier, so that pending retries survive a restart.
+func (notifier *notifier) attempt(ctx context.Context, delivery Delivery, scheduled bool) {
+	log := logger.FromContext(ctx).WithField("webhook_url", delivery.URL)
+
+	delivery.Attempts++
+	err := notifier.post(ctx, delivery)
+	if err == nil {
+		log.Info("Delivered webhook", delivery.ID)
+		notifier.forget(log, delivery, scheduled)
+		return
+	}
+
+	log.Warn("Webhook delivery attempt failed", delivery.ID, delivery.Attempts, err.Error())
+	delivery.LastError = err.Error()
+
+	if delivery.Attempts < notifier.maxAttempts && !isPrivateTargetError(err) {
+		delivery.NextAttemptAt = time.Now().Add(notifier.initialDelay << uint(delivery.Attempts-1))
+		if err := notifier.store.SaveDelivery(delivery); err != nil {
+			log.Error("Error scheduling webhook retry", delivery.ID, err.Error())
+		}
+		return
+	}
+
+	deadLetter := DeadLetter{
+		DeliveryID: delivery.ID,
+		URL:        delivery.URL,
+		Payload:    delivery.Payload,
+		Attempts:   delivery.Attempts,
+		LastError:  delivery.LastError,
+		FailedAt:   time.Now(),
+	}
+
+	if err := notifier.store.SaveDeadLetter(deadLetter); err != nil {
+		log.Error("Error saving webhook dead letter", delivery.ID, err.Error())
+		return
+	}
+	notifier.forget(log, delivery, scheduled)
+
+	log.Error("Webhook delivery failed, moved to dead letter", delivery.ID)
+}
+
+func (notifier *notifier) forget(log *logger.Entry, delivery Delivery, scheduled bool) {
+	if !scheduled {
+		return
+	}
+	if err := notifier.store.DeleteDelivery(delivery.ID); err != nil {
+		log.Error("Error removing scheduled webhook delivery", delivery.ID, err.Error())
+	}
+}
+
+func (notifier *notifier) Redeliver(deliveryID string) error {
+	deadLetter, err := notifier.store.GetDeadLetter(deliveryID)
+	if err != nil {
+		return err
+	}
+
+	delivery := Delivery{
+		ID:            deadLetter.DeliveryID,
+		URL:           deadLetter.URL,
+		Payload:       deadLetter.Payload,
+		LastError:     deadLetter.LastError,
+		NextAttemptAt: time.Now(),
+	}
+	if err := notifier.store.SaveDelivery(delivery); err != nil {
+		return err
+	}
+	return notifier.store.DeleteDeadLetter(deliveryID)
+}
+
+func (notifier *notifier) StartRetrier(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(retryPollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				notifier.retryDue(ctx)
+			}
+		}
+	}()
+}
+
+func (notifier *notifier) retryDue(ctx context.Context) {
+	log := logger.FromContext(ctx)
+
+	deliveries, err := notifier.store.PendingDeliveries()
+	if err != nil {
+		log.Error("Error fetching pending webhook deliveries", err.Error())
+		return
+	}
+
+	now := time.Now()
+	for _, delivery := range deliveries {
+		if delivery.NextAttemptAt.After(now) {
+			continue
+		}
+
+		claimed, err := notifier.store.ClaimDelivery(delivery.ID, deliveryClaimLease)
+		if err != nil {
+			log.Error("Error claiming webhook delivery", delivery.ID, err.Error())
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		notifier.attempt(ctx, delivery, true)
+		if err := notifier.store.ReleaseDelivery(delivery.ID); err != nil {
+			log.Error("Error releasing webhook delivery", delivery.ID, err.Error())
+		}
+	}
+}
+
+func (notifier *notifier) post(ctx context.Context, delivery Delivery) error {
+	body, err := json.Marshal(delivery.Payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, delivery.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(DeliveryIDHeaderKey, delivery.ID)
+	req.Header.Set(TimestampHeaderKey, timestamp)
+	req.Header.Set(SignatureHeaderKey, Sign(notifier.signingSecret, timestamp, body))
+
+	resp, err := notifier.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,24 @@
+package webhook
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockNotifier struct {
+	mock.Mock
+}
+
+func (m *MockNotifier) Notify(ctx context.Context, urls []string, payload Payload) {
+	m.Called(ctx, urls, payload)
+}
+
+func (m *MockNotifier) Redeliver(deliveryID string) error {
+	args := m.Called(deliveryID)
+	return args.Error(0)
+}
+
+func (m *MockNotifier) StartRetrier(ctx context.Context) {
+	m.Called(ctx)
+}
@@ -0,0 +1,211 @@
+package webhook
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type NotifierTestSuite struct {
+	suite.Suite
+	mockStore    *MockStore
+	testNotifier *notifier
+}
+
+func (s *NotifierTestSuite) SetupTest() {
+	s.mockStore = &MockStore{}
+
+	s.testNotifier = NewNotifier(s.mockStore, "secret", 3, time.Minute, []string{"127.0.0.1"}).(*notifier)
+}
+
+func respondWith(statusCode int, requests *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		*requests++
+		w.WriteHeader(statusCode)
+	}))
+}
+
+func (s *NotifierTestSuite) TestNotifySignsPayload() {
+	t := s.T()
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests++
+		body, err := ioutil.ReadAll(req.Body)
+		assert.NoError(t, err)
+
+		timestamp := req.Header.Get(TimestampHeaderKey)
+		assert.NotEmpty(t, timestamp)
+		assert.Equal(t, Sign("secret", timestamp, body), req.Header.Get(SignatureHeaderKey))
+		assert.NotEmpty(t, req.Header.Get(DeliveryIDHeaderKey))
+		assert.Contains(t, string(body), `"execution_name":"proctor-ipsum-lorem"`)
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	s.testNotifier.Notify(context.Background(), []string{server.URL}, Payload{ExecutionName: "proctor-ipsum-lorem", Status: "SUCCEEDED"})
+
+	assert.Equal(t, 1, requests)
+	s.mockStore.AssertNotCalled(t, "SaveDelivery", mock.Anything)
+	s.mockStore.AssertNotCalled(t, "DeleteDelivery", mock.Anything)
+}
+
+func (s *NotifierTestSuite) TestNotifySkipsDeliveriesWithoutSigningSecret() {
+	t := s.T()
+
+	requests := 0
+	server := respondWith(http.StatusOK, &requests)
+	defer server.Close()
+
+	NewNotifier(s.mockStore, "", 3, time.Minute, []string{"127.0.0.1"}).Notify(context.Background(), []string{server.URL}, Payload{ExecutionName: "proctor-ipsum-lorem", Status: "SUCCEEDED"})
+
+	assert.Equal(t, 0, requests)
+	s.mockStore.AssertNotCalled(t, "SaveDelivery", mock.Anything)
+}
+
+func (s *NotifierTestSuite) TestNotifyDeadLettersPrivateTargetWithoutRetrying() {
+	t := s.T()
+
+	requests := 0
+	server := respondWith(http.StatusOK, &requests)
+	defer server.Close()
+
+	s.mockStore.On("SaveDeadLetter", mock.MatchedBy(func(deadLetter DeadLetter) bool {
+		return deadLetter.Attempts == 1 && deadLetter.LastError == "Post \""+server.URL+"\": "+ErrPrivateTarget.Error()
+	})).Return(nil).Once()
+
+	NewNotifier(s.mockStore, "secret", 3, time.Minute, nil).Notify(context.Background(), []string{server.URL}, Payload{ExecutionName: "proctor-ipsum-lorem", Status: "SUCCEEDED"})
+
+	assert.Equal(t, 0, requests)
+	s.mockStore.AssertExpectations(t)
+	s.mockStore.AssertNotCalled(t, "SaveDelivery", mock.Anything)
+}
+
+func TestSignCoversTimestamp(t *testing.T) {
+	body := []byte(`{"status":"SUCCEEDED"}`)
+
+	assert.NotEqual(t, Sign("secret", "1520071200", body), Sign("secret", "1520071201", body))
+}
+
+func (s *NotifierTestSuite) TestNotifySchedulesRetryOfFailedDelivery() {
+	t := s.T()
+
+	requests := 0
+	server := respondWith(http.StatusBadGateway, &requests)
+	defer server.Close()
+
+	s.mockStore.On("SaveDelivery", mock.MatchedBy(func(delivery Delivery) bool {
+		return delivery.URL == server.URL &&
+			delivery.Attempts == 1 &&
+			delivery.Payload.ExecutionName == "proctor-ipsum-lorem" &&
+			delivery.LastError == "webhook responded with status 502" &&
+			delivery.NextAttemptAt.After(time.Now())
+	})).Return(nil).Once()
+
+	s.testNotifier.Notify(context.Background(), []string{server.URL}, Payload{ExecutionName: "proctor-ipsum-lorem"})
+
+	assert.Equal(t, 1, requests)
+	s.mockStore.AssertExpectations(t)
+	s.mockStore.AssertNotCalled(t, "SaveDeadLetter", mock.Anything)
+}
+
+func (s *NotifierTestSuite) TestRetryDueDeliversScheduledDelivery() {
+	t := s.T()
+
+	requests := 0
+	server := respondWith(http.StatusNoContent, &requests)
+	defer server.Close()
+
+	due := Delivery{ID: "due", URL: server.URL, Attempts: 1, NextAttemptAt: time.Now().Add(-time.Second)}
+	notDue := Delivery{ID: "not-due", URL: server.URL, Attempts: 1, NextAttemptAt: time.Now().Add(time.Hour)}
+	s.mockStore.On("PendingDeliveries").Return([]Delivery{due, notDue}, nil).Once()
+	s.mockStore.On("ClaimDelivery", "due", deliveryClaimLease).Return(true, nil).Once()
+	s.mockStore.On("DeleteDelivery", "due").Return(nil).Once()
+	s.mockStore.On("ReleaseDelivery", "due").Return(nil).Once()
+
+	s.testNotifier.retryDue(context.Background())
+
+	assert.Equal(t, 1, requests)
+	s.mockStore.AssertExpectations(t)
+	s.mockStore.AssertNotCalled(t, "ClaimDelivery", "not-due", mock.Anything)
+}
+
+func (s *NotifierTestSuite) TestRetryDueSkipsDeliveryClaimedByAnotherReplica() {
+	t := s.T()
+
+	requests := 0
+	server := respondWith(http.StatusNoContent, &requests)
+	defer server.Close()
+
+	s.mockStore.On("PendingDeliveries").Return([]Delivery{{ID: "due", URL: server.URL, Attempts: 1}}, nil).Once()
+	s.mockStore.On("ClaimDelivery", "due", deliveryClaimLease).Return(false, nil).Once()
+
+	s.testNotifier.retryDue(context.Background())
+
+	assert.Equal(t, 0, requests)
+	s.mockStore.AssertNotCalled(t, "DeleteDelivery", mock.Anything)
+}
+
+func (s *NotifierTestSuite) TestRetryDueDeadLettersExhaustedDelivery() {
+	t := s.T()
+
+	requests := 0
+	server := respondWith(http.StatusInternalServerError, &requests)
+	defer server.Close()
+
+	s.mockStore.On("PendingDeliveries").Return([]Delivery{{ID: "due", URL: server.URL, Attempts: 2, Payload: Payload{ExecutionName: "proctor-ipsum-lorem"}}}, nil).Once()
+	s.mockStore.On("ClaimDelivery", "due", deliveryClaimLease).Return(true, nil).Once()
+	s.mockStore.On("SaveDeadLetter", mock.MatchedBy(func(deadLetter DeadLetter) bool {
+		return deadLetter.DeliveryID == "due" &&
+			deadLetter.URL == server.URL &&
+			deadLetter.Attempts == 3 &&
+			deadLetter.Payload.ExecutionName == "proctor-ipsum-lorem" &&
+			deadLetter.LastError == "webhook responded with status 500"
+	})).Return(nil).Once()
+	s.mockStore.On("DeleteDelivery", "due").Return(nil).Once()
+	s.mockStore.On("ReleaseDelivery", "due").Return(nil).Once()
+
+	s.testNotifier.retryDue(context.Background())
+
+	assert.Equal(t, 1, requests)
+	s.mockStore.AssertExpectations(t)
+}
+
+func (s *NotifierTestSuite) TestRedeliver() {
+	t := s.T()
+
+	deadLetter := &DeadLetter{DeliveryID: "delivery-id", URL: "http://example.com/hook", Attempts: 3, LastError: "timeout"}
+	s.mockStore.On("GetDeadLetter", "delivery-id").Return(deadLetter, nil).Once()
+	s.mockStore.On("SaveDelivery", mock.MatchedBy(func(delivery Delivery) bool {
+		return delivery.ID == "delivery-id" && delivery.URL == "http://example.com/hook" && delivery.Attempts == 0
+	})).Return(nil).Once()
+	s.mockStore.On("DeleteDeadLetter", "delivery-id").Return(nil).Once()
+
+	err := s.testNotifier.Redeliver("delivery-id")
+	assert.NoError(t, err)
+
+	s.mockStore.AssertExpectations(t)
+}
+
+func (s *NotifierTestSuite) TestRedeliverUnknownDeadLetter() {
+	t := s.T()
+
+	s.mockStore.On("GetDeadLetter", "delivery-id").Return(&DeadLetter{}, ErrDeadLetterNotFound).Once()
+
+	err := s.testNotifier.Redeliver("delivery-id")
+	assert.Equal(t, ErrDeadLetterNotFound, err)
+
+	s.mockStore.AssertNotCalled(t, "SaveDelivery", mock.Anything)
+}
+
+func TestNotifierTestSuite(t *testing.T) {
+	suite.Run(t, new(NotifierTestSuite))
+}
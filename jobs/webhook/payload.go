@@ -0,0 +1,32 @@
+package webhook
+
+import "time"
+
+type Payload struct {
+	ExecutionName   string    `json:"execution_name"`
+	JobName         string    `json:"job_name"`
+	Status          string    `json:"status"`
+	ExitCode        *int32    `json:"exit_code,omitempty"`
+	StartedAt       time.Time `json:"started_at"`
+	FinishedAt      time.Time `json:"finished_at"`
+	DurationSeconds float64   `json:"duration_seconds"`
+	LogsURL         string    `json:"logs_url"`
+}
+
+type Delivery struct {
+	ID            string    `json:"id"`
+	URL           string    `json:"url"`
+	Payload       Payload   `json:"payload"`
+	Attempts      int       `json:"attempts"`
+	LastError     string    `json:"last_error,omitempty"`
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+}
+
+type DeadLetter struct {
+	DeliveryID string    `json:"delivery_id"`
+	URL        string    `json:"url"`
+	Payload    Payload   `json:"payload"`
+	Attempts   int       `json:"attempts"`
+	LastError  string    `json:"last_error"`
+	FailedAt   time.Time `json:"failed_at"`
+}
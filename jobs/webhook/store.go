@@ -0,0 +1,185 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/gojektech/proctor-engine/config"
+	"github.com/gojektech/proctor-engine/redis"
+)
+
+const (
+	DeadLetterKeySuffix    = "-webhook-dead-letter"
+	DeliveryKeySuffix      = "-webhook-delivery"
+	DeliveryClaimKeySuffix = "-webhook-delivery-claim"
+	DeadLettersKey         = "proctor-webhook-dead-letters"
+	PendingDeliveriesKey   = "proctor-webhook-pending-deliveries"
+)
+
+var ErrDeadLetterNotFound = errors.New("webhook dead letter not found")
+
+type Store interface {
+	SaveDelivery(Delivery) error
+	PendingDeliveries() ([]Delivery, error)
+	ClaimDelivery(string, time.Duration) (bool, error)
+	ReleaseDelivery(string) error
+	DeleteDelivery(string) error
+	SaveDeadLetter(DeadLetter) error
+	GetDeadLetter(string) (*DeadLetter, error)
+	DeadLetters() ([]DeadLetter, error)
+	DeleteDeadLetter(string) error
+}
+
+type store struct {
+	redisClient redis.Client
+}
+
+func NewStore(redisClient redis.Client) Store {
+	return &store{
+		redisClient: redisClient,
+	}
+}
+
+func deadLetterKey(deliveryID string) string {
+	return deliveryID + DeadLetterKeySuffix
+}
+
+func deliveryKey(deliveryID string) string {
+	return deliveryID + DeliveryKeySuffix
+}
+
+func deliveryClaimKey(deliveryID string) string {
+	return deliveryID + DeliveryClaimKeySuffix
+}
+
+func expiryInSeconds() int {
+	return int(config.WebhookDeadLetterExpiry().Seconds())
+}
+
+// members loads every record indexed under indexKey, dropping ids whose
+// records have expired.
+func (store *store) members(indexKey string, key func(string) string) ([][]byte, error) {
+	ids, err := store.redisClient.SMEMBERS(indexKey)
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+
+	keys := make([]interface{}, len(ids))
+	for i, id := range ids {
+		keys[i] = key(id)
+	}
+	values, err := store.redisClient.MGET(keys...)
+	if err != nil {
+		return nil, err
+	}
+
+	var records [][]byte
+	for i, value := range values {
+		if value == nil {
+			store.redisClient.SREM(indexKey, ids[i])
+			continue
+		}
+		records = append(records, value)
+	}
+	return records, nil
+}
+
+func (store *store) SaveDelivery(delivery Delivery) error {
+	binaryDelivery, err := json.Marshal(delivery)
+	if err != nil {
+		return err
+	}
+
+	if err := store.redisClient.SETEX(deliveryKey(delivery.ID), expiryInSeconds(), binaryDelivery); err != nil {
+		return err
+	}
+	return store.redisClient.SADD(PendingDeliveriesKey, delivery.ID)
+}
+
+func (store *store) PendingDeliveries() ([]Delivery, error) {
+	records, err := store.members(PendingDeliveriesKey, deliveryKey)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]Delivery, 0, len(records))
+	for _, record := range records {
+		var delivery Delivery
+		if err := json.Unmarshal(record, &delivery); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, nil
+}
+
+func (store *store) ClaimDelivery(deliveryID string, lease time.Duration) (bool, error) {
+	return store.redisClient.SETNX(deliveryClaimKey(deliveryID), int(lease.Seconds()), []byte(deliveryID))
+}
+
+func (store *store) ReleaseDelivery(deliveryID string) error {
+	return store.redisClient.DEL(deliveryClaimKey(deliveryID))
+}
+
+func (store *store) DeleteDelivery(deliveryID string) error {
+	if err := store.redisClient.DEL(deliveryKey(deliveryID)); err != nil {
+		return err
+	}
+	if err := store.redisClient.SREM(PendingDeliveriesKey, deliveryID); err != nil {
+		return err
+	}
+	return store.redisClient.DEL(deliveryClaimKey(deliveryID))
+}
+
+func (store *store) SaveDeadLetter(deadLetter DeadLetter) error {
+	binaryDeadLetter, err := json.Marshal(deadLetter)
+	if err != nil {
+		return err
+	}
+
+	if err := store.redisClient.SETEX(deadLetterKey(deadLetter.DeliveryID), expiryInSeconds(), binaryDeadLetter); err != nil {
+		return err
+	}
+	return store.redisClient.SADD(DeadLettersKey, deadLetter.DeliveryID)
+}
+
+func (store *store) GetDeadLetter(deliveryID string) (*DeadLetter, error) {
+	binaryDeadLetter, err := store.redisClient.GET(deadLetterKey(deliveryID))
+	if err == redis.ErrNil {
+		return nil, ErrDeadLetterNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var deadLetter DeadLetter
+	if err := json.Unmarshal(binaryDeadLetter, &deadLetter); err != nil {
+		return nil, err
+	}
+	return &deadLetter, nil
+}
+
+func (store *store) DeadLetters() ([]DeadLetter, error) {
+	records, err := store.members(DeadLettersKey, deadLetterKey)
+	if err != nil {
+		return nil, err
+	}
+
+	deadLetters := make([]DeadLetter, 0, len(records))
+	for _, record := range records {
+		var deadLetter DeadLetter
+		if err := json.Unmarshal(record, &deadLetter); err != nil {
+			return nil, err
+		}
+		deadLetters = append(deadLetters, deadLetter)
+	}
+	return deadLetters, nil
+}
+
+func (store *store) DeleteDeadLetter(deliveryID string) error {
+	if err := store.redisClient.DEL(deadLetterKey(deliveryID)); err != nil {
+		return err
+	}
+	return store.redisClient.SREM(DeadLettersKey, deliveryID)
+}
@@ -0,0 +1,56 @@
+package webhook
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockStore struct {
+	mock.Mock
+}
+
+func (m *MockStore) SaveDelivery(delivery Delivery) error {
+	args := m.Called(delivery)
+	return args.Error(0)
+}
+
+func (m *MockStore) PendingDeliveries() ([]Delivery, error) {
+	args := m.Called()
+	return args.Get(0).([]Delivery), args.Error(1)
+}
+
+func (m *MockStore) ClaimDelivery(deliveryID string, lease time.Duration) (bool, error) {
+	args := m.Called(deliveryID, lease)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockStore) ReleaseDelivery(deliveryID string) error {
+	args := m.Called(deliveryID)
+	return args.Error(0)
+}
+
+func (m *MockStore) DeleteDelivery(deliveryID string) error {
+	args := m.Called(deliveryID)
+	return args.Error(0)
+}
+
+func (m *MockStore) SaveDeadLetter(deadLetter DeadLetter) error {
+	args := m.Called(deadLetter)
+	return args.Error(0)
+}
+
+func (m *MockStore) GetDeadLetter(deliveryID string) (*DeadLetter, error) {
+	args := m.Called(deliveryID)
+	return args.Get(0).(*DeadLetter), args.Error(1)
+}
+
+func (m *MockStore) DeadLetters() ([]DeadLetter, error) {
+	args := m.Called()
+	return args.Get(0).([]DeadLetter), args.Error(1)
+}
+
+func (m *MockStore) DeleteDeadLetter(deliveryID string) error {
+	args := m.Called(deliveryID)
+	return args.Error(0)
+}
@@ -0,0 +1,157 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gojektech/proctor-engine/config"
+	"github.com/gojektech/proctor-engine/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type WebhookStoreTestSuite struct {
+	suite.Suite
+	mockRedisClient  *redis.MockClient
+	testWebhookStore Store
+}
+
+func (s *WebhookStoreTestSuite) SetupTest() {
+	s.mockRedisClient = &redis.MockClient{}
+
+	s.testWebhookStore = NewStore(s.mockRedisClient)
+}
+
+func (s *WebhookStoreTestSuite) TestSaveDeadLetter() {
+	t := s.T()
+
+	deadLetter := DeadLetter{
+		DeliveryID: "delivery-id",
+		URL:        "http://example.com/hook",
+		Attempts:   5,
+	}
+	binaryDeadLetter, err := json.Marshal(deadLetter)
+	assert.NoError(t, err)
+
+	expiry := int(config.WebhookDeadLetterExpiry().Seconds())
+	s.mockRedisClient.On("SETEX", "delivery-id-webhook-dead-letter", expiry, binaryDeadLetter).Return(nil).Once()
+	s.mockRedisClient.On("SADD", DeadLettersKey, "delivery-id").Return(nil).Once()
+
+	err = s.testWebhookStore.SaveDeadLetter(deadLetter)
+	assert.NoError(t, err)
+
+	s.mockRedisClient.AssertExpectations(t)
+}
+
+func (s *WebhookStoreTestSuite) TestSaveDeadLetterRedisFailure() {
+	t := s.T()
+
+	binaryDeadLetter, err := json.Marshal(DeadLetter{DeliveryID: "delivery-id"})
+	assert.NoError(t, err)
+
+	expiry := int(config.WebhookDeadLetterExpiry().Seconds())
+	s.mockRedisClient.On("SETEX", "delivery-id-webhook-dead-letter", expiry, binaryDeadLetter).Return(errors.New("error")).Once()
+
+	err = s.testWebhookStore.SaveDeadLetter(DeadLetter{DeliveryID: "delivery-id"})
+	assert.EqualError(t, err, "error")
+
+	s.mockRedisClient.AssertNotCalled(t, "SADD", DeadLettersKey, "delivery-id")
+}
+
+func (s *WebhookStoreTestSuite) TestDeadLettersDropsExpiredEntries() {
+	t := s.T()
+
+	binaryDeadLetter, err := json.Marshal(DeadLetter{DeliveryID: "live"})
+	assert.NoError(t, err)
+
+	s.mockRedisClient.On("SMEMBERS", DeadLettersKey).Return([]string{"live", "expired"}, nil).Once()
+	s.mockRedisClient.On("MGET", "live-webhook-dead-letter", "expired-webhook-dead-letter").Return([][]byte{binaryDeadLetter, nil}, nil).Once()
+	s.mockRedisClient.On("SREM", DeadLettersKey, "expired").Return(nil).Once()
+
+	deadLetters, err := s.testWebhookStore.DeadLetters()
+	assert.NoError(t, err)
+	assert.Equal(t, []DeadLetter{{DeliveryID: "live"}}, deadLetters)
+
+	s.mockRedisClient.AssertExpectations(t)
+}
+
+func (s *WebhookStoreTestSuite) TestGetMissingDeadLetter() {
+	t := s.T()
+
+	s.mockRedisClient.On("GET", "delivery-id-webhook-dead-letter").Return([]byte(nil), redis.ErrNil).Once()
+
+	_, err := s.testWebhookStore.GetDeadLetter("delivery-id")
+	assert.Equal(t, ErrDeadLetterNotFound, err)
+}
+
+func (s *WebhookStoreTestSuite) TestDeleteDeadLetter() {
+	t := s.T()
+
+	s.mockRedisClient.On("DEL", "delivery-id-webhook-dead-letter").Return(nil).Once()
+	s.mockRedisClient.On("SREM", DeadLettersKey, "delivery-id").Return(nil).Once()
+
+	err := s.testWebhookStore.DeleteDeadLetter("delivery-id")
+	assert.NoError(t, err)
+
+	s.mockRedisClient.AssertExpectations(t)
+}
+
+func (s *WebhookStoreTestSuite) TestSaveDelivery() {
+	t := s.T()
+
+	delivery := Delivery{ID: "delivery-id", URL: "http://example.com/hook", Attempts: 1}
+	binaryDelivery, err := json.Marshal(delivery)
+	assert.NoError(t, err)
+
+	expiry := int(config.WebhookDeadLetterExpiry().Seconds())
+	s.mockRedisClient.On("SETEX", "delivery-id-webhook-delivery", expiry, binaryDelivery).Return(nil).Once()
+	s.mockRedisClient.On("SADD", PendingDeliveriesKey, "delivery-id").Return(nil).Once()
+
+	err = s.testWebhookStore.SaveDelivery(delivery)
+	assert.NoError(t, err)
+
+	s.mockRedisClient.AssertExpectations(t)
+}
+
+func (s *WebhookStoreTestSuite) TestPendingDeliveries() {
+	t := s.T()
+
+	binaryDelivery, err := json.Marshal(Delivery{ID: "delivery-id", Attempts: 2})
+	assert.NoError(t, err)
+
+	s.mockRedisClient.On("SMEMBERS", PendingDeliveriesKey).Return([]string{"delivery-id"}, nil).Once()
+	s.mockRedisClient.On("MGET", "delivery-id-webhook-delivery").Return([][]byte{binaryDelivery}, nil).Once()
+
+	deliveries, err := s.testWebhookStore.PendingDeliveries()
+	assert.NoError(t, err)
+	assert.Equal(t, []Delivery{{ID: "delivery-id", Attempts: 2}}, deliveries)
+}
+
+func (s *WebhookStoreTestSuite) TestClaimDelivery() {
+	t := s.T()
+
+	s.mockRedisClient.On("SETNX", "delivery-id-webhook-delivery-claim", 60, []byte("delivery-id")).Return(true, nil).Once()
+
+	claimed, err := s.testWebhookStore.ClaimDelivery("delivery-id", time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+}
+
+func (s *WebhookStoreTestSuite) TestDeleteDelivery() {
+	t := s.T()
+
+	s.mockRedisClient.On("DEL", "delivery-id-webhook-delivery").Return(nil).Once()
+	s.mockRedisClient.On("SREM", PendingDeliveriesKey, "delivery-id").Return(nil).Once()
+	s.mockRedisClient.On("DEL", "delivery-id-webhook-delivery-claim").Return(nil).Once()
+
+	err := s.testWebhookStore.DeleteDelivery("delivery-id")
+	assert.NoError(t, err)
+
+	s.mockRedisClient.AssertExpectations(t)
+}
+
+func TestWebhookStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(WebhookStoreTestSuite))
+}
@@ -0,0 +1,120 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gojektech/proctor-engine/config"
+	"github.com/gojektech/proctor-engine/utility"
+)
+
+var ErrPrivateTarget = errors.New("webhook target resolves to a private address")
+
+var privateNetworks = parseNetworks("10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "100.64.0.0/10", "fc00::/7")
+
+func parseNetworks(cidrs ...string) []*net.IPNet {
+	var networks []*net.IPNet
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err.Error())
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
+
+// AllowedHosts lists hosts that may receive webhooks even though they
+// resolve to private addresses, such as services inside the cluster.
+func AllowedHosts() []string {
+	var hosts []string
+	for _, host := range strings.Split(config.WebhookAllowedHosts(), ",") {
+		if host = strings.TrimSpace(host); host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts
+}
+
+func isAllowedHost(host string, allowedHosts []string) bool {
+	for _, allowedHost := range allowedHosts {
+		if strings.EqualFold(host, allowedHost) {
+			return true
+		}
+	}
+	return false
+}
+
+func isPrivateIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return true
+	}
+	for _, network := range privateNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPermittedURL rejects urls that name a private address outright. Hosts
+// given by name are checked again when a delivery resolves them.
+func IsPermittedURL(rawURL string) bool {
+	if !utility.IsHTTPURL(rawURL) {
+		return false
+	}
+
+	parsedURL, _ := url.Parse(rawURL)
+	host := parsedURL.Hostname()
+	if isAllowedHost(host, AllowedHosts()) {
+		return true
+	}
+	if strings.EqualFold(host, "localhost") {
+		return false
+	}
+	ip := net.ParseIP(host)
+	return ip == nil || !isPrivateIP(ip)
+}
+
+// newTransport resolves hosts itself so that the address it checks is the
+// address it connects to, and does not use a proxy, which would connect on
+// its behalf.
+func newTransport(allowedHosts []string) *http.Transport {
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+
+	return &http.Transport{
+		DialContext: func(ctx context.Context, network string, address string) (net.Conn, error) {
+			host, port, err := net.SplitHostPort(address)
+			if err != nil {
+				return nil, err
+			}
+			if isAllowedHost(host, allowedHosts) {
+				return dialer.DialContext(ctx, network, address)
+			}
+
+			addresses, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+			if err != nil {
+				return nil, err
+			}
+			for _, ipAddress := range addresses {
+				if isPrivateIP(ipAddress.IP) {
+					return nil, ErrPrivateTarget
+				}
+			}
+			return dialer.DialContext(ctx, network, net.JoinHostPort(addresses[0].IP.String(), port))
+		},
+		TLSHandshakeTimeout: 10 * time.Second,
+	}
+}
+
+func isPrivateTargetError(err error) bool {
+	if urlErr, ok := err.(*url.Error); ok {
+		err = urlErr.Err
+	}
+	return err == ErrPrivateTarget
+}
@@ -0,0 +1,45 @@
+package webhook
+
+import (
+	"net"
+	"os"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsPermittedURL(t *testing.T) {
+	assert.True(t, IsPermittedURL("https://hooks.example.com/proctor"))
+	assert.True(t, IsPermittedURL("http://93.184.216.34:8080/proctor"))
+
+	assert.False(t, IsPermittedURL("ftp://hooks.example.com/proctor"))
+	assert.False(t, IsPermittedURL("http://localhost:5000/proctor"))
+	assert.False(t, IsPermittedURL("http://127.0.0.1/proctor"))
+	assert.False(t, IsPermittedURL("http://10.1.2.3/proctor"))
+	assert.False(t, IsPermittedURL("http://169.254.169.254/latest/meta-data"))
+	assert.False(t, IsPermittedURL("http://[::1]:5000/proctor"))
+	assert.False(t, IsPermittedURL("http://[fd00::1]/proctor"))
+}
+
+func TestIsPermittedURLWithAllowedHosts(t *testing.T) {
+	os.Setenv("PROCTOR_WEBHOOK_ALLOWED_HOSTS", "localhost, 10.1.2.3")
+	defer os.Unsetenv("PROCTOR_WEBHOOK_ALLOWED_HOSTS")
+	viper.AutomaticEnv()
+
+	assert.Equal(t, []string{"localhost", "10.1.2.3"}, AllowedHosts())
+	assert.True(t, IsPermittedURL("http://localhost:5000/proctor"))
+	assert.True(t, IsPermittedURL("http://10.1.2.3/proctor"))
+	assert.False(t, IsPermittedURL("http://10.1.2.4/proctor"))
+}
+
+func TestIsPrivateIP(t *testing.T) {
+	assert.True(t, isPrivateIP(net.ParseIP("172.20.0.1")))
+	assert.True(t, isPrivateIP(net.ParseIP("192.168.1.1")))
+	assert.True(t, isPrivateIP(net.ParseIP("100.64.0.1")))
+	assert.True(t, isPrivateIP(net.ParseIP("::ffff:127.0.0.1")))
+	assert.True(t, isPrivateIP(net.ParseIP("0.0.0.0")))
+
+	assert.False(t, isPrivateIP(net.ParseIP("172.32.0.1")))
+	assert.False(t, isPrivateIP(net.ParseIP("2606:4700::1111")))
+}
@@ -0,0 +1,7 @@
+package workflow
+
+import "context"
+
+type Executor interface {
+	Execute(ctx context.Context, jobName string, args map[string]string, callbackURL string, requester string, approvedBy []string) (string, error)
+}
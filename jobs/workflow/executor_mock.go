@@ -0,0 +1,16 @@
+package workflow
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockExecutor struct {
+	mock.Mock
+}
+
+func (m *MockExecutor) Execute(ctx context.Context, jobName string, args map[string]string, callbackURL string, requester string, approvedBy []string) (string, error) {
+	arguments := m.Called(ctx, jobName, args, callbackURL, requester, approvedBy)
+	return arguments.String(0), arguments.Error(1)
+}
@@ -0,0 +1,215 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gojektech/proctor-engine/jobs/metadata"
+	"github.com/gojektech/proctor-engine/logger"
+	"github.com/gojektech/proctor-engine/utility"
+
+	"github.com/gorilla/mux"
+)
+
+type workflowHandler struct {
+	store         Store
+	metadataStore metadata.Store
+	runner        Runner
+}
+
+type WorkflowHandler interface {
+	HandleSubmission() http.HandlerFunc
+	HandleDisplay() http.HandlerFunc
+	HandleStart() http.HandlerFunc
+	HandleRunDisplay() http.HandlerFunc
+	HandleCancellation() http.HandlerFunc
+}
+
+type startRequest struct {
+	Inputs map[string]string `json:"inputs"`
+}
+
+func NewWorkflowHandler(store Store, metadataStore metadata.Store, runner Runner) WorkflowHandler {
+	return &workflowHandler{
+		store:         store,
+		metadataStore: metadataStore,
+		runner:        runner,
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
+
+func (workflowHandler *workflowHandler) HandleSubmission() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		log := logger.FromContext(req.Context())
+
+		var workflow Workflow
+		err := json.NewDecoder(req.Body).Decode(&workflow)
+		defer req.Body.Close()
+		if err != nil {
+			log.Error("Error parsing request body", err.Error())
+
+			utility.WriteError(w, req, http.StatusBadRequest, utility.ErrCodeMalformedRequest, utility.ClientError)
+			return
+		}
+
+		if fieldErrors := workflow.Validate(); len(fieldErrors) > 0 {
+			utility.WriteValidationError(w, req, fieldErrors)
+			return
+		}
+
+		var fieldErrors []utility.FieldError
+		for i, step := range workflow.Steps {
+			field := fmt.Sprintf("steps[%d].job", i)
+
+			jobMetadata, err := workflowHandler.metadataStore.GetJobMetadata(step.Job)
+			if err == metadata.ErrJobNotFound {
+				fieldErrors = append(fieldErrors, utility.FieldError{Field: field, Message: "must name an existing job"})
+				continue
+			}
+			if err != nil {
+				log.WithField(logger.JobNameField, step.Job).Error("Error fetching metadata of workflow step", err.Error())
+
+				utility.WriteError(w, req, http.StatusServiceUnavailable, utility.ErrCodeStoreUnavailable, "unable to fetch job metadata")
+				return
+			}
+
+			if jobMetadata.RequiredApprovals > 0 {
+				fieldErrors = append(fieldErrors, utility.FieldError{Field: field, Message: "must not require approval"})
+			}
+		}
+		if len(fieldErrors) > 0 {
+			utility.WriteValidationError(w, req, fieldErrors)
+			return
+		}
+
+		err = workflowHandler.store.SaveWorkflow(workflow)
+		if err != nil {
+			log.Error("Error saving workflow", err.Error())
+
+			utility.WriteError(w, req, http.StatusServiceUnavailable, utility.ErrCodeStoreUnavailable, "unable to save workflow")
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+	}
+}
+
+func (workflowHandler *workflowHandler) HandleDisplay() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		workflow, ok := workflowHandler.workflow(w, req)
+		if !ok {
+			return
+		}
+
+		writeJSON(w, http.StatusOK, workflow)
+	}
+}
+
+func (workflowHandler *workflowHandler) HandleStart() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		log := logger.FromContext(req.Context())
+
+		var request startRequest
+		err := json.NewDecoder(req.Body).Decode(&request)
+		defer req.Body.Close()
+		if err != nil && err != io.EOF {
+			log.Error("Error parsing request body", err.Error())
+
+			utility.WriteError(w, req, http.StatusBadRequest, utility.ErrCodeMalformedRequest, utility.ClientError)
+			return
+		}
+
+		workflow, ok := workflowHandler.workflow(w, req)
+		if !ok {
+			return
+		}
+
+		run, err := workflowHandler.runner.Start(req.Context(), *workflow, request.Inputs, req.Header.Get(utility.UserEmailHeaderKey))
+		if err != nil {
+			log.Error("Error starting workflow run", err.Error())
+
+			utility.WriteError(w, req, http.StatusServiceUnavailable, utility.ErrCodeStoreUnavailable, "unable to start workflow run")
+			return
+		}
+
+		log.WithField(logger.WorkflowRunIDField, run.ID).Info("Started workflow run of ", workflow.Name)
+
+		writeJSON(w, http.StatusCreated, run)
+	}
+}
+
+func (workflowHandler *workflowHandler) HandleRunDisplay() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		run, ok := workflowHandler.run(w, req)
+		if !ok {
+			return
+		}
+
+		writeJSON(w, http.StatusOK, run)
+	}
+}
+
+func (workflowHandler *workflowHandler) HandleCancellation() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		run, ok := workflowHandler.run(w, req)
+		if !ok {
+			return
+		}
+
+		if run.HasFinished() {
+			utility.WriteError(w, req, http.StatusConflict, utility.ErrCodeWorkflowRunFinished, fmt.Sprintf("workflow run %s has already finished", run.ID))
+			return
+		}
+
+		err := workflowHandler.store.RequestCancellation(run.ID)
+		if err != nil {
+			logger.FromContext(req.Context()).Error("Error requesting cancellation of workflow run", err.Error())
+
+			utility.WriteError(w, req, http.StatusServiceUnavailable, utility.ErrCodeStoreUnavailable, "unable to cancel workflow run")
+			return
+		}
+
+		writeJSON(w, http.StatusAccepted, run)
+	}
+}
+
+func (workflowHandler *workflowHandler) workflow(w http.ResponseWriter, req *http.Request) (*Workflow, bool) {
+	name := mux.Vars(req)["name"]
+
+	workflow, err := workflowHandler.store.GetWorkflow(name)
+	if err == ErrWorkflowNotFound {
+		utility.WriteError(w, req, http.StatusNotFound, utility.ErrCodeWorkflowNotFound, fmt.Sprintf("workflow %s not found", name))
+		return nil, false
+	}
+	if err != nil {
+		logger.FromContext(req.Context()).Error("Error fetching workflow", err.Error())
+
+		utility.WriteError(w, req, http.StatusServiceUnavailable, utility.ErrCodeStoreUnavailable, "unable to fetch workflow")
+		return nil, false
+	}
+	return workflow, true
+}
+
+func (workflowHandler *workflowHandler) run(w http.ResponseWriter, req *http.Request) (*Run, bool) {
+	vars := mux.Vars(req)
+
+	run, err := workflowHandler.store.GetRun(vars["id"])
+	if err == ErrRunNotFound || (err == nil && run.WorkflowName != vars["name"]) {
+		utility.WriteError(w, req, http.StatusNotFound, utility.ErrCodeWorkflowRunNotFound, fmt.Sprintf("workflow run %s not found", vars["id"]))
+		return nil, false
+	}
+	if err != nil {
+		logger.FromContext(req.Context()).Error("Error fetching workflow run", err.Error())
+
+		utility.WriteError(w, req, http.StatusServiceUnavailable, utility.ErrCodeStoreUnavailable, "unable to fetch workflow run")
+		return nil, false
+	}
+	return run, true
+}
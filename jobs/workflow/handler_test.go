@@ -0,0 +1,226 @@
+package workflow
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gojektech/proctor-engine/jobs/metadata"
+	"github.com/gojektech/proctor-engine/utility"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type WorkflowHandlerTestSuite struct {
+	suite.Suite
+	mockStore           *MockStore
+	mockMetadataStore   *metadata.MockStore
+	mockRunner          *MockRunner
+	testWorkflowHandler WorkflowHandler
+	testRouter          *mux.Router
+}
+
+func (s *WorkflowHandlerTestSuite) SetupTest() {
+	s.mockStore = &MockStore{}
+	s.mockMetadataStore = &metadata.MockStore{}
+	s.mockRunner = &MockRunner{}
+	s.testWorkflowHandler = NewWorkflowHandler(s.mockStore, s.mockMetadataStore, s.mockRunner)
+
+	s.testRouter = mux.NewRouter()
+	s.testRouter.HandleFunc("/workflows", s.testWorkflowHandler.HandleSubmission()).Methods("POST")
+	s.testRouter.HandleFunc("/workflows/{name}", s.testWorkflowHandler.HandleDisplay()).Methods("GET")
+	s.testRouter.HandleFunc("/workflows/{name}/runs", s.testWorkflowHandler.HandleStart()).Methods("POST")
+	s.testRouter.HandleFunc("/workflows/{name}/runs/{id}", s.testWorkflowHandler.HandleRunDisplay()).Methods("GET")
+	s.testRouter.HandleFunc("/workflows/{name}/runs/{id}/cancel", s.testWorkflowHandler.HandleCancellation()).Methods("POST")
+}
+
+func (s *WorkflowHandlerTestSuite) serve(method, path string, body interface{}) *httptest.ResponseRecorder {
+	var requestBody []byte
+	if body != nil {
+		var err error
+		requestBody, err = json.Marshal(body)
+		assert.NoError(s.T(), err)
+	}
+
+	req := httptest.NewRequest(method, path, bytes.NewReader(requestBody))
+	responseRecorder := httptest.NewRecorder()
+
+	s.testRouter.ServeHTTP(responseRecorder, req)
+	return responseRecorder
+}
+
+func errorResponse(t *testing.T, responseRecorder *httptest.ResponseRecorder) utility.Error {
+	var errorResponse utility.ErrorResponse
+	err := json.Unmarshal(responseRecorder.Body.Bytes(), &errorResponse)
+	assert.NoError(t, err)
+	return errorResponse.Error
+}
+
+func (s *WorkflowHandlerTestSuite) TestSubmission() {
+	t := s.T()
+
+	workflow := migrationWorkflow()
+	s.mockMetadataStore.On("GetJobMetadata", mock.Anything).Return(&metadata.Metadata{}, nil)
+	s.mockStore.On("SaveWorkflow", workflow).Return(nil).Once()
+
+	responseRecorder := s.serve("POST", "/workflows", workflow)
+
+	s.mockStore.AssertExpectations(t)
+	assert.Equal(t, http.StatusCreated, responseRecorder.Code)
+}
+
+func (s *WorkflowHandlerTestSuite) TestSubmissionWithInvalidWorkflow() {
+	t := s.T()
+
+	responseRecorder := s.serve("POST", "/workflows", Workflow{Name: "migrate"})
+
+	s.mockStore.AssertNotCalled(t, "SaveWorkflow", mock.Anything)
+	assert.Equal(t, http.StatusUnprocessableEntity, responseRecorder.Code)
+}
+
+func (s *WorkflowHandlerTestSuite) TestSubmissionWithUnusableJobs() {
+	t := s.T()
+
+	workflow := Workflow{Name: "migrate", Steps: []Step{{Name: "snapshot", Job: "db-snapshot"}, {Name: "migrate", Job: "db-migrate"}}}
+	s.mockMetadataStore.On("GetJobMetadata", "db-snapshot").Return((*metadata.Metadata)(nil), metadata.ErrJobNotFound).Once()
+	s.mockMetadataStore.On("GetJobMetadata", "db-migrate").Return(&metadata.Metadata{RequiredApprovals: 1}, nil).Once()
+
+	responseRecorder := s.serve("POST", "/workflows", workflow)
+
+	s.mockStore.AssertNotCalled(t, "SaveWorkflow", mock.Anything)
+	assert.Equal(t, http.StatusUnprocessableEntity, responseRecorder.Code)
+	details := errorResponse(t, responseRecorder).Details
+	assert.Equal(t, "steps[0].job", details[0].Field)
+	assert.Equal(t, "steps[1].job", details[1].Field)
+}
+
+func (s *WorkflowHandlerTestSuite) TestDisplay() {
+	t := s.T()
+
+	workflow := migrationWorkflow()
+	s.mockStore.On("GetWorkflow", "migrate").Return(&workflow, nil).Once()
+
+	responseRecorder := s.serve("GET", "/workflows/migrate", nil)
+
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+	var displayedWorkflow Workflow
+	assert.NoError(t, json.NewDecoder(responseRecorder.Body).Decode(&displayedWorkflow))
+	assert.Equal(t, workflow, displayedWorkflow)
+}
+
+func (s *WorkflowHandlerTestSuite) TestStart() {
+	t := s.T()
+
+	workflow := migrationWorkflow()
+	inputs := map[string]string{"DATABASE": "orders"}
+	run := newRun("run-id", workflow, inputs, "")
+	s.mockStore.On("GetWorkflow", "migrate").Return(&workflow, nil).Once()
+	s.mockRunner.On("Start", mock.Anything, workflow, inputs, "").Return(&run, nil).Once()
+
+	responseRecorder := s.serve("POST", "/workflows/migrate/runs", startRequest{Inputs: inputs})
+
+	s.mockRunner.AssertExpectations(t)
+	assert.Equal(t, http.StatusCreated, responseRecorder.Code)
+	var startedRun Run
+	assert.NoError(t, json.NewDecoder(responseRecorder.Body).Decode(&startedRun))
+	assert.Equal(t, "run-id", startedRun.ID)
+	assert.Len(t, startedRun.Steps, len(workflow.Steps))
+}
+
+func (s *WorkflowHandlerTestSuite) TestStartWithoutBody() {
+	t := s.T()
+
+	workflow := migrationWorkflow()
+	run := newRun("run-id", workflow, nil, "")
+	s.mockStore.On("GetWorkflow", "migrate").Return(&workflow, nil).Once()
+	s.mockRunner.On("Start", mock.Anything, workflow, map[string]string(nil), "").Return(&run, nil).Once()
+
+	responseRecorder := s.serve("POST", "/workflows/migrate/runs", nil)
+
+	assert.Equal(t, http.StatusCreated, responseRecorder.Code)
+}
+
+func (s *WorkflowHandlerTestSuite) TestStartUnknownWorkflow() {
+	t := s.T()
+
+	s.mockStore.On("GetWorkflow", "migrate").Return((*Workflow)(nil), ErrWorkflowNotFound).Once()
+
+	responseRecorder := s.serve("POST", "/workflows/migrate/runs", nil)
+
+	s.mockRunner.AssertNotCalled(t, "Start", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	assert.Equal(t, http.StatusNotFound, responseRecorder.Code)
+	assert.Equal(t, utility.ErrCodeWorkflowNotFound, errorResponse(t, responseRecorder).Code)
+}
+
+func (s *WorkflowHandlerTestSuite) TestRunDisplay() {
+	t := s.T()
+
+	run := newRun("run-id", migrationWorkflow(), nil, "")
+	s.mockStore.On("GetRun", "run-id").Return(&run, nil).Once()
+
+	responseRecorder := s.serve("GET", "/workflows/migrate/runs/run-id", nil)
+
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+	var displayedRun Run
+	assert.NoError(t, json.NewDecoder(responseRecorder.Body).Decode(&displayedRun))
+	assert.Equal(t, run.Steps, displayedRun.Steps)
+}
+
+func (s *WorkflowHandlerTestSuite) TestRunDisplayOfOtherWorkflow() {
+	t := s.T()
+
+	run := newRun("run-id", migrationWorkflow(), nil, "")
+	s.mockStore.On("GetRun", "run-id").Return(&run, nil).Once()
+
+	responseRecorder := s.serve("GET", "/workflows/other/runs/run-id", nil)
+
+	assert.Equal(t, http.StatusNotFound, responseRecorder.Code)
+	assert.Equal(t, utility.ErrCodeWorkflowRunNotFound, errorResponse(t, responseRecorder).Code)
+}
+
+func (s *WorkflowHandlerTestSuite) TestRunDisplayStoreFailure() {
+	t := s.T()
+
+	s.mockStore.On("GetRun", "run-id").Return((*Run)(nil), errors.New("error")).Once()
+
+	responseRecorder := s.serve("GET", "/workflows/migrate/runs/run-id", nil)
+
+	assert.Equal(t, http.StatusServiceUnavailable, responseRecorder.Code)
+}
+
+func (s *WorkflowHandlerTestSuite) TestCancellation() {
+	t := s.T()
+
+	run := newRun("run-id", migrationWorkflow(), nil, "")
+	s.mockStore.On("GetRun", "run-id").Return(&run, nil).Once()
+	s.mockStore.On("RequestCancellation", "run-id").Return(nil).Once()
+
+	responseRecorder := s.serve("POST", "/workflows/migrate/runs/run-id/cancel", nil)
+
+	s.mockStore.AssertExpectations(t)
+	assert.Equal(t, http.StatusAccepted, responseRecorder.Code)
+}
+
+func (s *WorkflowHandlerTestSuite) TestCancellationOfFinishedRun() {
+	t := s.T()
+
+	run := newRun("run-id", migrationWorkflow(), nil, "")
+	run.conclude()
+	s.mockStore.On("GetRun", "run-id").Return(&run, nil).Once()
+
+	responseRecorder := s.serve("POST", "/workflows/migrate/runs/run-id/cancel", nil)
+
+	s.mockStore.AssertNotCalled(t, "RequestCancellation", mock.Anything)
+	assert.Equal(t, http.StatusConflict, responseRecorder.Code)
+	assert.Equal(t, utility.ErrCodeWorkflowRunFinished, errorResponse(t, responseRecorder).Code)
+}
+
+func TestWorkflowHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(WorkflowHandlerTestSuite))
+}
@@ -0,0 +1,182 @@
+package workflow
+
+import (
+	"bytes"
+	"encoding/json"
+	"text/template"
+	"time"
+)
+
+const (
+	StatusPending   = "PENDING"
+	StatusQueued    = "QUEUED"
+	StatusRunning   = "RUNNING"
+	StatusSucceeded = "SUCCEEDED"
+	StatusFailed    = "FAILED"
+	StatusSkipped   = "SKIPPED"
+	StatusCancelled = "CANCELLED"
+)
+
+type StepRun struct {
+	Name          string            `json:"name"`
+	Job           string            `json:"job"`
+	Status        string            `json:"status"`
+	Args          map[string]string `json:"args,omitempty"`
+	QueueID       string            `json:"queue_id,omitempty"`
+	ExecutionName string            `json:"execution_name,omitempty"`
+	Output        string            `json:"output,omitempty"`
+	Error         string            `json:"error,omitempty"`
+	StartedAt     *time.Time        `json:"started_at,omitempty"`
+	FinishedAt    *time.Time        `json:"finished_at,omitempty"`
+}
+
+type Run struct {
+	ID           string            `json:"id"`
+	WorkflowName string            `json:"workflow_name"`
+	Status       string            `json:"status"`
+	Inputs       map[string]string `json:"inputs,omitempty"`
+	RequestedBy  string            `json:"requested_by,omitempty"`
+	Steps        []StepRun         `json:"steps"`
+	CreatedAt    time.Time         `json:"created_at"`
+	FinishedAt   *time.Time        `json:"finished_at,omitempty"`
+}
+
+func newRun(id string, workflow Workflow, inputs map[string]string, requester string) Run {
+	run := Run{
+		ID:           id,
+		WorkflowName: workflow.Name,
+		Status:       StatusRunning,
+		Inputs:       inputs,
+		RequestedBy:  requester,
+		CreatedAt:    time.Now(),
+	}
+	for _, step := range workflow.Steps {
+		run.Steps = append(run.Steps, StepRun{Name: step.Name, Job: step.Job, Status: StatusPending})
+	}
+	return run
+}
+
+func (stepRun StepRun) HasFinished() bool {
+	switch stepRun.Status {
+	case StatusSucceeded, StatusFailed, StatusSkipped, StatusCancelled:
+		return true
+	}
+	return false
+}
+
+func (stepRun *StepRun) finish(status string) {
+	now := time.Now()
+	stepRun.Status = status
+	stepRun.FinishedAt = &now
+}
+
+func (stepRun *StepRun) fail(err error) {
+	stepRun.Error = err.Error()
+	stepRun.finish(StatusFailed)
+}
+
+func (run Run) HasFinished() bool {
+	return run.Status != StatusRunning
+}
+
+func (run *Run) stepRun(name string) *StepRun {
+	for i := range run.Steps {
+		if run.Steps[i].Name == name {
+			return &run.Steps[i]
+		}
+	}
+	return nil
+}
+
+func (run *Run) allStepsFinished() bool {
+	for _, stepRun := range run.Steps {
+		if !stepRun.HasFinished() {
+			return false
+		}
+	}
+	return true
+}
+
+func (run *Run) finish(status string) {
+	now := time.Now()
+	run.Status = status
+	run.FinishedAt = &now
+}
+
+func (run *Run) conclude() {
+	for _, stepRun := range run.Steps {
+		if stepRun.Status == StatusFailed {
+			run.finish(StatusFailed)
+			return
+		}
+	}
+	run.finish(StatusSucceeded)
+}
+
+// follows reports whether the run was started from the same steps as the
+// workflow, so a resumed run can be driven by the stored workflow.
+func (run Run) follows(workflow Workflow) bool {
+	if len(run.Steps) != len(workflow.Steps) {
+		return false
+	}
+	for i, step := range workflow.Steps {
+		if run.Steps[i].Name != step.Name || run.Steps[i].Job != step.Job {
+			return false
+		}
+	}
+	return true
+}
+
+func (run *Run) abandon(err error) {
+	for i := range run.Steps {
+		if !run.Steps[i].HasFinished() {
+			run.Steps[i].fail(err)
+		}
+	}
+	run.finish(StatusFailed)
+}
+
+func (run *Run) templateData() map[string]interface{} {
+	steps := map[string]interface{}{}
+	for _, stepRun := range run.Steps {
+		steps[stepRun.Name] = map[string]interface{}{
+			"status":         stepRun.Status,
+			"execution_name": stepRun.ExecutionName,
+			"output":         parseOutput(stepRun.Output),
+		}
+	}
+
+	inputs := run.Inputs
+	if inputs == nil {
+		inputs = map[string]string{}
+	}
+	return map[string]interface{}{
+		"inputs": inputs,
+		"steps":  steps,
+	}
+}
+
+func parseOutput(output string) interface{} {
+	var parsed interface{}
+	if err := json.Unmarshal([]byte(output), &parsed); err != nil {
+		return output
+	}
+	return parsed
+}
+
+func renderArgs(args map[string]string, data map[string]interface{}) (map[string]string, error) {
+	renderedArgs := make(map[string]string, len(args))
+	for name, value := range args {
+		argTemplate, err := template.New(name).Option("missingkey=error").Parse(value)
+		if err != nil {
+			return nil, err
+		}
+
+		var rendered bytes.Buffer
+		if err := argTemplate.Execute(&rendered, data); err != nil {
+			return nil, err
+		}
+		renderedArgs[name] = rendered.String()
+	}
+	return renderedArgs, nil
+}
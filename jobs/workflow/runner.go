@@ -0,0 +1,385 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gojektech/proctor-engine/config"
+	"github.com/gojektech/proctor-engine/jobs/execution"
+	"github.com/gojektech/proctor-engine/jobs/queue"
+	"github.com/gojektech/proctor-engine/kubernetes"
+	"github.com/gojektech/proctor-engine/logger"
+	"github.com/gojektech/proctor-engine/utility"
+)
+
+var pollInterval = 5 * time.Second
+
+const defaultRunLease = time.Minute
+
+var (
+	errQueuedExecutionFailed = errors.New("queued execution failed to start")
+	errWorkflowChanged       = errors.New("workflow steps changed since the run started")
+)
+
+type runner struct {
+	store          Store
+	executor       Executor
+	executionStore execution.Store
+	queueStore     queue.Store
+	kubeClient     kubernetes.Client
+	owner          string
+}
+
+type Runner interface {
+	Start(ctx context.Context, workflow Workflow, inputs map[string]string, requester string) (*Run, error)
+	StartResumer(ctx context.Context)
+}
+
+func NewRunner(store Store, executor Executor, executionStore execution.Store, queueStore queue.Store, kubeClient kubernetes.Client) Runner {
+	owner, _ := utility.RandomID()
+	return &runner{
+		store:          store,
+		executor:       executor,
+		executionStore: executionStore,
+		queueStore:     queueStore,
+		kubeClient:     kubeClient,
+		owner:          owner,
+	}
+}
+
+func runLease() time.Duration {
+	if lease := config.WorkflowRunLease(); lease > 0 {
+		return lease
+	}
+	return defaultRunLease
+}
+
+func (runner *runner) Start(ctx context.Context, workflow Workflow, inputs map[string]string, requester string) (*Run, error) {
+	runID, err := utility.RandomID()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := runner.store.AcquireLease(runID, runner.owner, runLease()); err != nil {
+		return nil, err
+	}
+
+	run := newRun(runID, workflow, inputs, requester)
+	if err := runner.store.SaveRun(run); err != nil {
+		runner.store.ReleaseLease(runID, runner.owner)
+		return nil, err
+	}
+
+	ctx = logger.WithFields(logger.Detach(ctx), logger.Fields{logger.WorkflowRunIDField: runID})
+	go runner.drive(ctx, workflow, run)
+
+	return &run, nil
+}
+
+// StartResumer periodically takes over runs whose lease lapsed, such as runs
+// left RUNNING by a replica that stopped.
+func (runner *runner) StartResumer(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(runLease())
+		defer ticker.Stop()
+
+		for {
+			runner.resume(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (runner *runner) resume(ctx context.Context) {
+	runs, err := runner.store.ActiveRuns()
+	if err != nil {
+		logger.FromContext(ctx).Error("Error listing active workflow runs", err.Error())
+		return
+	}
+
+	for _, run := range runs {
+		runCtx := logger.WithFields(ctx, logger.Fields{logger.WorkflowRunIDField: run.ID})
+		log := logger.FromContext(runCtx)
+
+		acquired, err := runner.store.AcquireLease(run.ID, runner.owner, runLease())
+		if err != nil {
+			log.Error("Error acquiring lease of workflow run", err.Error())
+			continue
+		}
+		if !acquired {
+			continue
+		}
+
+		workflow, err := runner.store.GetWorkflow(run.WorkflowName)
+		if err == nil && !run.follows(*workflow) {
+			err = errWorkflowChanged
+		}
+		if err == ErrWorkflowNotFound || err == errWorkflowChanged {
+			log.Error("Abandoning workflow run", err.Error())
+
+			run.abandon(err)
+			if err := runner.store.SaveRun(run); err != nil {
+				log.Error("Error saving workflow run", err.Error())
+			}
+			runner.releaseLease(runCtx, run.ID)
+			continue
+		}
+		if err != nil {
+			log.Error("Error fetching workflow of workflow run", err.Error())
+
+			runner.releaseLease(runCtx, run.ID)
+			continue
+		}
+
+		log.Info("Resuming workflow run of ", run.WorkflowName)
+		go runner.drive(runCtx, *workflow, run)
+	}
+}
+
+func (runner *runner) releaseLease(ctx context.Context, runID string) {
+	if err := runner.store.ReleaseLease(runID, runner.owner); err != nil {
+		logger.FromContext(ctx).Error("Error releasing lease of workflow run", err.Error())
+	}
+}
+
+func (runner *runner) drive(ctx context.Context, workflow Workflow, run Run) {
+	log := logger.FromContext(ctx)
+	defer runner.releaseLease(ctx, run.ID)
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		renewed, err := runner.store.RenewLease(run.ID, runner.owner, runLease())
+		if err != nil {
+			log.Error("Error renewing lease of workflow run", err.Error())
+		} else if !renewed {
+			log.Info("Workflow run taken over by another runner")
+			return
+		}
+
+		if runner.advance(ctx, workflow, &run) {
+			if err := runner.store.SaveRun(run); err != nil {
+				log.Error("Error saving workflow run", err.Error())
+			}
+		}
+
+		if run.HasFinished() {
+			log.Info("Workflow run finished with status ", run.Status)
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (runner *runner) advance(ctx context.Context, workflow Workflow, run *Run) bool {
+	cancelled, err := runner.store.CancellationRequested(run.ID)
+	if err != nil {
+		logger.FromContext(ctx).Error("Error checking cancellation of workflow run", err.Error())
+	}
+	if cancelled {
+		runner.cancel(ctx, run)
+		return true
+	}
+
+	changed := false
+	for i, step := range workflow.Steps {
+		stepRun := &run.Steps[i]
+		stepCtx := logger.WithFields(ctx, logger.Fields{"step": step.Name, logger.JobNameField: step.Job})
+
+		switch stepRun.Status {
+		case StatusPending:
+			changed = runner.startStep(stepCtx, step, stepRun, run) || changed
+		case StatusQueued:
+			changed = runner.checkQueued(stepCtx, stepRun) || changed
+		case StatusRunning:
+			changed = runner.checkRunning(stepCtx, stepRun) || changed
+		}
+	}
+
+	if run.allStepsFinished() {
+		run.conclude()
+		return true
+	}
+	return changed
+}
+
+func (runner *runner) startStep(ctx context.Context, step Step, stepRun *StepRun, run *Run) bool {
+	var dependencies []StepRun
+	for _, dependency := range step.DependsOn {
+		dependencyRun := run.stepRun(dependency)
+		if !dependencyRun.HasFinished() {
+			return false
+		}
+		dependencies = append(dependencies, *dependencyRun)
+	}
+
+	if !conditionMet(step.Condition(), dependencies) {
+		stepRun.finish(StatusSkipped)
+		return true
+	}
+
+	args, err := renderArgs(step.Args, run.templateData())
+	if err != nil {
+		stepRun.fail(err)
+		return true
+	}
+
+	now := time.Now()
+	stepRun.Args = args
+	stepRun.StartedAt = &now
+
+	executedJobName, err := runner.executor.Execute(ctx, step.Job, args, "", run.RequestedBy, nil)
+	if queuedErr, ok := err.(*queue.QueuedError); ok {
+		stepRun.Status = StatusQueued
+		stepRun.QueueID = queuedErr.Position.ID
+		return true
+	}
+	if err != nil {
+		logger.FromContext(ctx).Error("Error executing workflow step", err.Error())
+		stepRun.fail(err)
+		return true
+	}
+
+	stepRun.Status = StatusRunning
+	stepRun.ExecutionName = executedJobName
+	return true
+}
+
+func conditionMet(condition string, dependencies []StepRun) bool {
+	switch condition {
+	case ConditionAlways:
+		return true
+	case ConditionFailure:
+		for _, dependency := range dependencies {
+			if dependency.Status == StatusFailed {
+				return true
+			}
+		}
+		return false
+	default:
+		for _, dependency := range dependencies {
+			if dependency.Status != StatusSucceeded {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+func (runner *runner) checkQueued(ctx context.Context, stepRun *StepRun) bool {
+	entry, err := runner.queueStore.GetEntry(stepRun.QueueID)
+	if err == queue.ErrEntryNotFound {
+		stepRun.fail(err)
+		return true
+	}
+	if err != nil {
+		logger.FromContext(ctx).Error("Error fetching queue entry of workflow step", err.Error())
+		return false
+	}
+
+	switch entry.Status {
+	case queue.StatusDispatched:
+		stepRun.Status = StatusRunning
+		stepRun.ExecutionName = entry.ExecutionName
+		return true
+	case queue.StatusFailed, queue.StatusCancelled:
+		stepRun.fail(errQueuedExecutionFailed)
+		return true
+	}
+	return false
+}
+
+func (runner *runner) checkRunning(ctx context.Context, stepRun *StepRun) bool {
+	jobExecution, err := runner.executionStore.GetExecution(stepRun.ExecutionName)
+	if err != nil {
+		logger.FromContext(ctx).Error("Error fetching execution of workflow step", err.Error())
+		return false
+	}
+
+	if !jobExecution.HasFinished() || jobExecution.RetryPending {
+		return false
+	}
+
+	if jobExecution.NextAttempt != "" {
+		stepRun.ExecutionName = jobExecution.NextAttempt
+		return true
+	}
+
+	stepRun.Output = jobExecution.Output
+	if jobExecution.Status == execution.StatusSucceeded {
+		stepRun.finish(StatusSucceeded)
+	} else {
+		stepRun.finish(StatusFailed)
+	}
+	return true
+}
+
+func (runner *runner) cancel(ctx context.Context, run *Run) {
+	log := logger.FromContext(ctx)
+
+	for i := range run.Steps {
+		stepRun := &run.Steps[i]
+
+		switch stepRun.Status {
+		case StatusQueued:
+			runner.cancelQueued(ctx, stepRun)
+		case StatusRunning:
+			runner.deleteJob(ctx, stepRun.ExecutionName)
+		}
+
+		if !stepRun.HasFinished() {
+			stepRun.finish(StatusCancelled)
+		}
+	}
+
+	log.Info("Cancelled workflow run")
+	run.finish(StatusCancelled)
+}
+
+func (runner *runner) cancelQueued(ctx context.Context, stepRun *StepRun) {
+	log := logger.FromContext(ctx)
+
+	entry, err := runner.queueStore.GetEntry(stepRun.QueueID)
+	if err != nil {
+		log.Error("Error fetching queue entry of cancelled workflow step", err.Error())
+		return
+	}
+
+	if entry.Status == queue.StatusDispatched {
+		runner.deleteJob(ctx, entry.ExecutionName)
+		return
+	}
+
+	entry.Status = queue.StatusCancelled
+	entry.Files = nil
+	entry.SensitiveFiles = nil
+	if err := runner.queueStore.SaveEntry(*entry); err != nil {
+		log.Error("Error cancelling queue entry of workflow step", err.Error())
+	}
+}
+
+func (runner *runner) deleteJob(ctx context.Context, executionName string) {
+	log := logger.FromContext(ctx)
+
+	jobExecution, err := runner.executionStore.GetExecution(executionName)
+	if err != nil {
+		log.Error("Error fetching execution of cancelled workflow step", err.Error())
+		return
+	}
+
+	if err := runner.kubeClient.DeleteJob(jobExecution.Target(), executionName); err != nil {
+		log.Error("Error deleting job of cancelled workflow step", err.Error())
+	}
+}
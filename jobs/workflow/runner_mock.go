@@ -0,0 +1,20 @@
+package workflow
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRunner struct {
+	mock.Mock
+}
+
+func (m *MockRunner) Start(ctx context.Context, workflow Workflow, inputs map[string]string, requester string) (*Run, error) {
+	args := m.Called(ctx, workflow, inputs, requester)
+	return args.Get(0).(*Run), args.Error(1)
+}
+
+func (m *MockRunner) StartResumer(ctx context.Context) {
+	m.Called(ctx)
+}
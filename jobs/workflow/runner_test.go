@@ -0,0 +1,383 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gojektech/proctor-engine/jobs/approval"
+	"github.com/gojektech/proctor-engine/jobs/execution"
+	"github.com/gojektech/proctor-engine/jobs/policy"
+	"github.com/gojektech/proctor-engine/jobs/queue"
+	"github.com/gojektech/proctor-engine/kubernetes"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type RunnerTestSuite struct {
+	suite.Suite
+	mockStore          *MockStore
+	mockExecutor       *MockExecutor
+	mockExecutionStore *execution.MockStore
+	mockQueueStore     *queue.MockStore
+	mockKubeClient     *kubernetes.MockClient
+	testRunner         *runner
+}
+
+func (s *RunnerTestSuite) SetupTest() {
+	os.Setenv("PROCTOR_WORKFLOW_RUN_LEASE_SECONDS", "60")
+	viper.AutomaticEnv()
+
+	s.mockStore = &MockStore{}
+	s.mockExecutor = &MockExecutor{}
+	s.mockExecutionStore = &execution.MockStore{}
+	s.mockQueueStore = &queue.MockStore{}
+	s.mockKubeClient = &kubernetes.MockClient{}
+	s.testRunner = &runner{
+		store:          s.mockStore,
+		executor:       s.mockExecutor,
+		executionStore: s.mockExecutionStore,
+		queueStore:     s.mockQueueStore,
+		kubeClient:     s.mockKubeClient,
+		owner:          "runner-id",
+	}
+}
+
+func (s *RunnerTestSuite) TearDownTest() {
+	os.Unsetenv("PROCTOR_WORKFLOW_RUN_LEASE_SECONDS")
+}
+
+func finishedExecution(name string, status string, output string) *execution.Execution {
+	finishedAt := time.Now()
+	return &execution.Execution{Name: name, Status: status, Output: output, FinishedAt: &finishedAt}
+}
+
+func (s *RunnerTestSuite) TestStart() {
+	t := s.T()
+
+	workflow := Workflow{Name: "migrate", Steps: []Step{{Name: "snapshot", Job: "db-snapshot"}}}
+	s.mockStore.On("AcquireLease", mock.Anything, "runner-id", time.Minute).Return(true, nil).Once()
+	s.mockStore.On("RenewLease", mock.Anything, "runner-id", time.Minute).Return(true, nil)
+	s.mockStore.On("ReleaseLease", mock.Anything, "runner-id").Return(nil)
+	s.mockStore.On("SaveRun", mock.MatchedBy(func(run Run) bool {
+		return run.WorkflowName == "migrate" && run.Status == StatusRunning && run.Steps[0].Status == StatusPending
+	})).Return(nil).Once()
+
+	executed := make(chan struct{})
+	s.mockStore.On("CancellationRequested", mock.Anything).Return(false, nil)
+	s.mockExecutor.On("Execute", mock.Anything, "db-snapshot", map[string]string{}, "", "jane@example.com", []string(nil)).Return("proctor-snapshot", nil).Run(func(mock.Arguments) {
+		close(executed)
+	}).Once()
+	s.mockStore.On("SaveRun", mock.Anything).Return(nil)
+	s.mockExecutionStore.On("GetExecution", "proctor-snapshot").Return(&execution.Execution{Name: "proctor-snapshot", Status: execution.StatusRunning}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	run, err := s.testRunner.Start(ctx, workflow, map[string]string{"DATABASE": "orders"}, "jane@example.com")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, run.ID)
+	assert.Equal(t, map[string]string{"DATABASE": "orders"}, run.Inputs)
+	assert.Equal(t, "jane@example.com", run.RequestedBy)
+
+	<-executed
+}
+
+func (s *RunnerTestSuite) TestAdvanceRunsWorkflowToCompletion() {
+	t := s.T()
+
+	workflow := migrationWorkflow()
+	run := newRun("run-id", workflow, nil, "")
+	s.mockStore.On("CancellationRequested", "run-id").Return(false, nil)
+
+	s.mockExecutor.On("Execute", mock.Anything, "db-snapshot", map[string]string{}, "", "", []string(nil)).Return("proctor-snapshot", nil).Once()
+	assert.True(t, s.testRunner.advance(context.Background(), workflow, &run))
+	assert.Equal(t, StatusRunning, run.Steps[0].Status)
+	assert.Equal(t, StatusPending, run.Steps[1].Status)
+
+	s.mockExecutionStore.On("GetExecution", "proctor-snapshot").Return(finishedExecution("proctor-snapshot", execution.StatusSucceeded, `{"snapshot_id":"snap-42"}`), nil).Once()
+	s.mockExecutor.On("Execute", mock.Anything, "db-migrate", map[string]string{"SNAPSHOT_ID": "snap-42"}, "", "", []string(nil)).Return("proctor-migrate", nil).Once()
+	assert.True(t, s.testRunner.advance(context.Background(), workflow, &run))
+	assert.Equal(t, StatusSucceeded, run.Steps[0].Status)
+	assert.Equal(t, StatusRunning, run.Steps[1].Status)
+
+	s.mockExecutionStore.On("GetExecution", "proctor-migrate").Return(finishedExecution("proctor-migrate", execution.StatusSucceeded, ""), nil).Once()
+	s.mockExecutor.On("Execute", mock.Anything, "db-verify", map[string]string{}, "", "", []string(nil)).Return("proctor-verify", nil).Once()
+	assert.True(t, s.testRunner.advance(context.Background(), workflow, &run))
+
+	s.mockExecutionStore.On("GetExecution", "proctor-verify").Return(finishedExecution("proctor-verify", execution.StatusFailed, ""), nil).Once()
+	s.mockExecutor.On("Execute", mock.Anything, "db-restore", map[string]string{}, "", "", []string(nil)).Return("proctor-restore", nil).Once()
+	assert.True(t, s.testRunner.advance(context.Background(), workflow, &run))
+	assert.Equal(t, StatusFailed, run.Steps[2].Status)
+	assert.Equal(t, StatusRunning, run.Steps[3].Status)
+
+	s.mockExecutionStore.On("GetExecution", "proctor-restore").Return(finishedExecution("proctor-restore", execution.StatusSucceeded, ""), nil).Once()
+	s.mockExecutor.On("Execute", mock.Anything, "notify", map[string]string{}, "", "", []string(nil)).Return("proctor-notify", nil).Once()
+	assert.True(t, s.testRunner.advance(context.Background(), workflow, &run))
+
+	s.mockExecutionStore.On("GetExecution", "proctor-notify").Return(finishedExecution("proctor-notify", execution.StatusSucceeded, ""), nil).Once()
+	assert.True(t, s.testRunner.advance(context.Background(), workflow, &run))
+
+	assert.Equal(t, StatusFailed, run.Status)
+	assert.True(t, run.HasFinished())
+	s.mockExecutor.AssertExpectations(t)
+}
+
+func (s *RunnerTestSuite) TestAdvanceSkipsStepsWhenConditionNotMet() {
+	t := s.T()
+
+	workflow := Workflow{
+		Name: "migrate",
+		Steps: []Step{
+			{Name: "snapshot", Job: "db-snapshot"},
+			{Name: "restore", Job: "db-restore", DependsOn: []string{"snapshot"}, When: ConditionFailure},
+			{Name: "after-restore", Job: "notify", DependsOn: []string{"restore"}},
+		},
+	}
+	run := newRun("run-id", workflow, nil, "")
+	run.Steps[0].Status = StatusSucceeded
+	s.mockStore.On("CancellationRequested", "run-id").Return(false, nil)
+
+	assert.True(t, s.testRunner.advance(context.Background(), workflow, &run))
+
+	assert.Equal(t, StatusSkipped, run.Steps[1].Status)
+	assert.Equal(t, StatusSkipped, run.Steps[2].Status)
+	assert.Equal(t, StatusSucceeded, run.Status)
+	s.mockExecutor.AssertNotCalled(t, "Execute", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *RunnerTestSuite) TestAdvanceFansOut() {
+	t := s.T()
+
+	workflow := Workflow{
+		Name: "fan-out",
+		Steps: []Step{
+			{Name: "left", Job: "job-left"},
+			{Name: "right", Job: "job-right"},
+			{Name: "join", Job: "job-join", DependsOn: []string{"left", "right"}},
+		},
+	}
+	run := newRun("run-id", workflow, nil, "")
+	s.mockStore.On("CancellationRequested", "run-id").Return(false, nil)
+	s.mockExecutor.On("Execute", mock.Anything, "job-left", map[string]string{}, "", "", []string(nil)).Return("proctor-left", nil).Once()
+	s.mockExecutor.On("Execute", mock.Anything, "job-right", map[string]string{}, "", "", []string(nil)).Return("proctor-right", nil).Once()
+
+	s.testRunner.advance(context.Background(), workflow, &run)
+
+	assert.Equal(t, StatusRunning, run.Steps[0].Status)
+	assert.Equal(t, StatusRunning, run.Steps[1].Status)
+	assert.Equal(t, StatusPending, run.Steps[2].Status)
+	s.mockExecutor.AssertExpectations(t)
+}
+
+func (s *RunnerTestSuite) TestAdvanceFollowsRetriedExecutions() {
+	t := s.T()
+
+	workflow := Workflow{Name: "migrate", Steps: []Step{{Name: "snapshot", Job: "db-snapshot"}}}
+	run := newRun("run-id", workflow, nil, "")
+	run.Steps[0].Status = StatusRunning
+	run.Steps[0].ExecutionName = "proctor-first"
+	s.mockStore.On("CancellationRequested", "run-id").Return(false, nil)
+
+	pending := finishedExecution("proctor-first", execution.StatusFailed, "")
+	pending.RetryPending = true
+	s.mockExecutionStore.On("GetExecution", "proctor-first").Return(pending, nil).Once()
+	assert.False(t, s.testRunner.advance(context.Background(), workflow, &run))
+
+	retried := finishedExecution("proctor-first", execution.StatusFailed, "")
+	retried.NextAttempt = "proctor-second"
+	s.mockExecutionStore.On("GetExecution", "proctor-first").Return(retried, nil).Once()
+	assert.True(t, s.testRunner.advance(context.Background(), workflow, &run))
+	assert.Equal(t, "proctor-second", run.Steps[0].ExecutionName)
+	assert.Equal(t, StatusRunning, run.Steps[0].Status)
+}
+
+func (s *RunnerTestSuite) TestAdvanceTracksQueuedSteps() {
+	t := s.T()
+
+	workflow := Workflow{Name: "migrate", Steps: []Step{{Name: "snapshot", Job: "db-snapshot"}}}
+	run := newRun("run-id", workflow, nil, "")
+	s.mockStore.On("CancellationRequested", "run-id").Return(false, nil)
+
+	queuedErr := &queue.QueuedError{Position: queue.Position{ID: "queue-id", Status: queue.StatusQueued, Position: 1}}
+	s.mockExecutor.On("Execute", mock.Anything, "db-snapshot", map[string]string{}, "", "", []string(nil)).Return("", queuedErr).Once()
+	s.testRunner.advance(context.Background(), workflow, &run)
+	assert.Equal(t, StatusQueued, run.Steps[0].Status)
+	assert.Equal(t, "queue-id", run.Steps[0].QueueID)
+
+	s.mockQueueStore.On("GetEntry", "queue-id").Return(&queue.Entry{ID: "queue-id", Status: queue.StatusDispatched, ExecutionName: "proctor-snapshot"}, nil).Once()
+	s.testRunner.advance(context.Background(), workflow, &run)
+	assert.Equal(t, StatusRunning, run.Steps[0].Status)
+	assert.Equal(t, "proctor-snapshot", run.Steps[0].ExecutionName)
+}
+
+func (s *RunnerTestSuite) TestAdvanceFailsStepOnExecutionError() {
+	t := s.T()
+
+	workflow := Workflow{Name: "migrate", Steps: []Step{{Name: "snapshot", Job: "db-snapshot"}}}
+	run := newRun("run-id", workflow, nil, "")
+	s.mockStore.On("CancellationRequested", "run-id").Return(false, nil)
+	s.mockExecutor.On("Execute", mock.Anything, "db-snapshot", map[string]string{}, "", "", []string(nil)).Return("", errors.New("error")).Once()
+
+	s.testRunner.advance(context.Background(), workflow, &run)
+
+	assert.Equal(t, StatusFailed, run.Steps[0].Status)
+	assert.Equal(t, "error", run.Steps[0].Error)
+	assert.Equal(t, StatusFailed, run.Status)
+}
+
+func (s *RunnerTestSuite) TestAdvanceFailsStepBlockedByPolicy() {
+	t := s.T()
+
+	workflow := Workflow{
+		Name: "migrate",
+		Steps: []Step{
+			{Name: "snapshot", Job: "db-snapshot"},
+			{Name: "migrate", Job: "db-migrate", DependsOn: []string{"snapshot"}},
+		},
+	}
+	run := newRun("run-id", workflow, nil, "jane@example.com")
+	s.mockStore.On("CancellationRequested", "run-id").Return(false, nil)
+	deniedErr := &policy.DeniedError{Denials: []policy.Denial{{Rule: "business-hours", Reason: "migrations run during business hours"}}}
+	s.mockExecutor.On("Execute", mock.Anything, "db-snapshot", map[string]string{}, "", "jane@example.com", []string(nil)).Return("", deniedErr).Once()
+
+	s.testRunner.advance(context.Background(), workflow, &run)
+	s.testRunner.advance(context.Background(), workflow, &run)
+
+	s.mockExecutor.AssertNotCalled(t, "Execute", mock.Anything, "db-migrate", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	assert.Equal(t, StatusFailed, run.Steps[0].Status)
+	assert.Equal(t, deniedErr.Error(), run.Steps[0].Error)
+	assert.Equal(t, StatusSkipped, run.Steps[1].Status)
+	assert.Equal(t, StatusFailed, run.Status)
+}
+
+func (s *RunnerTestSuite) TestAdvanceFailsStepRequiringApproval() {
+	t := s.T()
+
+	workflow := Workflow{Name: "migrate", Steps: []Step{{Name: "snapshot", Job: "db-snapshot"}}}
+	run := newRun("run-id", workflow, nil, "jane@example.com")
+	s.mockStore.On("CancellationRequested", "run-id").Return(false, nil)
+	s.mockExecutor.On("Execute", mock.Anything, "db-snapshot", map[string]string{}, "", "jane@example.com", []string(nil)).Return("", approval.ErrApprovalRequired).Once()
+
+	s.testRunner.advance(context.Background(), workflow, &run)
+
+	assert.Equal(t, StatusFailed, run.Steps[0].Status)
+	assert.Equal(t, approval.ErrApprovalRequired.Error(), run.Steps[0].Error)
+	assert.Equal(t, StatusFailed, run.Status)
+}
+
+func (s *RunnerTestSuite) TestResumeDrivesOrphanedRun() {
+	t := s.T()
+
+	workflow := Workflow{Name: "migrate", Steps: []Step{{Name: "snapshot", Job: "db-snapshot"}}}
+	run := newRun("run-id", workflow, nil, "")
+	run.Steps[0].Status = StatusRunning
+	run.Steps[0].ExecutionName = "proctor-snapshot"
+
+	released := make(chan struct{})
+	s.mockStore.On("ActiveRuns").Return([]Run{run}, nil).Once()
+	s.mockStore.On("AcquireLease", "run-id", "runner-id", time.Minute).Return(true, nil).Once()
+	s.mockStore.On("GetWorkflow", "migrate").Return(&workflow, nil).Once()
+	s.mockStore.On("RenewLease", "run-id", "runner-id", time.Minute).Return(true, nil).Once()
+	s.mockStore.On("CancellationRequested", "run-id").Return(false, nil).Once()
+	s.mockExecutionStore.On("GetExecution", "proctor-snapshot").Return(finishedExecution("proctor-snapshot", execution.StatusSucceeded, ""), nil).Once()
+	s.mockStore.On("SaveRun", mock.MatchedBy(func(run Run) bool {
+		return run.ID == "run-id" && run.Status == StatusSucceeded
+	})).Return(nil).Once()
+	s.mockStore.On("ReleaseLease", "run-id", "runner-id").Return(nil).Run(func(mock.Arguments) {
+		close(released)
+	}).Once()
+
+	s.testRunner.resume(context.Background())
+	<-released
+
+	s.mockStore.AssertExpectations(t)
+}
+
+func (s *RunnerTestSuite) TestResumeSkipsLeasedRun() {
+	t := s.T()
+
+	workflow := Workflow{Name: "migrate", Steps: []Step{{Name: "snapshot", Job: "db-snapshot"}}}
+	s.mockStore.On("ActiveRuns").Return([]Run{newRun("run-id", workflow, nil, "")}, nil).Once()
+	s.mockStore.On("AcquireLease", "run-id", "runner-id", time.Minute).Return(false, nil).Once()
+
+	s.testRunner.resume(context.Background())
+
+	s.mockStore.AssertNotCalled(t, "GetWorkflow", mock.Anything)
+}
+
+func (s *RunnerTestSuite) TestResumeAbandonsRunOfChangedWorkflow() {
+	t := s.T()
+
+	workflow := Workflow{Name: "migrate", Steps: []Step{{Name: "snapshot", Job: "db-snapshot"}}}
+	run := newRun("run-id", workflow, nil, "")
+	changedWorkflow := Workflow{Name: "migrate", Steps: []Step{{Name: "backup", Job: "db-backup"}}}
+
+	s.mockStore.On("ActiveRuns").Return([]Run{run}, nil).Once()
+	s.mockStore.On("AcquireLease", "run-id", "runner-id", time.Minute).Return(true, nil).Once()
+	s.mockStore.On("GetWorkflow", "migrate").Return(&changedWorkflow, nil).Once()
+	s.mockStore.On("SaveRun", mock.MatchedBy(func(run Run) bool {
+		return run.Status == StatusFailed && run.Steps[0].Error == errWorkflowChanged.Error()
+	})).Return(nil).Once()
+	s.mockStore.On("ReleaseLease", "run-id", "runner-id").Return(nil).Once()
+
+	s.testRunner.resume(context.Background())
+
+	s.mockStore.AssertExpectations(t)
+	s.mockExecutor.AssertNotCalled(t, "Execute", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *RunnerTestSuite) TestDriveStopsWhenLeaseIsTakenOver() {
+	t := s.T()
+
+	workflow := Workflow{Name: "migrate", Steps: []Step{{Name: "snapshot", Job: "db-snapshot"}}}
+	s.mockStore.On("RenewLease", "run-id", "runner-id", time.Minute).Return(false, nil).Once()
+	s.mockStore.On("ReleaseLease", "run-id", "runner-id").Return(nil).Once()
+
+	s.testRunner.drive(context.Background(), workflow, newRun("run-id", workflow, nil, ""))
+
+	s.mockStore.AssertNotCalled(t, "CancellationRequested", mock.Anything)
+	s.mockStore.AssertNotCalled(t, "SaveRun", mock.Anything)
+}
+
+func (s *RunnerTestSuite) TestAdvanceCancelsRun() {
+	t := s.T()
+
+	workflow := Workflow{
+		Name: "migrate",
+		Steps: []Step{
+			{Name: "snapshot", Job: "db-snapshot"},
+			{Name: "backup", Job: "db-backup"},
+			{Name: "migrate", Job: "db-migrate", DependsOn: []string{"snapshot"}},
+		},
+	}
+	run := newRun("run-id", workflow, nil, "")
+	run.Steps[0].Status = StatusRunning
+	run.Steps[0].ExecutionName = "proctor-snapshot"
+	run.Steps[1].Status = StatusQueued
+	run.Steps[1].QueueID = "queue-id"
+
+	s.mockStore.On("CancellationRequested", "run-id").Return(true, nil).Once()
+	s.mockExecutionStore.On("GetExecution", "proctor-snapshot").Return(&execution.Execution{Name: "proctor-snapshot", Cluster: "staging", Namespace: "batch"}, nil).Once()
+	s.mockKubeClient.On("DeleteJob", kubernetes.Target{Cluster: "staging", Namespace: "batch"}, "proctor-snapshot").Return(nil).Once()
+	s.mockQueueStore.On("GetEntry", "queue-id").Return(&queue.Entry{ID: "queue-id", Status: queue.StatusQueued}, nil).Once()
+	s.mockQueueStore.On("SaveEntry", queue.Entry{ID: "queue-id", Status: queue.StatusCancelled}).Return(nil).Once()
+
+	assert.True(t, s.testRunner.advance(context.Background(), workflow, &run))
+
+	s.mockKubeClient.AssertExpectations(t)
+	s.mockQueueStore.AssertExpectations(t)
+	assert.Equal(t, StatusCancelled, run.Status)
+	for _, stepRun := range run.Steps {
+		assert.Equal(t, StatusCancelled, stepRun.Status)
+	}
+}
+
+func TestRunnerTestSuite(t *testing.T) {
+	suite.Run(t, new(RunnerTestSuite))
+}
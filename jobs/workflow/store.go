@@ -0,0 +1,219 @@
+package workflow
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/gojektech/proctor-engine/config"
+	"github.com/gojektech/proctor-engine/redis"
+)
+
+const (
+	WorkflowKeySuffix  = "-workflow"
+	RunKeySuffix       = "-workflow-run"
+	CancelledKeySuffix = "-workflow-run-cancelled"
+	LeaseKeySuffix     = "-workflow-run-lease"
+	ActiveRunsKey      = "proctor-active-workflow-runs"
+)
+
+// A run lease is held by the runner driving it; renewing and releasing only
+// succeed for the owner so a runner never extends a lease taken over by
+// another replica.
+const renewLeaseScript = `
+if redis.call('GET', KEYS[1]) ~= ARGV[1] then
+  return 0
+end
+redis.call('EXPIRE', KEYS[1], ARGV[2])
+return 1
+`
+
+const releaseLeaseScript = `
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+  redis.call('DEL', KEYS[1])
+end
+return 1
+`
+
+var (
+	ErrWorkflowNotFound = errors.New("workflow not found")
+	ErrRunNotFound      = errors.New("workflow run not found")
+)
+
+type Store interface {
+	SaveWorkflow(Workflow) error
+	GetWorkflow(string) (*Workflow, error)
+	SaveRun(Run) error
+	GetRun(string) (*Run, error)
+	RequestCancellation(string) error
+	CancellationRequested(string) (bool, error)
+	ActiveRuns() ([]Run, error)
+	AcquireLease(string, string, time.Duration) (bool, error)
+	RenewLease(string, string, time.Duration) (bool, error)
+	ReleaseLease(string, string) error
+}
+
+type store struct {
+	redisClient redis.Client
+}
+
+func NewStore(redisClient redis.Client) Store {
+	return &store{
+		redisClient: redisClient,
+	}
+}
+
+func workflowKey(name string) string {
+	return name + WorkflowKeySuffix
+}
+
+func runKey(id string) string {
+	return id + RunKeySuffix
+}
+
+func cancelledKey(id string) string {
+	return id + CancelledKeySuffix
+}
+
+func leaseKey(id string) string {
+	return id + LeaseKeySuffix
+}
+
+func leaseSeconds(lease time.Duration) int {
+	if seconds := int(lease.Seconds()); seconds > 0 {
+		return seconds
+	}
+	return 1
+}
+
+func (store *store) SaveWorkflow(workflow Workflow) error {
+	binaryWorkflow, err := json.Marshal(workflow)
+	if err != nil {
+		return err
+	}
+
+	return store.redisClient.SET(workflowKey(workflow.Name), binaryWorkflow)
+}
+
+func (store *store) GetWorkflow(name string) (*Workflow, error) {
+	binaryWorkflow, err := store.redisClient.GET(workflowKey(name))
+	if err == redis.ErrNil {
+		return nil, ErrWorkflowNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var workflow Workflow
+	err = json.Unmarshal(binaryWorkflow, &workflow)
+	if err != nil {
+		return nil, err
+	}
+	return &workflow, nil
+}
+
+func (store *store) SaveRun(run Run) error {
+	binaryRun, err := json.Marshal(run)
+	if err != nil {
+		return err
+	}
+
+	if err := store.set(runKey(run.ID), binaryRun); err != nil {
+		return err
+	}
+
+	if run.HasFinished() {
+		return store.redisClient.SREM(ActiveRunsKey, run.ID)
+	}
+	return store.redisClient.SADD(ActiveRunsKey, run.ID)
+}
+
+// ActiveRuns lists unfinished runs, dropping ids whose records have expired.
+func (store *store) ActiveRuns() ([]Run, error) {
+	ids, err := store.redisClient.SMEMBERS(ActiveRunsKey)
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+
+	keys := make([]interface{}, len(ids))
+	for i, id := range ids {
+		keys[i] = runKey(id)
+	}
+	values, err := store.redisClient.MGET(keys...)
+	if err != nil {
+		return nil, err
+	}
+
+	var runs []Run
+	for i, value := range values {
+		if value == nil {
+			store.redisClient.SREM(ActiveRunsKey, ids[i])
+			continue
+		}
+
+		var run Run
+		if err := json.Unmarshal(value, &run); err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	return runs, nil
+}
+
+func (store *store) AcquireLease(id string, owner string, lease time.Duration) (bool, error) {
+	return store.redisClient.SETNX(leaseKey(id), leaseSeconds(lease), []byte(owner))
+}
+
+func (store *store) RenewLease(id string, owner string, lease time.Duration) (bool, error) {
+	reply, err := store.redisClient.EVAL(renewLeaseScript, 1, leaseKey(id), owner, leaseSeconds(lease))
+	if err != nil {
+		return false, err
+	}
+	renewed, _ := reply.(int64)
+	return renewed == 1, nil
+}
+
+func (store *store) ReleaseLease(id string, owner string) error {
+	_, err := store.redisClient.EVAL(releaseLeaseScript, 1, leaseKey(id), owner)
+	return err
+}
+
+func (store *store) GetRun(id string) (*Run, error) {
+	binaryRun, err := store.redisClient.GET(runKey(id))
+	if err == redis.ErrNil {
+		return nil, ErrRunNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var run Run
+	err = json.Unmarshal(binaryRun, &run)
+	if err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+func (store *store) RequestCancellation(id string) error {
+	return store.set(cancelledKey(id), []byte("true"))
+}
+
+func (store *store) CancellationRequested(id string) (bool, error) {
+	_, err := store.redisClient.GET(cancelledKey(id))
+	if err == redis.ErrNil {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (store *store) set(key string, value []byte) error {
+	expiryInSeconds := int(config.ExecutionRecordExpiry().Seconds())
+	if expiryInSeconds <= 0 {
+		return store.redisClient.SET(key, value)
+	}
+	return store.redisClient.SETEX(key, expiryInSeconds, value)
+}
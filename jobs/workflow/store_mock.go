@@ -0,0 +1,61 @@
+package workflow
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockStore struct {
+	mock.Mock
+}
+
+func (m *MockStore) SaveWorkflow(workflow Workflow) error {
+	args := m.Called(workflow)
+	return args.Error(0)
+}
+
+func (m *MockStore) GetWorkflow(name string) (*Workflow, error) {
+	args := m.Called(name)
+	return args.Get(0).(*Workflow), args.Error(1)
+}
+
+func (m *MockStore) SaveRun(run Run) error {
+	args := m.Called(run)
+	return args.Error(0)
+}
+
+func (m *MockStore) GetRun(id string) (*Run, error) {
+	args := m.Called(id)
+	return args.Get(0).(*Run), args.Error(1)
+}
+
+func (m *MockStore) RequestCancellation(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockStore) CancellationRequested(id string) (bool, error) {
+	args := m.Called(id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockStore) ActiveRuns() ([]Run, error) {
+	args := m.Called()
+	return args.Get(0).([]Run), args.Error(1)
+}
+
+func (m *MockStore) AcquireLease(id string, owner string, lease time.Duration) (bool, error) {
+	args := m.Called(id, owner, lease)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockStore) RenewLease(id string, owner string, lease time.Duration) (bool, error) {
+	args := m.Called(id, owner, lease)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockStore) ReleaseLease(id string, owner string) error {
+	args := m.Called(id, owner)
+	return args.Error(0)
+}
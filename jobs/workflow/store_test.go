@@ -0,0 +1,202 @@
+package workflow
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gojektech/proctor-engine/redis"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type WorkflowStoreTestSuite struct {
+	suite.Suite
+	mockRedisClient   *redis.MockClient
+	testWorkflowStore Store
+}
+
+func (s *WorkflowStoreTestSuite) SetupTest() {
+	os.Setenv("PROCTOR_EXECUTION_RECORD_EXPIRY_SECONDS", "0")
+	viper.AutomaticEnv()
+
+	s.mockRedisClient = &redis.MockClient{}
+
+	s.testWorkflowStore = NewStore(s.mockRedisClient)
+}
+
+func (s *WorkflowStoreTestSuite) TearDownTest() {
+	os.Unsetenv("PROCTOR_EXECUTION_RECORD_EXPIRY_SECONDS")
+}
+
+func (s *WorkflowStoreTestSuite) TestSaveWorkflow() {
+	t := s.T()
+
+	workflow := Workflow{Name: "migrate", Steps: []Step{{Name: "snapshot", Job: "db-snapshot"}}}
+	binaryWorkflow, err := json.Marshal(workflow)
+	assert.NoError(t, err)
+
+	s.mockRedisClient.On("SET", "migrate-workflow", binaryWorkflow).Return(nil).Once()
+
+	err = s.testWorkflowStore.SaveWorkflow(workflow)
+	assert.NoError(t, err)
+
+	s.mockRedisClient.AssertExpectations(t)
+}
+
+func (s *WorkflowStoreTestSuite) TestGetWorkflow() {
+	t := s.T()
+
+	workflow := Workflow{Name: "migrate", Steps: []Step{{Name: "snapshot", Job: "db-snapshot"}}}
+	binaryWorkflow, err := json.Marshal(workflow)
+	assert.NoError(t, err)
+
+	s.mockRedisClient.On("GET", "migrate-workflow").Return(binaryWorkflow, nil).Once()
+
+	fetchedWorkflow, err := s.testWorkflowStore.GetWorkflow("migrate")
+	assert.NoError(t, err)
+	assert.Equal(t, workflow, *fetchedWorkflow)
+}
+
+func (s *WorkflowStoreTestSuite) TestGetWorkflowNotFound() {
+	t := s.T()
+
+	s.mockRedisClient.On("GET", "migrate-workflow").Return([]byte{}, redis.ErrNil).Once()
+
+	_, err := s.testWorkflowStore.GetWorkflow("migrate")
+	assert.Equal(t, ErrWorkflowNotFound, err)
+}
+
+func (s *WorkflowStoreTestSuite) TestSaveAndGetRun() {
+	t := s.T()
+
+	run := Run{ID: "run-id", WorkflowName: "migrate", Status: StatusRunning, Steps: []StepRun{{Name: "snapshot", Job: "db-snapshot", Status: StatusPending}}, CreatedAt: time.Now().UTC()}
+	binaryRun, err := json.Marshal(run)
+	assert.NoError(t, err)
+
+	s.mockRedisClient.On("SET", "run-id-workflow-run", binaryRun).Return(nil).Once()
+	s.mockRedisClient.On("SADD", ActiveRunsKey, "run-id").Return(nil).Once()
+	s.mockRedisClient.On("GET", "run-id-workflow-run").Return(binaryRun, nil).Once()
+
+	assert.NoError(t, s.testWorkflowStore.SaveRun(run))
+
+	fetchedRun, err := s.testWorkflowStore.GetRun("run-id")
+	assert.NoError(t, err)
+	assert.Equal(t, run.ID, fetchedRun.ID)
+	assert.Equal(t, run.Steps, fetchedRun.Steps)
+
+	s.mockRedisClient.AssertExpectations(t)
+}
+
+func (s *WorkflowStoreTestSuite) TestSaveFinishedRunWithExpiry() {
+	t := s.T()
+
+	os.Setenv("PROCTOR_EXECUTION_RECORD_EXPIRY_SECONDS", "86400")
+
+	run := Run{ID: "run-id", WorkflowName: "migrate", Status: StatusSucceeded, CreatedAt: time.Now().UTC()}
+	binaryRun, err := json.Marshal(run)
+	assert.NoError(t, err)
+
+	s.mockRedisClient.On("SETEX", "run-id-workflow-run", 86400, binaryRun).Return(nil).Once()
+	s.mockRedisClient.On("SREM", ActiveRunsKey, "run-id").Return(nil).Once()
+
+	assert.NoError(t, s.testWorkflowStore.SaveRun(run))
+
+	s.mockRedisClient.AssertExpectations(t)
+}
+
+func (s *WorkflowStoreTestSuite) TestActiveRuns() {
+	t := s.T()
+
+	run := Run{ID: "run-id", WorkflowName: "migrate", Status: StatusRunning, CreatedAt: time.Now().UTC()}
+	binaryRun, err := json.Marshal(run)
+	assert.NoError(t, err)
+
+	s.mockRedisClient.On("SMEMBERS", ActiveRunsKey).Return([]string{"run-id", "expired-id"}, nil).Once()
+	s.mockRedisClient.On("MGET", "run-id-workflow-run", "expired-id-workflow-run").Return([][]byte{binaryRun, nil}, nil).Once()
+	s.mockRedisClient.On("SREM", ActiveRunsKey, "expired-id").Return(nil).Once()
+
+	runs, err := s.testWorkflowStore.ActiveRuns()
+	assert.NoError(t, err)
+	assert.Equal(t, []Run{run}, runs)
+
+	s.mockRedisClient.AssertExpectations(t)
+}
+
+func (s *WorkflowStoreTestSuite) TestAcquireLease() {
+	t := s.T()
+
+	s.mockRedisClient.On("SETNX", "run-id-workflow-run-lease", 60, []byte("runner-id")).Return(true, nil).Once()
+
+	acquired, err := s.testWorkflowStore.AcquireLease("run-id", "runner-id", time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+}
+
+func (s *WorkflowStoreTestSuite) TestRenewLease() {
+	t := s.T()
+
+	s.mockRedisClient.On("EVAL", renewLeaseScript, 1, "run-id-workflow-run-lease", "runner-id", 60).Return(int64(1), nil).Once()
+	s.mockRedisClient.On("EVAL", renewLeaseScript, 1, "run-id-workflow-run-lease", "other-runner-id", 60).Return(int64(0), nil).Once()
+
+	renewed, err := s.testWorkflowStore.RenewLease("run-id", "runner-id", time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, renewed)
+
+	renewed, err = s.testWorkflowStore.RenewLease("run-id", "other-runner-id", time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, renewed)
+}
+
+func (s *WorkflowStoreTestSuite) TestReleaseLease() {
+	t := s.T()
+
+	s.mockRedisClient.On("EVAL", releaseLeaseScript, 1, "run-id-workflow-run-lease", "runner-id").Return(int64(1), nil).Once()
+
+	assert.NoError(t, s.testWorkflowStore.ReleaseLease("run-id", "runner-id"))
+
+	s.mockRedisClient.AssertExpectations(t)
+}
+
+func (s *WorkflowStoreTestSuite) TestGetRunNotFound() {
+	t := s.T()
+
+	s.mockRedisClient.On("GET", "run-id-workflow-run").Return([]byte{}, redis.ErrNil).Once()
+
+	_, err := s.testWorkflowStore.GetRun("run-id")
+	assert.Equal(t, ErrRunNotFound, err)
+}
+
+func (s *WorkflowStoreTestSuite) TestCancellation() {
+	t := s.T()
+
+	s.mockRedisClient.On("SET", "run-id-workflow-run-cancelled", []byte("true")).Return(nil).Once()
+	s.mockRedisClient.On("GET", "run-id-workflow-run-cancelled").Return([]byte{}, redis.ErrNil).Once()
+	s.mockRedisClient.On("GET", "run-id-workflow-run-cancelled").Return([]byte("true"), nil).Once()
+
+	cancelled, err := s.testWorkflowStore.CancellationRequested("run-id")
+	assert.NoError(t, err)
+	assert.False(t, cancelled)
+
+	assert.NoError(t, s.testWorkflowStore.RequestCancellation("run-id"))
+
+	cancelled, err = s.testWorkflowStore.CancellationRequested("run-id")
+	assert.NoError(t, err)
+	assert.True(t, cancelled)
+}
+
+func (s *WorkflowStoreTestSuite) TestCancellationRequestedRedisFailure() {
+	t := s.T()
+
+	s.mockRedisClient.On("GET", "run-id-workflow-run-cancelled").Return([]byte{}, errors.New("error")).Once()
+
+	_, err := s.testWorkflowStore.CancellationRequested("run-id")
+	assert.EqualError(t, err, "error")
+}
+
+func TestWorkflowStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(WorkflowStoreTestSuite))
+}
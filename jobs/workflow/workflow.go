@@ -0,0 +1,131 @@
+package workflow
+
+import (
+	"fmt"
+	"text/template"
+
+	"github.com/gojektech/proctor-engine/utility"
+)
+
+const (
+	ConditionSuccess = "success"
+	ConditionFailure = "failure"
+	ConditionAlways  = "always"
+)
+
+type Step struct {
+	Name      string            `json:"name"`
+	Job       string            `json:"job"`
+	Args      map[string]string `json:"args,omitempty"`
+	DependsOn []string          `json:"depends_on,omitempty"`
+	When      string            `json:"when,omitempty"`
+}
+
+type Workflow struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Steps       []Step `json:"steps"`
+}
+
+func (step Step) Condition() string {
+	if step.When == "" {
+		return ConditionSuccess
+	}
+	return step.When
+}
+
+func (workflow Workflow) Validate() []utility.FieldError {
+	var fieldErrors []utility.FieldError
+	if workflow.Name == "" {
+		fieldErrors = append(fieldErrors, utility.FieldError{Field: "name", Message: "is required"})
+	}
+	if len(workflow.Steps) == 0 {
+		fieldErrors = append(fieldErrors, utility.FieldError{Field: "steps", Message: "must contain at least one step"})
+	}
+
+	stepNames := map[string]bool{}
+	for _, step := range workflow.Steps {
+		stepNames[step.Name] = true
+	}
+
+	seen := map[string]bool{}
+	for i, step := range workflow.Steps {
+		field := fmt.Sprintf("steps[%d]", i)
+		if step.Name == "" {
+			fieldErrors = append(fieldErrors, utility.FieldError{Field: field + ".name", Message: "is required"})
+		} else if seen[step.Name] {
+			fieldErrors = append(fieldErrors, utility.FieldError{Field: field + ".name", Message: "must be unique"})
+		}
+		seen[step.Name] = true
+
+		if step.Job == "" {
+			fieldErrors = append(fieldErrors, utility.FieldError{Field: field + ".job", Message: "is required"})
+		}
+
+		switch step.Condition() {
+		case ConditionSuccess, ConditionAlways:
+		case ConditionFailure:
+			if len(step.DependsOn) == 0 {
+				fieldErrors = append(fieldErrors, utility.FieldError{Field: field + ".when", Message: "failure requires depends_on"})
+			}
+		default:
+			fieldErrors = append(fieldErrors, utility.FieldError{Field: field + ".when", Message: "must be one of success, failure, always"})
+		}
+
+		for j, dependency := range step.DependsOn {
+			if !stepNames[dependency] || dependency == step.Name {
+				fieldErrors = append(fieldErrors, utility.FieldError{Field: fmt.Sprintf("%s.depends_on[%d]", field, j), Message: "must name another step of the workflow"})
+			}
+		}
+
+		for name, value := range step.Args {
+			if _, err := template.New(name).Parse(value); err != nil {
+				fieldErrors = append(fieldErrors, utility.FieldError{Field: fmt.Sprintf("%s.args.%s", field, name), Message: "must be a valid template"})
+			}
+		}
+	}
+
+	if len(fieldErrors) == 0 && workflow.hasCycle() {
+		fieldErrors = append(fieldErrors, utility.FieldError{Field: "steps", Message: "must not contain dependency cycles"})
+	}
+	return fieldErrors
+}
+
+func (workflow Workflow) hasCycle() bool {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+
+	dependencies := map[string][]string{}
+	for _, step := range workflow.Steps {
+		dependencies[step.Name] = step.DependsOn
+	}
+
+	state := map[string]int{}
+	var visit func(string) bool
+	visit = func(name string) bool {
+		switch state[name] {
+		case visiting:
+			return true
+		case visited:
+			return false
+		}
+
+		state[name] = visiting
+		for _, dependency := range dependencies[name] {
+			if visit(dependency) {
+				return true
+			}
+		}
+		state[name] = visited
+		return false
+	}
+
+	for _, step := range workflow.Steps {
+		if visit(step.Name) {
+			return true
+		}
+	}
+	return false
+}
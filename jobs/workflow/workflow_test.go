@@ -0,0 +1,93 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func migrationWorkflow() Workflow {
+	return Workflow{
+		Name: "migrate",
+		Steps: []Step{
+			{Name: "snapshot", Job: "db-snapshot"},
+			{Name: "migrate", Job: "db-migrate", Args: map[string]string{"SNAPSHOT_ID": "{{ .steps.snapshot.output.snapshot_id }}"}, DependsOn: []string{"snapshot"}},
+			{Name: "verify", Job: "db-verify", DependsOn: []string{"migrate"}},
+			{Name: "restore", Job: "db-restore", DependsOn: []string{"migrate", "verify"}, When: ConditionFailure},
+			{Name: "notify", Job: "notify", DependsOn: []string{"verify", "restore"}, When: ConditionAlways},
+		},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	assert.Empty(t, migrationWorkflow().Validate())
+}
+
+func TestValidateStepFields(t *testing.T) {
+	workflow := Workflow{
+		Name: "migrate",
+		Steps: []Step{
+			{Name: "snapshot", Job: "db-snapshot"},
+			{Name: "snapshot", When: "sometimes", DependsOn: []string{"unknown"}, Args: map[string]string{"ID": "{{ .steps"}},
+			{Name: "restore", Job: "db-restore", When: ConditionFailure},
+		},
+	}
+
+	fields := []string{}
+	for _, fieldError := range workflow.Validate() {
+		fields = append(fields, fieldError.Field)
+	}
+	assert.ElementsMatch(t, []string{"steps[1].name", "steps[1].job", "steps[1].when", "steps[1].depends_on[0]", "steps[1].args.ID", "steps[2].when"}, fields)
+}
+
+func TestValidateRejectsCycles(t *testing.T) {
+	workflow := Workflow{
+		Name: "migrate",
+		Steps: []Step{
+			{Name: "a", Job: "job", DependsOn: []string{"c"}},
+			{Name: "b", Job: "job", DependsOn: []string{"a"}},
+			{Name: "c", Job: "job", DependsOn: []string{"b"}},
+		},
+	}
+
+	fieldErrors := workflow.Validate()
+	assert.Len(t, fieldErrors, 1)
+	assert.Equal(t, "steps", fieldErrors[0].Field)
+}
+
+func TestRenderArgs(t *testing.T) {
+	run := newRun("run-id", migrationWorkflow(), map[string]string{"DATABASE": "orders"}, "")
+	run.Steps[0].Status = StatusSucceeded
+	run.Steps[0].Output = `{"snapshot_id":"snap-42"}`
+
+	args, err := renderArgs(map[string]string{
+		"SNAPSHOT_ID": "{{ .steps.snapshot.output.snapshot_id }}",
+		"DATABASE":    "{{ .inputs.DATABASE }}",
+		"STATUS":      "{{ .steps.snapshot.status }}",
+	}, run.templateData())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"SNAPSHOT_ID": "snap-42", "DATABASE": "orders", "STATUS": StatusSucceeded}, args)
+}
+
+func TestRenderArgsWithMissingValue(t *testing.T) {
+	run := newRun("run-id", migrationWorkflow(), nil, "")
+
+	_, err := renderArgs(map[string]string{"DATABASE": "{{ .inputs.DATABASE }}"}, run.templateData())
+	assert.Error(t, err)
+}
+
+func TestConditionMet(t *testing.T) {
+	succeeded := StepRun{Status: StatusSucceeded}
+	failed := StepRun{Status: StatusFailed}
+	skipped := StepRun{Status: StatusSkipped}
+
+	assert.True(t, conditionMet(ConditionSuccess, nil))
+	assert.True(t, conditionMet(ConditionSuccess, []StepRun{succeeded, succeeded}))
+	assert.False(t, conditionMet(ConditionSuccess, []StepRun{succeeded, failed}))
+	assert.False(t, conditionMet(ConditionSuccess, []StepRun{skipped}))
+
+	assert.True(t, conditionMet(ConditionFailure, []StepRun{succeeded, failed}))
+	assert.False(t, conditionMet(ConditionFailure, []StepRun{succeeded, skipped}))
+
+	assert.True(t, conditionMet(ConditionAlways, []StepRun{failed, skipped}))
+}
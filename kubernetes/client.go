@@ -1,56 +1,106 @@
 package kubernetes
 
 import (
+	"bufio"
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"time"
 
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/gojektech/proctor-engine/config"
 	"github.com/gojektech/proctor-engine/logger"
+	"github.com/gojektech/proctor-engine/metrics"
+	authorization_v1 "k8s.io/api/authorization/v1"
+	batch_v1 "k8s.io/api/batch/v1"
+	"k8s.io/api/core/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/pkg/api/v1"
-	batch_v1 "k8s.io/client-go/pkg/apis/batch/v1"
+	batch_client_v1 "k8s.io/client-go/kubernetes/typed/batch/v1"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
+const OutputPath = "/proctor/output.json"
+
 var typeMeta meta_v1.TypeMeta
-var namespace string
 
 func init() {
 	typeMeta = meta_v1.TypeMeta{
 		Kind:       "Job",
 		APIVersion: "batch/v1",
 	}
-	namespace = config.DefaultNamespace()
 }
 
+const jobNamePrefix = "proctor-"
+
+const (
+	slotLabelKey    = "proctor-slot"
+	slotJobLabelKey = "proctor-slot-job"
+)
+
+const (
+	rewatchMaxDelay    = 30 * time.Second
+	waitDeadlineMargin = 5 * time.Minute
+)
+
+var rewatchInitialDelay = time.Second
+
+var (
+	ErrJobNotFound     = errors.New("kubernetes job not found")
+	ErrJobWaitTimedOut = errors.New("timed out waiting for kubernetes job to finish")
+)
+
 type client struct {
-	clientSet kubernetes.Interface
+	clusters []*cluster
 }
 
 type Client interface {
-	ExecuteJob(string, map[string]string) (string, error)
-	StreamJobLogs(string) (io.ReadCloser, error)
+	ExecuteJob(context.Context, ExecutionSpec) (string, error)
+	StreamJobLogs(context.Context, Target, string) (io.ReadCloser, error)
+	WaitForJobCompletion(context.Context, Target, string) (*JobStatus, error)
+	JobLogsTail(context.Context, Target, string, int) ([]string, error)
+	ListFinishedJobs() ([]JobStatus, error)
+	ListActiveSlots() ([]Slot, error)
+	DeleteJob(Target, string) error
+	Ping() error
+	CheckNamespaceAccess() error
 }
 
 func NewClient(kubeconfig string) Client {
 	var newClient client
 
+	clusterConfigs, err := ClusterConfigs()
+	if err != nil {
+		panic(err.Error())
+	}
+
 	config, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
 	if err != nil {
 		panic(err.Error())
 	}
 
-	newClient.clientSet, err = kubernetes.NewForConfig(config)
+	defaultClientSet, err := kubernetes.NewForConfig(config)
 	if err != nil {
 		panic(err.Error())
 	}
+	newClient.clusters = append(newClient.clusters, newCluster(clusterConfigs[0], defaultClientSet))
+
+	for _, clusterConfig := range clusterConfigs[1:] {
+		clientSet, err := clusterClientSet(clusterConfig)
+		if err != nil {
+			panic(fmt.Sprintf("kubernetes cluster %s: %s", clusterConfig.Name, err))
+		}
+		newClient.clusters = append(newClient.clusters, newCluster(clusterConfig, clientSet))
+	}
 
 	return &newClient
 }
@@ -68,7 +118,7 @@ func getEnvVars(envMap map[string]string) []v1.EnvVar {
 }
 
 func uniqueName() string {
-	return "proctor" + "-" + rand.String(9)
+	return jobNamePrefix + rand.String(9)
 }
 
 func jobLabel(jobName string) map[string]string {
@@ -77,27 +127,62 @@ func jobLabel(jobName string) map[string]string {
 	}
 }
 
+func slotLabel(jobName string, slot Slot) map[string]string {
+	label := jobLabel(jobName)
+	if slot.ID != "" {
+		label[slotLabelKey] = slot.ID
+		label[slotJobLabelKey] = slot.JobName
+	}
+	return label
+}
+
 func jobLabelSelector(jobName string) string {
 	return fmt.Sprintf("job=%s", jobName)
 }
 
-func (client *client) ExecuteJob(imageName string, envMap map[string]string) (string, error) {
+func restartPolicy(backoffLimit *int32) v1.RestartPolicy {
+	if backoffLimit != nil && *backoffLimit == 0 {
+		return v1.RestartPolicyNever
+	}
+	return v1.RestartPolicyOnFailure
+}
+
+func (client *client) ExecuteJob(ctx context.Context, spec ExecutionSpec) (string, error) {
 	uniqueJobName := uniqueName()
-	label := jobLabel(uniqueJobName)
+	label := slotLabel(uniqueJobName, spec.Slot)
+	log := logger.FromContext(ctx).WithField(logger.ExecutionNameField, uniqueJobName)
 
-	batchV1 := client.clientSet.BatchV1()
+	cluster, namespace, err := client.resolve(spec.Target)
+	if err != nil {
+		log.Error("Error resolving kubernetes cluster for job: ", err)
+		return "", err
+	}
+
+	batchV1 := cluster.clientSet.BatchV1()
 	kubernetesJobs := batchV1.Jobs(namespace)
 
 	container := v1.Container{
-		Name:  uniqueJobName,
-		Image: imageName,
-		Env:   getEnvVars(envMap),
+		Name:                   uniqueJobName,
+		Image:                  spec.ImageName,
+		Command:                spec.Command,
+		Args:                   spec.Args,
+		WorkingDir:             spec.WorkingDir,
+		Env:                    getEnvVars(spec.EnvVars),
+		TerminationMessagePath: OutputPath,
+	}
+
+	defaultScheduling, err := DefaultScheduling()
+	if err != nil {
+		log.Error("Error reading default job scheduling configuration: ", err)
+		return "", err
 	}
 
 	podSpec := v1.PodSpec{
 		Containers:    []v1.Container{container},
-		RestartPolicy: v1.RestartPolicyOnFailure,
+		RestartPolicy: restartPolicy(spec.BackoffLimit),
 	}
+	defaultScheduling.Merge(spec.Scheduling).apply(&podSpec)
+	mountVolumes(&podSpec, uniqueJobName, spec.Volumes, spec.Files)
 
 	objectMeta := meta_v1.ObjectMeta{
 		Name:   uniqueJobName,
@@ -110,8 +195,10 @@ func (client *client) ExecuteJob(imageName string, envMap map[string]string) (st
 	}
 
 	jobSpec := batch_v1.JobSpec{
-		Template:              template,
-		ActiveDeadlineSeconds: config.KubeJobActiveDeadlineSeconds(),
+		Template:                template,
+		ActiveDeadlineSeconds:   config.KubeJobActiveDeadlineSeconds(),
+		BackoffLimit:            spec.BackoffLimit,
+		TTLSecondsAfterFinished: cluster.ttlSecondsAfterFinished(log),
 	}
 
 	jobToRun := batch_v1.Job{
@@ -120,26 +207,93 @@ func (client *client) ExecuteJob(imageName string, envMap map[string]string) (st
 		Spec:       jobSpec,
 	}
 
-	_, err := kubernetesJobs.Create(&jobToRun)
+	start := time.Now()
+	createdJob, err := kubernetesJobs.Create(&jobToRun)
+	metrics.ObserveKubeAPICall("create_job", time.Since(start), err)
 	if err != nil {
+		log.Error("Error creating kubernetes job: ", err)
 		return "", err
 	}
+
+	err = cluster.stageFiles(namespace, createdJob, spec.Files)
+	if err != nil {
+		log.Error("Error staging input files for kubernetes job: ", err)
+		if deleteErr := client.DeleteJob(spec.Target, uniqueJobName); deleteErr != nil {
+			log.Error("Error deleting kubernetes job without input files: ", deleteErr)
+		}
+		return "", err
+	}
+
+	log.Info("Created kubernetes job in cluster ", cluster.config.Name, " namespace ", namespace, " with image: ", spec.ImageName)
 	return uniqueJobName, nil
 }
 
-func (client *client) StreamJobLogs(jobName string) (io.ReadCloser, error) {
+func jobTTLSecondsAfterFinished() int {
+	ttl := config.SucceededJobRetention()
+	if failedRetention := config.FailedJobRetention(); failedRetention > ttl {
+		ttl = failedRetention
+	}
+	return int(ttl.Seconds())
+}
+
+func supportsTTLAfterFinished(serverVersion *version.Info) bool {
+	major, err := strconv.Atoi(serverVersion.Major)
+	if err != nil {
+		return false
+	}
+	minor, err := strconv.Atoi(strings.TrimRight(serverVersion.Minor, "+"))
+	if err != nil {
+		return false
+	}
+	return major > 1 || (major == 1 && minor >= 12)
+}
+
+func (cluster *cluster) ttlSecondsAfterFinished(log *logger.Entry) *int32 {
+	ttl := jobTTLSecondsAfterFinished()
+	if ttl <= 0 {
+		return nil
+	}
+
+	cluster.ttlAfterFinishedDetection.Do(func() {
+		start := time.Now()
+		serverVersion, err := cluster.clientSet.Discovery().ServerVersion()
+		metrics.ObserveKubeAPICall("server_version", time.Since(start), err)
+		if err != nil {
+			log.Error("Error detecting kubernetes server version: ", err)
+			return
+		}
+		cluster.supportsTTLAfterFinished = supportsTTLAfterFinished(serverVersion)
+	})
+	if !cluster.supportsTTLAfterFinished {
+		return nil
+	}
+
+	ttlSeconds := int32(ttl)
+	return &ttlSeconds
+}
+
+func (client *client) StreamJobLogs(ctx context.Context, target Target, jobName string) (io.ReadCloser, error) {
+	log := logger.FromContext(ctx).WithField(logger.ExecutionNameField, jobName)
+
+	cluster, namespace, err := client.resolve(target)
+	if err != nil {
+		return nil, err
+	}
+
 	listOptions := meta_v1.ListOptions{
 		TypeMeta:      typeMeta,
 		LabelSelector: jobLabelSelector(jobName),
 	}
 
-	coreV1 := client.clientSet.CoreV1()
+	coreV1 := cluster.clientSet.CoreV1()
 	kubernetesPods := coreV1.Pods(namespace)
 
-	logger.Debug("list of pods")
+	log.Debug("list of pods")
 
 	for {
+		start := time.Now()
 		listOfPods, err := kubernetesPods.List(listOptions)
+		metrics.ObserveKubeAPICall("list_pods", time.Since(start), err)
 		if err != nil {
 			return nil, errors.New(fmt.Sprintf("Error fetching kubernetes Pods list %v", err))
 		}
@@ -147,9 +301,11 @@ func (client *client) StreamJobLogs(jobName string) (io.ReadCloser, error) {
 		if len(listOfPods.Items) > 0 {
 			podJob := listOfPods.Items[0]
 			if podJob.Status.Phase == v1.PodRunning || podJob.Status.Phase == v1.PodSucceeded || podJob.Status.Phase == v1.PodFailed {
-				return getLogsStreamReaderFor(podJob.ObjectMeta.Name)
+				return cluster.podLogs(log, namespace, podJob.ObjectMeta.Name, &v1.PodLogOptions{Follow: true})
 			} else {
+				start := time.Now()
 				watchPod, err := kubernetesPods.Watch(listOptions)
+				metrics.ObserveKubeAPICall("watch_pods", time.Since(start), err)
 				if err != nil {
 					return nil, errors.New(fmt.Sprintf("Error watching kubernetes Pods %v", err))
 				}
@@ -160,7 +316,7 @@ func (client *client) StreamJobLogs(jobName string) (io.ReadCloser, error) {
 				waitingForKubePods := make(chan bool)
 				go func() {
 					defer close(waitingForKubePods)
-					time.Sleep(time.Duration(config.KubePodsListWaitTime()) * time.Second)
+					time.Sleep(config.KubePodsListWaitTime())
 					waitingForKubePods <- true
 				}()
 
@@ -168,14 +324,16 @@ func (client *client) StreamJobLogs(jobName string) (io.ReadCloser, error) {
 				case <-resultChan:
 					continue
 				case <-waitingForKubePods:
-					return nil, errors.New(fmt.Sprintf("Pod didn't reach active state after waiting for %d minutes", config.KubePodsListWaitTime()))
+					return nil, errors.New(fmt.Sprintf("Pod didn't reach active state after waiting for %s", config.KubePodsListWaitTime()))
 				}
 			}
 		} else {
-			batchV1 := client.clientSet.BatchV1()
+			batchV1 := cluster.clientSet.BatchV1()
 			kubernetesJobs := batchV1.Jobs(namespace)
 
+			start := time.Now()
 			watchJob, err := kubernetesJobs.Watch(listOptions)
+			metrics.ObserveKubeAPICall("watch_jobs", time.Since(start), err)
 			if err != nil {
 				return nil, errors.New(fmt.Sprintf("Error watching kubernetes Jobs %v", err))
 			}
@@ -186,7 +344,7 @@ func (client *client) StreamJobLogs(jobName string) (io.ReadCloser, error) {
 			waitingForKubeJobs := make(chan bool)
 			go func() {
 				defer close(waitingForKubeJobs)
-				time.Sleep(time.Duration(config.KubePodsListWaitTime()) * time.Second)
+				time.Sleep(config.KubePodsListWaitTime())
 				waitingForKubeJobs <- true
 			}()
 
@@ -194,14 +352,340 @@ func (client *client) StreamJobLogs(jobName string) (io.ReadCloser, error) {
 			case <-resultChan:
 				continue
 			case <-waitingForKubeJobs:
-				return nil, errors.New(fmt.Sprintf("Couldn't find a pod for job's given list options %v after waiting for %d minutes", listOptions, config.KubePodsListWaitTime()))
+				return nil, errors.New(fmt.Sprintf("Couldn't find a pod for job's given list options %v after waiting for %s", listOptions, config.KubePodsListWaitTime()))
 			}
 		}
 	}
 }
 
-func getLogsStreamReaderFor(podName string) (io.ReadCloser, error) {
-	logger.Debug("reading pod logs for: ", podName)
-	resp, err := http.Get("http://" + config.KubeClusterHostName() + "/api/v1/namespaces/default/pods/" + podName + "/log?follow=true")
-	return resp.Body, err
+func (client *client) WaitForJobCompletion(ctx context.Context, target Target, jobName string) (*JobStatus, error) {
+	log := logger.FromContext(ctx).WithField(logger.ExecutionNameField, jobName)
+
+	cluster, namespace, err := client.resolve(target)
+	if err != nil {
+		return nil, err
+	}
+
+	listOptions := meta_v1.ListOptions{
+		TypeMeta:      typeMeta,
+		LabelSelector: jobLabelSelector(jobName),
+	}
+
+	batchV1 := cluster.clientSet.BatchV1()
+	kubernetesJobs := batchV1.Jobs(namespace)
+
+	waitCtx, cancel := context.WithTimeout(ctx, waitDeadline())
+	defer cancel()
+
+	delay := rewatchInitialDelay
+	for {
+		start := time.Now()
+		watchJob, err := kubernetesJobs.Watch(listOptions)
+		metrics.ObserveKubeAPICall("watch_jobs", time.Since(start), err)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("Error watching kubernetes Jobs %v", err))
+		}
+
+		job, err := waitForFinishedJob(waitCtx, watchJob)
+		watchJob.Stop()
+		if err == nil && job == nil {
+			log.Debug("watch closed before job completion, checking job")
+			job, err = getFinishedJob(kubernetesJobs, jobName)
+		}
+		if err != nil {
+			return nil, waitError(ctx, err)
+		}
+
+		if job != nil {
+			jobStatus := newJobStatus(job)
+			jobStatus.Target = Target{Cluster: cluster.config.Name, Namespace: namespace}
+			terminated := cluster.terminatedState(log, namespace, jobName)
+			if terminated != nil {
+				jobStatus.ExitCode = &terminated.ExitCode
+				jobStatus.Output = terminated.Message
+			}
+			return jobStatus, nil
+		}
+
+		select {
+		case <-waitCtx.Done():
+			return nil, waitError(ctx, waitCtx.Err())
+		case <-time.After(delay):
+		}
+		delay *= 2
+		if delay > rewatchMaxDelay {
+			delay = rewatchMaxDelay
+		}
+	}
+}
+
+// waitDeadline bounds a wait to the job's active deadline plus a margin for
+// the job controller to record the failure.
+func waitDeadline() time.Duration {
+	return time.Duration(*config.KubeJobActiveDeadlineSeconds())*time.Second + waitDeadlineMargin
+}
+
+// waitError reports the wait's own deadline as ErrJobWaitTimedOut while
+// passing through the caller's cancellation or deadline unchanged.
+func waitError(ctx context.Context, err error) error {
+	if err == context.DeadlineExceeded && ctx.Err() == nil {
+		return ErrJobWaitTimedOut
+	}
+	return err
+}
+
+func getFinishedJob(kubernetesJobs batch_client_v1.JobInterface, jobName string) (*batch_v1.Job, error) {
+	start := time.Now()
+	job, err := kubernetesJobs.Get(jobName, meta_v1.GetOptions{})
+	metrics.ObserveKubeAPICall("get_job", time.Since(start), err)
+	if k8s_errors.IsNotFound(err) {
+		return nil, ErrJobNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if finishedCondition(job) == nil {
+		return nil, nil
+	}
+	return job, nil
+}
+
+func waitForFinishedJob(ctx context.Context, watchJob watch.Interface) (*batch_v1.Job, error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case event, ok := <-watchJob.ResultChan():
+			if !ok {
+				return nil, nil
+			}
+			if event.Type == watch.Deleted {
+				return nil, ErrJobNotFound
+			}
+
+			job, isJob := event.Object.(*batch_v1.Job)
+			if isJob && finishedCondition(job) != nil {
+				return job, nil
+			}
+		}
+	}
+}
+
+func (cluster *cluster) terminatedState(log *logger.Entry, namespace string, jobName string) *v1.ContainerStateTerminated {
+	listOptions := meta_v1.ListOptions{
+		TypeMeta:      typeMeta,
+		LabelSelector: jobLabelSelector(jobName),
+	}
+
+	start := time.Now()
+	listOfPods, err := cluster.clientSet.CoreV1().Pods(namespace).List(listOptions)
+	metrics.ObserveKubeAPICall("list_pods", time.Since(start), err)
+	if err != nil {
+		log.Error("Error fetching pods to find terminated state: ", err)
+		return nil
+	}
+
+	return lastTerminatedState(listOfPods.Items)
+}
+
+func (client *client) JobLogsTail(ctx context.Context, target Target, jobName string, tailLines int) ([]string, error) {
+	log := logger.FromContext(ctx).WithField(logger.ExecutionNameField, jobName)
+
+	cluster, namespace, err := client.resolve(target)
+	if err != nil {
+		return nil, err
+	}
+
+	listOptions := meta_v1.ListOptions{
+		TypeMeta:      typeMeta,
+		LabelSelector: jobLabelSelector(jobName),
+	}
+
+	start := time.Now()
+	listOfPods, err := cluster.clientSet.CoreV1().Pods(namespace).List(listOptions)
+	metrics.ObserveKubeAPICall("list_pods", time.Since(start), err)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("Error fetching kubernetes Pods list %v", err))
+	}
+
+	pod := latestPod(listOfPods.Items)
+	if pod == nil {
+		return nil, errors.New(fmt.Sprintf("No pods found for job %s", jobName))
+	}
+
+	tail := int64(tailLines)
+	logs, err := cluster.podLogs(log, namespace, pod.ObjectMeta.Name, &v1.PodLogOptions{TailLines: &tail})
+	if err != nil {
+		return nil, err
+	}
+	defer logs.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(logs)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines, scanner.Err()
+}
+
+func (client *client) ListFinishedJobs() ([]JobStatus, error) {
+	listOptions := meta_v1.ListOptions{
+		TypeMeta:      typeMeta,
+		LabelSelector: "job",
+	}
+
+	var finishedJobs []JobStatus
+	for _, cluster := range client.clusters {
+		for _, namespace := range cluster.config.namespaces() {
+			start := time.Now()
+			listOfJobs, err := cluster.clientSet.BatchV1().Jobs(namespace).List(listOptions)
+			metrics.ObserveKubeAPICall("list_jobs", time.Since(start), err)
+			if err != nil {
+				return nil, errors.New(fmt.Sprintf("Error fetching kubernetes Jobs list in cluster %s namespace %s %v", cluster.config.Name, namespace, err))
+			}
+
+			for i := range listOfJobs.Items {
+				job := &listOfJobs.Items[i]
+				if !strings.HasPrefix(job.Name, jobNamePrefix) || finishedCondition(job) == nil {
+					continue
+				}
+				jobStatus := newJobStatus(job)
+				jobStatus.Target = Target{Cluster: cluster.config.Name, Namespace: namespace}
+				finishedJobs = append(finishedJobs, *jobStatus)
+			}
+		}
+	}
+	return finishedJobs, nil
+}
+
+func (client *client) ListActiveSlots() ([]Slot, error) {
+	listOptions := meta_v1.ListOptions{
+		TypeMeta:      typeMeta,
+		LabelSelector: slotLabelKey,
+	}
+
+	var slots []Slot
+	for _, cluster := range client.clusters {
+		for _, namespace := range cluster.config.namespaces() {
+			start := time.Now()
+			listOfJobs, err := cluster.clientSet.BatchV1().Jobs(namespace).List(listOptions)
+			metrics.ObserveKubeAPICall("list_jobs", time.Since(start), err)
+			if err != nil {
+				return nil, errors.New(fmt.Sprintf("Error fetching kubernetes Jobs list in cluster %s namespace %s %v", cluster.config.Name, namespace, err))
+			}
+
+			for i := range listOfJobs.Items {
+				job := &listOfJobs.Items[i]
+				if finishedCondition(job) != nil {
+					continue
+				}
+				slots = append(slots, Slot{JobName: job.Labels[slotJobLabelKey], ID: job.Labels[slotLabelKey]})
+			}
+		}
+	}
+	return slots, nil
+}
+
+func (client *client) DeleteJob(target Target, jobName string) error {
+	cluster, namespace, err := client.resolve(target)
+	if err != nil {
+		return err
+	}
+
+	propagationPolicy := meta_v1.DeletePropagationBackground
+	deleteOptions := &meta_v1.DeleteOptions{
+		PropagationPolicy: &propagationPolicy,
+	}
+
+	start := time.Now()
+	err = cluster.clientSet.BatchV1().Jobs(namespace).Delete(jobName, deleteOptions)
+	metrics.ObserveKubeAPICall("delete_job", time.Since(start), err)
+	return err
+}
+
+func (client *client) Ping() error {
+	for _, cluster := range client.clusters {
+		start := time.Now()
+		_, err := cluster.clientSet.Discovery().ServerVersion()
+		metrics.ObserveKubeAPICall("server_version", time.Since(start), err)
+		if err != nil {
+			return fmt.Errorf("kubernetes cluster %s: %s", cluster.config.Name, err)
+		}
+	}
+	return nil
+}
+
+func requiredNamespaceAccess(namespace string) []authorization_v1.ResourceAttributes {
+	return []authorization_v1.ResourceAttributes{
+		{Namespace: namespace, Verb: "create", Group: "batch", Resource: "jobs"},
+		{Namespace: namespace, Verb: "watch", Group: "batch", Resource: "jobs"},
+		{Namespace: namespace, Verb: "list", Group: "batch", Resource: "jobs"},
+		{Namespace: namespace, Verb: "delete", Group: "batch", Resource: "jobs"},
+		{Namespace: namespace, Verb: "list", Resource: "pods"},
+		{Namespace: namespace, Verb: "get", Resource: "pods", Subresource: "log"},
+	}
+}
+
+func (client *client) CheckNamespaceAccess() error {
+	for _, cluster := range client.clusters {
+		for _, namespace := range cluster.config.namespaces() {
+			if err := cluster.checkNamespaceAccess(namespace); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (cluster *cluster) checkNamespaceAccess(namespace string) error {
+	selfSubjectAccessReviews := cluster.clientSet.AuthorizationV1().SelfSubjectAccessReviews()
+
+	for _, resourceAttributes := range requiredNamespaceAccess(namespace) {
+		accessReview := &authorization_v1.SelfSubjectAccessReview{
+			Spec: authorization_v1.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &resourceAttributes,
+			},
+		}
+
+		start := time.Now()
+		result, err := selfSubjectAccessReviews.Create(accessReview)
+		metrics.ObserveKubeAPICall("create_self_subject_access_review", time.Since(start), err)
+		if err != nil {
+			return err
+		}
+
+		if !result.Status.Allowed {
+			return errors.New(fmt.Sprintf("Not allowed to %s %s%s in namespace %s", resourceAttributes.Verb, resourceAttributes.Resource, subresourceSuffix(resourceAttributes.Subresource), namespace))
+		}
+	}
+	return nil
+}
+
+func subresourceSuffix(subresource string) string {
+	if subresource == "" {
+		return ""
+	}
+	return "/" + subresource
+}
+
+func (cluster *cluster) podLogs(log *logger.Entry, namespace string, podName string, logOptions *v1.PodLogOptions) (io.ReadCloser, error) {
+	log.Debug("reading pod logs for: ", podName)
+	start := time.Now()
+
+	if cluster.config.HostName == "" {
+		logs, err := cluster.clientSet.CoreV1().Pods(namespace).GetLogs(podName, logOptions).Stream()
+		metrics.ObserveKubeAPICall("get_pod_logs", time.Since(start), err)
+		return logs, err
+	}
+
+	query := "follow=true"
+	if logOptions.TailLines != nil {
+		query = fmt.Sprintf("tailLines=%d", *logOptions.TailLines)
+	}
+	resp, err := http.Get(fmt.Sprintf("http://%s/api/v1/namespaces/%s/pods/%s/log?%s", cluster.config.HostName, namespace, podName, query))
+	metrics.ObserveKubeAPICall("get_pod_logs", time.Since(start), err)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
 }
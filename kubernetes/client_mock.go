@@ -1,6 +1,7 @@
 package kubernetes
 
 import (
+	"context"
 	"io"
 
 	"github.com/gojektech/proctor-engine/utility"
@@ -11,12 +12,47 @@ type MockClient struct {
 	mock.Mock
 }
 
-func (m *MockClient) ExecuteJob(jobName string, envMap map[string]string) (string, error) {
-	args := m.Called(jobName, envMap)
+func (m *MockClient) ExecuteJob(ctx context.Context, spec ExecutionSpec) (string, error) {
+	args := m.Called(ctx, spec)
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockClient) StreamJobLogs(jobName string) (io.ReadCloser, error) {
-	args := m.Called(jobName)
+func (m *MockClient) StreamJobLogs(ctx context.Context, target Target, jobName string) (io.ReadCloser, error) {
+	args := m.Called(ctx, target, jobName)
 	return args.Get(0).(*utility.Buffer), args.Error(1)
 }
+
+func (m *MockClient) WaitForJobCompletion(ctx context.Context, target Target, jobName string) (*JobStatus, error) {
+	args := m.Called(ctx, target, jobName)
+	return args.Get(0).(*JobStatus), args.Error(1)
+}
+
+func (m *MockClient) JobLogsTail(ctx context.Context, target Target, jobName string, tailLines int) ([]string, error) {
+	args := m.Called(ctx, target, jobName, tailLines)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockClient) ListFinishedJobs() ([]JobStatus, error) {
+	args := m.Called()
+	return args.Get(0).([]JobStatus), args.Error(1)
+}
+
+func (m *MockClient) ListActiveSlots() ([]Slot, error) {
+	args := m.Called()
+	return args.Get(0).([]Slot), args.Error(1)
+}
+
+func (m *MockClient) DeleteJob(target Target, jobName string) error {
+	args := m.Called(target, jobName)
+	return args.Error(0)
+}
+
+func (m *MockClient) Ping() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *MockClient) CheckNamespaceAccess() error {
+	args := m.Called()
+	return args.Error(0)
+}
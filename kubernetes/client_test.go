@@ -2,18 +2,28 @@ package kubernetes
 
 import (
 	"bufio"
+	"context"
+	"os"
 	"testing"
+	"time"
 
 	"github.com/gojektech/proctor-engine/config"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
+	batch_api_v1 "k8s.io/api/batch/v1"
+	"k8s.io/api/core/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
 	batch_v1 "k8s.io/client-go/kubernetes/typed/batch/v1"
-	"k8s.io/client-go/pkg/api/v1"
+	core "k8s.io/client-go/testing"
 
 	fakeclientset "k8s.io/client-go/kubernetes/fake"
 
 	"github.com/jarcoal/httpmock"
+	authorization_v1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/version"
 )
 
 type ClientTestSuite struct {
@@ -30,7 +40,7 @@ type ClientTestSuite struct {
 func (suite *ClientTestSuite) SetupTest() {
 	suite.fakeClientSet = fakeclientset.NewSimpleClientset()
 	suite.testClient = &client{
-		clientSet: suite.fakeClientSet,
+		clusters: []*cluster{newCluster(defaultClusterConfig(), suite.fakeClientSet)},
 	}
 	suite.jobName = "job1"
 	suite.podName = "pod1"
@@ -53,7 +63,7 @@ func (suite *ClientTestSuite) SetupTest() {
 	})
 
 	suite.testClientStreaming = &client{
-		clientSet: suite.fakeClientSetStreaming,
+		clusters: []*cluster{newCluster(defaultClusterConfig(), suite.fakeClientSetStreaming)},
 	}
 }
 
@@ -63,7 +73,7 @@ func (suite *ClientTestSuite) TestJobExecution() {
 	envVarsForContainer := map[string]string{"SAMPLE_ARG": "samle-value"}
 	sampleImageName := "img1"
 
-	executedJobname, err := suite.testClient.ExecuteJob(sampleImageName, envVarsForContainer)
+	executedJobname, err := suite.testClient.ExecuteJob(context.Background(), ExecutionSpec{ImageName: sampleImageName, EnvVars: envVarsForContainer})
 	assert.NoError(t, err)
 
 	typeMeta := meta_v1.TypeMeta{
@@ -92,26 +102,218 @@ func (suite *ClientTestSuite) TestJobExecution() {
 	assert.Equal(t, config.KubeJobActiveDeadlineSeconds(), executedJob.Spec.ActiveDeadlineSeconds)
 
 	assert.Equal(t, v1.RestartPolicyOnFailure, executedJob.Spec.Template.Spec.RestartPolicy)
+	assert.Nil(t, executedJob.Spec.BackoffLimit)
 
 	container := executedJob.Spec.Template.Spec.Containers[0]
 	assert.Equal(t, executedJobname, container.Name)
 
 	assert.Equal(t, sampleImageName, container.Image)
+	assert.Equal(t, OutputPath, container.TerminationMessagePath)
 
 	expectedEnvVars := getEnvVars(envVarsForContainer)
 	assert.Equal(t, expectedEnvVars, container.Env)
 }
 
+func (s *ClientTestSuite) TestJobExecutionWithCommandAndArgs() {
+	t := s.T()
+
+	spec := ExecutionSpec{
+		ImageName:  "ops-toolbox",
+		Command:    []string{"/bin/sh", "-c"},
+		Args:       []string{"vacuum.sh --table=orders"},
+		WorkingDir: "/opt/scripts",
+	}
+	executedJobName, err := s.testClient.ExecuteJob(context.Background(), spec)
+	assert.NoError(t, err)
+
+	executedJob, err := s.fakeClientSet.BatchV1().Jobs(config.DefaultNamespace()).Get(executedJobName, meta_v1.GetOptions{})
+	assert.NoError(t, err)
+
+	container := executedJob.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, "ops-toolbox", container.Image)
+	assert.Equal(t, spec.Command, container.Command)
+	assert.Equal(t, spec.Args, container.Args)
+	assert.Equal(t, "/opt/scripts", container.WorkingDir)
+}
+
+func (s *ClientTestSuite) TestJobExecutionWithScheduling() {
+	t := s.T()
+
+	os.Setenv("PROCTOR_JOB_NODE_SELECTOR", "pool=batch")
+	os.Setenv("PROCTOR_JOB_SERVICE_ACCOUNT_NAME", "proctor-jobs")
+	viper.AutomaticEnv()
+	defer os.Unsetenv("PROCTOR_JOB_NODE_SELECTOR")
+	defer os.Unsetenv("PROCTOR_JOB_SERVICE_ACCOUNT_NAME")
+
+	runAsNonRoot := true
+	scheduling := &Scheduling{
+		NodeSelector:      map[string]string{"zone": "a"},
+		Tolerations:       []v1.Toleration{{Key: "dedicated", Operator: v1.TolerationOpEqual, Value: "batch", Effect: v1.TaintEffectNoSchedule}},
+		ImagePullSecrets:  []string{"registry"},
+		PriorityClassName: "batch-low",
+		RunAsNonRoot:      &runAsNonRoot,
+	}
+	executedJobName, err := s.testClient.ExecuteJob(context.Background(), ExecutionSpec{ImageName: "img1", Scheduling: scheduling})
+	assert.NoError(t, err)
+
+	executedJob, err := s.fakeClientSet.BatchV1().Jobs(config.DefaultNamespace()).Get(executedJobName, meta_v1.GetOptions{})
+	assert.NoError(t, err)
+
+	podSpec := executedJob.Spec.Template.Spec
+	assert.Equal(t, map[string]string{"pool": "batch", "zone": "a"}, podSpec.NodeSelector)
+	assert.Equal(t, scheduling.Tolerations, podSpec.Tolerations)
+	assert.Equal(t, "proctor-jobs", podSpec.ServiceAccountName)
+	assert.Equal(t, []v1.LocalObjectReference{{Name: "registry"}}, podSpec.ImagePullSecrets)
+	assert.Equal(t, "batch-low", podSpec.PriorityClassName)
+	assert.Equal(t, &runAsNonRoot, podSpec.Containers[0].SecurityContext.RunAsNonRoot)
+	assert.Nil(t, podSpec.Containers[0].SecurityContext.ReadOnlyRootFilesystem)
+}
+
+func (s *ClientTestSuite) TestJobExecutionWithVolumesAndFiles() {
+	t := s.T()
+
+	spec := ExecutionSpec{
+		ImageName: "importer",
+		Volumes: []Volume{
+			{Name: "scratch", MountPath: "/scratch", EmptyDir: &EmptyDirVolume{SizeLimit: "1Gi"}},
+			{Name: "archive", MountPath: "/archive", ReadOnly: true, PersistentVolumeClaim: &PersistentVolumeClaimVolume{ClaimName: "archive-pvc"}},
+		},
+		Files: []File{
+			{Name: "users.csv", MountPath: "/data/users.csv", Content: []byte("id,email\n")},
+			{Name: "key.pem", MountPath: "/etc/keys/key.pem", Content: []byte("private"), Sensitive: true},
+		},
+	}
+	executedJobName, err := s.testClient.ExecuteJob(context.Background(), spec)
+	assert.NoError(t, err)
+
+	executedJob, err := s.fakeClientSet.BatchV1().Jobs(config.DefaultNamespace()).Get(executedJobName, meta_v1.GetOptions{})
+	assert.NoError(t, err)
+
+	podSpec := executedJob.Spec.Template.Spec
+	volumeNames := []string{}
+	for _, volume := range podSpec.Volumes {
+		volumeNames = append(volumeNames, volume.Name)
+	}
+	assert.Equal(t, []string{"volume-scratch", "volume-archive", "files", "secret-files"}, volumeNames)
+	assert.Equal(t, "archive-pvc", podSpec.Volumes[1].PersistentVolumeClaim.ClaimName)
+	assert.Equal(t, executedJobName+"-files", podSpec.Volumes[2].ConfigMap.Name)
+	assert.Equal(t, executedJobName+"-files", podSpec.Volumes[3].Secret.SecretName)
+
+	mounts := podSpec.Containers[0].VolumeMounts
+	assert.Len(t, mounts, 4)
+	assert.Equal(t, v1.VolumeMount{Name: "files", MountPath: "/data/users.csv", SubPath: "users.csv", ReadOnly: true}, mounts[2])
+	assert.Equal(t, v1.VolumeMount{Name: "secret-files", MountPath: "/etc/keys/key.pem", SubPath: "key.pem", ReadOnly: true}, mounts[3])
+
+	configMap, err := s.fakeClientSet.CoreV1().ConfigMaps(config.DefaultNamespace()).Get(executedJobName+"-files", meta_v1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"users.csv": []byte("id,email\n")}, configMap.BinaryData)
+	assert.Equal(t, executedJobName, configMap.OwnerReferences[0].Name)
+
+	secret, err := s.fakeClientSet.CoreV1().Secrets(config.DefaultNamespace()).Get(executedJobName+"-files", meta_v1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"key.pem": []byte("private")}, secret.Data)
+}
+
+func TestVolumeValidate(t *testing.T) {
+	assert.Empty(t, Volume{Name: "scratch", MountPath: "/scratch", EmptyDir: &EmptyDirVolume{Medium: "Memory", SizeLimit: "64Mi"}}.Validate())
+	assert.Empty(t, Volume{Name: "archive", MountPath: "/archive", PersistentVolumeClaim: &PersistentVolumeClaimVolume{ClaimName: "archive-pvc"}}.Validate())
+
+	fieldErrors := Volume{Name: "scratch", MountPath: "scratch"}.Validate()
+	assert.Len(t, fieldErrors, 2)
+	assert.Equal(t, "mount_path", fieldErrors[0].Field)
+	assert.Equal(t, "empty_dir", fieldErrors[1].Field)
+
+	fieldErrors = Volume{Name: "scratch", MountPath: "/scratch", EmptyDir: &EmptyDirVolume{SizeLimit: "lots"}}.Validate()
+	assert.Len(t, fieldErrors, 1)
+	assert.Equal(t, "empty_dir.size_limit", fieldErrors[0].Field)
+}
+
+func (s *ClientTestSuite) TestJobExecutionInNamedCluster() {
+	t := s.T()
+
+	stagingClientSet := fakeclientset.NewSimpleClientset()
+	stagingConfig := ClusterConfig{Name: "staging", Namespace: "proctor", Namespaces: []string{"batch"}}
+	testClient := &client{
+		clusters: []*cluster{newCluster(defaultClusterConfig(), s.fakeClientSet), newCluster(stagingConfig, stagingClientSet)},
+	}
+
+	target := Target{Cluster: "staging", Namespace: "batch"}
+	executedJobName, err := testClient.ExecuteJob(context.Background(), ExecutionSpec{ImageName: "img1", Target: target})
+	assert.NoError(t, err)
+
+	_, err = stagingClientSet.BatchV1().Jobs("batch").Get(executedJobName, meta_v1.GetOptions{})
+	assert.NoError(t, err)
+	_, err = s.fakeClientSet.BatchV1().Jobs(config.DefaultNamespace()).Get(executedJobName, meta_v1.GetOptions{})
+	assert.Error(t, err)
+
+	err = testClient.DeleteJob(target, executedJobName)
+	assert.NoError(t, err)
+	_, err = stagingClientSet.BatchV1().Jobs("batch").Get(executedJobName, meta_v1.GetOptions{})
+	assert.Error(t, err)
+}
+
+func (s *ClientTestSuite) TestJobExecutionWithUnknownTarget() {
+	t := s.T()
+
+	_, err := s.testClient.ExecuteJob(context.Background(), ExecutionSpec{ImageName: "img1", Target: Target{Cluster: "production"}})
+	assert.EqualError(t, err, "kubernetes cluster production is not configured")
+
+	_, err = s.testClient.ExecuteJob(context.Background(), ExecutionSpec{ImageName: "img1", Target: Target{Namespace: "kube-system"}})
+	assert.EqualError(t, err, "namespace kube-system is not allowed in kubernetes cluster default")
+}
+
+func (s *ClientTestSuite) TestJobExecutionWithBackoffLimit() {
+	t := s.T()
+
+	backoffLimit := int32(3)
+	executedJobName, err := s.testClient.ExecuteJob(context.Background(), ExecutionSpec{ImageName: "img1", BackoffLimit: &backoffLimit})
+	assert.NoError(t, err)
+
+	executedJob, err := s.fakeClientSet.BatchV1().Jobs(config.DefaultNamespace()).Get(executedJobName, meta_v1.GetOptions{})
+	assert.NoError(t, err)
+
+	assert.Equal(t, int32(3), *executedJob.Spec.BackoffLimit)
+	assert.Equal(t, v1.RestartPolicyOnFailure, executedJob.Spec.Template.Spec.RestartPolicy)
+}
+
+func (s *ClientTestSuite) TestJobExecutionWithoutRetries() {
+	t := s.T()
+
+	backoffLimit := int32(0)
+	executedJobName, err := s.testClient.ExecuteJob(context.Background(), ExecutionSpec{ImageName: "img1", BackoffLimit: &backoffLimit})
+	assert.NoError(t, err)
+
+	executedJob, err := s.fakeClientSet.BatchV1().Jobs(config.DefaultNamespace()).Get(executedJobName, meta_v1.GetOptions{})
+	assert.NoError(t, err)
+
+	assert.Equal(t, int32(0), *executedJob.Spec.BackoffLimit)
+	assert.Equal(t, v1.RestartPolicyNever, executedJob.Spec.Template.Spec.RestartPolicy)
+}
+
+func (s *ClientTestSuite) TestJobExecutionWithSlot() {
+	t := s.T()
+
+	executedJobName, err := s.testClient.ExecuteJob(context.Background(), ExecutionSpec{ImageName: "img1", Slot: Slot{JobName: "vacuum", ID: "slot-id"}})
+	assert.NoError(t, err)
+
+	executedJob, err := s.fakeClientSet.BatchV1().Jobs(config.DefaultNamespace()).Get(executedJobName, meta_v1.GetOptions{})
+	assert.NoError(t, err)
+
+	expectedLabel := map[string]string{"job": executedJobName, slotLabelKey: "slot-id", slotJobLabelKey: "vacuum"}
+	assert.Equal(t, expectedLabel, executedJob.ObjectMeta.Labels)
+	assert.Equal(t, expectedLabel, executedJob.Spec.Template.ObjectMeta.Labels)
+}
+
 func (s *ClientTestSuite) TestStreamLogsSuccess() {
 	t := s.T()
 
 	httpmock.Activate()
 	defer httpmock.DeactivateAndReset()
 
-	httpmock.RegisterResponder("GET", "http://"+config.KubeClusterHostName()+"/api/v1/namespaces/default/pods/"+s.podName+"/log?follow=true",
+	httpmock.RegisterResponder("GET", "http://"+config.KubeClusterHostName()+"/api/v1/namespaces/"+config.DefaultNamespace()+"/pods/"+s.podName+"/log?follow=true",
 		httpmock.NewStringResponder(200, "logs are streaming"))
 
-	logStream, err := s.testClientStreaming.StreamJobLogs(s.jobName)
+	logStream, err := s.testClientStreaming.StreamJobLogs(context.Background(), Target{}, s.jobName)
 	assert.NoError(t, err)
 
 	defer logStream.Close()
@@ -128,10 +330,265 @@ func (s *ClientTestSuite) TestStreamLogsSuccess() {
 func (s *ClientTestSuite) TestStreamLogsPodNotFoundFailure() {
 	t := s.T()
 
-	_, err := s.testClientStreaming.StreamJobLogs("unknown-job")
+	_, err := s.testClientStreaming.StreamJobLogs(context.Background(), Target{}, "unknown-job")
 	assert.Error(t, err)
 }
 
+func (s *ClientTestSuite) finishedJob(conditionType batch_api_v1.JobConditionType, startTime, finishTime time.Time) *batch_api_v1.Job {
+	jobStartTime := meta_v1.NewTime(startTime)
+	return &batch_api_v1.Job{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:   s.jobName,
+			Labels: jobLabel(s.jobName),
+		},
+		Status: batch_api_v1.JobStatus{
+			StartTime: &jobStartTime,
+			Conditions: []batch_api_v1.JobCondition{
+				{
+					Type:               conditionType,
+					Status:             v1.ConditionTrue,
+					LastTransitionTime: meta_v1.NewTime(finishTime),
+				},
+			},
+		},
+	}
+}
+
+func (s *ClientTestSuite) TestWaitForJobCompletionOnSuccess() {
+	t := s.T()
+
+	startTime := time.Date(2018, 3, 3, 10, 0, 0, 0, time.UTC)
+	finishTime := startTime.Add(90 * time.Second)
+
+	_, err := s.fakeClientSet.CoreV1().Pods(config.DefaultNamespace()).Create(&v1.Pod{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:   s.podName,
+			Labels: jobLabel(s.jobName),
+		},
+		Status: v1.PodStatus{
+			ContainerStatuses: []v1.ContainerStatus{
+				{
+					State: v1.ContainerState{
+						Terminated: &v1.ContainerStateTerminated{ExitCode: 0, Message: `{"rows_updated":1234}`, FinishedAt: meta_v1.NewTime(finishTime)},
+					},
+				},
+			},
+		},
+	})
+	assert.NoError(t, err)
+
+	fakeWatch := watch.NewFake()
+	s.fakeClientSet.PrependWatchReactor("jobs", core.DefaultWatchReactor(fakeWatch, nil))
+
+	go func() {
+		fakeWatch.Add(&batch_api_v1.Job{ObjectMeta: meta_v1.ObjectMeta{Name: s.jobName}})
+		fakeWatch.Modify(s.finishedJob(batch_api_v1.JobComplete, startTime, finishTime))
+	}()
+
+	jobStatus, err := s.testClient.WaitForJobCompletion(context.Background(), Target{}, s.jobName)
+	assert.NoError(t, err)
+
+	assert.True(t, jobStatus.Succeeded)
+	assert.Equal(t, int32(0), *jobStatus.ExitCode)
+	assert.Equal(t, `{"rows_updated":1234}`, jobStatus.Output)
+	assert.Equal(t, 90*time.Second, jobStatus.Duration())
+}
+
+func (s *ClientTestSuite) TestWaitForJobCompletionOnFailure() {
+	t := s.T()
+
+	fakeWatch := watch.NewFake()
+	s.fakeClientSet.PrependWatchReactor("jobs", core.DefaultWatchReactor(fakeWatch, nil))
+
+	go fakeWatch.Modify(s.finishedJob(batch_api_v1.JobFailed, time.Now(), time.Now()))
+
+	jobStatus, err := s.testClient.WaitForJobCompletion(context.Background(), Target{}, s.jobName)
+	assert.NoError(t, err)
+
+	assert.False(t, jobStatus.Succeeded)
+	assert.Nil(t, jobStatus.ExitCode)
+}
+
+func (s *ClientTestSuite) TestWaitForJobCompletionOnContextCancellation() {
+	t := s.T()
+
+	fakeWatch := watch.NewFake()
+	s.fakeClientSet.PrependWatchReactor("jobs", core.DefaultWatchReactor(fakeWatch, nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := s.testClient.WaitForJobCompletion(ctx, Target{}, s.jobName)
+	assert.Equal(t, context.Canceled, err)
+}
+
+func (s *ClientTestSuite) TestWaitForJobCompletionWhenJobIsDeleted() {
+	t := s.T()
+
+	fakeWatch := watch.NewFake()
+	s.fakeClientSet.PrependWatchReactor("jobs", core.DefaultWatchReactor(fakeWatch, nil))
+
+	go fakeWatch.Delete(&batch_api_v1.Job{ObjectMeta: meta_v1.ObjectMeta{Name: s.jobName}})
+
+	_, err := s.testClient.WaitForJobCompletion(context.Background(), Target{}, s.jobName)
+	assert.Equal(t, ErrJobNotFound, err)
+}
+
+func (s *ClientTestSuite) TestWaitForJobCompletionWhenWatchClosesAfterJobIsGone() {
+	t := s.T()
+
+	fakeWatch := watch.NewFake()
+	s.fakeClientSet.PrependWatchReactor("jobs", core.DefaultWatchReactor(fakeWatch, nil))
+
+	go fakeWatch.Stop()
+
+	_, err := s.testClient.WaitForJobCompletion(context.Background(), Target{}, s.jobName)
+	assert.Equal(t, ErrJobNotFound, err)
+}
+
+func (s *ClientTestSuite) TestWaitForJobCompletionWhenWatchClosesAfterJobFinished() {
+	t := s.T()
+
+	_, err := s.fakeClientSet.BatchV1().Jobs(config.DefaultNamespace()).Create(s.finishedJob(batch_api_v1.JobFailed, time.Now(), time.Now()))
+	assert.NoError(t, err)
+
+	fakeWatch := watch.NewFake()
+	s.fakeClientSet.PrependWatchReactor("jobs", core.DefaultWatchReactor(fakeWatch, nil))
+
+	go fakeWatch.Stop()
+
+	jobStatus, err := s.testClient.WaitForJobCompletion(context.Background(), Target{}, s.jobName)
+	assert.NoError(t, err)
+	assert.False(t, jobStatus.Succeeded)
+}
+
+func (s *ClientTestSuite) TestJobLogsTail() {
+	t := s.T()
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://"+config.KubeClusterHostName()+"/api/v1/namespaces/"+config.DefaultNamespace()+"/pods/"+s.podName+"/log?tailLines=2",
+		httpmock.NewStringResponder(200, "second last line\nlast line\n"))
+
+	lines, err := s.testClientStreaming.JobLogsTail(context.Background(), Target{}, s.jobName, 2)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"second last line", "last line"}, lines)
+}
+
+func (s *ClientTestSuite) TestJobLogsTailWithoutPods() {
+	t := s.T()
+
+	_, err := s.testClientStreaming.JobLogsTail(context.Background(), Target{}, "unknown-job", 2)
+	assert.Error(t, err)
+}
+
+func (s *ClientTestSuite) allowAccessReviews(allowed func(*authorization_v1.ResourceAttributes) bool) {
+	s.fakeClientSet.PrependReactor("create", "selfsubjectaccessreviews", func(action core.Action) (bool, runtime.Object, error) {
+		accessReview := action.(core.CreateAction).GetObject().(*authorization_v1.SelfSubjectAccessReview)
+		accessReview.Status.Allowed = allowed(accessReview.Spec.ResourceAttributes)
+		return true, accessReview, nil
+	})
+}
+
+func (s *ClientTestSuite) TestCheckNamespaceAccess() {
+	t := s.T()
+
+	s.allowAccessReviews(func(*authorization_v1.ResourceAttributes) bool {
+		return true
+	})
+
+	err := s.testClient.CheckNamespaceAccess()
+	assert.NoError(t, err)
+}
+
+func (s *ClientTestSuite) TestCheckNamespaceAccessWhenForbidden() {
+	t := s.T()
+
+	s.allowAccessReviews(func(resourceAttributes *authorization_v1.ResourceAttributes) bool {
+		return resourceAttributes.Resource != "pods"
+	})
+
+	err := s.testClient.CheckNamespaceAccess()
+	assert.EqualError(t, err, "Not allowed to list pods in namespace "+config.DefaultNamespace())
+}
+
+func (s *ClientTestSuite) TestListFinishedJobs() {
+	t := s.T()
+
+	startTime := time.Date(2018, 3, 3, 10, 0, 0, 0, time.UTC)
+	finishTime := startTime.Add(90 * time.Second)
+
+	succeededJob := s.finishedJob(batch_api_v1.JobComplete, startTime, finishTime)
+	succeededJob.Name = "proctor-succeeded"
+	failedJob := s.finishedJob(batch_api_v1.JobFailed, startTime, finishTime)
+	failedJob.Name = "proctor-failed"
+	runningJob := &batch_api_v1.Job{ObjectMeta: meta_v1.ObjectMeta{Name: "proctor-running", Labels: jobLabel("proctor-running")}}
+	foreignJob := s.finishedJob(batch_api_v1.JobComplete, startTime, finishTime)
+	foreignJob.Name = "not-proctor"
+
+	for _, job := range []*batch_api_v1.Job{succeededJob, failedJob, runningJob, foreignJob} {
+		_, err := s.fakeClientSet.BatchV1().Jobs(config.DefaultNamespace()).Create(job)
+		assert.NoError(t, err)
+	}
+
+	finishedJobs, err := s.testClient.ListFinishedJobs()
+	assert.NoError(t, err)
+
+	statusByName := map[string]bool{}
+	for _, finishedJob := range finishedJobs {
+		statusByName[finishedJob.Name] = finishedJob.Succeeded
+		assert.Equal(t, finishTime, finishedJob.CompletionTime.UTC())
+		assert.Equal(t, Target{Cluster: DefaultCluster, Namespace: config.DefaultNamespace()}, finishedJob.Target)
+	}
+	assert.Equal(t, map[string]bool{"proctor-succeeded": true, "proctor-failed": false}, statusByName)
+}
+
+func (s *ClientTestSuite) TestListActiveSlots() {
+	t := s.T()
+
+	startTime := time.Date(2018, 3, 3, 10, 0, 0, 0, time.UTC)
+
+	runningJob := &batch_api_v1.Job{ObjectMeta: meta_v1.ObjectMeta{Name: "proctor-running", Labels: slotLabel("proctor-running", Slot{JobName: "vacuum", ID: "running-slot"})}}
+	finishedJob := s.finishedJob(batch_api_v1.JobComplete, startTime, startTime.Add(time.Minute))
+	finishedJob.Name = "proctor-finished"
+	finishedJob.Labels = slotLabel("proctor-finished", Slot{JobName: "vacuum", ID: "finished-slot"})
+	unlimitedJob := &batch_api_v1.Job{ObjectMeta: meta_v1.ObjectMeta{Name: "proctor-unlimited", Labels: jobLabel("proctor-unlimited")}}
+
+	for _, job := range []*batch_api_v1.Job{runningJob, finishedJob, unlimitedJob} {
+		_, err := s.fakeClientSet.BatchV1().Jobs(config.DefaultNamespace()).Create(job)
+		assert.NoError(t, err)
+	}
+
+	slots, err := s.testClient.ListActiveSlots()
+	assert.NoError(t, err)
+	assert.Equal(t, []Slot{{JobName: "vacuum", ID: "running-slot"}}, slots)
+}
+
+func (s *ClientTestSuite) TestDeleteJob() {
+	t := s.T()
+
+	_, err := s.fakeClientSet.BatchV1().Jobs(config.DefaultNamespace()).Create(&batch_api_v1.Job{ObjectMeta: meta_v1.ObjectMeta{Name: "proctor-ipsum-lorem"}})
+	assert.NoError(t, err)
+
+	err = s.testClient.DeleteJob(Target{}, "proctor-ipsum-lorem")
+	assert.NoError(t, err)
+
+	deleteAction := s.fakeClientSet.Actions()[len(s.fakeClientSet.Actions())-1].(core.DeleteAction)
+	assert.Equal(t, "proctor-ipsum-lorem", deleteAction.GetName())
+
+	_, err = s.fakeClientSet.BatchV1().Jobs(config.DefaultNamespace()).Get("proctor-ipsum-lorem", meta_v1.GetOptions{})
+	assert.Error(t, err)
+}
+
+func TestSupportsTTLAfterFinished(t *testing.T) {
+	assert.True(t, supportsTTLAfterFinished(&version.Info{Major: "1", Minor: "12"}))
+	assert.True(t, supportsTTLAfterFinished(&version.Info{Major: "1", Minor: "14+"}))
+	assert.False(t, supportsTTLAfterFinished(&version.Info{Major: "1", Minor: "9"}))
+	assert.False(t, supportsTTLAfterFinished(&version.Info{}))
+}
+
 func TestClientTestSuite(t *testing.T) {
 	suite.Run(t, new(ClientTestSuite))
 }
@@ -0,0 +1,176 @@
+package kubernetes
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/gojektech/proctor-engine/config"
+	"github.com/gojektech/proctor-engine/utility"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const DefaultCluster = "default"
+
+type Target struct {
+	Cluster   string `json:"cluster,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+func (target Target) Override(override Target) Target {
+	if override.Cluster != "" {
+		target.Cluster = override.Cluster
+		target.Namespace = ""
+	}
+	if override.Namespace != "" {
+		target.Namespace = override.Namespace
+	}
+	return target
+}
+
+type ClusterConfig struct {
+	Name       string   `json:"name"`
+	KubeConfig string   `json:"kubeconfig"`
+	Context    string   `json:"context,omitempty"`
+	HostName   string   `json:"host_name,omitempty"`
+	Namespace  string   `json:"namespace"`
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+func (clusterConfig ClusterConfig) allows(namespace string) bool {
+	if namespace == clusterConfig.Namespace {
+		return true
+	}
+	for _, allowed := range clusterConfig.Namespaces {
+		if namespace == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+func (clusterConfig ClusterConfig) namespaces() []string {
+	namespaces := []string{clusterConfig.Namespace}
+	for _, namespace := range clusterConfig.Namespaces {
+		if namespace != clusterConfig.Namespace {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	return namespaces
+}
+
+func defaultClusterConfig() ClusterConfig {
+	clusterConfig := ClusterConfig{
+		Name:      DefaultCluster,
+		HostName:  config.KubeClusterHostName(),
+		Namespace: config.DefaultNamespace(),
+	}
+	for _, namespace := range strings.Split(config.KubeAllowedNamespaces(), ",") {
+		if namespace = strings.TrimSpace(namespace); namespace != "" {
+			clusterConfig.Namespaces = append(clusterConfig.Namespaces, namespace)
+		}
+	}
+	return clusterConfig
+}
+
+func ClusterConfigs() ([]ClusterConfig, error) {
+	clusterConfigs := []ClusterConfig{defaultClusterConfig()}
+
+	rawClusters := config.KubeClusters()
+	if rawClusters == "" {
+		return clusterConfigs, nil
+	}
+
+	var namedClusters []ClusterConfig
+	if err := json.Unmarshal([]byte(rawClusters), &namedClusters); err != nil {
+		return nil, fmt.Errorf("parsing kubernetes clusters: %s", err)
+	}
+
+	seen := map[string]bool{DefaultCluster: true}
+	for _, clusterConfig := range namedClusters {
+		if clusterConfig.Name == "" || seen[clusterConfig.Name] {
+			return nil, fmt.Errorf("kubernetes cluster names must be unique and non-empty, got %q", clusterConfig.Name)
+		}
+		if clusterConfig.KubeConfig == "" {
+			return nil, fmt.Errorf("kubernetes cluster %s has no kubeconfig", clusterConfig.Name)
+		}
+		if clusterConfig.Namespace == "" {
+			clusterConfig.Namespace = "default"
+		}
+		seen[clusterConfig.Name] = true
+		clusterConfigs = append(clusterConfigs, clusterConfig)
+	}
+	return clusterConfigs, nil
+}
+
+func ValidateTarget(target Target) []utility.FieldError {
+	clusterConfigs, err := ClusterConfigs()
+	if err != nil {
+		return []utility.FieldError{{Field: "cluster", Message: "cannot be checked: " + err.Error()}}
+	}
+
+	clusterName := target.Cluster
+	if clusterName == "" {
+		clusterName = DefaultCluster
+	}
+	for _, clusterConfig := range clusterConfigs {
+		if clusterConfig.Name != clusterName {
+			continue
+		}
+		if target.Namespace != "" && !clusterConfig.allows(target.Namespace) {
+			return []utility.FieldError{{Field: "namespace", Message: fmt.Sprintf("is not allowed in cluster %s", clusterName)}}
+		}
+		return nil
+	}
+	return []utility.FieldError{{Field: "cluster", Message: fmt.Sprintf("%s is not configured", clusterName)}}
+}
+
+type cluster struct {
+	config                    ClusterConfig
+	clientSet                 kubernetes.Interface
+	ttlAfterFinishedDetection sync.Once
+	supportsTTLAfterFinished  bool
+}
+
+func newCluster(clusterConfig ClusterConfig, clientSet kubernetes.Interface) *cluster {
+	return &cluster{
+		config:    clusterConfig,
+		clientSet: clientSet,
+	}
+}
+
+func clusterClientSet(clusterConfig ClusterConfig) (kubernetes.Interface, error) {
+	restConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		&clientcmd.ClientConfigLoadingRules{ExplicitPath: clusterConfig.KubeConfig},
+		&clientcmd.ConfigOverrides{CurrentContext: clusterConfig.Context},
+	).ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	return kubernetes.NewForConfig(restConfig)
+}
+
+func (client *client) resolve(target Target) (*cluster, string, error) {
+	clusterName := target.Cluster
+	if clusterName == "" {
+		clusterName = DefaultCluster
+	}
+
+	for _, cluster := range client.clusters {
+		if cluster.config.Name != clusterName {
+			continue
+		}
+
+		namespace := target.Namespace
+		if namespace == "" {
+			namespace = cluster.config.Namespace
+		}
+		if !cluster.config.allows(namespace) {
+			return nil, "", fmt.Errorf("namespace %s is not allowed in kubernetes cluster %s", namespace, clusterName)
+		}
+		return cluster, namespace, nil
+	}
+	return nil, "", fmt.Errorf("kubernetes cluster %s is not configured", clusterName)
+}
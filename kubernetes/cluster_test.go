@@ -0,0 +1,68 @@
+package kubernetes
+
+import (
+	"os"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClusterConfigs(t *testing.T) {
+	os.Setenv("PROCTOR_DEFAULT_NAMESPACE", "proctor")
+	os.Setenv("PROCTOR_KUBE_ALLOWED_NAMESPACES", "batch, reports")
+	os.Setenv("PROCTOR_KUBE_CLUSTERS", `[{"name":"staging","kubeconfig":"/etc/proctor/staging.kubeconfig","context":"staging","namespaces":["batch"]}]`)
+	viper.AutomaticEnv()
+	defer os.Unsetenv("PROCTOR_DEFAULT_NAMESPACE")
+	defer os.Unsetenv("PROCTOR_KUBE_ALLOWED_NAMESPACES")
+	defer os.Unsetenv("PROCTOR_KUBE_CLUSTERS")
+
+	clusterConfigs, err := ClusterConfigs()
+	assert.NoError(t, err)
+
+	assert.Len(t, clusterConfigs, 2)
+	assert.Equal(t, DefaultCluster, clusterConfigs[0].Name)
+	assert.Equal(t, []string{"proctor", "batch", "reports"}, clusterConfigs[0].namespaces())
+	assert.Equal(t, "staging", clusterConfigs[1].Name)
+	assert.Equal(t, "staging", clusterConfigs[1].Context)
+	assert.Equal(t, []string{"default", "batch"}, clusterConfigs[1].namespaces())
+}
+
+func TestClusterConfigsWithInvalidConfiguration(t *testing.T) {
+	defer os.Unsetenv("PROCTOR_KUBE_CLUSTERS")
+
+	for _, rawClusters := range []string{
+		`not-json`,
+		`[{"name":"default","kubeconfig":"/etc/proctor/kubeconfig"}]`,
+		`[{"name":"staging"}]`,
+	} {
+		os.Setenv("PROCTOR_KUBE_CLUSTERS", rawClusters)
+		viper.AutomaticEnv()
+
+		_, err := ClusterConfigs()
+		assert.Error(t, err, rawClusters)
+	}
+}
+
+func TestValidateTarget(t *testing.T) {
+	os.Setenv("PROCTOR_KUBE_CLUSTERS", `[{"name":"staging","kubeconfig":"/etc/proctor/staging.kubeconfig","namespace":"batch"}]`)
+	viper.AutomaticEnv()
+	defer os.Unsetenv("PROCTOR_KUBE_CLUSTERS")
+
+	assert.Empty(t, ValidateTarget(Target{}))
+	assert.Empty(t, ValidateTarget(Target{Cluster: "staging", Namespace: "batch"}))
+
+	fieldErrors := ValidateTarget(Target{Cluster: "production"})
+	assert.Equal(t, "cluster", fieldErrors[0].Field)
+
+	fieldErrors = ValidateTarget(Target{Cluster: "staging", Namespace: "kube-system"})
+	assert.Equal(t, "namespace", fieldErrors[0].Field)
+}
+
+func TestTargetOverride(t *testing.T) {
+	target := Target{Cluster: "staging", Namespace: "batch"}
+
+	assert.Equal(t, target, target.Override(Target{}))
+	assert.Equal(t, Target{Cluster: "staging", Namespace: "reports"}, target.Override(Target{Namespace: "reports"}))
+	assert.Equal(t, Target{Cluster: "production"}, target.Override(Target{Cluster: "production"}))
+}
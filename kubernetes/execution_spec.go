@@ -0,0 +1,22 @@
+package kubernetes
+
+type ExecutionSpec struct {
+	ImageName    string
+	Target       Target
+	Command      []string
+	Args         []string
+	WorkingDir   string
+	EnvVars      map[string]string
+	BackoffLimit *int32
+	Scheduling   *Scheduling
+	Volumes      []Volume
+	Files        []File
+	Slot         Slot
+}
+
+// Slot is the concurrency slot held by an execution. It is recorded on the
+// kubernetes job so held slots can be reconciled against live jobs.
+type Slot struct {
+	JobName string
+	ID      string
+}
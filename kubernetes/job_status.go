@@ -0,0 +1,82 @@
+package kubernetes
+
+import (
+	"time"
+
+	batch_v1 "k8s.io/api/batch/v1"
+	"k8s.io/api/core/v1"
+)
+
+type JobStatus struct {
+	Name           string
+	Target         Target
+	Succeeded      bool
+	ExitCode       *int32
+	Output         string
+	StartTime      time.Time
+	CompletionTime time.Time
+}
+
+func (jobStatus *JobStatus) Duration() time.Duration {
+	if jobStatus.StartTime.IsZero() || jobStatus.CompletionTime.IsZero() {
+		return 0
+	}
+	return jobStatus.CompletionTime.Sub(jobStatus.StartTime)
+}
+
+func finishedCondition(job *batch_v1.Job) *batch_v1.JobCondition {
+	for i, condition := range job.Status.Conditions {
+		if condition.Status != v1.ConditionTrue {
+			continue
+		}
+
+		if condition.Type == batch_v1.JobComplete || condition.Type == batch_v1.JobFailed {
+			return &job.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+func newJobStatus(job *batch_v1.Job) *JobStatus {
+	condition := finishedCondition(job)
+
+	jobStatus := &JobStatus{
+		Name:           job.Name,
+		Succeeded:      condition.Type == batch_v1.JobComplete,
+		CompletionTime: condition.LastTransitionTime.Time,
+	}
+	if job.Status.StartTime != nil {
+		jobStatus.StartTime = job.Status.StartTime.Time
+	}
+	if job.Status.CompletionTime != nil {
+		jobStatus.CompletionTime = job.Status.CompletionTime.Time
+	}
+	return jobStatus
+}
+
+func latestPod(pods []v1.Pod) *v1.Pod {
+	var latest *v1.Pod
+	for i := range pods {
+		if latest == nil || latest.CreationTimestamp.Time.Before(pods[i].CreationTimestamp.Time) {
+			latest = &pods[i]
+		}
+	}
+	return latest
+}
+
+func lastTerminatedState(pods []v1.Pod) *v1.ContainerStateTerminated {
+	var last *v1.ContainerStateTerminated
+	for _, pod := range pods {
+		for _, containerStatus := range pod.Status.ContainerStatuses {
+			terminated := containerStatus.State.Terminated
+			if terminated == nil {
+				continue
+			}
+
+			if last == nil || last.FinishedAt.Time.Before(terminated.FinishedAt.Time) {
+				last = terminated
+			}
+		}
+	}
+	return last
+}
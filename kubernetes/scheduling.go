@@ -0,0 +1,141 @@
+package kubernetes
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/gojektech/proctor-engine/config"
+	"k8s.io/api/core/v1"
+)
+
+type Scheduling struct {
+	NodeSelector           map[string]string `json:"node_selector,omitempty"`
+	Tolerations            []v1.Toleration   `json:"tolerations,omitempty"`
+	Affinity               *v1.Affinity      `json:"affinity,omitempty"`
+	ServiceAccountName     string            `json:"service_account_name,omitempty"`
+	ImagePullSecrets       []string          `json:"image_pull_secrets,omitempty"`
+	PriorityClassName      string            `json:"priority_class_name,omitempty"`
+	RunAsNonRoot           *bool             `json:"run_as_non_root,omitempty"`
+	ReadOnlyRootFilesystem *bool             `json:"read_only_root_filesystem,omitempty"`
+}
+
+func DefaultScheduling() (Scheduling, error) {
+	scheduling := Scheduling{
+		ServiceAccountName: config.JobServiceAccountName(),
+		ImagePullSecrets:   splitList(config.JobImagePullSecrets()),
+		PriorityClassName:  config.JobPriorityClassName(),
+	}
+
+	nodeSelector, err := parseNodeSelector(config.JobNodeSelector())
+	if err != nil {
+		return Scheduling{}, err
+	}
+	scheduling.NodeSelector = nodeSelector
+
+	if tolerations := config.JobTolerations(); tolerations != "" {
+		if err := json.Unmarshal([]byte(tolerations), &scheduling.Tolerations); err != nil {
+			return Scheduling{}, fmt.Errorf("invalid job tolerations: %s", err)
+		}
+	}
+	if affinity := config.JobAffinity(); affinity != "" {
+		if err := json.Unmarshal([]byte(affinity), &scheduling.Affinity); err != nil {
+			return Scheduling{}, fmt.Errorf("invalid job affinity: %s", err)
+		}
+	}
+
+	if config.JobRunAsNonRoot() {
+		runAsNonRoot := true
+		scheduling.RunAsNonRoot = &runAsNonRoot
+	}
+	if config.JobReadOnlyRootFilesystem() {
+		readOnlyRootFilesystem := true
+		scheduling.ReadOnlyRootFilesystem = &readOnlyRootFilesystem
+	}
+	return scheduling, nil
+}
+
+func splitList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func parseNodeSelector(nodeSelector string) (map[string]string, error) {
+	labels := map[string]string{}
+	for _, label := range splitList(nodeSelector) {
+		parts := strings.SplitN(label, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid job node selector label: %s", label)
+		}
+		labels[parts[0]] = parts[1]
+	}
+	if len(labels) == 0 {
+		return nil, nil
+	}
+	return labels, nil
+}
+
+func (scheduling Scheduling) Merge(override *Scheduling) Scheduling {
+	if override == nil {
+		return scheduling
+	}
+
+	merged := scheduling
+	if len(override.NodeSelector) > 0 {
+		merged.NodeSelector = map[string]string{}
+		for key, value := range scheduling.NodeSelector {
+			merged.NodeSelector[key] = value
+		}
+		for key, value := range override.NodeSelector {
+			merged.NodeSelector[key] = value
+		}
+	}
+	if len(override.Tolerations) > 0 {
+		merged.Tolerations = override.Tolerations
+	}
+	if override.Affinity != nil {
+		merged.Affinity = override.Affinity
+	}
+	if override.ServiceAccountName != "" {
+		merged.ServiceAccountName = override.ServiceAccountName
+	}
+	if len(override.ImagePullSecrets) > 0 {
+		merged.ImagePullSecrets = override.ImagePullSecrets
+	}
+	if override.PriorityClassName != "" {
+		merged.PriorityClassName = override.PriorityClassName
+	}
+	if override.RunAsNonRoot != nil {
+		merged.RunAsNonRoot = override.RunAsNonRoot
+	}
+	if override.ReadOnlyRootFilesystem != nil {
+		merged.ReadOnlyRootFilesystem = override.ReadOnlyRootFilesystem
+	}
+	return merged
+}
+
+func (scheduling Scheduling) apply(podSpec *v1.PodSpec) {
+	podSpec.NodeSelector = scheduling.NodeSelector
+	podSpec.Tolerations = scheduling.Tolerations
+	podSpec.Affinity = scheduling.Affinity
+	podSpec.ServiceAccountName = scheduling.ServiceAccountName
+	podSpec.PriorityClassName = scheduling.PriorityClassName
+
+	for _, secret := range scheduling.ImagePullSecrets {
+		podSpec.ImagePullSecrets = append(podSpec.ImagePullSecrets, v1.LocalObjectReference{Name: secret})
+	}
+
+	if scheduling.RunAsNonRoot != nil || scheduling.ReadOnlyRootFilesystem != nil {
+		for i := range podSpec.Containers {
+			podSpec.Containers[i].SecurityContext = &v1.SecurityContext{
+				RunAsNonRoot:           scheduling.RunAsNonRoot,
+				ReadOnlyRootFilesystem: scheduling.ReadOnlyRootFilesystem,
+			}
+		}
+	}
+}
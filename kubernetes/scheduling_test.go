@@ -0,0 +1,63 @@
+package kubernetes
+
+import (
+	"os"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/api/core/v1"
+)
+
+func TestDefaultScheduling(t *testing.T) {
+	os.Setenv("PROCTOR_JOB_NODE_SELECTOR", "pool=batch, zone=a")
+	os.Setenv("PROCTOR_JOB_TOLERATIONS", `[{"key":"dedicated","operator":"Equal","value":"batch","effect":"NoSchedule"}]`)
+	os.Setenv("PROCTOR_JOB_IMAGE_PULL_SECRETS", "registry-a,registry-b")
+	os.Setenv("PROCTOR_JOB_READ_ONLY_ROOT_FILESYSTEM", "true")
+	viper.AutomaticEnv()
+	defer os.Unsetenv("PROCTOR_JOB_NODE_SELECTOR")
+	defer os.Unsetenv("PROCTOR_JOB_TOLERATIONS")
+	defer os.Unsetenv("PROCTOR_JOB_IMAGE_PULL_SECRETS")
+	defer os.Unsetenv("PROCTOR_JOB_READ_ONLY_ROOT_FILESYSTEM")
+
+	scheduling, err := DefaultScheduling()
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"pool": "batch", "zone": "a"}, scheduling.NodeSelector)
+	assert.Equal(t, []v1.Toleration{{Key: "dedicated", Operator: v1.TolerationOpEqual, Value: "batch", Effect: v1.TaintEffectNoSchedule}}, scheduling.Tolerations)
+	assert.Equal(t, []string{"registry-a", "registry-b"}, scheduling.ImagePullSecrets)
+	assert.True(t, *scheduling.ReadOnlyRootFilesystem)
+	assert.Nil(t, scheduling.RunAsNonRoot)
+}
+
+func TestDefaultSchedulingWithInvalidConfiguration(t *testing.T) {
+	os.Setenv("PROCTOR_JOB_NODE_SELECTOR", "pool")
+	viper.AutomaticEnv()
+	defer os.Unsetenv("PROCTOR_JOB_NODE_SELECTOR")
+
+	_, err := DefaultScheduling()
+	assert.Error(t, err)
+}
+
+func TestSchedulingMerge(t *testing.T) {
+	runAsNonRoot := false
+	defaults := Scheduling{
+		NodeSelector:       map[string]string{"pool": "batch"},
+		ServiceAccountName: "proctor-jobs",
+		ImagePullSecrets:   []string{"registry"},
+	}
+	override := &Scheduling{
+		NodeSelector:       map[string]string{"pool": "gpu", "zone": "a"},
+		ServiceAccountName: "db-admin",
+		RunAsNonRoot:       &runAsNonRoot,
+	}
+
+	merged := defaults.Merge(override)
+
+	assert.Equal(t, map[string]string{"pool": "gpu", "zone": "a"}, merged.NodeSelector)
+	assert.Equal(t, "db-admin", merged.ServiceAccountName)
+	assert.Equal(t, []string{"registry"}, merged.ImagePullSecrets)
+	assert.False(t, *merged.RunAsNonRoot)
+	assert.Equal(t, map[string]string{"pool": "batch"}, defaults.NodeSelector)
+	assert.Equal(t, defaults, defaults.Merge(nil))
+}
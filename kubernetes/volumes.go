@@ -0,0 +1,188 @@
+package kubernetes
+
+import (
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/gojektech/proctor-engine/metrics"
+	"github.com/gojektech/proctor-engine/utility"
+	batch_v1 "k8s.io/api/batch/v1"
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	filesVolumeName       = "files"
+	secretFilesVolumeName = "secret-files"
+	volumeNamePrefix      = "volume-"
+)
+
+type EmptyDirVolume struct {
+	Medium    string `json:"medium,omitempty"`
+	SizeLimit string `json:"size_limit,omitempty"`
+}
+
+type PersistentVolumeClaimVolume struct {
+	ClaimName string `json:"claim_name"`
+}
+
+type Volume struct {
+	Name                  string                       `json:"name"`
+	MountPath             string                       `json:"mount_path"`
+	ReadOnly              bool                         `json:"read_only,omitempty"`
+	EmptyDir              *EmptyDirVolume              `json:"empty_dir,omitempty"`
+	PersistentVolumeClaim *PersistentVolumeClaimVolume `json:"persistent_volume_claim,omitempty"`
+}
+
+type File struct {
+	Name      string
+	MountPath string
+	Content   []byte
+	Sensitive bool
+}
+
+func (volume Volume) Validate() []utility.FieldError {
+	var fieldErrors []utility.FieldError
+	if volume.Name == "" {
+		fieldErrors = append(fieldErrors, utility.FieldError{Field: "name", Message: "is required"})
+	}
+	if !path.IsAbs(volume.MountPath) {
+		fieldErrors = append(fieldErrors, utility.FieldError{Field: "mount_path", Message: "must be an absolute path"})
+	}
+
+	switch {
+	case volume.EmptyDir != nil && volume.PersistentVolumeClaim != nil, volume.EmptyDir == nil && volume.PersistentVolumeClaim == nil:
+		fieldErrors = append(fieldErrors, utility.FieldError{Field: "empty_dir", Message: "exactly one of empty_dir, persistent_volume_claim is required"})
+	case volume.EmptyDir != nil:
+		if volume.EmptyDir.Medium != "" && volume.EmptyDir.Medium != string(v1.StorageMediumMemory) {
+			fieldErrors = append(fieldErrors, utility.FieldError{Field: "empty_dir.medium", Message: "must be empty or Memory"})
+		}
+		if volume.EmptyDir.SizeLimit != "" {
+			if _, err := resource.ParseQuantity(volume.EmptyDir.SizeLimit); err != nil {
+				fieldErrors = append(fieldErrors, utility.FieldError{Field: "empty_dir.size_limit", Message: "must be a quantity such as 1Gi"})
+			}
+		}
+	case volume.PersistentVolumeClaim.ClaimName == "":
+		fieldErrors = append(fieldErrors, utility.FieldError{Field: "persistent_volume_claim.claim_name", Message: "is required"})
+	}
+	return fieldErrors
+}
+
+func (volume Volume) podVolume() v1.Volume {
+	podVolume := v1.Volume{Name: volumeNamePrefix + volume.Name}
+	if volume.EmptyDir != nil {
+		emptyDir := &v1.EmptyDirVolumeSource{Medium: v1.StorageMedium(volume.EmptyDir.Medium)}
+		if sizeLimit, err := resource.ParseQuantity(volume.EmptyDir.SizeLimit); err == nil {
+			emptyDir.SizeLimit = &sizeLimit
+		}
+		podVolume.EmptyDir = emptyDir
+		return podVolume
+	}
+
+	podVolume.PersistentVolumeClaim = &v1.PersistentVolumeClaimVolumeSource{
+		ClaimName: volume.PersistentVolumeClaim.ClaimName,
+		ReadOnly:  volume.ReadOnly,
+	}
+	return podVolume
+}
+
+func filesObjectName(jobName string) string {
+	return jobName + "-files"
+}
+
+func mountVolumes(podSpec *v1.PodSpec, jobName string, volumes []Volume, files []File) {
+	container := &podSpec.Containers[0]
+
+	for _, volume := range volumes {
+		podSpec.Volumes = append(podSpec.Volumes, volume.podVolume())
+		container.VolumeMounts = append(container.VolumeMounts, v1.VolumeMount{
+			Name:      volumeNamePrefix + volume.Name,
+			MountPath: volume.MountPath,
+			ReadOnly:  volume.ReadOnly,
+		})
+	}
+
+	var hasFiles, hasSecretFiles bool
+	for _, file := range files {
+		volumeName := filesVolumeName
+		if file.Sensitive {
+			volumeName = secretFilesVolumeName
+			hasSecretFiles = true
+		} else {
+			hasFiles = true
+		}
+
+		container.VolumeMounts = append(container.VolumeMounts, v1.VolumeMount{
+			Name:      volumeName,
+			MountPath: file.MountPath,
+			SubPath:   file.Name,
+			ReadOnly:  true,
+		})
+	}
+
+	if hasFiles {
+		podSpec.Volumes = append(podSpec.Volumes, v1.Volume{
+			Name: filesVolumeName,
+			VolumeSource: v1.VolumeSource{
+				ConfigMap: &v1.ConfigMapVolumeSource{LocalObjectReference: v1.LocalObjectReference{Name: filesObjectName(jobName)}},
+			},
+		})
+	}
+	if hasSecretFiles {
+		podSpec.Volumes = append(podSpec.Volumes, v1.Volume{
+			Name: secretFilesVolumeName,
+			VolumeSource: v1.VolumeSource{
+				Secret: &v1.SecretVolumeSource{SecretName: filesObjectName(jobName)},
+			},
+		})
+	}
+}
+
+func (cluster *cluster) stageFiles(namespace string, job *batch_v1.Job, files []File) error {
+	if len(files) == 0 {
+		return nil
+	}
+
+	isController := true
+	objectMeta := meta_v1.ObjectMeta{
+		Name:   filesObjectName(job.Name),
+		Labels: jobLabel(job.Name),
+		OwnerReferences: []meta_v1.OwnerReference{{
+			APIVersion: typeMeta.APIVersion,
+			Kind:       typeMeta.Kind,
+			Name:       job.Name,
+			UID:        job.UID,
+			Controller: &isController,
+		}},
+	}
+
+	configMapData := map[string][]byte{}
+	secretData := map[string][]byte{}
+	for _, file := range files {
+		if file.Sensitive {
+			secretData[file.Name] = file.Content
+		} else {
+			configMapData[file.Name] = file.Content
+		}
+	}
+
+	if len(configMapData) > 0 {
+		start := time.Now()
+		_, err := cluster.clientSet.CoreV1().ConfigMaps(namespace).Create(&v1.ConfigMap{ObjectMeta: objectMeta, BinaryData: configMapData})
+		metrics.ObserveKubeAPICall("create_config_map", time.Since(start), err)
+		if err != nil {
+			return fmt.Errorf("staging files in config map: %s", err)
+		}
+	}
+	if len(secretData) > 0 {
+		start := time.Now()
+		_, err := cluster.clientSet.CoreV1().Secrets(namespace).Create(&v1.Secret{ObjectMeta: objectMeta, Data: secretData})
+		metrics.ObserveKubeAPICall("create_secret", time.Since(start), err)
+		if err != nil {
+			return fmt.Errorf("staging files in secret: %s", err)
+		}
+	}
+	return nil
+}
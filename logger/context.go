@@ -0,0 +1,70 @@
+package logger
+
+import (
+	"context"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	RequestIDField     = "request_id"
+	UserField          = "user"
+	JobNameField       = "job_name"
+	ExecutionNameField = "execution_name"
+	WorkflowRunIDField = "workflow_run_id"
+)
+
+type Fields map[string]interface{}
+
+type contextKey struct{}
+
+type Entry struct {
+	entry *log.Entry
+}
+
+func fieldsFrom(ctx context.Context) Fields {
+	fields, ok := ctx.Value(contextKey{}).(Fields)
+	if !ok {
+		return Fields{}
+	}
+	return fields
+}
+
+func WithFields(ctx context.Context, fields Fields) context.Context {
+	mergedFields := Fields{}
+	for k, v := range fieldsFrom(ctx) {
+		mergedFields[k] = v
+	}
+	for k, v := range fields {
+		mergedFields[k] = v
+	}
+	return context.WithValue(ctx, contextKey{}, mergedFields)
+}
+
+func Detach(ctx context.Context) context.Context {
+	return WithFields(context.Background(), fieldsFrom(ctx))
+}
+
+func FromContext(ctx context.Context) *Entry {
+	return &Entry{log.WithFields(log.Fields(fieldsFrom(ctx)))}
+}
+
+func (e *Entry) WithField(key string, value interface{}) *Entry {
+	return &Entry{e.entry.WithField(key, value)}
+}
+
+func (e *Entry) Debug(args ...interface{}) {
+	e.entry.Debug(args...)
+}
+
+func (e *Entry) Info(args ...interface{}) {
+	e.entry.Info(args...)
+}
+
+func (e *Entry) Warn(args ...interface{}) {
+	e.entry.Warn(args...)
+}
+
+func (e *Entry) Error(args ...interface{}) {
+	e.entry.Error(args...)
+}
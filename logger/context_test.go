@@ -0,0 +1,40 @@
+package logger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithFieldsMergesIntoContext(t *testing.T) {
+	ctx := WithFields(context.Background(), Fields{RequestIDField: "request-id"})
+	ctx = WithFields(ctx, Fields{JobNameField: "job1"})
+
+	assert.Equal(t, Fields{RequestIDField: "request-id", JobNameField: "job1"}, fieldsFrom(ctx))
+}
+
+func TestWithFieldsDoesNotModifyParentContext(t *testing.T) {
+	parent := WithFields(context.Background(), Fields{RequestIDField: "request-id"})
+	WithFields(parent, Fields{JobNameField: "job1"})
+
+	assert.Equal(t, Fields{RequestIDField: "request-id"}, fieldsFrom(parent))
+}
+
+func TestDetachKeepsFieldsButNotCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(WithFields(context.Background(), Fields{RequestIDField: "request-id"}))
+	detached := Detach(ctx)
+	cancel()
+
+	assert.NoError(t, detached.Err())
+	assert.Equal(t, Fields{RequestIDField: "request-id"}, fieldsFrom(detached))
+}
+
+func TestFromContext(t *testing.T) {
+	ctx := WithFields(context.Background(), Fields{UserField: "user@example.com"})
+
+	entry := FromContext(ctx).WithField(ExecutionNameField, "proctor-ipsum-lorem")
+
+	assert.Equal(t, "user@example.com", entry.entry.Data[UserField])
+	assert.Equal(t, "proctor-ipsum-lorem", entry.entry.Data[ExecutionNameField])
+}
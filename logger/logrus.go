@@ -12,9 +12,15 @@ func init() {
 	log.SetFormatter(&log.JSONFormatter{})
 	log.SetOutput(os.Stdout)
 
-	logLevel, err := log.ParseLevel(config.LogLevel())
+	SetLevel(config.LogLevel())
+}
+
+// SetLevel falls back to info for an unknown level, which config validation
+// reports at startup.
+func SetLevel(level string) {
+	logLevel, err := log.ParseLevel(level)
 	if err != nil {
-		log.Panic(err)
+		logLevel = log.InfoLevel
 	}
 	log.SetLevel(logLevel)
 }
@@ -1,27 +1,74 @@
 package main
 
 import (
+	"fmt"
 	"os"
 
+	"github.com/gojektech/proctor-engine/command"
+	"github.com/gojektech/proctor-engine/config"
+	"github.com/gojektech/proctor-engine/logger"
 	"github.com/gojektech/proctor-engine/server"
 
 	"github.com/urfave/cli"
 )
 
+var configFlag = cli.StringFlag{
+	Name:  "config, c",
+	Usage: "YAML or TOML config file, PROCTOR_* environment variables take precedence",
+}
+
+func loadConfig(c *cli.Context) error {
+	if path := c.String("config"); path != "" {
+		if err := config.Load(path); err != nil {
+			return err
+		}
+	}
+	logger.SetLevel(config.LogLevel())
+	return nil
+}
+
 func main() {
 	proctor := cli.NewApp()
 	proctor.Name = "Proctor"
 	proctor.Usage = "Handle orchestration of automated tasks"
-	proctor.Commands = []cli.Command{
+	proctor.Commands = append([]cli.Command{
 		{
 			Name:    "start",
 			Aliases: []string{"s"},
 			Usage:   "start server",
+			Flags:   []cli.Flag{configFlag},
 			Action: func(c *cli.Context) error {
-				return server.Start()
+				if err := loadConfig(c); err != nil {
+					return cli.NewExitError(err.Error(), 1)
+				}
+				if err := server.Start(); err != nil {
+					return cli.NewExitError(err.Error(), 1)
+				}
+				return nil
 			},
 		},
-	}
+		{
+			Name:  "config",
+			Usage: "inspect server configuration",
+			Subcommands: []cli.Command{
+				{
+					Name:  "check",
+					Usage: "validate server configuration and report all problems",
+					Flags: []cli.Flag{configFlag},
+					Action: func(c *cli.Context) error {
+						if err := loadConfig(c); err != nil {
+							return cli.NewExitError(err.Error(), 1)
+						}
+						if problems := server.CheckConfig(); len(problems) > 0 {
+							return cli.NewExitError(problems.Error(), 1)
+						}
+						fmt.Fprintln(c.App.Writer, "configuration is valid")
+						return nil
+					},
+				},
+			},
+		},
+	}, command.Commands()...)
 
 	proctor.Run(os.Args)
 }
@@ -0,0 +1,123 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "proctor_http_request_duration_seconds",
+		Help: "Duration of HTTP requests served by the engine",
+	}, []string{"route", "method", "status"})
+
+	executionsStarted = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "proctor_job_executions_started_total",
+		Help: "Number of job executions started on kubernetes",
+	}, []string{"job"})
+
+	executionsSucceeded = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "proctor_job_executions_succeeded_total",
+		Help: "Number of job executions which completed successfully",
+	}, []string{"job"})
+
+	executionsFailed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "proctor_job_executions_failed_total",
+		Help: "Number of job executions which failed to start or completed unsuccessfully",
+	}, []string{"job"})
+
+	jobsReaped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "proctor_reaped_jobs_total",
+		Help: "Number of finished kubernetes jobs deleted by the reaper",
+	}, []string{"status"})
+
+	activeLogStreams = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "proctor_active_log_streams",
+		Help: "Number of job log streams currently open",
+	})
+
+	redisOperationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "proctor_redis_operation_duration_seconds",
+		Help: "Duration of redis operations",
+	}, []string{"operation"})
+
+	redisOperationErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "proctor_redis_operation_errors_total",
+		Help: "Number of failed redis operations",
+	}, []string{"operation"})
+
+	kubeAPICallDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "proctor_kubernetes_api_call_duration_seconds",
+		Help: "Duration of kubernetes API calls",
+	}, []string{"operation"})
+
+	kubeAPICallErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "proctor_kubernetes_api_call_errors_total",
+		Help: "Number of failed kubernetes API calls",
+	}, []string{"operation"})
+)
+
+func init() {
+	prometheus.MustRegister(
+		httpRequestDuration,
+		executionsStarted,
+		executionsSucceeded,
+		executionsFailed,
+		jobsReaped,
+		activeLogStreams,
+		redisOperationDuration,
+		redisOperationErrors,
+		kubeAPICallDuration,
+		kubeAPICallErrors,
+	)
+}
+
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+func ObserveHTTPRequest(route, method string, status int, duration time.Duration) {
+	httpRequestDuration.WithLabelValues(route, method, strconv.Itoa(status)).Observe(duration.Seconds())
+}
+
+func ExecutionStarted(jobName string) {
+	executionsStarted.WithLabelValues(jobName).Inc()
+}
+
+func ExecutionSucceeded(jobName string) {
+	executionsSucceeded.WithLabelValues(jobName).Inc()
+}
+
+func ExecutionFailed(jobName string) {
+	executionsFailed.WithLabelValues(jobName).Inc()
+}
+
+func JobReaped(status string) {
+	jobsReaped.WithLabelValues(status).Inc()
+}
+
+func LogStreamOpened() {
+	activeLogStreams.Inc()
+}
+
+func LogStreamClosed() {
+	activeLogStreams.Dec()
+}
+
+func ObserveRedisOperation(operation string, duration time.Duration, err error) {
+	redisOperationDuration.WithLabelValues(operation).Observe(duration.Seconds())
+	if err != nil {
+		redisOperationErrors.WithLabelValues(operation).Inc()
+	}
+}
+
+func ObserveKubeAPICall(operation string, duration time.Duration, err error) {
+	kubeAPICallDuration.WithLabelValues(operation).Observe(duration.Seconds())
+	if err != nil {
+		kubeAPICallErrors.WithLabelValues(operation).Inc()
+	}
+}
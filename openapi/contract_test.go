@@ -0,0 +1,159 @@
+package openapi
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const serverPackage = "../server"
+
+var refPattern = regexp.MustCompile(`"\$ref": "([^"]+)"`)
+
+type routerSource struct {
+	constants map[string]string
+	prefixes  map[string]string
+}
+
+func (source *routerSource) stringValue(expr ast.Expr) (string, bool) {
+	switch value := expr.(type) {
+	case *ast.BasicLit:
+		unquoted, err := strconv.Unquote(value.Value)
+		return unquoted, err == nil && value.Kind == token.STRING
+	case *ast.Ident:
+		constant, ok := source.constants[value.Name]
+		return constant, ok
+	}
+	return "", false
+}
+
+func (source *routerSource) routeRegistration(call *ast.CallExpr) (string, bool) {
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || (selector.Sel.Name != "HandleFunc" && selector.Sel.Name != "Handle") || len(call.Args) == 0 {
+		return "", false
+	}
+	path, ok := source.stringValue(call.Args[0])
+	if !ok {
+		return "", false
+	}
+	if receiver, ok := selector.X.(*ast.Ident); ok {
+		path = source.prefixes[receiver.Name] + path
+	}
+	return path, true
+}
+
+func (source *routerSource) subrouterPrefix(expr ast.Expr) (string, bool) {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return "", false
+	}
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || selector.Sel.Name != "Subrouter" {
+		return "", false
+	}
+	pathPrefix, ok := selector.X.(*ast.CallExpr)
+	if !ok || len(pathPrefix.Args) != 1 {
+		return "", false
+	}
+	if selector, ok := pathPrefix.Fun.(*ast.SelectorExpr); !ok || selector.Sel.Name != "PathPrefix" {
+		return "", false
+	}
+	return source.stringValue(pathPrefix.Args[0])
+}
+
+func routerOperations(t *testing.T) []string {
+	packages, err := parser.ParseDir(token.NewFileSet(), serverPackage, func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, 0)
+	if !assert.NoError(t, err) {
+		return nil
+	}
+
+	source := &routerSource{constants: map[string]string{}, prefixes: map[string]string{}}
+	for _, file := range packages["server"].Files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			if spec, ok := node.(*ast.ValueSpec); ok {
+				for i, name := range spec.Names {
+					if i < len(spec.Values) {
+						if value, ok := source.stringValue(spec.Values[i]); ok {
+							source.constants[name.Name] = value
+						}
+					}
+				}
+			}
+			return true
+		})
+	}
+
+	var operations []string
+	registered := map[*ast.CallExpr]bool{}
+	for _, file := range packages["server"].Files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			switch node := node.(type) {
+			case *ast.AssignStmt:
+				if len(node.Lhs) == 1 && len(node.Rhs) == 1 {
+					if prefix, ok := source.subrouterPrefix(node.Rhs[0]); ok {
+						source.prefixes[node.Lhs[0].(*ast.Ident).Name] = prefix
+					}
+				}
+			case *ast.CallExpr:
+				if selector, ok := node.Fun.(*ast.SelectorExpr); ok && selector.Sel.Name == "Methods" {
+					if inner, ok := selector.X.(*ast.CallExpr); ok {
+						if path, ok := source.routeRegistration(inner); ok {
+							registered[inner] = true
+							for _, arg := range node.Args {
+								method, _ := source.stringValue(arg)
+								operations = append(operations, strings.ToLower(method)+" "+path)
+							}
+						}
+					}
+					return true
+				}
+				if path, ok := source.routeRegistration(node); ok && !registered[node] {
+					operations = append(operations, "get "+path)
+				}
+			}
+			return true
+		})
+	}
+
+	sort.Strings(operations)
+	return operations
+}
+
+func specOperations() []string {
+	var operations []string
+	for path, pathItem := range Load().Paths {
+		for method := range pathItem {
+			operations = append(operations, method+" "+path)
+		}
+	}
+
+	sort.Strings(operations)
+	return operations
+}
+
+func TestSpecMatchesRouter(t *testing.T) {
+	routes := routerOperations(t)
+	assert.NotEmpty(t, routes)
+
+	assert.Equal(t, routes, specOperations(), "server/router.go and the OpenAPI document have drifted")
+}
+
+func TestSpecReferencesResolve(t *testing.T) {
+	for _, ref := range refPattern.FindAllStringSubmatch(Spec, -1) {
+		name := ref[1]
+		if strings.HasPrefix(name, schemaRefPrefix) {
+			_, ok := Load().Components.Schemas[strings.TrimPrefix(name, schemaRefPrefix)]
+			assert.True(t, ok, "unresolved reference "+name)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/gojektech/proctor-engine/utility"
+)
+
+const jsonMediaType = "application/json"
+
+var (
+	ErrMalformedBody        = errors.New("request body is not valid json")
+	ErrBodyTooLarge         = errors.New("request body is too large")
+	ErrUnsupportedMediaType = errors.New("request body media type is not supported")
+)
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Operation struct {
+	Summary     string       `json:"summary"`
+	RequestBody *RequestBody `json:"requestBody"`
+}
+
+type Document struct {
+	Paths      map[string]map[string]Operation `json:"paths"`
+	Components struct {
+		Schemas map[string]*Schema `json:"schemas"`
+	} `json:"components"`
+}
+
+var document = mustLoad()
+
+func mustLoad() *Document {
+	var document Document
+	if err := json.Unmarshal([]byte(Spec), &document); err != nil {
+		panic(err.Error())
+	}
+	return &document
+}
+
+func Load() *Document {
+	return document
+}
+
+func Handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", jsonMediaType)
+		w.Write([]byte(Spec))
+	}
+}
+
+func (document *Document) Operation(path string, method string) (Operation, bool) {
+	operation, ok := document.Paths[path][strings.ToLower(method)]
+	return operation, ok
+}
+
+func (document *Document) ValidateRequest(path string, req *http.Request, maxBytes int64) ([]utility.FieldError, error) {
+	operation, ok := document.Operation(path, req.Method)
+	if !ok || operation.RequestBody == nil {
+		return nil, nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if mediaType == "" {
+		mediaType = jsonMediaType
+	}
+	content, ok := operation.RequestBody.Content[mediaType]
+	if !ok {
+		return nil, ErrUnsupportedMediaType
+	}
+	if mediaType != jsonMediaType {
+		return nil, nil
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(req.Body, maxBytes+1))
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > maxBytes {
+		return nil, ErrBodyTooLarge
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	if len(bytes.TrimSpace(body)) == 0 {
+		if operation.RequestBody.Required {
+			return []utility.FieldError{{Field: "body", Message: "is required"}}, nil
+		}
+		return nil, nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return nil, ErrMalformedBody
+	}
+	return document.validate(content.Schema, value, ""), nil
+}
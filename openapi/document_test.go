@@ -0,0 +1,107 @@
+package openapi
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gojektech/proctor-engine/utility"
+	"github.com/stretchr/testify/assert"
+)
+
+func validateRequest(t *testing.T, method string, path string, contentType string, body string) ([]utility.FieldError, error) {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	return Load().ValidateRequest(path, req, 1024)
+}
+
+func TestValidateRequestWithValidBody(t *testing.T) {
+	fieldErrors, err := validateRequest(t, "POST", "/jobs/execute", "application/json", `{"name":"vacuum","args":{"TABLE":"orders"}}`)
+	assert.NoError(t, err)
+	assert.Empty(t, fieldErrors)
+}
+
+func TestValidateRequestWithInvalidBody(t *testing.T) {
+	fieldErrors, err := validateRequest(t, "POST", "/jobs/metadata", "", `[{"name":"vacuum","required_approvals":-1,"retry":{"strategy":"always"},"env_vars":{"args":"TABLE"}}]`)
+	assert.NoError(t, err)
+	assert.Equal(t, []utility.FieldError{
+		{Field: "[0].image_name", Message: "is required"},
+		{Field: "[0].env_vars.args", Message: "must be an array"},
+		{Field: "[0].required_approvals", Message: "must be at least 0"},
+		{Field: "[0].retry.strategy", Message: "must be one of none, kubernetes, engine"},
+	}, fieldErrors)
+}
+
+func TestValidateRequestForVersionedRoute(t *testing.T) {
+	fieldErrors, err := validateRequest(t, "POST", "/api/v1/jobs/{name}/executions", "application/json", `{"args":{"TABLE":1}}`)
+	assert.NoError(t, err)
+	assert.Equal(t, []utility.FieldError{{Field: "args.TABLE", Message: "must be a string"}}, fieldErrors)
+}
+
+func TestValidateRequestWithWrongRootType(t *testing.T) {
+	fieldErrors, err := validateRequest(t, "POST", "/jobs/secrets", "application/json", `["vacuum"]`)
+	assert.NoError(t, err)
+	assert.Equal(t, []utility.FieldError{{Field: "body", Message: "must be an object"}}, fieldErrors)
+}
+
+func TestValidateRequestWithMapValues(t *testing.T) {
+	fieldErrors, err := validateRequest(t, "POST", "/jobs/secrets", "application/json", `{"job_name":"vacuum","secrets":{"PASSWORD":42}}`)
+	assert.NoError(t, err)
+	assert.Equal(t, []utility.FieldError{{Field: "secrets.PASSWORD", Message: "must be a string"}}, fieldErrors)
+}
+
+func TestValidateRequestWithMalformedBody(t *testing.T) {
+	_, err := validateRequest(t, "POST", "/jobs/execute", "application/json", `{"name":`)
+	assert.Equal(t, ErrMalformedBody, err)
+}
+
+func TestValidateRequestWithBodyOverLimit(t *testing.T) {
+	_, err := validateRequest(t, "POST", "/jobs/execute", "application/json", `{"name":"`+strings.Repeat("a", 1024)+`"}`)
+	assert.Equal(t, ErrBodyTooLarge, err)
+}
+
+func TestValidateRequestWithUnsupportedMediaType(t *testing.T) {
+	_, err := validateRequest(t, "POST", "/jobs/secrets", "text/plain", `{"job_name":"vacuum","secrets":{"PASSWORD":42}}`)
+	assert.Equal(t, ErrUnsupportedMediaType, err)
+}
+
+func TestValidateRequestWithOptionalBody(t *testing.T) {
+	fieldErrors, err := validateRequest(t, "POST", "/api/v1/workflows/{name}/runs", "", "")
+	assert.NoError(t, err)
+	assert.Empty(t, fieldErrors)
+
+	fieldErrors, err = validateRequest(t, "POST", "/jobs/secrets", "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, []utility.FieldError{{Field: "body", Message: "is required"}}, fieldErrors)
+}
+
+func TestValidateRequestSkipsMultipartAndUndocumentedRoutes(t *testing.T) {
+	fieldErrors, err := validateRequest(t, "POST", "/jobs/execute", "multipart/form-data; boundary=x", "--x--")
+	assert.NoError(t, err)
+	assert.Empty(t, fieldErrors)
+
+	fieldErrors, err = validateRequest(t, "POST", "/unknown", "application/json", `{`)
+	assert.NoError(t, err)
+	assert.Empty(t, fieldErrors)
+}
+
+func TestValidateRequestKeepsBodyReadable(t *testing.T) {
+	req := httptest.NewRequest("POST", "/jobs/execute", strings.NewReader(`{"name":"vacuum"}`))
+	_, err := Load().ValidateRequest("/jobs/execute", req, 1024)
+	assert.NoError(t, err)
+
+	body := make([]byte, 64)
+	n, _ := req.Body.Read(body)
+	assert.Equal(t, `{"name":"vacuum"}`, string(body[:n]))
+}
+
+func TestHandler(t *testing.T) {
+	responseRecorder := httptest.NewRecorder()
+	Handler()(responseRecorder, httptest.NewRequest("GET", "/openapi.json", nil))
+
+	assert.Equal(t, 200, responseRecorder.Code)
+	assert.Equal(t, "application/json", responseRecorder.Header().Get("Content-Type"))
+	assert.Equal(t, Spec, responseRecorder.Body.String())
+}
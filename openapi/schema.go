@@ -0,0 +1,136 @@
+package openapi
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/gojektech/proctor-engine/utility"
+)
+
+const schemaRefPrefix = "#/components/schemas/"
+
+type Schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 string             `json:"type"`
+	Properties           map[string]*Schema `json:"properties"`
+	Required             []string           `json:"required"`
+	Items                *Schema            `json:"items"`
+	AdditionalProperties *Schema            `json:"additionalProperties"`
+	Enum                 []interface{}      `json:"enum"`
+	Minimum              *float64           `json:"minimum"`
+}
+
+func propertyField(field string, name string) string {
+	if field == "" {
+		return name
+	}
+	return field + "." + name
+}
+
+func (document *Document) resolve(schema *Schema) *Schema {
+	for schema != nil && schema.Ref != "" {
+		schema = document.Components.Schemas[strings.TrimPrefix(schema.Ref, schemaRefPrefix)]
+	}
+	return schema
+}
+
+func (document *Document) validate(schema *Schema, value interface{}, field string) []utility.FieldError {
+	schema = document.resolve(schema)
+	if schema == nil {
+		return nil
+	}
+
+	invalid := func(message string) []utility.FieldError {
+		if field == "" {
+			return []utility.FieldError{{Field: "body", Message: message}}
+		}
+		return []utility.FieldError{{Field: field, Message: message}}
+	}
+
+	switch schema.Type {
+	case "string":
+		if _, ok := value.(string); !ok {
+			return invalid("must be a string")
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return invalid("must be a boolean")
+		}
+	case "integer":
+		number, ok := value.(float64)
+		if !ok || number != math.Trunc(number) {
+			return invalid("must be an integer")
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			return invalid("must be a number")
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return invalid("must be an array")
+		}
+		var fieldErrors []utility.FieldError
+		for i, item := range items {
+			fieldErrors = append(fieldErrors, document.validate(schema.Items, item, fmt.Sprintf("%s[%d]", field, i))...)
+		}
+		return fieldErrors
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return invalid("must be an object")
+		}
+		return document.validateObject(schema, object, field)
+	}
+
+	if number, ok := value.(float64); ok && schema.Minimum != nil && number < *schema.Minimum {
+		return invalid(fmt.Sprintf("must be at least %v", *schema.Minimum))
+	}
+	if len(schema.Enum) > 0 && !contains(schema.Enum, value) {
+		var allowed []string
+		for _, enumValue := range schema.Enum {
+			allowed = append(allowed, fmt.Sprint(enumValue))
+		}
+		return invalid("must be one of " + strings.Join(allowed, ", "))
+	}
+	return nil
+}
+
+func (document *Document) validateObject(schema *Schema, object map[string]interface{}, field string) []utility.FieldError {
+	var fieldErrors []utility.FieldError
+	for _, name := range schema.Required {
+		if _, ok := object[name]; !ok {
+			fieldErrors = append(fieldErrors, utility.FieldError{Field: propertyField(field, name), Message: "is required"})
+		}
+	}
+
+	var names []string
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value := object[name]
+		if value == nil {
+			continue
+		}
+		if property, ok := schema.Properties[name]; ok {
+			fieldErrors = append(fieldErrors, document.validate(property, value, propertyField(field, name))...)
+		} else if schema.AdditionalProperties != nil {
+			fieldErrors = append(fieldErrors, document.validate(schema.AdditionalProperties, value, propertyField(field, name))...)
+		}
+	}
+	return fieldErrors
+}
+
+func contains(values []interface{}, value interface{}) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
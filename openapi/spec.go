@@ -0,0 +1,839 @@
+package openapi
+
+const Spec = `{
+  "openapi": "3.0.1",
+  "info": {
+    "title": "Proctor Engine API",
+    "version": "1.0.0"
+  },
+  "paths": {
+    "/ping": {
+      "get": {
+        "summary": "Liveness text probe",
+        "responses": {"200": {"description": "pong", "content": {"text/plain": {"schema": {"type": "string"}}}}}
+      }
+    },
+    "/healthz": {
+      "get": {
+        "summary": "Liveness probe",
+        "responses": {"200": {"description": "Engine is alive", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Status"}}}}}
+      }
+    },
+    "/readyz": {
+      "get": {
+        "summary": "Readiness probe checking redis and kubernetes",
+        "responses": {
+          "200": {"description": "All dependencies are reachable", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/HealthReport"}}}},
+          "503": {"description": "A dependency is unreachable", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/HealthReport"}}}}
+        }
+      }
+    },
+    "/metrics": {
+      "get": {
+        "summary": "Prometheus metrics",
+        "responses": {"200": {"description": "Metrics in the prometheus text format", "content": {"text/plain": {"schema": {"type": "string"}}}}}
+      }
+    },
+    "/openapi.json": {
+      "get": {
+        "summary": "This document",
+        "responses": {"200": {"description": "OpenAPI document", "content": {"application/json": {"schema": {"type": "object"}}}}}
+      }
+    },
+    "/api/v1/jobs": {
+      "get": {
+        "summary": "List jobs",
+        "responses": {
+          "200": {"description": "All jobs", "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/Metadata"}}}}},
+          "503": {"$ref": "#/components/responses/Error"}
+        }
+      },
+      "post": {
+        "summary": "Create or update jobs",
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/Metadata"}}}}
+        },
+        "responses": {
+          "201": {"description": "Jobs saved"},
+          "400": {"$ref": "#/components/responses/Error"},
+          "422": {"$ref": "#/components/responses/Error"},
+          "503": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/api/v1/jobs/{name}": {
+      "get": {
+        "summary": "Fetch a job",
+        "parameters": [{"$ref": "#/components/parameters/Name"}],
+        "responses": {
+          "200": {"description": "Job metadata", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Metadata"}}}},
+          "404": {"$ref": "#/components/responses/Error"},
+          "503": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/api/v1/jobs/{name}/secrets": {
+      "put": {
+        "summary": "Create or replace the secrets of a job",
+        "parameters": [{"$ref": "#/components/parameters/Name"}],
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {"$ref": "#/components/schemas/SecretValues"}}}
+        },
+        "responses": {
+          "201": {"description": "Secrets saved"},
+          "400": {"$ref": "#/components/responses/Error"},
+          "422": {"$ref": "#/components/responses/Error"},
+          "503": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/api/v1/jobs/{name}/executions": {
+      "post": {
+        "summary": "Execute a job",
+        "parameters": [
+          {"$ref": "#/components/parameters/Name"},
+          {"$ref": "#/components/parameters/UserEmail"},
+          {"name": "Idempotency-Key", "in": "header", "description": "Scoped to the requester identified by Email-Id. Kept once the job has started, so a retry replays the response instead of starting it again", "schema": {"type": "string"}},
+          {"name": "wait", "in": "query", "schema": {"type": "string", "enum": ["true", "false"]}},
+          {"name": "timeout", "in": "query", "schema": {"type": "string"}, "description": "Go duration such as 10m, used with wait=true"},
+          {"name": "tail_lines", "in": "query", "schema": {"type": "integer", "minimum": 0}}
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {"schema": {"$ref": "#/components/schemas/ExecutionRequest"}},
+            "multipart/form-data": {
+              "schema": {
+                "type": "object",
+                "required": ["job"],
+                "properties": {"job": {"type": "string", "description": "ExecutionRequest encoded as JSON"}},
+                "additionalProperties": {"type": "string", "format": "binary"}
+              }
+            }
+          }
+        },
+        "responses": {
+          "201": {"description": "Job started, or succeeded when wait=true", "content": {"application/json": {"schema": {"oneOf": [{"$ref": "#/components/schemas/ExecutionName"}, {"$ref": "#/components/schemas/ExecutionResult"}]}}}},
+          "202": {"description": "Execution queued or awaiting approval, or with wait=true still running at the timeout or the first heartbeat, in which case the final status is sent in the X-Execution-Status trailer", "content": {"application/json": {"schema": {"oneOf": [{"$ref": "#/components/schemas/QueuePosition"}, {"$ref": "#/components/schemas/ApprovalRequestID"}, {"$ref": "#/components/schemas/ExecutionResult"}]}}}},
+          "400": {"$ref": "#/components/responses/Error"},
+          "403": {"$ref": "#/components/responses/Error"},
+          "404": {"$ref": "#/components/responses/Error"},
+          "409": {"$ref": "#/components/responses/Error"},
+          "413": {"$ref": "#/components/responses/Error"},
+          "422": {"$ref": "#/components/responses/Error"},
+          "424": {"description": "Job failed or was cancelled when wait=true", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ExecutionResult"}}}},
+          "429": {"$ref": "#/components/responses/Error"},
+          "500": {"$ref": "#/components/responses/Error"},
+          "503": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/api/v1/executions/{name}": {
+      "get": {
+        "summary": "Fetch the status of an execution",
+        "parameters": [{"$ref": "#/components/parameters/Name"}],
+        "responses": {
+          "200": {"description": "Execution record", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Execution"}}}},
+          "404": {"$ref": "#/components/responses/Error"},
+          "503": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/api/v1/executions/{name}/cancel": {
+      "post": {
+        "summary": "Cancel a running execution",
+        "parameters": [{"$ref": "#/components/parameters/Name"}],
+        "responses": {
+          "200": {"description": "Cancelled execution record", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Execution"}}}},
+          "404": {"$ref": "#/components/responses/Error"},
+          "409": {"$ref": "#/components/responses/Error"},
+          "500": {"$ref": "#/components/responses/Error"},
+          "503": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/api/v1/executions/{name}/output": {
+      "get": {
+        "summary": "Fetch the output of a finished execution",
+        "parameters": [{"$ref": "#/components/parameters/Name"}],
+        "responses": {
+          "200": {"description": "Output written by the job", "content": {"application/octet-stream": {"schema": {"type": "string", "format": "binary"}}}},
+          "404": {"$ref": "#/components/responses/Error"},
+          "409": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/api/v1/executions/{name}/logs": {
+      "get": {
+        "summary": "Stream execution logs over a websocket",
+        "parameters": [{"$ref": "#/components/parameters/Name"}],
+        "responses": {
+          "101": {"description": "Switching to the websocket protocol"},
+          "400": {"$ref": "#/components/responses/Error"},
+          "503": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/api/v1/queue/{id}": {
+      "get": {
+        "summary": "Fetch the position of a queued execution",
+        "parameters": [{"$ref": "#/components/parameters/ID"}],
+        "responses": {
+          "200": {"description": "Queue position", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/QueuePosition"}}}},
+          "404": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/api/v1/queue/{id}/cancel": {
+      "post": {
+        "summary": "Cancel a queued execution before it is dispatched",
+        "parameters": [{"$ref": "#/components/parameters/ID"}],
+        "responses": {
+          "200": {"description": "Cancelled queue entry", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/QueuePosition"}}}},
+          "404": {"$ref": "#/components/responses/Error"},
+          "409": {"$ref": "#/components/responses/Error"},
+          "503": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/api/v1/policies": {
+      "get": {
+        "summary": "List execution policy rules from configuration and redis",
+        "responses": {
+          "200": {"description": "Policy rules", "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/PolicyRule"}}}}},
+          "503": {"$ref": "#/components/responses/Error"}
+        }
+      },
+      "post": {
+        "summary": "Replace the execution policy rules stored in redis",
+        "security": [{"PolicyAdminToken": []}],
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/PolicyRule"}}}}
+        },
+        "responses": {
+          "201": {"description": "Policy rules saved"},
+          "400": {"$ref": "#/components/responses/Error"},
+          "401": {"$ref": "#/components/responses/Error"},
+          "403": {"$ref": "#/components/responses/Error"},
+          "422": {"$ref": "#/components/responses/Error"},
+          "503": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/api/v1/webhooks/dead-letters": {
+      "get": {
+        "summary": "List webhook deliveries that exhausted their retries",
+        "responses": {
+          "200": {"description": "Webhook dead letters", "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/DeadLetter"}}}}},
+          "503": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/api/v1/webhooks/dead-letters/{id}/redeliver": {
+      "post": {
+        "summary": "Schedule a dead lettered webhook for redelivery",
+        "parameters": [{"$ref": "#/components/parameters/ID"}],
+        "responses": {
+          "202": {"description": "Redelivery scheduled"},
+          "404": {"$ref": "#/components/responses/Error"},
+          "503": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/api/v1/execution-requests/{id}/approve": {
+      "post": {
+        "summary": "Approve an execution request",
+        "parameters": [{"$ref": "#/components/parameters/ID"}, {"$ref": "#/components/parameters/UserEmail"}],
+        "responses": {
+          "201": {"description": "Final approval given and job started", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ExecutionName"}}}},
+          "202": {"description": "Approval recorded or execution queued", "content": {"application/json": {"schema": {"oneOf": [{"$ref": "#/components/schemas/PendingApprovals"}, {"$ref": "#/components/schemas/QueuePosition"}]}}}},
+          "403": {"$ref": "#/components/responses/Error"},
+          "404": {"$ref": "#/components/responses/Error"},
+          "409": {"$ref": "#/components/responses/Error"},
+          "422": {"$ref": "#/components/responses/Error"},
+          "429": {"$ref": "#/components/responses/Error"},
+          "500": {"$ref": "#/components/responses/Error"},
+          "503": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/api/v1/execution-requests/{id}/reject": {
+      "post": {
+        "summary": "Reject an execution request",
+        "parameters": [{"$ref": "#/components/parameters/ID"}, {"$ref": "#/components/parameters/UserEmail"}],
+        "responses": {
+          "200": {"description": "Request rejected"},
+          "404": {"$ref": "#/components/responses/Error"},
+          "422": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/api/v1/workflows": {
+      "post": {
+        "summary": "Create or update a workflow",
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Workflow"}}}
+        },
+        "responses": {
+          "201": {"description": "Workflow saved"},
+          "400": {"$ref": "#/components/responses/Error"},
+          "422": {"$ref": "#/components/responses/Error"},
+          "503": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/api/v1/workflows/{name}": {
+      "get": {
+        "summary": "Fetch a workflow",
+        "parameters": [{"$ref": "#/components/parameters/Name"}],
+        "responses": {
+          "200": {"description": "Workflow", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Workflow"}}}},
+          "404": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/api/v1/workflows/{name}/runs": {
+      "post": {
+        "summary": "Start a workflow run",
+        "parameters": [{"$ref": "#/components/parameters/Name"}],
+        "requestBody": {
+          "required": false,
+          "content": {"application/json": {"schema": {"$ref": "#/components/schemas/WorkflowRunRequest"}}}
+        },
+        "responses": {
+          "201": {"description": "Run started", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/WorkflowRun"}}}},
+          "400": {"$ref": "#/components/responses/Error"},
+          "404": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/api/v1/workflows/{name}/runs/{id}": {
+      "get": {
+        "summary": "Fetch a workflow run",
+        "parameters": [{"$ref": "#/components/parameters/Name"}, {"$ref": "#/components/parameters/ID"}],
+        "responses": {
+          "200": {"description": "Workflow run", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/WorkflowRun"}}}},
+          "404": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/api/v1/workflows/{name}/runs/{id}/cancel": {
+      "post": {
+        "summary": "Cancel a workflow run",
+        "parameters": [{"$ref": "#/components/parameters/Name"}, {"$ref": "#/components/parameters/ID"}],
+        "responses": {
+          "202": {"description": "Cancellation requested", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/WorkflowRun"}}}},
+          "404": {"$ref": "#/components/responses/Error"},
+          "409": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/jobs/execute": {
+      "post": {
+        "summary": "Execute a job",
+        "deprecated": true,
+        "parameters": [
+          {"$ref": "#/components/parameters/UserEmail"},
+          {"name": "Idempotency-Key", "in": "header", "description": "Scoped to the requester identified by Email-Id. Kept once the job has started, so a retry replays the response instead of starting it again", "schema": {"type": "string"}},
+          {"name": "wait", "in": "query", "schema": {"type": "string", "enum": ["true", "false"]}},
+          {"name": "timeout", "in": "query", "schema": {"type": "string"}, "description": "Go duration such as 10m, used with wait=true"},
+          {"name": "tail_lines", "in": "query", "schema": {"type": "integer", "minimum": 0}}
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {"schema": {"$ref": "#/components/schemas/Job"}},
+            "multipart/form-data": {
+              "schema": {
+                "type": "object",
+                "required": ["job"],
+                "properties": {"job": {"type": "string", "description": "Job encoded as JSON"}},
+                "additionalProperties": {"type": "string", "format": "binary"}
+              }
+            }
+          }
+        },
+        "responses": {
+          "201": {"description": "Job started, or succeeded when wait=true", "content": {"application/json": {"schema": {"oneOf": [{"$ref": "#/components/schemas/ExecutionName"}, {"$ref": "#/components/schemas/ExecutionResult"}]}}}},
+          "202": {"description": "Execution queued or awaiting approval, or with wait=true still running at the timeout or the first heartbeat, in which case the final status is sent in the X-Execution-Status trailer", "content": {"application/json": {"schema": {"oneOf": [{"$ref": "#/components/schemas/QueuePosition"}, {"$ref": "#/components/schemas/ApprovalRequestID"}, {"$ref": "#/components/schemas/ExecutionResult"}]}}}},
+          "400": {"$ref": "#/components/responses/Error"},
+          "403": {"$ref": "#/components/responses/Error"},
+          "404": {"$ref": "#/components/responses/Error"},
+          "409": {"$ref": "#/components/responses/Error"},
+          "413": {"$ref": "#/components/responses/Error"},
+          "422": {"$ref": "#/components/responses/Error"},
+          "424": {"description": "Job failed or was cancelled when wait=true", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ExecutionResult"}}}},
+          "429": {"$ref": "#/components/responses/Error"},
+          "500": {"$ref": "#/components/responses/Error"},
+          "503": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/jobs/execute/{name}/output": {
+      "get": {
+        "summary": "Fetch the output of a finished execution",
+        "deprecated": true,
+        "parameters": [{"$ref": "#/components/parameters/Name"}],
+        "responses": {
+          "200": {"description": "Output written by the job", "content": {"application/octet-stream": {"schema": {"type": "string", "format": "binary"}}}},
+          "404": {"$ref": "#/components/responses/Error"},
+          "409": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/jobs/queue/{id}": {
+      "get": {
+        "summary": "Fetch the position of a queued execution",
+        "deprecated": true,
+        "parameters": [{"$ref": "#/components/parameters/ID"}],
+        "responses": {
+          "200": {"description": "Queue position", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/QueuePosition"}}}},
+          "404": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/jobs/logs": {
+      "get": {
+        "summary": "Stream execution logs over a websocket",
+        "deprecated": true,
+        "parameters": [{"name": "job_name", "in": "query", "required": true, "schema": {"type": "string"}}],
+        "responses": {
+          "101": {"description": "Switching to the websocket protocol"},
+          "400": {"$ref": "#/components/responses/Error"},
+          "422": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/jobs/metadata": {
+      "get": {
+        "summary": "List job metadata",
+        "deprecated": true,
+        "responses": {
+          "200": {"description": "All jobs", "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/Metadata"}}}}},
+          "503": {"$ref": "#/components/responses/Error"}
+        }
+      },
+      "post": {
+        "summary": "Create or update job metadata",
+        "deprecated": true,
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/Metadata"}}}}
+        },
+        "responses": {
+          "201": {"description": "Metadata saved"},
+          "400": {"$ref": "#/components/responses/Error"},
+          "422": {"$ref": "#/components/responses/Error"},
+          "503": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/jobs/secrets": {
+      "post": {
+        "summary": "Create or update job secrets",
+        "deprecated": true,
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Secret"}}}
+        },
+        "responses": {
+          "201": {"description": "Secrets saved"},
+          "400": {"$ref": "#/components/responses/Error"},
+          "422": {"$ref": "#/components/responses/Error"},
+          "503": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/jobs/policies": {
+      "get": {
+        "summary": "List execution policy rules from configuration and redis",
+        "deprecated": true,
+        "responses": {
+          "200": {"description": "Policy rules", "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/PolicyRule"}}}}},
+          "503": {"$ref": "#/components/responses/Error"}
+        }
+      },
+      "post": {
+        "summary": "Replace the execution policy rules stored in redis",
+        "security": [{"PolicyAdminToken": []}],
+        "deprecated": true,
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/PolicyRule"}}}}
+        },
+        "responses": {
+          "201": {"description": "Policy rules saved"},
+          "400": {"$ref": "#/components/responses/Error"},
+          "401": {"$ref": "#/components/responses/Error"},
+          "403": {"$ref": "#/components/responses/Error"},
+          "422": {"$ref": "#/components/responses/Error"},
+          "503": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/jobs/requests/{id}/approve": {
+      "post": {
+        "summary": "Approve an execution request",
+        "deprecated": true,
+        "parameters": [{"$ref": "#/components/parameters/ID"}, {"$ref": "#/components/parameters/UserEmail"}],
+        "responses": {
+          "201": {"description": "Final approval given and job started", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ExecutionName"}}}},
+          "202": {"description": "Approval recorded or execution queued", "content": {"application/json": {"schema": {"oneOf": [{"$ref": "#/components/schemas/PendingApprovals"}, {"$ref": "#/components/schemas/QueuePosition"}]}}}},
+          "403": {"$ref": "#/components/responses/Error"},
+          "404": {"$ref": "#/components/responses/Error"},
+          "409": {"$ref": "#/components/responses/Error"},
+          "422": {"$ref": "#/components/responses/Error"},
+          "429": {"$ref": "#/components/responses/Error"},
+          "500": {"$ref": "#/components/responses/Error"},
+          "503": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/jobs/requests/{id}/reject": {
+      "post": {
+        "summary": "Reject an execution request",
+        "deprecated": true,
+        "parameters": [{"$ref": "#/components/parameters/ID"}, {"$ref": "#/components/parameters/UserEmail"}],
+        "responses": {
+          "200": {"description": "Request rejected"},
+          "404": {"$ref": "#/components/responses/Error"},
+          "422": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    }
+  },
+  "components": {
+    "parameters": {
+      "Name": {"name": "name", "in": "path", "required": true, "schema": {"type": "string"}},
+      "ID": {"name": "id", "in": "path", "required": true, "schema": {"type": "string"}},
+      "UserEmail": {"name": "Email-Id", "in": "header", "schema": {"type": "string"}}
+    },
+    "responses": {
+      "Error": {"description": "Error", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ErrorResponse"}}}}
+    },
+    "securitySchemes": {
+      "PolicyAdminToken": {"type": "http", "scheme": "bearer", "description": "PROCTOR_POLICY_ADMIN_TOKEN; policy submission is disabled when unset"}
+    },
+    "schemas": {
+      "StringMap": {"type": "object", "additionalProperties": {"type": "string"}},
+      "Job": {
+        "type": "object",
+        "required": ["name"],
+        "properties": {
+          "name": {"type": "string"},
+          "args": {"$ref": "#/components/schemas/StringMap"},
+          "callback_url": {"type": "string"},
+          "cluster": {"type": "string"},
+          "namespace": {"type": "string"}
+        }
+      },
+      "ExecutionRequest": {
+        "type": "object",
+        "properties": {
+          "args": {"$ref": "#/components/schemas/StringMap"},
+          "callback_url": {"type": "string"},
+          "cluster": {"type": "string"},
+          "namespace": {"type": "string"}
+        }
+      },
+      "Metadata": {
+        "type": "object",
+        "required": ["name", "image_name"],
+        "properties": {
+          "name": {"type": "string"},
+          "description": {"type": "string"},
+          "image_name": {"type": "string"},
+          "image_digest": {"type": "string"},
+          "cluster": {"type": "string"},
+          "namespace": {"type": "string"},
+          "command": {"type": "array", "items": {"type": "string"}},
+          "args": {"type": "array", "items": {"type": "string"}},
+          "working_dir": {"type": "string"},
+          "scheduling": {"$ref": "#/components/schemas/Scheduling"},
+          "volumes": {"type": "array", "items": {"$ref": "#/components/schemas/Volume"}},
+          "files": {"type": "array", "items": {"$ref": "#/components/schemas/FileInput"}},
+          "env_vars": {"$ref": "#/components/schemas/EnvVars"},
+          "required_approvals": {"type": "integer", "minimum": 0},
+          "webhooks": {"type": "array", "items": {"type": "string"}},
+          "retry": {"$ref": "#/components/schemas/RetryPolicy"},
+          "max_concurrent": {"type": "integer", "minimum": 0},
+          "on_concurrency_limit": {"type": "string", "enum": ["queue", "reject"]},
+          "rate_limit": {"$ref": "#/components/schemas/RateLimit"}
+        }
+      },
+      "EnvVars": {
+        "type": "object",
+        "properties": {
+          "secrets": {"type": "array", "items": {"$ref": "#/components/schemas/VarMetadata"}},
+          "args": {"type": "array", "items": {"$ref": "#/components/schemas/VarMetadata"}}
+        }
+      },
+      "VarMetadata": {
+        "type": "object",
+        "properties": {
+          "name": {"type": "string"},
+          "description": {"type": "string"}
+        }
+      },
+      "Scheduling": {
+        "type": "object",
+        "properties": {
+          "node_selector": {"$ref": "#/components/schemas/StringMap"},
+          "tolerations": {"type": "array", "items": {"type": "object"}},
+          "affinity": {"type": "object"},
+          "service_account_name": {"type": "string"},
+          "image_pull_secrets": {"type": "array", "items": {"type": "string"}},
+          "priority_class_name": {"type": "string"},
+          "run_as_non_root": {"type": "boolean"},
+          "read_only_root_filesystem": {"type": "boolean"}
+        }
+      },
+      "Volume": {
+        "type": "object",
+        "required": ["name", "mount_path"],
+        "properties": {
+          "name": {"type": "string"},
+          "mount_path": {"type": "string"},
+          "read_only": {"type": "boolean"},
+          "empty_dir": {
+            "type": "object",
+            "properties": {
+              "medium": {"type": "string"},
+              "size_limit": {"type": "string"}
+            }
+          },
+          "persistent_volume_claim": {
+            "type": "object",
+            "required": ["claim_name"],
+            "properties": {"claim_name": {"type": "string"}}
+          }
+        }
+      },
+      "FileInput": {
+        "type": "object",
+        "required": ["name", "mount_path"],
+        "properties": {
+          "name": {"type": "string"},
+          "description": {"type": "string"},
+          "mount_path": {"type": "string"},
+          "required": {"type": "boolean"},
+          "sensitive": {"type": "boolean"}
+        }
+      },
+      "RetryPolicy": {
+        "type": "object",
+        "required": ["strategy"],
+        "properties": {
+          "strategy": {"type": "string", "enum": ["none", "kubernetes", "engine"]},
+          "backoff_limit": {"type": "integer", "minimum": 0},
+          "max_attempts": {"type": "integer", "minimum": 0},
+          "initial_delay_seconds": {"type": "integer", "minimum": 0}
+        }
+      },
+      "RateLimit": {
+        "type": "object",
+        "required": ["executions", "period_seconds"],
+        "properties": {
+          "executions": {"type": "integer", "minimum": 1},
+          "period_seconds": {"type": "integer", "minimum": 1},
+          "burst": {"type": "integer", "minimum": 0}
+        }
+      },
+      "Secret": {
+        "type": "object",
+        "required": ["job_name", "secrets"],
+        "properties": {
+          "job_name": {"type": "string"},
+          "secrets": {"$ref": "#/components/schemas/StringMap"}
+        }
+      },
+      "SecretValues": {
+        "type": "object",
+        "required": ["secrets"],
+        "properties": {
+          "secrets": {"$ref": "#/components/schemas/StringMap"}
+        }
+      },
+      "PolicyRule": {
+        "type": "object",
+        "required": ["name", "deny", "reason"],
+        "properties": {
+          "name": {"type": "string"},
+          "deny": {"type": "string", "description": "CEL expression over request, user, job, time and approved that denies the execution when true. user.executions_last_hour counts the executions admitted in the rolling 60 minutes before the request. Executions requested without Email-Id share the usage of the anonymous user"},
+          "reason": {"type": "string"}
+        }
+      },
+      "Workflow": {
+        "type": "object",
+        "required": ["name", "steps"],
+        "properties": {
+          "name": {"type": "string"},
+          "description": {"type": "string"},
+          "steps": {"type": "array", "items": {"$ref": "#/components/schemas/WorkflowStep"}}
+        }
+      },
+      "WorkflowStep": {
+        "type": "object",
+        "required": ["name", "job"],
+        "properties": {
+          "name": {"type": "string"},
+          "job": {"type": "string"},
+          "args": {"$ref": "#/components/schemas/StringMap"},
+          "depends_on": {"type": "array", "items": {"type": "string"}},
+          "when": {"type": "string"}
+        }
+      },
+      "WorkflowRunRequest": {
+        "type": "object",
+        "properties": {
+          "inputs": {"$ref": "#/components/schemas/StringMap"}
+        }
+      },
+      "WorkflowRun": {
+        "type": "object",
+        "properties": {
+          "id": {"type": "string"},
+          "workflow_name": {"type": "string"},
+          "status": {"type": "string"},
+          "inputs": {"$ref": "#/components/schemas/StringMap"},
+          "requested_by": {"type": "string"},
+          "steps": {
+            "type": "array",
+            "items": {
+              "type": "object",
+              "properties": {
+                "name": {"type": "string"},
+                "job": {"type": "string"},
+                "status": {"type": "string"},
+                "args": {"$ref": "#/components/schemas/StringMap"},
+                "queue_id": {"type": "string"},
+                "execution_name": {"type": "string"},
+                "output": {"type": "string"},
+                "error": {"type": "string"},
+                "started_at": {"type": "string", "format": "date-time"},
+                "finished_at": {"type": "string", "format": "date-time"}
+              }
+            }
+          },
+          "created_at": {"type": "string", "format": "date-time"},
+          "finished_at": {"type": "string", "format": "date-time"}
+        }
+      },
+      "ExecutionName": {
+        "type": "object",
+        "properties": {"name": {"type": "string"}}
+      },
+      "Execution": {
+        "type": "object",
+        "properties": {
+          "name": {"type": "string"},
+          "job_name": {"type": "string"},
+          "cluster": {"type": "string"},
+          "namespace": {"type": "string"},
+          "status": {"type": "string"},
+          "exit_code": {"type": "integer"},
+          "output": {"type": "string"},
+          "attempt": {"type": "integer"},
+          "previous_attempt": {"type": "string"},
+          "next_attempt": {"type": "string"},
+          "retry_pending": {"type": "boolean"},
+          "started_at": {"type": "string", "format": "date-time"},
+          "finished_at": {"type": "string", "format": "date-time"}
+        }
+      },
+      "ExecutionResult": {
+        "type": "object",
+        "properties": {
+          "name": {"type": "string"},
+          "status": {"type": "string"},
+          "exit_code": {"type": "integer"},
+          "duration_seconds": {"type": "number"},
+          "logs": {"type": "array", "items": {"type": "string"}},
+          "error": {"type": "string"}
+        }
+      },
+      "ApprovalRequestID": {
+        "type": "object",
+        "properties": {"request_id": {"type": "string"}}
+      },
+      "PendingApprovals": {
+        "type": "object",
+        "properties": {
+          "request_id": {"type": "string"},
+          "pending_approvals": {"type": "integer"}
+        }
+      },
+      "DeadLetter": {
+        "type": "object",
+        "properties": {
+          "delivery_id": {"type": "string"},
+          "url": {"type": "string"},
+          "payload": {"type": "object"},
+          "attempts": {"type": "integer"},
+          "last_error": {"type": "string"},
+          "failed_at": {"type": "string", "format": "date-time"}
+        }
+      },
+      "QueuePosition": {
+        "type": "object",
+        "properties": {
+          "queue_id": {"type": "string"},
+          "job_name": {"type": "string"},
+          "status": {"type": "string"},
+          "position": {"type": "integer"},
+          "eta_seconds": {"type": "number"},
+          "execution_name": {"type": "string"}
+        }
+      },
+      "Status": {
+        "type": "object",
+        "properties": {"status": {"type": "string"}}
+      },
+      "HealthReport": {
+        "type": "object",
+        "properties": {
+          "status": {"type": "string"},
+          "checks": {
+            "type": "object",
+            "additionalProperties": {
+              "type": "object",
+              "properties": {
+                "status": {"type": "string"},
+                "latency_ms": {"type": "integer"},
+                "error": {"type": "string"}
+              }
+            }
+          },
+          "checked_at": {"type": "string", "format": "date-time"}
+        }
+      },
+      "ErrorResponse": {
+        "type": "object",
+        "properties": {
+          "error": {
+            "type": "object",
+            "properties": {
+              "code": {"type": "string"},
+              "message": {"type": "string"},
+              "details": {
+                "type": "array",
+                "items": {
+                  "type": "object",
+                  "properties": {
+                    "field": {"type": "string"},
+                    "message": {"type": "string"}
+                  }
+                }
+              },
+              "request_id": {"type": "string"}
+            }
+          }
+        }
+      }
+    }
+  }
+}`
@@ -0,0 +1,151 @@
+package ratelimit
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gojektech/proctor-engine/config"
+	"github.com/gojektech/proctor-engine/redis"
+)
+
+const KeySuffix = "-rate-limit"
+
+const (
+	LimitHeaderKey      = "X-RateLimit-Limit"
+	RemainingHeaderKey  = "X-RateLimit-Remaining"
+	ResetHeaderKey      = "X-RateLimit-Reset"
+	RetryAfterHeaderKey = "Retry-After"
+)
+
+const takeTokenScript = `
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'updated_at')
+local tokens = tonumber(state[1])
+local updatedAt = tonumber(state[2])
+if tokens == nil or updatedAt == nil then
+	tokens = burst
+	updatedAt = now
+end
+tokens = math.min(burst, tokens + math.max(0, now - updatedAt) * rate / 1000)
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+redis.call('HMSET', KEYS[1], 'tokens', tostring(tokens), 'updated_at', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst / rate * 1000))
+return {allowed, tostring(tokens)}
+`
+
+var errUnexpectedReply = errors.New("unexpected reply from rate limit script")
+
+var ErrLimitExceeded = errors.New("rate limit exceeded")
+
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+func (limit Limit) Enabled() bool {
+	return limit.Rate > 0 && limit.Burst > 0
+}
+
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration
+	Reset      time.Duration
+}
+
+func (result Result) WriteHeaders(w http.ResponseWriter) {
+	w.Header().Set(LimitHeaderKey, strconv.Itoa(result.Limit))
+	w.Header().Set(RemainingHeaderKey, strconv.Itoa(result.Remaining))
+	w.Header().Set(ResetHeaderKey, strconv.Itoa(ceilSeconds(result.Reset)))
+	if !result.Allowed {
+		w.Header().Set(RetryAfterHeaderKey, strconv.Itoa(ceilSeconds(result.RetryAfter)))
+	}
+}
+
+func ceilSeconds(duration time.Duration) int {
+	return int(math.Ceil(duration.Seconds()))
+}
+
+type Limiter interface {
+	Allow(string, Limit) (Result, error)
+}
+
+type limiter struct {
+	redisClient redis.Client
+	now         func() time.Time
+}
+
+func NewLimiter(redisClient redis.Client) Limiter {
+	return &limiter{
+		redisClient: redisClient,
+		now:         time.Now,
+	}
+}
+
+func limitKey(key string) string {
+	return key + KeySuffix
+}
+
+func (limiter *limiter) Allow(key string, limit Limit) (Result, error) {
+	nowMilliseconds := limiter.now().UnixNano() / int64(time.Millisecond)
+	reply, err := limiter.redisClient.EVAL(takeTokenScript, 1, limitKey(key), limit.Rate, limit.Burst, nowMilliseconds)
+	if err != nil {
+		return Result{}, err
+	}
+
+	values, ok := reply.([]interface{})
+	if !ok || len(values) != 2 {
+		return Result{}, errUnexpectedReply
+	}
+	allowed, ok := values[0].(int64)
+	if !ok {
+		return Result{}, errUnexpectedReply
+	}
+	binaryTokens, ok := values[1].([]byte)
+	if !ok {
+		return Result{}, errUnexpectedReply
+	}
+	tokens, err := strconv.ParseFloat(string(binaryTokens), 64)
+	if err != nil {
+		return Result{}, err
+	}
+
+	result := Result{
+		Allowed:   allowed == 1,
+		Limit:     limit.Burst,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     secondsToDuration((float64(limit.Burst) - tokens) / limit.Rate),
+	}
+	if !result.Allowed {
+		result.RetryAfter = secondsToDuration((1 - tokens) / limit.Rate)
+	}
+	return result, nil
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
+
+func DefaultLimit() Limit {
+	return Limit{
+		Rate:  config.RateLimitRequestsPerSecond(),
+		Burst: config.RateLimitBurst(),
+	}
+}
+
+func AddressLimit() Limit {
+	return Limit{
+		Rate:  config.RateLimitAddressRequestsPerSecond(),
+		Burst: config.RateLimitAddressBurst(),
+	}
+}
@@ -0,0 +1,14 @@
+package ratelimit
+
+import (
+	"github.com/stretchr/testify/mock"
+)
+
+type MockLimiter struct {
+	mock.Mock
+}
+
+func (m *MockLimiter) Allow(key string, limit Limit) (Result, error) {
+	args := m.Called(key, limit)
+	return args.Get(0).(Result), args.Error(1)
+}
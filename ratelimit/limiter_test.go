@@ -0,0 +1,86 @@
+package ratelimit
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gojektech/proctor-engine/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type LimiterTestSuite struct {
+	suite.Suite
+	mockRedisClient *redis.MockClient
+	testLimiter     Limiter
+	now             time.Time
+}
+
+func (s *LimiterTestSuite) SetupTest() {
+	s.mockRedisClient = &redis.MockClient{}
+	s.now = time.Unix(1542000000, 0)
+	s.testLimiter = &limiter{
+		redisClient: s.mockRedisClient,
+		now:         func() time.Time { return s.now },
+	}
+}
+
+func (s *LimiterTestSuite) TestAllow() {
+	t := s.T()
+
+	limit := Limit{Rate: 0.5, Burst: 10}
+	s.mockRedisClient.On("EVAL", takeTokenScript, 1, "user:jane-rate-limit", 0.5, 10, int64(1542000000000)).Return([]interface{}{int64(1), []byte("7.5")}, nil).Once()
+
+	result, err := s.testLimiter.Allow("user:jane", limit)
+	assert.NoError(t, err)
+	assert.Equal(t, Result{Allowed: true, Limit: 10, Remaining: 7, Reset: 5 * time.Second}, result)
+
+	s.mockRedisClient.AssertExpectations(t)
+}
+
+func (s *LimiterTestSuite) TestAllowWhenBucketIsEmpty() {
+	t := s.T()
+
+	s.mockRedisClient.On("EVAL", takeTokenScript, 1, "user:jane-rate-limit", 0.5, 10, int64(1542000000000)).Return([]interface{}{int64(0), []byte("0.25")}, nil).Once()
+
+	result, err := s.testLimiter.Allow("user:jane", Limit{Rate: 0.5, Burst: 10})
+	assert.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+	assert.Equal(t, 1500*time.Millisecond, result.RetryAfter)
+}
+
+func (s *LimiterTestSuite) TestAllowOnRedisFailure() {
+	t := s.T()
+
+	s.mockRedisClient.On("EVAL", takeTokenScript, 1, "user:jane-rate-limit", 0.5, 10, int64(1542000000000)).Return(nil, errors.New("redis down")).Once()
+
+	_, err := s.testLimiter.Allow("user:jane", Limit{Rate: 0.5, Burst: 10})
+	assert.Error(t, err)
+}
+
+func TestLimiterTestSuite(t *testing.T) {
+	suite.Run(t, new(LimiterTestSuite))
+}
+
+func TestWriteHeaders(t *testing.T) {
+	responseRecorder := httptest.NewRecorder()
+	Result{Allowed: false, Limit: 10, Remaining: 0, RetryAfter: 1500 * time.Millisecond, Reset: 19 * time.Second}.WriteHeaders(responseRecorder)
+
+	assert.Equal(t, "10", responseRecorder.Header().Get(LimitHeaderKey))
+	assert.Equal(t, "0", responseRecorder.Header().Get(RemainingHeaderKey))
+	assert.Equal(t, "19", responseRecorder.Header().Get(ResetHeaderKey))
+	assert.Equal(t, "2", responseRecorder.Header().Get(RetryAfterHeaderKey))
+
+	responseRecorder = httptest.NewRecorder()
+	Result{Allowed: true, Limit: 10, Remaining: 9}.WriteHeaders(responseRecorder)
+	assert.Empty(t, responseRecorder.Header().Get(RetryAfterHeaderKey))
+}
+
+func TestLimitEnabled(t *testing.T) {
+	assert.True(t, Limit{Rate: 1, Burst: 1}.Enabled())
+	assert.False(t, Limit{Rate: 0, Burst: 10}.Enabled())
+	assert.False(t, Limit{Rate: 1, Burst: 0}.Enabled())
+}
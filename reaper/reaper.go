@@ -0,0 +1,123 @@
+package reaper
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/gojektech/proctor-engine/kubernetes"
+	"github.com/gojektech/proctor-engine/logger"
+	"github.com/gojektech/proctor-engine/metrics"
+	"github.com/gojektech/proctor-engine/redis"
+)
+
+const LockKey = "proctor-reaper-lock"
+
+type reaper struct {
+	kubeClient         kubernetes.Client
+	redisClient        redis.Client
+	interval           time.Duration
+	succeededRetention time.Duration
+	failedRetention    time.Duration
+}
+
+type Reaper interface {
+	Start(context.Context)
+}
+
+func NewReaper(kubeClient kubernetes.Client, redisClient redis.Client, interval, succeededRetention, failedRetention time.Duration) Reaper {
+	return &reaper{
+		kubeClient:         kubeClient,
+		redisClient:        redisClient,
+		interval:           interval,
+		succeededRetention: succeededRetention,
+		failedRetention:    failedRetention,
+	}
+}
+
+func (reaper *reaper) Start(ctx context.Context) {
+	if reaper.interval <= 0 {
+		logger.Info("Reaper disabled, no interval configured")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(reaper.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				reaper.reap(time.Now())
+			}
+		}
+	}()
+}
+
+func (reaper *reaper) lockExpirySeconds() int {
+	expiry := int(reaper.interval.Seconds()) - 1
+	if expiry < 1 {
+		return 1
+	}
+	return expiry
+}
+
+func (reaper *reaper) acquireLock() bool {
+	owner, _ := os.Hostname()
+
+	acquired, err := reaper.redisClient.SETNX(LockKey, reaper.lockExpirySeconds(), []byte(owner))
+	if err != nil {
+		logger.Error("Error acquiring reaper lock", err.Error())
+		return false
+	}
+	return acquired
+}
+
+func (reaper *reaper) retentionFor(jobStatus kubernetes.JobStatus) time.Duration {
+	if jobStatus.Succeeded {
+		return reaper.succeededRetention
+	}
+	return reaper.failedRetention
+}
+
+func (reaper *reaper) hasExpired(jobStatus kubernetes.JobStatus, now time.Time) bool {
+	retention := reaper.retentionFor(jobStatus)
+	if retention <= 0 || jobStatus.CompletionTime.IsZero() {
+		return false
+	}
+	return now.Sub(jobStatus.CompletionTime) > retention
+}
+
+func (reaper *reaper) reap(now time.Time) {
+	if !reaper.acquireLock() {
+		logger.Debug("Reaper lock held by another replica, skipping")
+		return
+	}
+
+	finishedJobs, err := reaper.kubeClient.ListFinishedJobs()
+	if err != nil {
+		logger.Error("Error listing finished jobs to reap", err.Error())
+		return
+	}
+
+	for _, jobStatus := range finishedJobs {
+		if !reaper.hasExpired(jobStatus, now) {
+			continue
+		}
+
+		err := reaper.kubeClient.DeleteJob(jobStatus.Target, jobStatus.Name)
+		if err != nil {
+			logger.Error("Error reaping job", jobStatus.Name, err.Error())
+			continue
+		}
+
+		status := "failed"
+		if jobStatus.Succeeded {
+			status = "succeeded"
+		}
+		metrics.JobReaped(status)
+		logger.Info("Reaped job", jobStatus.Name, status)
+	}
+}
@@ -0,0 +1,125 @@
+package reaper
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gojektech/proctor-engine/kubernetes"
+	"github.com/gojektech/proctor-engine/redis"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type ReaperTestSuite struct {
+	suite.Suite
+	mockKubeClient  *kubernetes.MockClient
+	mockRedisClient *redis.MockClient
+	testReaper      *reaper
+}
+
+func (s *ReaperTestSuite) SetupTest() {
+	s.mockKubeClient = &kubernetes.MockClient{}
+	s.mockRedisClient = &redis.MockClient{}
+	s.testReaper = &reaper{
+		kubeClient:         s.mockKubeClient,
+		redisClient:        s.mockRedisClient,
+		interval:           5 * time.Minute,
+		succeededRetention: time.Hour,
+		failedRetention:    24 * time.Hour,
+	}
+}
+
+func (s *ReaperTestSuite) TestReapDeletesExpiredJobs() {
+	t := s.T()
+
+	now := time.Now()
+	stagingTarget := kubernetes.Target{Cluster: "staging", Namespace: "batch"}
+	finishedJobs := []kubernetes.JobStatus{
+		{Name: "proctor-old-success", Target: stagingTarget, Succeeded: true, CompletionTime: now.Add(-2 * time.Hour)},
+		{Name: "proctor-recent-success", Succeeded: true, CompletionTime: now.Add(-30 * time.Minute)},
+		{Name: "proctor-old-failure", Succeeded: false, CompletionTime: now.Add(-25 * time.Hour)},
+		{Name: "proctor-recent-failure", Succeeded: false, CompletionTime: now.Add(-2 * time.Hour)},
+	}
+
+	s.mockRedisClient.On("SETNX", LockKey, 299, mock.Anything).Return(true, nil).Once()
+	s.mockKubeClient.On("ListFinishedJobs").Return(finishedJobs, nil).Once()
+	s.mockKubeClient.On("DeleteJob", stagingTarget, "proctor-old-success").Return(nil).Once()
+	s.mockKubeClient.On("DeleteJob", kubernetes.Target{}, "proctor-old-failure").Return(nil).Once()
+
+	s.testReaper.reap(now)
+
+	s.mockRedisClient.AssertExpectations(t)
+	s.mockKubeClient.AssertExpectations(t)
+	s.mockKubeClient.AssertNotCalled(t, "DeleteJob", mock.Anything, "proctor-recent-success")
+	s.mockKubeClient.AssertNotCalled(t, "DeleteJob", mock.Anything, "proctor-recent-failure")
+}
+
+func (s *ReaperTestSuite) TestReapContinuesAfterDeleteFailure() {
+	t := s.T()
+
+	now := time.Now()
+	finishedJobs := []kubernetes.JobStatus{
+		{Name: "proctor-one", Succeeded: true, CompletionTime: now.Add(-2 * time.Hour)},
+		{Name: "proctor-two", Succeeded: true, CompletionTime: now.Add(-2 * time.Hour)},
+	}
+
+	s.mockRedisClient.On("SETNX", LockKey, 299, mock.Anything).Return(true, nil).Once()
+	s.mockKubeClient.On("ListFinishedJobs").Return(finishedJobs, nil).Once()
+	s.mockKubeClient.On("DeleteJob", kubernetes.Target{}, "proctor-one").Return(errors.New("error")).Once()
+	s.mockKubeClient.On("DeleteJob", kubernetes.Target{}, "proctor-two").Return(nil).Once()
+
+	s.testReaper.reap(now)
+
+	s.mockKubeClient.AssertExpectations(t)
+}
+
+func (s *ReaperTestSuite) TestReapSkipsWhenLockIsHeld() {
+	t := s.T()
+
+	s.mockRedisClient.On("SETNX", LockKey, 299, mock.Anything).Return(false, nil).Once()
+
+	s.testReaper.reap(time.Now())
+
+	s.mockRedisClient.AssertExpectations(t)
+	s.mockKubeClient.AssertNotCalled(t, "ListFinishedJobs")
+}
+
+func (s *ReaperTestSuite) TestReapSkipsWhenLockFails() {
+	t := s.T()
+
+	s.mockRedisClient.On("SETNX", LockKey, 299, mock.Anything).Return(false, errors.New("error")).Once()
+
+	s.testReaper.reap(time.Now())
+
+	s.mockKubeClient.AssertNotCalled(t, "ListFinishedJobs")
+}
+
+func (s *ReaperTestSuite) TestReapKeepsJobsWithoutRetention() {
+	t := s.T()
+
+	s.testReaper.failedRetention = 0
+
+	now := time.Now()
+	finishedJobs := []kubernetes.JobStatus{
+		{Name: "proctor-old-failure", Succeeded: false, CompletionTime: now.Add(-100 * time.Hour)},
+	}
+
+	s.mockRedisClient.On("SETNX", LockKey, 299, mock.Anything).Return(true, nil).Once()
+	s.mockKubeClient.On("ListFinishedJobs").Return(finishedJobs, nil).Once()
+
+	s.testReaper.reap(now)
+
+	s.mockKubeClient.AssertNotCalled(t, "DeleteJob", mock.Anything, mock.Anything)
+}
+
+func TestLockExpirySeconds(t *testing.T) {
+	assert.Equal(t, 299, (&reaper{interval: 5 * time.Minute}).lockExpirySeconds())
+	assert.Equal(t, 1, (&reaper{interval: time.Second}).lockExpirySeconds())
+}
+
+func TestReaperTestSuite(t *testing.T) {
+	suite.Run(t, new(ReaperTestSuite))
+}
@@ -1,6 +1,10 @@
 package redis
 
 import (
+	"time"
+
+	"github.com/gojektech/proctor-engine/metrics"
+
 	"github.com/garyburd/redigo/redis"
 )
 
@@ -9,8 +13,20 @@ type Client interface {
 	SET(string, []byte) error
 	KEYS(string) ([]string, error)
 	MGET(...interface{}) ([][]byte, error)
+	SETEX(string, int, []byte) error
+	SETNX(string, int, []byte) (bool, error)
+	DEL(string) error
+	RPUSH(string, []byte) (int, error)
+	LRANGE(string, int, int) ([]string, error)
+	SADD(string, string) error
+	SREM(string, string) error
+	SMEMBERS(string) ([]string, error)
+	EVAL(string, int, ...interface{}) (interface{}, error)
+	PING() error
 }
 
+var ErrNil = redis.ErrNil
+
 type redisClient struct {
 	connPool *redis.Pool
 }
@@ -24,30 +40,148 @@ func NewClient() Client {
 	return &redisClient{connPool}
 }
 
-func (c *redisClient) GET(key string) ([]byte, error) {
+func observe(operation string, start time.Time, err *error) {
+	operationErr := *err
+	if operationErr == ErrNil {
+		operationErr = nil
+	}
+	metrics.ObserveRedisOperation(operation, time.Since(start), operationErr)
+}
+
+func (c *redisClient) GET(key string) (value []byte, err error) {
+	defer observe("GET", time.Now(), &err)
+
 	conn := c.connPool.Get()
 	defer conn.Close()
 
 	return redis.Bytes(conn.Do("GET", key))
 }
 
-func (c *redisClient) SET(key string, value []byte) error {
+func (c *redisClient) SET(key string, value []byte) (err error) {
+	defer observe("SET", time.Now(), &err)
+
 	conn := c.connPool.Get()
 	defer conn.Close()
 
 	return conn.Send("SET", key, value)
 }
 
-func (c *redisClient) KEYS(regex string) ([]string, error) {
+func (c *redisClient) KEYS(regex string) (keys []string, err error) {
+	defer observe("KEYS", time.Now(), &err)
+
 	conn := c.connPool.Get()
 	defer conn.Close()
 
 	return redis.Strings(conn.Do("KEYS", regex))
 }
 
-func (c *redisClient) MGET(keys ...interface{}) ([][]byte, error) {
+func (c *redisClient) MGET(keys ...interface{}) (values [][]byte, err error) {
+	defer observe("MGET", time.Now(), &err)
+
 	conn := c.connPool.Get()
 	defer conn.Close()
 
 	return redis.ByteSlices(conn.Do("MGET", keys...))
 }
+
+func (c *redisClient) SETEX(key string, expiryInSeconds int, value []byte) (err error) {
+	defer observe("SETEX", time.Now(), &err)
+
+	conn := c.connPool.Get()
+	defer conn.Close()
+
+	_, err = conn.Do("SETEX", key, expiryInSeconds, value)
+	return err
+}
+
+func (c *redisClient) SETNX(key string, expiryInSeconds int, value []byte) (set bool, err error) {
+	defer observe("SETNX", time.Now(), &err)
+
+	conn := c.connPool.Get()
+	defer conn.Close()
+
+	_, err = redis.String(conn.Do("SET", key, value, "NX", "EX", expiryInSeconds))
+	if err == ErrNil {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (c *redisClient) DEL(key string) (err error) {
+	defer observe("DEL", time.Now(), &err)
+
+	conn := c.connPool.Get()
+	defer conn.Close()
+
+	_, err = conn.Do("DEL", key)
+	return err
+}
+
+func (c *redisClient) RPUSH(key string, value []byte) (length int, err error) {
+	defer observe("RPUSH", time.Now(), &err)
+
+	conn := c.connPool.Get()
+	defer conn.Close()
+
+	return redis.Int(conn.Do("RPUSH", key, value))
+}
+
+func (c *redisClient) LRANGE(key string, start, stop int) (values []string, err error) {
+	defer observe("LRANGE", time.Now(), &err)
+
+	conn := c.connPool.Get()
+	defer conn.Close()
+
+	return redis.Strings(conn.Do("LRANGE", key, start, stop))
+}
+
+func (c *redisClient) SADD(key string, member string) (err error) {
+	defer observe("SADD", time.Now(), &err)
+
+	conn := c.connPool.Get()
+	defer conn.Close()
+
+	_, err = conn.Do("SADD", key, member)
+	return err
+}
+
+func (c *redisClient) SREM(key string, member string) (err error) {
+	defer observe("SREM", time.Now(), &err)
+
+	conn := c.connPool.Get()
+	defer conn.Close()
+
+	_, err = conn.Do("SREM", key, member)
+	return err
+}
+
+func (c *redisClient) SMEMBERS(key string) (members []string, err error) {
+	defer observe("SMEMBERS", time.Now(), &err)
+
+	conn := c.connPool.Get()
+	defer conn.Close()
+
+	return redis.Strings(conn.Do("SMEMBERS", key))
+}
+
+func (c *redisClient) EVAL(script string, keyCount int, keysAndArgs ...interface{}) (reply interface{}, err error) {
+	defer observe("EVAL", time.Now(), &err)
+
+	conn := c.connPool.Get()
+	defer conn.Close()
+
+	return redis.NewScript(keyCount, script).Do(conn, keysAndArgs...)
+}
+
+func (c *redisClient) PING() (err error) {
+	defer observe("PING", time.Now(), &err)
+
+	conn := c.connPool.Get()
+	defer conn.Close()
+
+	_, err = conn.Do("PING")
+	return err
+}
@@ -25,3 +25,53 @@ func (m *MockClient) MGET(keys ...interface{}) ([][]byte, error) {
 	args := m.Called(keys...)
 	return args.Get(0).([][]byte), args.Error(1)
 }
+
+func (m *MockClient) SETEX(key string, expiryInSeconds int, value []byte) error {
+	args := m.Called(key, expiryInSeconds, value)
+	return args.Error(0)
+}
+
+func (m *MockClient) SETNX(key string, expiryInSeconds int, value []byte) (bool, error) {
+	args := m.Called(key, expiryInSeconds, value)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockClient) DEL(key string) error {
+	args := m.Called(key)
+	return args.Error(0)
+}
+
+func (m *MockClient) RPUSH(key string, value []byte) (int, error) {
+	args := m.Called(key, value)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockClient) LRANGE(key string, start, stop int) ([]string, error) {
+	args := m.Called(key, start, stop)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockClient) SADD(key string, member string) error {
+	args := m.Called(key, member)
+	return args.Error(0)
+}
+
+func (m *MockClient) SREM(key string, member string) error {
+	args := m.Called(key, member)
+	return args.Error(0)
+}
+
+func (m *MockClient) SMEMBERS(key string) ([]string, error) {
+	args := m.Called(key)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockClient) EVAL(script string, keyCount int, keysAndArgs ...interface{}) (interface{}, error) {
+	args := m.Called(append([]interface{}{script, keyCount}, keysAndArgs...)...)
+	return args.Get(0), args.Error(1)
+}
+
+func (m *MockClient) PING() error {
+	args := m.Called()
+	return args.Error(0)
+}
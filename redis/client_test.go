@@ -98,6 +98,114 @@ func (s *RedisClientTestSuite) TestMGET() {
 	assert.EqualValues(t, [][]byte{[]byte("anyValue1"), []byte("anyValue2")}, values)
 }
 
+func (s *RedisClientTestSuite) TestSETEX() {
+	t := s.T()
+
+	key, value := "expiringKey", []byte("anyValue")
+	err := s.testRedisClient.SETEX(key, 60, value)
+	assert.NoError(t, err)
+
+	ttl, err := redis.Int(s.testRedisConn.Do("TTL", key))
+	assert.NoError(t, err)
+	assert.True(t, ttl > 0 && ttl <= 60)
+
+	savedValue, err := s.testRedisClient.GET(key)
+	assert.NoError(t, err)
+	assert.Equal(t, value, savedValue)
+}
+
+func (s *RedisClientTestSuite) TestSETNX() {
+	t := s.T()
+
+	key := "lockKey"
+	_, err := s.testRedisConn.Do("DEL", key)
+	assert.NoError(t, err)
+
+	set, err := s.testRedisClient.SETNX(key, 60, []byte("first"))
+	assert.NoError(t, err)
+	assert.True(t, set)
+
+	set, err = s.testRedisClient.SETNX(key, 60, []byte("second"))
+	assert.NoError(t, err)
+	assert.False(t, set)
+
+	ttl, err := redis.Int(s.testRedisConn.Do("TTL", key))
+	assert.NoError(t, err)
+	assert.True(t, ttl > 0 && ttl <= 60)
+
+	savedValue, err := s.testRedisClient.GET(key)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("first"), savedValue)
+}
+
+func (s *RedisClientTestSuite) TestListOperations() {
+	t := s.T()
+
+	key := "anyList"
+	_, err := s.testRedisConn.Do("DEL", key)
+	assert.NoError(t, err)
+
+	length, err := s.testRedisClient.RPUSH(key, []byte("first"))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, length)
+	length, err = s.testRedisClient.RPUSH(key, []byte("second"))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, length)
+
+	values, err := s.testRedisClient.LRANGE(key, 0, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"first", "second"}, values)
+}
+
+func (s *RedisClientTestSuite) TestSetOperations() {
+	t := s.T()
+
+	key := "anySet"
+	_, err := s.testRedisConn.Do("DEL", key)
+	assert.NoError(t, err)
+
+	assert.NoError(t, s.testRedisClient.SADD(key, "one"))
+	assert.NoError(t, s.testRedisClient.SADD(key, "two"))
+	assert.NoError(t, s.testRedisClient.SREM(key, "one"))
+
+	members, err := s.testRedisClient.SMEMBERS(key)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"two"}, members)
+}
+
+func (s *RedisClientTestSuite) TestEVAL() {
+	t := s.T()
+
+	reply, err := s.testRedisClient.EVAL("return redis.call('SET', KEYS[1], ARGV[1])", 1, "scriptKey", "scriptValue")
+	assert.NoError(t, err)
+	assert.Equal(t, "OK", reply)
+
+	savedValue, err := s.testRedisClient.GET("scriptKey")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("scriptValue"), savedValue)
+}
+
+func (s *RedisClientTestSuite) TestDEL() {
+	t := s.T()
+
+	key, value := "keyToDelete", []byte("anyValue")
+	err := s.testRedisClient.SET(key, value)
+	assert.NoError(t, err)
+
+	err = s.testRedisClient.DEL(key)
+	assert.NoError(t, err)
+
+	_, err = s.testRedisClient.GET(key)
+	assert.Equal(t, ErrNil, err)
+}
+
+func (s *RedisClientTestSuite) TestPING() {
+	t := s.T()
+
+	err := s.testRedisClient.PING()
+	assert.NoError(t, err)
+}
+
 func (s *RedisClientTestSuite) TearDownSuite() {
 	s.testRedisConn.Close()
 }
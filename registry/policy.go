@@ -0,0 +1,70 @@
+package registry
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/gojektech/proctor-engine/config"
+)
+
+type Policy struct {
+	AllowedPatterns []string
+	RequireDigest   bool
+	ResolveDigests  bool
+}
+
+func DefaultPolicy() Policy {
+	var allowedPatterns []string
+	for _, pattern := range strings.Split(config.ImageAllowedPatterns(), ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			allowedPatterns = append(allowedPatterns, pattern)
+		}
+	}
+
+	return Policy{
+		AllowedPatterns: allowedPatterns,
+		RequireDigest:   config.ImageRequireDigest(),
+		ResolveDigests:  config.ImageResolveDigests(),
+	}
+}
+
+func InsecureRegistries() []string {
+	var registries []string
+	for _, registry := range strings.Split(config.ImageInsecureRegistries(), ",") {
+		if registry = strings.TrimSpace(registry); registry != "" {
+			registries = append(registries, registry)
+		}
+	}
+	return registries
+}
+
+func matches(pattern string, name string) bool {
+	if strings.HasSuffix(pattern, "/**") {
+		return strings.HasPrefix(name, strings.TrimSuffix(pattern, "**"))
+	}
+	matched, err := path.Match(pattern, name)
+	return err == nil && matched
+}
+
+func (policy Policy) Allows(reference Reference) bool {
+	if len(policy.AllowedPatterns) == 0 {
+		return true
+	}
+	for _, pattern := range policy.AllowedPatterns {
+		if matches(pattern, reference.FullName()) {
+			return true
+		}
+	}
+	return false
+}
+
+func (policy Policy) Check(reference Reference) error {
+	if !policy.Allows(reference) {
+		return fmt.Errorf("image %s is not in an allowed registry or repository", reference.FullName())
+	}
+	if policy.RequireDigest && reference.Digest == "" {
+		return fmt.Errorf("image %s must be pinned by a sha256 digest", reference)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package registry
+
+import (
+	"os"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultPolicy(t *testing.T) {
+	os.Setenv("PROCTOR_IMAGE_ALLOWED_PATTERNS", "gcr.io/ops/*, docker.io/gojek/**")
+	os.Setenv("PROCTOR_IMAGE_REQUIRE_DIGEST", "true")
+	viper.AutomaticEnv()
+	defer os.Unsetenv("PROCTOR_IMAGE_ALLOWED_PATTERNS")
+	defer os.Unsetenv("PROCTOR_IMAGE_REQUIRE_DIGEST")
+
+	policy := DefaultPolicy()
+
+	assert.Equal(t, []string{"gcr.io/ops/*", "docker.io/gojek/**"}, policy.AllowedPatterns)
+	assert.True(t, policy.RequireDigest)
+	assert.False(t, policy.ResolveDigests)
+}
+
+func TestPolicyAllows(t *testing.T) {
+	policy := Policy{AllowedPatterns: []string{"gcr.io/ops/*", "docker.io/gojek/**"}}
+
+	for image, allowed := range map[string]bool{
+		"gcr.io/ops/toolbox":          true,
+		"gcr.io/ops/team/toolbox":     false,
+		"gojek/proctor":               true,
+		"gojek/team/proctor":          true,
+		"alpine":                      false,
+		"evil.io/gcr.io/ops/toolbox":  false,
+		"gcr.io/ops-extended/toolbox": false,
+	} {
+		reference, err := ParseReference(image)
+		assert.NoError(t, err, image)
+		assert.Equal(t, allowed, policy.Allows(reference), image)
+	}
+}
+
+func TestPolicyAllowsAnyImageWithoutPatterns(t *testing.T) {
+	reference, err := ParseReference("alpine")
+	assert.NoError(t, err)
+
+	assert.NoError(t, Policy{}.Check(reference))
+}
+
+func TestPolicyCheckRequiresDigest(t *testing.T) {
+	policy := Policy{RequireDigest: true}
+
+	reference, err := ParseReference("gcr.io/ops/toolbox:3")
+	assert.NoError(t, err)
+	assert.EqualError(t, policy.Check(reference), "image gcr.io/ops/toolbox:3 must be pinned by a sha256 digest")
+
+	reference.Digest = testDigest
+	assert.NoError(t, policy.Check(reference))
+}
+
+func TestPolicyCheckRejectsDisallowedImage(t *testing.T) {
+	policy := Policy{AllowedPatterns: []string{"gcr.io/ops/*"}}
+
+	reference, err := ParseReference("alpine")
+	assert.NoError(t, err)
+	assert.EqualError(t, policy.Check(reference), "image docker.io/library/alpine is not in an allowed registry or repository")
+}
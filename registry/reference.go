@@ -0,0 +1,95 @@
+package registry
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	dockerHubDomain   = "docker.io"
+	dockerHubRegistry = "registry-1.docker.io"
+	defaultTag        = "latest"
+)
+
+var (
+	repositoryPattern = regexp.MustCompile(`^[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*(?:/[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*)*$`)
+	tagPattern        = regexp.MustCompile(`^[\w][\w.-]{0,127}$`)
+	digestPattern     = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+)
+
+type Reference struct {
+	Domain     string
+	Repository string
+	Tag        string
+	Digest     string
+	name       string
+}
+
+func ParseReference(image string) (Reference, error) {
+	var reference Reference
+
+	name := image
+	if i := strings.Index(name, "@"); i >= 0 {
+		name, reference.Digest = name[:i], name[i+1:]
+		if !digestPattern.MatchString(reference.Digest) {
+			return reference, fmt.Errorf("invalid digest %s", reference.Digest)
+		}
+	}
+
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name, reference.Tag = name[:i], name[i+1:]
+		if !tagPattern.MatchString(reference.Tag) {
+			return reference, fmt.Errorf("invalid tag %s", reference.Tag)
+		}
+	}
+	reference.name = name
+
+	reference.Domain, reference.Repository = dockerHubDomain, name
+	if i := strings.Index(name, "/"); i >= 0 {
+		domain := name[:i]
+		if strings.ContainsAny(domain, ".:") || domain == "localhost" {
+			reference.Domain, reference.Repository = domain, name[i+1:]
+		}
+	}
+	if reference.Domain == dockerHubDomain && !strings.Contains(reference.Repository, "/") {
+		reference.Repository = "library/" + reference.Repository
+	}
+
+	if !repositoryPattern.MatchString(reference.Repository) {
+		return reference, fmt.Errorf("invalid repository %s", reference.Repository)
+	}
+	if reference.Tag == "" && reference.Digest == "" {
+		reference.Tag = defaultTag
+	}
+	return reference, nil
+}
+
+func (reference Reference) FullName() string {
+	return reference.Domain + "/" + reference.Repository
+}
+
+func (reference Reference) String() string {
+	image := reference.name
+	if reference.Tag != "" {
+		image += ":" + reference.Tag
+	}
+	if reference.Digest != "" {
+		image += "@" + reference.Digest
+	}
+	return image
+}
+
+func (reference Reference) registryHost() string {
+	if reference.Domain == dockerHubDomain {
+		return dockerHubRegistry
+	}
+	return reference.Domain
+}
+
+func (reference Reference) manifestReference() string {
+	if reference.Digest != "" {
+		return reference.Digest
+	}
+	return reference.Tag
+}
@@ -0,0 +1,45 @@
+package registry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testDigest = "sha256:4bcff63911fcb4448bd4fdacec207030997caf25e9bea4045fa6c8c44de311d1"
+
+func TestParseReference(t *testing.T) {
+	for image, expected := range map[string]Reference{
+		"alpine":                               {Domain: "docker.io", Repository: "library/alpine", Tag: "latest"},
+		"gojek/proctor:v1.2":                   {Domain: "docker.io", Repository: "gojek/proctor", Tag: "v1.2"},
+		"gcr.io/ops/toolbox:3":                 {Domain: "gcr.io", Repository: "ops/toolbox", Tag: "3"},
+		"localhost:5000/toolbox":               {Domain: "localhost:5000", Repository: "toolbox", Tag: "latest"},
+		"gcr.io/ops/toolbox@" + testDigest:     {Domain: "gcr.io", Repository: "ops/toolbox", Digest: testDigest},
+		"gcr.io/ops/toolbox:3@" + testDigest:   {Domain: "gcr.io", Repository: "ops/toolbox", Tag: "3", Digest: testDigest},
+		"registry.local:443/team/app/job:edge": {Domain: "registry.local:443", Repository: "team/app/job", Tag: "edge"},
+	} {
+		reference, err := ParseReference(image)
+		assert.NoError(t, err, image)
+
+		reference.name = ""
+		assert.Equal(t, expected, reference, image)
+	}
+}
+
+func TestParseReferenceWithInvalidImage(t *testing.T) {
+	for _, image := range []string{"", "Upper/Case", "alpine:bad tag", "alpine@sha256:short", "alpine@md5:" + testDigest[7:]} {
+		_, err := ParseReference(image)
+		assert.Error(t, err, image)
+	}
+}
+
+func TestReferenceString(t *testing.T) {
+	reference, err := ParseReference("gcr.io/ops/toolbox:3")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "gcr.io/ops/toolbox:3", reference.String())
+	assert.Equal(t, "gcr.io/ops/toolbox", reference.FullName())
+
+	reference.Digest = testDigest
+	assert.Equal(t, "gcr.io/ops/toolbox:3@"+testDigest, reference.String())
+}
@@ -0,0 +1,180 @@
+package registry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gojektech/proctor-engine/logger"
+)
+
+const digestHeaderKey = "Docker-Content-Digest"
+
+var manifestMediaTypes = []string{
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.oci.image.manifest.v1+json",
+}
+
+var ErrManifestNotFound = errors.New("image manifest not found")
+
+type resolver struct {
+	httpClient         *http.Client
+	insecureRegistries map[string]bool
+}
+
+type Resolver interface {
+	Resolve(context.Context, Reference) (string, error)
+}
+
+func NewResolver(insecureRegistries []string) Resolver {
+	insecure := map[string]bool{}
+	for _, registry := range insecureRegistries {
+		insecure[registry] = true
+	}
+
+	return &resolver{
+		httpClient:         &http.Client{Timeout: 10 * time.Second},
+		insecureRegistries: insecure,
+	}
+}
+
+func (resolver *resolver) manifestURL(reference Reference) string {
+	scheme := "https"
+	if resolver.insecureRegistries[reference.Domain] {
+		scheme = "http"
+	}
+	return fmt.Sprintf("%s://%s/v2/%s/manifests/%s", scheme, reference.registryHost(), reference.Repository, reference.manifestReference())
+}
+
+func (resolver *resolver) Resolve(ctx context.Context, reference Reference) (string, error) {
+	log := logger.FromContext(ctx).WithField("image", reference.String())
+
+	var token string
+	resp, err := resolver.requestManifest(ctx, http.MethodHead, reference, token)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		token, err = resolver.token(ctx, resp.Header.Get("Www-Authenticate"), reference)
+		if err != nil {
+			return "", err
+		}
+
+		resp, err = resolver.requestManifest(ctx, http.MethodHead, reference, token)
+		if err != nil {
+			return "", err
+		}
+		resp.Body.Close()
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return "", ErrManifestNotFound
+	case resp.StatusCode != http.StatusOK:
+		return "", fmt.Errorf("registry %s responded with %d", reference.registryHost(), resp.StatusCode)
+	}
+
+	digest := resp.Header.Get(digestHeaderKey)
+	if digest == "" {
+		log.Debug("Registry did not return a digest header, hashing manifest")
+		return resolver.manifestDigest(ctx, reference, token)
+	}
+	if !digestPattern.MatchString(digest) {
+		return "", fmt.Errorf("registry %s returned unsupported digest %s", reference.registryHost(), digest)
+	}
+	return digest, nil
+}
+
+func (resolver *resolver) requestManifest(ctx context.Context, method string, reference Reference, token string) (*http.Response, error) {
+	req, err := http.NewRequest(method, resolver.manifestURL(reference), nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+
+	req.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return resolver.httpClient.Do(req)
+}
+
+func (resolver *resolver) manifestDigest(ctx context.Context, reference Reference, token string) (string, error) {
+	resp, err := resolver.requestManifest(ctx, http.MethodGet, reference, token)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("registry %s responded with %d", reference.registryHost(), resp.StatusCode)
+	}
+
+	manifest, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(manifest)
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
+func (resolver *resolver) token(ctx context.Context, challenge string, reference Reference) (string, error) {
+	if !strings.HasPrefix(challenge, "Bearer ") {
+		return "", fmt.Errorf("registry %s requires unsupported authentication %q", reference.registryHost(), challenge)
+	}
+
+	params := map[string]string{}
+	for _, param := range strings.Split(strings.TrimPrefix(challenge, "Bearer "), ",") {
+		keyValue := strings.SplitN(strings.TrimSpace(param), "=", 2)
+		if len(keyValue) == 2 {
+			params[keyValue[0]] = strings.Trim(keyValue[1], `"`)
+		}
+	}
+	if params["realm"] == "" {
+		return "", fmt.Errorf("registry %s sent an authentication challenge without realm", reference.registryHost())
+	}
+
+	query := url.Values{}
+	if params["service"] != "" {
+		query.Set("service", params["service"])
+	}
+	query.Set("scope", fmt.Sprintf("repository:%s:pull", reference.Repository))
+
+	req, err := http.NewRequest(http.MethodGet, params["realm"]+"?"+query.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := resolver.httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("registry token endpoint responded with %d", resp.StatusCode)
+	}
+
+	var tokenResponse struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResponse); err != nil {
+		return "", err
+	}
+	if tokenResponse.Token != "" {
+		return tokenResponse.Token, nil
+	}
+	return tokenResponse.AccessToken, nil
+}
@@ -0,0 +1,16 @@
+package registry
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockResolver struct {
+	mock.Mock
+}
+
+func (m *MockResolver) Resolve(ctx context.Context, reference Reference) (string, error) {
+	args := m.Called(ctx, reference)
+	return args.String(0), args.Error(1)
+}
@@ -0,0 +1,117 @@
+package registry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeRegistry struct {
+	*httptest.Server
+	manifests     map[string]string
+	omitDigest    bool
+	requireToken  bool
+	tokenRequests int
+}
+
+func newFakeRegistry() *fakeRegistry {
+	registry := &fakeRegistry{manifests: map[string]string{}}
+	registry.Server = httptest.NewServer(http.HandlerFunc(registry.serve))
+	return registry
+}
+
+func (registry *fakeRegistry) host() string {
+	return strings.TrimPrefix(registry.URL, "http://")
+}
+
+func (registry *fakeRegistry) serve(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path == "/token" {
+		registry.tokenRequests++
+		if req.URL.Query().Get("scope") != "repository:ops/toolbox:pull" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		fmt.Fprint(w, `{"token":"pull-token"}`)
+		return
+	}
+
+	if registry.requireToken && req.Header.Get("Authorization") != "Bearer pull-token" {
+		w.Header().Set("Www-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="fake-registry"`, registry.URL))
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	manifest, ok := registry.manifests[req.URL.Path]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	sum := sha256.Sum256([]byte(manifest))
+	if !registry.omitDigest {
+		w.Header().Set(digestHeaderKey, "sha256:"+hex.EncodeToString(sum[:]))
+	}
+	if req.Method == http.MethodGet {
+		fmt.Fprint(w, manifest)
+	}
+}
+
+func manifestDigest(manifest string) string {
+	sum := sha256.Sum256([]byte(manifest))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func (registry *fakeRegistry) resolve(t *testing.T, image string) (string, error) {
+	reference, err := ParseReference(registry.host() + "/" + image)
+	assert.NoError(t, err)
+
+	return NewResolver([]string{registry.host()}).Resolve(context.Background(), reference)
+}
+
+func TestResolve(t *testing.T) {
+	registry := newFakeRegistry()
+	defer registry.Close()
+	registry.manifests["/v2/ops/toolbox/manifests/3"] = `{"schemaVersion":2}`
+
+	digest, err := registry.resolve(t, "ops/toolbox:3")
+	assert.NoError(t, err)
+	assert.Equal(t, manifestDigest(`{"schemaVersion":2}`), digest)
+}
+
+func TestResolveWithTokenAuthentication(t *testing.T) {
+	registry := newFakeRegistry()
+	defer registry.Close()
+	registry.requireToken = true
+	registry.manifests["/v2/ops/toolbox/manifests/3"] = `{"schemaVersion":2}`
+
+	digest, err := registry.resolve(t, "ops/toolbox:3")
+	assert.NoError(t, err)
+	assert.Equal(t, manifestDigest(`{"schemaVersion":2}`), digest)
+	assert.Equal(t, 1, registry.tokenRequests)
+}
+
+func TestResolveWithoutDigestHeader(t *testing.T) {
+	registry := newFakeRegistry()
+	defer registry.Close()
+	registry.omitDigest = true
+	registry.manifests["/v2/ops/toolbox/manifests/3"] = `{"schemaVersion":2}`
+
+	digest, err := registry.resolve(t, "ops/toolbox:3")
+	assert.NoError(t, err)
+	assert.Equal(t, manifestDigest(`{"schemaVersion":2}`), digest)
+}
+
+func TestResolveUnknownTag(t *testing.T) {
+	registry := newFakeRegistry()
+	defer registry.Close()
+
+	_, err := registry.resolve(t, "ops/toolbox:missing")
+	assert.Equal(t, ErrManifestNotFound, err)
+}
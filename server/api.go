@@ -1,21 +1,54 @@
 package server
 
 import (
+	"context"
 	"time"
 
 	"github.com/gojektech/proctor-engine/config"
+	"github.com/gojektech/proctor-engine/kubernetes"
 	"github.com/gojektech/proctor-engine/logger"
+	"github.com/gojektech/proctor-engine/openapi"
+	"github.com/gojektech/proctor-engine/ratelimit"
+	"github.com/gojektech/proctor-engine/reaper"
+	"github.com/gojektech/proctor-engine/redis"
 
 	"github.com/tylerb/graceful"
 	"github.com/urfave/negroni"
 )
 
 func Start() error {
+	if problems := CheckConfig(); len(problems) > 0 {
+		return problems
+	}
+
 	appPort := ":" + config.AppPort()
 
+	redisClient := redis.NewClient()
+	kubeClient := kubernetes.NewClient(kubernetes.KubeConfig())
+	rateLimiter := ratelimit.NewLimiter(redisClient)
+	handlers := newHandlers(redisClient, kubeClient, rateLimiter)
+	router := NewRouter(handlers)
+
+	jobReaper := reaper.NewReaper(kubeClient, redisClient,
+		config.ReaperInterval(),
+		config.SucceededJobRetention(),
+		config.FailedJobRetention())
+
 	server := negroni.New(negroni.NewRecovery())
+	server.Use(negroni.HandlerFunc(assignRequestID))
+	server.Use(instrumentRequests(router))
+	server.Use(limitRequests(router, rateLimiter))
+	server.Use(validateRequests(router, openapi.Load()))
 	server.UseHandler(router)
 
+	ctx, stopBackgroundWork := context.WithCancel(context.Background())
+	defer stopBackgroundWork()
+	jobReaper.Start(ctx)
+	handlers.Executioner.StartDispatcher(ctx)
+	handlers.Tracker.StartResumer(ctx)
+	handlers.Notifier.StartRetrier(ctx)
+	handlers.WorkflowRunner.StartResumer(ctx)
+
 	logger.Info("Starting server on port", appPort)
 
 	graceful.Run(appPort, 2*time.Second, server)
@@ -0,0 +1,34 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/gojektech/proctor-engine/config"
+	"github.com/gojektech/proctor-engine/jobs/policy"
+	"github.com/gojektech/proctor-engine/kubernetes"
+)
+
+// CheckConfig validates the engine configuration, including the structured
+// settings parsed by other packages, and returns every problem found.
+func CheckConfig() config.Problems {
+	problems := config.Validate()
+
+	if _, err := kubernetes.ClusterConfigs(); err != nil {
+		problems = append(problems, err)
+	}
+	if _, err := kubernetes.DefaultScheduling(); err != nil {
+		problems = append(problems, err)
+	}
+
+	rules, err := policy.ConfiguredRules()
+	if err != nil {
+		problems = append(problems, err)
+	}
+	for _, rule := range rules {
+		for _, fieldError := range rule.Validate() {
+			problems = append(problems, fmt.Errorf("invalid PROCTOR_POLICY_RULES: rule %q %s %s", rule.Name, fieldError.Field, fieldError.Message))
+		}
+	}
+
+	return problems
+}
@@ -0,0 +1,20 @@
+package server
+
+import (
+	"net/http"
+)
+
+const (
+	apiPrefix = "/api/v1"
+
+	deprecationHeaderKey = "Deprecation"
+	linkHeaderKey        = "Link"
+)
+
+func deprecated(handler http.HandlerFunc, successor string) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set(deprecationHeaderKey, "true")
+		w.Header().Set(linkHeaderKey, "<"+apiPrefix+successor+`>; rel="successor-version"`)
+		handler(w, req)
+	}
+}
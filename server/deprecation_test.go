@@ -0,0 +1,25 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeprecatedRouteAnnouncesSuccessor(t *testing.T) {
+	served := false
+	handler := deprecated(func(w http.ResponseWriter, req *http.Request) {
+		served = true
+		w.WriteHeader(http.StatusCreated)
+	}, "/policies")
+
+	responseRecorder := httptest.NewRecorder()
+	handler(responseRecorder, httptest.NewRequest("POST", "/jobs/policies", nil))
+
+	assert.True(t, served)
+	assert.Equal(t, http.StatusCreated, responseRecorder.Code)
+	assert.Equal(t, "true", responseRecorder.Header().Get(deprecationHeaderKey))
+	assert.Equal(t, `</api/v1/policies>; rel="successor-version"`, responseRecorder.Header().Get(linkHeaderKey))
+}
@@ -0,0 +1,35 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gojektech/proctor-engine/metrics"
+
+	"github.com/gorilla/mux"
+	"github.com/urfave/negroni"
+)
+
+const unmatchedRoute = "unmatched"
+
+func instrumentRequests(router *mux.Router) negroni.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+		start := time.Now()
+
+		next(w, req)
+
+		status := w.(negroni.ResponseWriter).Status()
+		metrics.ObserveHTTPRequest(routeTemplate(router, req), req.Method, status, time.Since(start))
+	}
+}
+
+func routeTemplate(router *mux.Router, req *http.Request) string {
+	var routeMatch mux.RouteMatch
+	if router.Match(req, &routeMatch) {
+		pathTemplate, err := routeMatch.Route.GetPathTemplate()
+		if err == nil {
+			return pathTemplate
+		}
+	}
+	return unmatchedRoute
+}
@@ -0,0 +1,36 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gojektech/proctor-engine/metrics"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/urfave/negroni"
+)
+
+func scrapeMetrics() string {
+	responseRecorder := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(responseRecorder, httptest.NewRequest("GET", "/metrics", nil))
+	return responseRecorder.Body.String()
+}
+
+func TestInstrumentRequestsObservesRouteTemplate(t *testing.T) {
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/executions/{name}", func(http.ResponseWriter, *http.Request) {}).Methods("GET")
+
+	for _, path := range []string{"/api/v1/executions/proctor-ipsum", "/unknown"} {
+		responseWriter := negroni.NewResponseWriter(httptest.NewRecorder())
+		instrumentRequests(router)(responseWriter, httptest.NewRequest("GET", path, nil), func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(http.StatusTeapot)
+		})
+	}
+
+	scraped := scrapeMetrics()
+	assert.Contains(t, scraped, `proctor_http_request_duration_seconds_count{method="GET",route="/api/v1/executions/{name}",status="418"}`)
+	assert.Contains(t, scraped, `proctor_http_request_duration_seconds_count{method="GET",route="unmatched",status="418"}`)
+	assert.NotContains(t, scraped, "proctor-ipsum")
+}
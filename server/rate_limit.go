@@ -0,0 +1,82 @@
+package server
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/gojektech/proctor-engine/logger"
+	"github.com/gojektech/proctor-engine/ratelimit"
+	"github.com/gojektech/proctor-engine/utility"
+
+	"github.com/gorilla/mux"
+	"github.com/urfave/negroni"
+)
+
+var rateLimitExemptRoutes = map[string]bool{
+	"/ping":    true,
+	"/healthz": true,
+	"/readyz":  true,
+	"/metrics": true,
+}
+
+type bucket struct {
+	key   string
+	limit ratelimit.Limit
+}
+
+// buckets lists what a request counts against: the caller, identified by the
+// Email-Id header or by its address when the header is absent, and, when an
+// address limit is configured, the address itself. The address limit is kept
+// separate since callers behind an ingress share one address.
+func buckets(req *http.Request) []bucket {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = req.RemoteAddr
+	}
+
+	var buckets []bucket
+	if limit := ratelimit.DefaultLimit(); limit.Enabled() {
+		identity := "ip:" + host
+		if user := req.Header.Get(utility.UserEmailHeaderKey); user != "" {
+			identity = "user:" + user
+		}
+		buckets = append(buckets, bucket{identity, limit})
+	}
+	if limit := ratelimit.AddressLimit(); limit.Enabled() {
+		buckets = append(buckets, bucket{"address:" + host, limit})
+	}
+	return buckets
+}
+
+func limitRequests(router *mux.Router, limiter ratelimit.Limiter) negroni.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+		route := routeTemplate(router, req)
+		if route == unmatchedRoute || rateLimitExemptRoutes[route] {
+			next(w, req)
+			return
+		}
+
+		var tightest *ratelimit.Result
+		for _, bucket := range buckets(req) {
+			result, err := limiter.Allow(bucket.key+":"+req.Method+" "+route, bucket.limit)
+			if err != nil {
+				logger.FromContext(req.Context()).Error("Error checking rate limit, allowing request", err.Error())
+				continue
+			}
+
+			if !result.Allowed {
+				result.WriteHeaders(w)
+				utility.WriteError(w, req, http.StatusTooManyRequests, utility.ErrCodeRateLimited, "rate limit exceeded, retry later")
+				return
+			}
+			if tightest == nil || result.Remaining < tightest.Remaining {
+				tightest = &result
+			}
+		}
+
+		if tightest != nil {
+			tightest.WriteHeaders(w)
+		}
+		next(w, req)
+	}
+}
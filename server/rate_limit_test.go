@@ -0,0 +1,153 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gojektech/proctor-engine/ratelimit"
+	"github.com/gojektech/proctor-engine/utility"
+
+	"github.com/gorilla/mux"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type RateLimitTestSuite struct {
+	suite.Suite
+	mockLimiter *ratelimit.MockLimiter
+	router      *mux.Router
+	limit       ratelimit.Limit
+}
+
+func (s *RateLimitTestSuite) SetupTest() {
+	os.Setenv("PROCTOR_RATE_LIMIT_REQUESTS_PER_SECOND", "1")
+	os.Setenv("PROCTOR_RATE_LIMIT_BURST", "5")
+	viper.AutomaticEnv()
+
+	s.mockLimiter = &ratelimit.MockLimiter{}
+	s.router = mux.NewRouter()
+	s.router.HandleFunc("/ping", func(http.ResponseWriter, *http.Request) {})
+	s.router.HandleFunc("/api/v1/jobs/{name}", func(http.ResponseWriter, *http.Request) {}).Methods("GET")
+	s.limit = ratelimit.Limit{Rate: 1, Burst: 5}
+}
+
+func (s *RateLimitTestSuite) TearDownTest() {
+	os.Unsetenv("PROCTOR_RATE_LIMIT_REQUESTS_PER_SECOND")
+	os.Unsetenv("PROCTOR_RATE_LIMIT_BURST")
+	os.Unsetenv("PROCTOR_RATE_LIMIT_ADDRESS_REQUESTS_PER_SECOND")
+	os.Unsetenv("PROCTOR_RATE_LIMIT_ADDRESS_BURST")
+}
+
+func (s *RateLimitTestSuite) serve(path string, userEmail string) (*httptest.ResponseRecorder, bool) {
+	req := httptest.NewRequest("GET", path, nil)
+	req.RemoteAddr = "10.0.0.1:52000"
+	if userEmail != "" {
+		req.Header.Set(utility.UserEmailHeaderKey, userEmail)
+	}
+	responseRecorder := httptest.NewRecorder()
+
+	served := false
+	limitRequests(s.router, s.mockLimiter)(responseRecorder, req, func(http.ResponseWriter, *http.Request) {
+		served = true
+	})
+	return responseRecorder, served
+}
+
+func (s *RateLimitTestSuite) TestRequestIsLimitedByRemoteAddress() {
+	t := s.T()
+
+	s.mockLimiter.On("Allow", "ip:10.0.0.1:GET /api/v1/jobs/{name}", s.limit).Return(ratelimit.Result{Allowed: true, Limit: 5, Remaining: 4}, nil).Once()
+
+	responseRecorder, served := s.serve("/api/v1/jobs/vacuum", "")
+
+	s.mockLimiter.AssertExpectations(t)
+	assert.True(t, served)
+	assert.Equal(t, "4", responseRecorder.Header().Get(ratelimit.RemainingHeaderKey))
+}
+
+func (s *RateLimitTestSuite) TestRequestIsLimitedByUser() {
+	t := s.T()
+
+	s.mockLimiter.On("Allow", "user:jane@example.com:GET /api/v1/jobs/{name}", s.limit).Return(ratelimit.Result{Allowed: true, Limit: 5, Remaining: 2}, nil).Once()
+
+	responseRecorder, served := s.serve("/api/v1/jobs/vacuum", "jane@example.com")
+
+	s.mockLimiter.AssertExpectations(t)
+	s.mockLimiter.AssertNotCalled(t, "Allow", "ip:10.0.0.1:GET /api/v1/jobs/{name}", s.limit)
+	assert.True(t, served)
+	assert.Equal(t, "2", responseRecorder.Header().Get(ratelimit.RemainingHeaderKey))
+}
+
+func (s *RateLimitTestSuite) TestUsersBehindOneAddressAreLimitedSeparately() {
+	t := s.T()
+
+	s.mockLimiter.On("Allow", "user:jane@example.com:GET /api/v1/jobs/{name}", s.limit).Return(ratelimit.Result{Limit: 5, RetryAfter: 3 * time.Second, Reset: 5 * time.Second}, nil).Once()
+	s.mockLimiter.On("Allow", "user:john@example.com:GET /api/v1/jobs/{name}", s.limit).Return(ratelimit.Result{Allowed: true, Limit: 5, Remaining: 4}, nil).Once()
+
+	responseRecorder, served := s.serve("/api/v1/jobs/vacuum", "jane@example.com")
+	assert.False(t, served)
+	assert.Equal(t, http.StatusTooManyRequests, responseRecorder.Code)
+	assert.Equal(t, "3", responseRecorder.Header().Get(ratelimit.RetryAfterHeaderKey))
+	assert.Equal(t, utility.ErrCodeRateLimited, errorCode(t, responseRecorder))
+
+	_, served = s.serve("/api/v1/jobs/vacuum", "john@example.com")
+	assert.True(t, served)
+	s.mockLimiter.AssertExpectations(t)
+}
+
+func (s *RateLimitTestSuite) TestAddressLimitIsSeparateFromUserLimit() {
+	t := s.T()
+
+	os.Setenv("PROCTOR_RATE_LIMIT_ADDRESS_REQUESTS_PER_SECOND", "10")
+	os.Setenv("PROCTOR_RATE_LIMIT_ADDRESS_BURST", "50")
+	addressLimit := ratelimit.Limit{Rate: 10, Burst: 50}
+
+	s.mockLimiter.On("Allow", "user:jane@example.com:GET /api/v1/jobs/{name}", s.limit).Return(ratelimit.Result{Allowed: true, Limit: 5, Remaining: 4}, nil).Once()
+	s.mockLimiter.On("Allow", "address:10.0.0.1:GET /api/v1/jobs/{name}", addressLimit).Return(ratelimit.Result{Limit: 50, RetryAfter: time.Second}, nil).Once()
+
+	responseRecorder, served := s.serve("/api/v1/jobs/vacuum", "jane@example.com")
+
+	s.mockLimiter.AssertExpectations(t)
+	assert.False(t, served)
+	assert.Equal(t, http.StatusTooManyRequests, responseRecorder.Code)
+}
+
+func (s *RateLimitTestSuite) TestExemptRoutesAreNotLimited() {
+	t := s.T()
+
+	_, served := s.serve("/ping", "")
+
+	s.mockLimiter.AssertNotCalled(t, "Allow", mock.Anything, mock.Anything)
+	assert.True(t, served)
+}
+
+func (s *RateLimitTestSuite) TestLimiterFailureAllowsRequest() {
+	t := s.T()
+
+	s.mockLimiter.On("Allow", "ip:10.0.0.1:GET /api/v1/jobs/{name}", s.limit).Return(ratelimit.Result{}, errors.New("redis down")).Once()
+
+	_, served := s.serve("/api/v1/jobs/vacuum", "")
+
+	assert.True(t, served)
+}
+
+func (s *RateLimitTestSuite) TestDisabledLimitIsNotChecked() {
+	t := s.T()
+
+	os.Setenv("PROCTOR_RATE_LIMIT_BURST", "0")
+
+	_, served := s.serve("/api/v1/jobs/vacuum", "")
+
+	s.mockLimiter.AssertNotCalled(t, "Allow", mock.Anything, mock.Anything)
+	assert.True(t, served)
+}
+
+func TestRateLimitTestSuite(t *testing.T) {
+	suite.Run(t, new(RateLimitTestSuite))
+}
@@ -0,0 +1,39 @@
+package server
+
+import (
+	"net/http"
+	"regexp"
+
+	"github.com/gojektech/proctor-engine/logger"
+	"github.com/gojektech/proctor-engine/utility"
+)
+
+const maxRequestIDLength = 128
+
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]+$`)
+
+// validRequestID guards logs and error envelopes against oversized or
+// malformed client supplied ids.
+func validRequestID(requestID string) bool {
+	return len(requestID) <= maxRequestIDLength && requestIDPattern.MatchString(requestID)
+}
+
+func assignRequestID(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	requestID := req.Header.Get(utility.RequestIDHeaderKey)
+	if !validRequestID(requestID) {
+		generatedID, err := utility.RandomID()
+		if err != nil {
+			logger.Error("Error generating request id", err.Error())
+		}
+		requestID = generatedID
+		req.Header.Set(utility.RequestIDHeaderKey, requestID)
+	}
+	w.Header().Set(utility.RequestIDHeaderKey, requestID)
+
+	fields := logger.Fields{logger.RequestIDField: requestID}
+	if user := req.Header.Get(utility.UserEmailHeaderKey); user != "" {
+		fields[logger.UserField] = user
+	}
+
+	next(w, req.WithContext(logger.WithFields(req.Context(), fields)))
+}
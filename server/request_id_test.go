@@ -0,0 +1,50 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gojektech/proctor-engine/utility"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func serveWithRequestID(requestID string) (*httptest.ResponseRecorder, string) {
+	req := httptest.NewRequest("GET", "/ping", nil)
+	if requestID != "" {
+		req.Header.Set(utility.RequestIDHeaderKey, requestID)
+	}
+	responseRecorder := httptest.NewRecorder()
+
+	var seenRequestID string
+	assignRequestID(responseRecorder, req, func(w http.ResponseWriter, req *http.Request) {
+		seenRequestID = req.Header.Get(utility.RequestIDHeaderKey)
+	})
+	return responseRecorder, seenRequestID
+}
+
+func TestAssignRequestIDKeepsValidClientID(t *testing.T) {
+	responseRecorder, seenRequestID := serveWithRequestID("client-id_1.2:3")
+
+	assert.Equal(t, "client-id_1.2:3", seenRequestID)
+	assert.Equal(t, "client-id_1.2:3", responseRecorder.Header().Get(utility.RequestIDHeaderKey))
+}
+
+func TestAssignRequestIDGeneratesMissingID(t *testing.T) {
+	responseRecorder, seenRequestID := serveWithRequestID("")
+
+	assert.Len(t, seenRequestID, 32)
+	assert.Equal(t, seenRequestID, responseRecorder.Header().Get(utility.RequestIDHeaderKey))
+}
+
+func TestAssignRequestIDReplacesUnsafeID(t *testing.T) {
+	for _, requestID := range []string{"id\nforged log line", "id with spaces", "<script>", strings.Repeat("a", maxRequestIDLength+1)} {
+		responseRecorder, seenRequestID := serveWithRequestID(requestID)
+
+		assert.NotEqual(t, requestID, seenRequestID)
+		assert.True(t, validRequestID(seenRequestID))
+		assert.Equal(t, seenRequestID, responseRecorder.Header().Get(utility.RequestIDHeaderKey))
+	}
+}
@@ -4,41 +4,130 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/gojektech/proctor-engine/config"
+	"github.com/gojektech/proctor-engine/health"
+	"github.com/gojektech/proctor-engine/jobs/approval"
 	"github.com/gojektech/proctor-engine/jobs/execution"
+	"github.com/gojektech/proctor-engine/jobs/idempotency"
 	"github.com/gojektech/proctor-engine/jobs/logs"
 	"github.com/gojektech/proctor-engine/jobs/metadata"
+	"github.com/gojektech/proctor-engine/jobs/policy"
+	"github.com/gojektech/proctor-engine/jobs/queue"
 	"github.com/gojektech/proctor-engine/jobs/secrets"
+	"github.com/gojektech/proctor-engine/jobs/webhook"
+	"github.com/gojektech/proctor-engine/jobs/workflow"
 	"github.com/gojektech/proctor-engine/kubernetes"
+	"github.com/gojektech/proctor-engine/metrics"
+	"github.com/gojektech/proctor-engine/openapi"
+	"github.com/gojektech/proctor-engine/ratelimit"
 	"github.com/gojektech/proctor-engine/redis"
+	"github.com/gojektech/proctor-engine/registry"
 
 	"github.com/gorilla/mux"
 )
 
-var router *mux.Router
+type Handlers struct {
+	Health         health.HealthHandler
+	Executioner    execution.Executioner
+	Tracker        execution.Tracker
+	Logger         logs.Logger
+	Metadata       metadata.MetadataHandler
+	Secrets        secrets.SecretsHandler
+	Policy         policy.PolicyHandler
+	Approval       approval.ApprovalHandler
+	Workflow       workflow.WorkflowHandler
+	WorkflowRunner workflow.Runner
+	Webhook        webhook.WebhookHandler
+	Notifier       webhook.Notifier
+}
 
-func init() {
-	router = mux.NewRouter()
+func newHandlers(redisClient redis.Client, kubeClient kubernetes.Client, rateLimiter ratelimit.Limiter) Handlers {
+	metadataStore := metadata.NewStore(redisClient)
+	secretsStore := secrets.NewStore(redisClient)
+	approvalStore := approval.NewStore(redisClient)
+	executionStore := execution.NewStore(redisClient)
+	idempotencyStore := idempotency.NewStore(redisClient)
+	queueStore := queue.NewStore(redisClient)
+	workflowStore := workflow.NewStore(redisClient)
+	policyStore := policy.NewStore(redisClient)
 
-	redisClient := redis.NewClient()
+	webhookStore := webhook.NewStore(redisClient)
 
-	kubeConfig := kubernetes.KubeConfig()
-	kubeClient := kubernetes.NewClient(kubeConfig)
+	webhookNotifier := webhook.NewNotifier(webhookStore, config.WebhookSigningSecret(), config.WebhookMaxAttempts(), config.WebhookRetryInitialDelay(), webhook.AllowedHosts())
+	policyEngine := policy.NewEngine(policyStore)
+	executionTracker := execution.NewTracker(kubeClient, executionStore, queueStore, webhookNotifier)
+	jobExecutioner := execution.NewExecutioner(kubeClient, metadataStore, secretsStore, approvalStore, executionStore, idempotencyStore, queueStore, executionTracker, policyEngine, rateLimiter)
+	workflowRunner := workflow.NewRunner(workflowStore, jobExecutioner, executionStore, queueStore, kubeClient)
 
-	metadataStore := metadata.NewStore(redisClient)
-	secretsStore := secrets.NewStore(redisClient)
+	healthChecks := map[string]health.Check{
+		"redis":                redisClient.PING,
+		"kubernetes":           kubeClient.Ping,
+		"kubernetes_namespace": kubeClient.CheckNamespaceAccess,
+	}
 
-	jobExecutioner := execution.NewExecutioner(kubeClient, metadataStore, secretsStore)
-	jobLogger := logs.NewLogger(kubeClient)
-	jobMetadataHandler := metadata.NewMetadataHandler(metadataStore)
-	jobSecretsHandler := secrets.NewSecretsHandler(secretsStore)
+	return Handlers{
+		Health:         health.NewHealthHandler(healthChecks, config.ReadinessCheckCache()),
+		Executioner:    jobExecutioner,
+		Tracker:        executionTracker,
+		Logger:         logs.NewLogger(kubeClient, executionStore),
+		Metadata:       metadata.NewMetadataHandler(metadataStore, registry.NewResolver(registry.InsecureRegistries())),
+		Secrets:        secrets.NewSecretsHandler(secretsStore),
+		Policy:         policy.NewPolicyHandler(policyStore, policyEngine),
+		Approval:       approval.NewApprovalHandler(approvalStore, jobExecutioner),
+		Workflow:       workflow.NewWorkflowHandler(workflowStore, metadataStore, workflowRunner),
+		WorkflowRunner: workflowRunner,
+		Webhook:        webhook.NewWebhookHandler(webhookStore, webhookNotifier),
+		Notifier:       webhookNotifier,
+	}
+}
+
+func NewRouter(handlers Handlers) *mux.Router {
+	router := mux.NewRouter()
 
 	router.HandleFunc("/ping", func(w http.ResponseWriter, req *http.Request) {
 		fmt.Fprintf(w, "pong")
 	})
 
-	router.HandleFunc("/jobs/execute", jobExecutioner.Handle()).Methods("POST")
-	router.HandleFunc("/jobs/logs", jobLogger.Stream()).Methods("GET")
-	router.HandleFunc("/jobs/metadata", jobMetadataHandler.HandleSubmission()).Methods("POST")
-	router.HandleFunc("/jobs/metadata", jobMetadataHandler.HandleBulkDisplay()).Methods("GET")
-	router.HandleFunc("/jobs/secrets", jobSecretsHandler.HandleSubmission()).Methods("POST")
+	router.HandleFunc("/healthz", handlers.Health.HandleLiveness()).Methods("GET")
+	router.HandleFunc("/readyz", handlers.Health.HandleReadiness()).Methods("GET")
+	router.Handle("/metrics", metrics.Handler()).Methods("GET")
+	router.HandleFunc("/openapi.json", openapi.Handler()).Methods("GET")
+
+	api := router.PathPrefix(apiPrefix).Subrouter()
+	api.HandleFunc("/jobs", handlers.Metadata.HandleSubmission()).Methods("POST")
+	api.HandleFunc("/jobs", handlers.Metadata.HandleBulkDisplay()).Methods("GET")
+	api.HandleFunc("/jobs/{name}", handlers.Metadata.HandleDisplay()).Methods("GET")
+	api.HandleFunc("/jobs/{name}/secrets", handlers.Secrets.HandleJobSubmission()).Methods("PUT")
+	api.HandleFunc("/jobs/{name}/executions", handlers.Executioner.HandleExecution()).Methods("POST")
+	api.HandleFunc("/executions/{name}", handlers.Executioner.HandleStatus()).Methods("GET")
+	api.HandleFunc("/executions/{name}/cancel", handlers.Executioner.HandleCancellation()).Methods("POST")
+	api.HandleFunc("/executions/{name}/output", handlers.Executioner.HandleOutput()).Methods("GET")
+	api.HandleFunc("/executions/{name}/logs", handlers.Logger.StreamExecution()).Methods("GET")
+	api.HandleFunc("/queue/{id}", handlers.Executioner.HandleQueueStatus()).Methods("GET")
+	api.HandleFunc("/queue/{id}/cancel", handlers.Executioner.HandleQueueCancellation()).Methods("POST")
+	api.HandleFunc("/policies", handlers.Policy.HandleSubmission()).Methods("POST")
+	api.HandleFunc("/policies", handlers.Policy.HandleDisplay()).Methods("GET")
+	api.HandleFunc("/webhooks/dead-letters", handlers.Webhook.HandleDeadLetters()).Methods("GET")
+	api.HandleFunc("/webhooks/dead-letters/{id}/redeliver", handlers.Webhook.HandleRedelivery()).Methods("POST")
+	api.HandleFunc("/execution-requests/{id}/approve", handlers.Approval.HandleApproval()).Methods("POST")
+	api.HandleFunc("/execution-requests/{id}/reject", handlers.Approval.HandleRejection()).Methods("POST")
+	api.HandleFunc("/workflows", handlers.Workflow.HandleSubmission()).Methods("POST")
+	api.HandleFunc("/workflows/{name}", handlers.Workflow.HandleDisplay()).Methods("GET")
+	api.HandleFunc("/workflows/{name}/runs", handlers.Workflow.HandleStart()).Methods("POST")
+	api.HandleFunc("/workflows/{name}/runs/{id}", handlers.Workflow.HandleRunDisplay()).Methods("GET")
+	api.HandleFunc("/workflows/{name}/runs/{id}/cancel", handlers.Workflow.HandleCancellation()).Methods("POST")
+
+	router.HandleFunc("/jobs/execute", deprecated(handlers.Executioner.Handle(), "/jobs/{name}/executions")).Methods("POST")
+	router.HandleFunc("/jobs/execute/{name}/output", deprecated(handlers.Executioner.HandleOutput(), "/executions/{name}/output")).Methods("GET")
+	router.HandleFunc("/jobs/queue/{id}", deprecated(handlers.Executioner.HandleQueueStatus(), "/queue/{id}")).Methods("GET")
+	router.HandleFunc("/jobs/logs", deprecated(handlers.Logger.Stream(), "/executions/{name}/logs")).Methods("GET")
+	router.HandleFunc("/jobs/metadata", deprecated(handlers.Metadata.HandleSubmission(), "/jobs")).Methods("POST")
+	router.HandleFunc("/jobs/metadata", deprecated(handlers.Metadata.HandleBulkDisplay(), "/jobs")).Methods("GET")
+	router.HandleFunc("/jobs/secrets", deprecated(handlers.Secrets.HandleSubmission(), "/jobs/{name}/secrets")).Methods("POST")
+	router.HandleFunc("/jobs/policies", deprecated(handlers.Policy.HandleSubmission(), "/policies")).Methods("POST")
+	router.HandleFunc("/jobs/policies", deprecated(handlers.Policy.HandleDisplay(), "/policies")).Methods("GET")
+	router.HandleFunc("/jobs/requests/{id}/approve", deprecated(handlers.Approval.HandleApproval(), "/execution-requests/{id}/approve")).Methods("POST")
+	router.HandleFunc("/jobs/requests/{id}/reject", deprecated(handlers.Approval.HandleRejection(), "/execution-requests/{id}/reject")).Methods("POST")
+
+	return router
 }
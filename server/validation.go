@@ -0,0 +1,45 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gojektech/proctor-engine/config"
+	"github.com/gojektech/proctor-engine/logger"
+	"github.com/gojektech/proctor-engine/openapi"
+	"github.com/gojektech/proctor-engine/utility"
+
+	"github.com/gorilla/mux"
+	"github.com/urfave/negroni"
+)
+
+func validateRequests(router *mux.Router, document *openapi.Document) negroni.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+		maxBytes := config.RequestBodyMaxBytes()
+		fieldErrors, err := document.ValidateRequest(routeTemplate(router, req), req, maxBytes)
+		if err == openapi.ErrMalformedBody {
+			utility.WriteError(w, req, http.StatusBadRequest, utility.ErrCodeMalformedRequest, utility.ClientError)
+			return
+		}
+		if err == openapi.ErrBodyTooLarge {
+			utility.WriteError(w, req, http.StatusRequestEntityTooLarge, utility.ErrCodeRequestTooLarge, fmt.Sprintf("request body must not exceed %d bytes", maxBytes))
+			return
+		}
+		if err == openapi.ErrUnsupportedMediaType {
+			utility.WriteError(w, req, http.StatusUnsupportedMediaType, utility.ErrCodeUnsupportedMediaType, fmt.Sprintf("content type %q is not supported", req.Header.Get("Content-Type")))
+			return
+		}
+		if err != nil {
+			logger.FromContext(req.Context()).Error("Error reading request body", err.Error())
+
+			utility.WriteError(w, req, http.StatusBadRequest, utility.ErrCodeMalformedRequest, utility.ClientError)
+			return
+		}
+		if len(fieldErrors) > 0 {
+			utility.WriteValidationError(w, req, fieldErrors)
+			return
+		}
+
+		next(w, req)
+	}
+}
@@ -0,0 +1,84 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gojektech/proctor-engine/openapi"
+	"github.com/gojektech/proctor-engine/utility"
+
+	"github.com/gorilla/mux"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func errorCode(t *testing.T, responseRecorder *httptest.ResponseRecorder) string {
+	var errorResponse utility.ErrorResponse
+	assert.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &errorResponse))
+	return errorResponse.Error.Code
+}
+
+func serveValidated(body string) (*httptest.ResponseRecorder, bool) {
+	return serveValidatedWithContentType(body, "application/json")
+}
+
+func serveValidatedWithContentType(body string, contentType string) (*httptest.ResponseRecorder, bool) {
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/policies", func(http.ResponseWriter, *http.Request) {}).Methods("POST")
+
+	req := httptest.NewRequest("POST", "/api/v1/policies", strings.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	responseRecorder := httptest.NewRecorder()
+
+	served := false
+	validateRequests(router, openapi.Load())(responseRecorder, req, func(http.ResponseWriter, *http.Request) {
+		served = true
+	})
+	return responseRecorder, served
+}
+
+func TestValidateRequestsPassesValidBody(t *testing.T) {
+	_, served := serveValidated(`[{"name": "quiet-hours", "deny": "time.hour >= 22", "reason": "too late"}]`)
+
+	assert.True(t, served)
+}
+
+func TestValidateRequestsRejectsInvalidBody(t *testing.T) {
+	responseRecorder, served := serveValidated(`[{"name": "quiet-hours"}]`)
+
+	assert.False(t, served)
+	assert.Equal(t, http.StatusUnprocessableEntity, responseRecorder.Code)
+	assert.Equal(t, utility.ErrCodeValidationFailed, errorCode(t, responseRecorder))
+}
+
+func TestValidateRequestsRejectsMalformedBody(t *testing.T) {
+	responseRecorder, served := serveValidated(`[{"name":`)
+
+	assert.False(t, served)
+	assert.Equal(t, http.StatusBadRequest, responseRecorder.Code)
+	assert.Equal(t, utility.ErrCodeMalformedRequest, errorCode(t, responseRecorder))
+}
+
+func TestValidateRequestsRejectsBodyOverLimit(t *testing.T) {
+	os.Setenv("PROCTOR_REQUEST_BODY_MAX_BYTES", "16")
+	defer os.Unsetenv("PROCTOR_REQUEST_BODY_MAX_BYTES")
+	viper.AutomaticEnv()
+
+	responseRecorder, served := serveValidated(`[{"name": "quiet-hours", "deny": "time.hour >= 22", "reason": "too late"}]`)
+
+	assert.False(t, served)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, responseRecorder.Code)
+	assert.Equal(t, utility.ErrCodeRequestTooLarge, errorCode(t, responseRecorder))
+}
+
+func TestValidateRequestsRejectsUnsupportedMediaType(t *testing.T) {
+	responseRecorder, served := serveValidatedWithContentType(`[{"name": "quiet-hours"}]`, "text/plain")
+
+	assert.False(t, served)
+	assert.Equal(t, http.StatusUnsupportedMediaType, responseRecorder.Code)
+	assert.Equal(t, utility.ErrCodeUnsupportedMediaType, errorCode(t, responseRecorder))
+}
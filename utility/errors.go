@@ -0,0 +1,81 @@
+package utility
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+const RequestIDHeaderKey = "X-Request-ID"
+
+const (
+	ErrCodeMalformedRequest         = "malformed_request"
+	ErrCodeValidationFailed         = "validation_failed"
+	ErrCodeJobNotFound              = "job_not_found"
+	ErrCodeExecutionRequestNotFound = "execution_request_not_found"
+	ErrCodeExecutionNotFound        = "execution_not_found"
+	ErrCodeExecutionNotFinished     = "execution_not_finished"
+	ErrCodeExecutionFinished        = "execution_finished"
+	ErrCodeOutputNotFound           = "output_not_found"
+	ErrCodeIdempotencyKeyReused     = "idempotency_key_reused"
+	ErrCodeRequestInProgress        = "request_in_progress"
+	ErrCodeConcurrencyLimitReached  = "concurrency_limit_reached"
+	ErrCodeQueueEntryNotFound       = "queue_entry_not_found"
+	ErrCodeQueueEntryNotQueued      = "queue_entry_not_queued"
+	ErrCodeQueueUnavailable         = "queue_unavailable"
+	ErrCodeWorkflowNotFound         = "workflow_not_found"
+	ErrCodeWorkflowRunNotFound      = "workflow_run_not_found"
+	ErrCodeWorkflowRunFinished      = "workflow_run_finished"
+	ErrCodeDeadLetterNotFound       = "dead_letter_not_found"
+	ErrCodeFilesTooLarge            = "files_too_large"
+	ErrCodeRequestTooLarge          = "request_too_large"
+	ErrCodeUnsupportedMediaType     = "unsupported_media_type"
+	ErrCodeImageNotAllowed          = "image_not_allowed"
+	ErrCodePolicyDenied             = "policy_denied"
+	ErrCodeRateLimited              = "rate_limited"
+	ErrCodeSelfApprovalForbidden    = "self_approval_forbidden"
+	ErrCodeApprovalRequired         = "approval_required"
+	ErrCodeUnauthorized             = "unauthorized"
+	ErrCodeForbidden                = "forbidden"
+	ErrCodeStoreUnavailable         = "store_unavailable"
+	ErrCodeExecutionFailed          = "execution_failed"
+	ErrCodeInternal                 = "internal_error"
+)
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+type Error struct {
+	Code      string       `json:"code"`
+	Message   string       `json:"message"`
+	Details   []FieldError `json:"details,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+}
+
+type ErrorResponse struct {
+	Error Error `json:"error"`
+}
+
+func NewErrorResponse(req *http.Request, code, message string, details ...FieldError) ErrorResponse {
+	return ErrorResponse{
+		Error: Error{
+			Code:      code,
+			Message:   message,
+			Details:   details,
+			RequestID: req.Header.Get(RequestIDHeaderKey),
+		},
+	}
+}
+
+func WriteError(w http.ResponseWriter, req *http.Request, status int, code, message string, details ...FieldError) {
+	errorResponse := NewErrorResponse(req, code, message, details...)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(errorResponse)
+}
+
+func WriteValidationError(w http.ResponseWriter, req *http.Request, details []FieldError) {
+	WriteError(w, req, http.StatusUnprocessableEntity, ErrCodeValidationFailed, "request failed validation", details...)
+}
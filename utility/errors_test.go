@@ -0,0 +1,47 @@
+package utility
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteError(t *testing.T) {
+	req := httptest.NewRequest("POST", "/jobs/execute", nil)
+	req.Header.Set(RequestIDHeaderKey, "request-id")
+	responseRecorder := httptest.NewRecorder()
+
+	WriteError(responseRecorder, req, http.StatusNotFound, ErrCodeJobNotFound, "job not found")
+
+	assert.Equal(t, http.StatusNotFound, responseRecorder.Code)
+	assert.Equal(t, "application/json", responseRecorder.Header().Get("Content-Type"))
+
+	var errorResponse ErrorResponse
+	err := json.Unmarshal(responseRecorder.Body.Bytes(), &errorResponse)
+	assert.NoError(t, err)
+
+	assert.Equal(t, ErrCodeJobNotFound, errorResponse.Error.Code)
+	assert.Equal(t, "job not found", errorResponse.Error.Message)
+	assert.Equal(t, "request-id", errorResponse.Error.RequestID)
+	assert.Empty(t, errorResponse.Error.Details)
+}
+
+func TestWriteValidationError(t *testing.T) {
+	req := httptest.NewRequest("POST", "/jobs/execute", nil)
+	responseRecorder := httptest.NewRecorder()
+
+	details := []FieldError{{Field: "name", Message: "is required"}}
+	WriteValidationError(responseRecorder, req, details)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, responseRecorder.Code)
+
+	var errorResponse ErrorResponse
+	err := json.Unmarshal(responseRecorder.Body.Bytes(), &errorResponse)
+	assert.NoError(t, err)
+
+	assert.Equal(t, ErrCodeValidationFailed, errorResponse.Error.Code)
+	assert.Equal(t, details, errorResponse.Error.Details)
+}
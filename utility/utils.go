@@ -1,8 +1,16 @@
 package utility
 
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/url"
+)
+
 const ClientError = "malformed request"
 const ServerError = "Something went wrong"
 
+const UserEmailHeaderKey = "Email-Id"
+
 func MergeMaps(mapOne, mapTwo map[string]string) map[string]string {
 	result := make(map[string]string)
 
@@ -14,3 +22,20 @@ func MergeMaps(mapOne, mapTwo map[string]string) map[string]string {
 	}
 	return result
 }
+
+func RandomID() (string, error) {
+	randomBytes := make([]byte, 16)
+	_, err := rand.Read(randomBytes)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(randomBytes), nil
+}
+
+func IsHTTPURL(rawURL string) bool {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	return (parsedURL.Scheme == "http" || parsedURL.Scheme == "https") && parsedURL.Host != ""
+}